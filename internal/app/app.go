@@ -4,12 +4,13 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"power-supply-sys/internal/domain/rbac"
 	"power-supply-sys/internal/infra/db"
 	httputil "power-supply-sys/internal/transport/http"
 	httphandler "power-supply-sys/internal/transport/http/handler"
 	httpmiddleware "power-supply-sys/internal/transport/http/middleware"
-	"power-supply-sys/pkg/auth"
 	"power-supply-sys/pkg/logger"
+	"sync"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -23,6 +24,10 @@ type App struct {
 	container *Container
 	router    *gin.Engine
 	server    *http.Server
+
+	// 后台任务
+	stopWorkers context.CancelFunc
+	workers     sync.WaitGroup
 }
 
 // New 创建新的应用实例
@@ -64,11 +69,17 @@ func New() (*App, error) {
 	logger.Info("Database migration completed")
 
 	// 5. 创建依赖容器
-	app.container = NewContainer(config, database)
+	container, err := NewContainer(config, database)
+	if err != nil {
+		return nil, fmt.Errorf("初始化依赖容器失败: %w", err)
+	}
+	app.container = container
 	logger.Info("Dependency container initialized")
 
 	// 6. 设置路由
-	app.setupRouter()
+	if err := app.setupRouter(); err != nil {
+		return nil, fmt.Errorf("设置路由失败: %w", err)
+	}
 	logger.Info("Router setup completed")
 
 	return app, nil
@@ -106,12 +117,15 @@ func (a *App) healthCheckHandler(c *gin.Context) {
 }
 
 // setupRouter 设置路由
-func (a *App) setupRouter() {
+func (a *App) setupRouter() error {
 	if !a.config.Debug {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	r := gin.New()
+	r, err := newEngine(&a.config.Server)
+	if err != nil {
+		return err
+	}
 
 	// 使用中间件
 	r.Use(httpmiddleware.Logger())
@@ -122,68 +136,320 @@ func (a *App) setupRouter() {
 	// 健康检查
 	r.GET("/health", a.healthCheckHandler)
 
-	// 从容器获取依赖
-	userService := a.container.UserService
-	powerService := a.container.PowerService
-	jwtManager := a.container.JWTManager
+	// JWT 验签公钥（供其他服务验证 token）
+	r.GET("/.well-known/jwks.json", httphandler.NewJWKSHandler(a.container.JWTManager).Get)
 
 	// 初始化 Handlers（从容器获取依赖）
-	userHandler := httphandler.NewUserHandler(userService, jwtManager)
-	powerHandler := httphandler.NewPowerHandler(powerService)
+	h := &handlers{
+		user:        httphandler.NewUserHandler(a.container.UserService, a.container.AuthService, a.container.TwoFactorService, a.container.EmailVerificationService),
+		power:       httphandler.NewPowerHandler(a.container.PowerService),
+		pricing:     httphandler.NewPricingHandler(a.container.PricingService),
+		recommend:   httphandler.NewRecommendHandler(a.container.RecommendService),
+		component:   httphandler.NewComponentHandler(a.container.ComponentService),
+		catalog:     httphandler.NewCatalogHandler(a.container.CatalogService),
+		auth:        httphandler.NewAuthHandler(a.container.AuthService),
+		rbac:        httphandler.NewRBACHandler(a.container.RBACService),
+		password:    httphandler.NewPasswordHandler(a.container.PasswordService),
+		twoFactor:   httphandler.NewTwoFactorHandler(a.container.TwoFactorService, a.container.AuthService),
+		apiKey:      httphandler.NewAPIKeyHandler(a.container.APIKeyService),
+		session:     httphandler.NewSessionHandler(a.container.AuthService),
+		oidc:        httphandler.NewOIDCHandler(a.container.OIDCService, a.container.AuthService, a.container.TwoFactorService),
+		verify:      httphandler.NewEmailVerificationHandler(a.container.EmailVerificationService),
+		impersonate: httphandler.NewImpersonationHandler(a.container.ImpersonationService),
+		inventory:   httphandler.NewInventoryHandler(a.container.InventoryService),
+		reservation: httphandler.NewReservationHandler(a.container.ReservationService),
+		order:       httphandler.NewOrderHandler(a.container.OrderService),
+		warehouse:   httphandler.NewWarehouseHandler(a.container.WarehouseService),
+		supplier:    httphandler.NewSupplierHandler(a.container.SupplierService),
+		purchase:    httphandler.NewPurchaseOrderHandler(a.container.PurchaseOrderService),
+	}
 
 	// 注册 API 路由
-	a.registerAPIRoutes(r, userHandler, powerHandler, jwtManager)
+	a.registerAPIRoutes(r, h)
 
 	a.router = r
+	return nil
+}
+
+// newEngine 创建 gin 引擎，只采信可信代理转发的客户端 IP
+// 登录失败的 IP 计数依赖 ClientIP，信任任意来源的 X-Forwarded-For 会让伪造请求头绕过限制
+func newEngine(cfg *ServerConfig) (*gin.Engine, error) {
+	r := gin.New()
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("可信代理配置无效: %w", err)
+	}
+	return r, nil
+}
+
+// handlers 路由使用的处理器集合
+type handlers struct {
+	user        *httphandler.UserHandler
+	power       *httphandler.PowerHandler
+	pricing     *httphandler.PricingHandler
+	recommend   *httphandler.RecommendHandler
+	component   *httphandler.ComponentHandler
+	catalog     *httphandler.CatalogHandler
+	auth        *httphandler.AuthHandler
+	rbac        *httphandler.RBACHandler
+	password    *httphandler.PasswordHandler
+	twoFactor   *httphandler.TwoFactorHandler
+	apiKey      *httphandler.APIKeyHandler
+	session     *httphandler.SessionHandler
+	oidc        *httphandler.OIDCHandler
+	verify      *httphandler.EmailVerificationHandler
+	impersonate *httphandler.ImpersonationHandler
+	inventory   *httphandler.InventoryHandler
+	reservation *httphandler.ReservationHandler
+	order       *httphandler.OrderHandler
+	warehouse   *httphandler.WarehouseHandler
+	supplier    *httphandler.SupplierHandler
+	purchase    *httphandler.PurchaseOrderHandler
+}
+
+// requirePermission 创建权限校验中间件
+func (a *App) requirePermission(permission string) gin.HandlerFunc {
+	return httpmiddleware.RequirePermission(a.container.RBACService, permission)
+}
+
+// jwtAuth 创建 JWT 认证中间件，按配置启用滑动会话
+func (a *App) jwtAuth() gin.HandlerFunc {
+	var opts []httpmiddleware.JWTAuthOption
+	if a.config.JWT.SlidingSession {
+		opts = append(opts, httpmiddleware.WithSlidingSession(a.config.JWT.GetSlidingRenewAfter()))
+	}
+	return httpmiddleware.JWTAuth(a.container.JWTManager, a.container.AuthService, opts...)
 }
 
 // registerAPIRoutes 注册 API 路由
-func (a *App) registerAPIRoutes(r *gin.Engine, userHandler *httphandler.UserHandler, powerHandler *httphandler.PowerHandler, jwtManager *auth.JWTManager) {
+func (a *App) registerAPIRoutes(r *gin.Engine, h *handlers) {
 	// API v1 路由组
 	v1 := r.Group("/api/v1")
 	{
 		// 认证相关路由（无需JWT验证）
-		a.registerAuthRoutes(v1, userHandler)
+		a.registerAuthRoutes(v1, h)
 
 		// 需要JWT认证的路由
+		// 模拟登录期间的写请求记录审计日志
+		jwtAuth := a.jwtAuth()
+		impersonationAudit := httpmiddleware.ImpersonationAudit(a.container.ImpersonationService)
 		authorized := v1.Group("")
-		authorized.Use(httpmiddleware.JWTAuth(jwtManager))
+		authorized.Use(jwtAuth, impersonationAudit)
+		{
+			a.registerUserRoutes(authorized, h)
+			a.registerRBACRoutes(authorized, h)
+			a.registerAPIKeyRoutes(authorized, h)
+			a.registerAdminRoutes(authorized, h)
+			a.registerOrderRoutes(authorized, h)
+			a.registerSupplierRoutes(authorized, h)
+			a.registerPurchaseOrderRoutes(authorized, h)
+		}
+
+		// 电源目录同时接受 API Key 认证（供同步脚本等机器调用），访问范围受 Key 的权限范围限制
+		catalog := v1.Group("")
+		catalog.Use(httpmiddleware.APIKeyAuth(a.container.APIKeyService, jwtAuth), impersonationAudit)
 		{
-			a.registerUserRoutes(authorized, userHandler)
-			a.registerPowerRoutes(authorized, powerHandler)
+			a.registerPowerRoutes(catalog, h)
+			a.registerReservationRoutes(catalog, h)
+			a.registerWarehouseRoutes(catalog, h)
+			a.registerComponentRoutes(catalog, h)
+			a.registerCatalogRoutes(catalog, h)
 		}
 	}
 }
 
 // registerAuthRoutes 注册认证路由
-func (a *App) registerAuthRoutes(rg *gin.RouterGroup, handler *httphandler.UserHandler) {
+func (a *App) registerAuthRoutes(rg *gin.RouterGroup, h *handlers) {
 	authGroup := rg.Group("/auth")
 	{
-		authGroup.POST("/login", handler.Login)
-		authGroup.POST("/register", handler.Create)
+		authGroup.POST("/login", h.user.Login)
+		authGroup.POST("/register", h.user.Register)
+		authGroup.POST("/verify-email", h.verify.Verify)
+		authGroup.POST("/verify-email/resend", h.verify.Resend)
+		authGroup.POST("/refresh", h.auth.Refresh)
+		authGroup.POST("/logout", h.auth.Logout)
+		authGroup.POST("/password/forgot", h.password.Forgot)
+		authGroup.POST("/password/reset", h.password.Reset)
+		authGroup.POST("/2fa/verify", h.twoFactor.Verify)
+		authGroup.GET("/oidc/login", h.oidc.Login)
+		authGroup.GET("/oidc/callback", h.oidc.Callback)
 	}
 }
 
 // registerUserRoutes 注册用户路由
-func (a *App) registerUserRoutes(rg *gin.RouterGroup, handler *httphandler.UserHandler) {
+func (a *App) registerUserRoutes(rg *gin.RouterGroup, h *handlers) {
+	denyImpersonation := httpmiddleware.DenyImpersonation()
 	userGroup := rg.Group("/users")
 	{
-		userGroup.GET("", handler.List)
-		userGroup.GET("/:id", handler.Get)
-		userGroup.PUT("/:id", handler.Update)
-		userGroup.DELETE("/:id", handler.Delete)
+		userGroup.GET("/me", h.user.GetMe)
+		userGroup.PUT("/me", h.user.UpdateMe)
+		// 凭证与会话管理不允许使用模拟令牌
+		userGroup.POST("/me/password", denyImpersonation, h.password.Change)
+		userGroup.GET("/me/sessions", denyImpersonation, h.session.List)
+		userGroup.DELETE("/me/sessions/:id", denyImpersonation, h.session.Terminate)
+		userGroup.GET("/me/2fa", denyImpersonation, h.twoFactor.Status)
+		userGroup.POST("/me/2fa/enroll", denyImpersonation, h.twoFactor.Enroll)
+		userGroup.POST("/me/2fa/confirm", denyImpersonation, h.twoFactor.Confirm)
+		userGroup.POST("/me/2fa/disable", denyImpersonation, h.twoFactor.Disable)
+		userGroup.POST("/me/2fa/recovery-codes", denyImpersonation, h.twoFactor.RegenerateRecoveryCodes)
+		userGroup.GET("", a.requirePermission(rbac.PermUserRead), h.user.List)
+		userGroup.GET("/:id", a.requirePermission(rbac.PermUserRead), h.user.Get)
+		// 修改与删除的资源级授权由 UserService 完成（本人或拥有相应权限）
+		userGroup.PUT("/:id", h.user.Update)
+		userGroup.DELETE("/:id", h.user.Delete)
+		userGroup.PUT("/:id/role", a.requirePermission(rbac.PermRoleManage), h.rbac.AssignUserRole)
+		userGroup.POST("/:id/unlock", a.requirePermission(rbac.PermUserManage), h.user.Unlock)
 	}
 }
 
 // registerPowerRoutes 注册电源路由
-func (a *App) registerPowerRoutes(rg *gin.RouterGroup, handler *httphandler.PowerHandler) {
+func (a *App) registerPowerRoutes(rg *gin.RouterGroup, h *handlers) {
 	powerGroup := rg.Group("/powers")
 	{
-		powerGroup.GET("", handler.List)
-		powerGroup.GET("/:id", handler.Get)
-		powerGroup.POST("", handler.Create)
-		powerGroup.PUT("/:id", handler.Update)
-		powerGroup.DELETE("/:id", handler.Delete)
+		powerGroup.GET("", a.requirePermission(rbac.PermPowerRead), h.power.List)
+		powerGroup.GET("/compare", a.requirePermission(rbac.PermPowerRead), h.power.Compare)
+		powerGroup.POST("/recommend", a.requirePermission(rbac.PermPowerRead), h.recommend.Recommend)
+		powerGroup.GET("/:id", a.requirePermission(rbac.PermPowerRead), h.power.Get)
+		powerGroup.POST("", a.requirePermission(rbac.PermPowerWrite), h.power.Create)
+		powerGroup.PUT("/:id", a.requirePermission(rbac.PermPowerWrite), h.power.Update)
+		powerGroup.DELETE("/:id", a.requirePermission(rbac.PermPowerWrite), h.power.Delete)
+		powerGroup.GET("/:id/stock-movements", a.requirePermission(rbac.PermPowerRead), h.inventory.ListMovements)
+		powerGroup.POST("/:id/stock-movements", a.requirePermission(rbac.PermPowerWrite), h.inventory.RecordMovement)
+		powerGroup.POST("/:id/stock-transfers", a.requirePermission(rbac.PermPowerWrite), h.inventory.Transfer)
+		powerGroup.GET("/:id/price-history", a.requirePermission(rbac.PermPowerRead), h.pricing.History)
+		powerGroup.GET("/:id/price-schedules", a.requirePermission(rbac.PermPowerRead), h.pricing.ListSchedules)
+		powerGroup.POST("/:id/price-schedules", a.requirePermission(rbac.PermPowerWrite), h.pricing.CreateSchedule)
+		powerGroup.POST("/:id/price-schedules/:schedule_id/cancel", a.requirePermission(rbac.PermPowerWrite), h.pricing.CancelSchedule)
+	}
+}
+
+// registerReservationRoutes 注册库存预留路由
+func (a *App) registerReservationRoutes(rg *gin.RouterGroup, h *handlers) {
+	reservationGroup := rg.Group("/reservations")
+	reservationGroup.Use(a.requirePermission(rbac.PermPowerWrite))
+	{
+		reservationGroup.POST("", h.reservation.Create)
+		reservationGroup.GET("/:id", h.reservation.Get)
+		reservationGroup.POST("/:id/confirm", h.reservation.Confirm)
+		reservationGroup.POST("/:id/cancel", h.reservation.Cancel)
+	}
+}
+
+// registerWarehouseRoutes 注册仓库路由
+func (a *App) registerWarehouseRoutes(rg *gin.RouterGroup, h *handlers) {
+	warehouseGroup := rg.Group("/warehouses")
+	{
+		warehouseGroup.GET("", a.requirePermission(rbac.PermPowerRead), h.warehouse.List)
+		warehouseGroup.GET("/:id", a.requirePermission(rbac.PermPowerRead), h.warehouse.Get)
+		warehouseGroup.POST("", a.requirePermission(rbac.PermPowerWrite), h.warehouse.Create)
+		warehouseGroup.PUT("/:id", a.requirePermission(rbac.PermPowerWrite), h.warehouse.Update)
+	}
+}
+
+// registerComponentRoutes 注册组件功耗档案路由（电源推荐使用的 CPU 与显卡功耗数据）
+func (a *App) registerComponentRoutes(rg *gin.RouterGroup, h *handlers) {
+	componentGroup := rg.Group("/components")
+	{
+		componentGroup.GET("", a.requirePermission(rbac.PermPowerRead), h.component.List)
+		componentGroup.GET("/:id", a.requirePermission(rbac.PermPowerRead), h.component.Get)
+		componentGroup.POST("", a.requirePermission(rbac.PermPowerWrite), h.component.Create)
+		componentGroup.POST("/import", a.requirePermission(rbac.PermPowerWrite), h.component.Import)
+		componentGroup.PUT("/:id", a.requirePermission(rbac.PermPowerWrite), h.component.Update)
+		componentGroup.DELETE("/:id", a.requirePermission(rbac.PermPowerWrite), h.component.Delete)
+	}
+}
+
+// registerCatalogRoutes 注册电源分类与标签路由
+func (a *App) registerCatalogRoutes(rg *gin.RouterGroup, h *handlers) {
+	categoryGroup := rg.Group("/categories")
+	{
+		categoryGroup.GET("", a.requirePermission(rbac.PermPowerRead), h.catalog.CategoryTree)
+		categoryGroup.GET("/:id", a.requirePermission(rbac.PermPowerRead), h.catalog.GetCategory)
+		categoryGroup.POST("", a.requirePermission(rbac.PermPowerWrite), h.catalog.CreateCategory)
+		categoryGroup.PUT("/:id", a.requirePermission(rbac.PermPowerWrite), h.catalog.UpdateCategory)
+		categoryGroup.DELETE("/:id", a.requirePermission(rbac.PermPowerWrite), h.catalog.DeleteCategory)
+	}
+
+	tagGroup := rg.Group("/tags")
+	{
+		tagGroup.GET("", a.requirePermission(rbac.PermPowerRead), h.catalog.ListTags)
+		tagGroup.POST("", a.requirePermission(rbac.PermPowerWrite), h.catalog.CreateTag)
+		tagGroup.PUT("/:id", a.requirePermission(rbac.PermPowerWrite), h.catalog.UpdateTag)
+		tagGroup.DELETE("/:id", a.requirePermission(rbac.PermPowerWrite), h.catalog.DeleteTag)
+	}
+}
+
+// registerOrderRoutes 注册订单路由
+// 下单用户可以查看、取消和完成本人的订单，其余操作由服务层按权限校验
+func (a *App) registerOrderRoutes(rg *gin.RouterGroup, h *handlers) {
+	orderGroup := rg.Group("/orders")
+	{
+		orderGroup.POST("", a.requirePermission(rbac.PermOrderCreate), h.order.Create)
+		orderGroup.GET("", h.order.List)
+		orderGroup.GET("/:id", h.order.Get)
+		orderGroup.POST("/:id/pay", a.requirePermission(rbac.PermOrderManage), h.order.Pay)
+		orderGroup.POST("/:id/cancel", h.order.Cancel)
+		orderGroup.POST("/:id/complete", h.order.Complete)
+		orderGroup.POST("/:id/ship", a.requirePermission(rbac.PermOrderManage), h.order.Ship)
+		orderGroup.POST("/:id/refund", a.requirePermission(rbac.PermOrderManage), h.order.Refund)
+	}
+}
+
+// registerSupplierRoutes 注册供应商路由
+func (a *App) registerSupplierRoutes(rg *gin.RouterGroup, h *handlers) {
+	supplierGroup := rg.Group("/suppliers")
+	{
+		supplierGroup.GET("", a.requirePermission(rbac.PermSupplierRead), h.supplier.List)
+		supplierGroup.GET("/:id", a.requirePermission(rbac.PermSupplierRead), h.supplier.Get)
+		supplierGroup.POST("", a.requirePermission(rbac.PermSupplierWrite), h.supplier.Create)
+		supplierGroup.PUT("/:id", a.requirePermission(rbac.PermSupplierWrite), h.supplier.Update)
+		supplierGroup.DELETE("/:id", a.requirePermission(rbac.PermSupplierWrite), h.supplier.Delete)
+		supplierGroup.PUT("/:id/products/:power_supply_id", a.requirePermission(rbac.PermSupplierWrite), h.supplier.SetProduct)
+		supplierGroup.DELETE("/:id/products/:power_supply_id", a.requirePermission(rbac.PermSupplierWrite), h.supplier.RemoveProduct)
+	}
+}
+
+// registerPurchaseOrderRoutes 注册采购单路由
+func (a *App) registerPurchaseOrderRoutes(rg *gin.RouterGroup, h *handlers) {
+	purchaseGroup := rg.Group("/purchase-orders")
+	{
+		purchaseGroup.GET("", a.requirePermission(rbac.PermPurchaseRead), h.purchase.List)
+		purchaseGroup.GET("/:id", a.requirePermission(rbac.PermPurchaseRead), h.purchase.Get)
+		purchaseGroup.POST("", a.requirePermission(rbac.PermPurchaseWrite), h.purchase.Create)
+		purchaseGroup.PUT("/:id", a.requirePermission(rbac.PermPurchaseWrite), h.purchase.Update)
+		purchaseGroup.POST("/:id/send", a.requirePermission(rbac.PermPurchaseWrite), h.purchase.Send)
+		purchaseGroup.POST("/:id/receive", a.requirePermission(rbac.PermPurchaseWrite), h.purchase.Receive)
+	}
+}
+
+// registerAPIKeyRoutes 注册 API Key 管理路由（仅管理本人的 Key，不允许使用模拟令牌）
+func (a *App) registerAPIKeyRoutes(rg *gin.RouterGroup, h *handlers) {
+	apiKeyGroup := rg.Group("/api-keys")
+	apiKeyGroup.Use(httpmiddleware.DenyImpersonation())
+	{
+		apiKeyGroup.GET("", h.apiKey.List)
+		apiKeyGroup.POST("", h.apiKey.Create)
+		apiKeyGroup.DELETE("/:id", h.apiKey.Revoke)
+	}
+}
+
+// registerRBACRoutes 注册角色权限管理路由
+func (a *App) registerRBACRoutes(rg *gin.RouterGroup, h *handlers) {
+	roleGroup := rg.Group("/roles")
+	roleGroup.Use(a.requirePermission(rbac.PermRoleManage))
+	{
+		roleGroup.GET("", h.rbac.ListRoles)
+		roleGroup.POST("", h.rbac.CreateRole)
+		roleGroup.PUT("/:id/permissions", h.rbac.SetRolePermissions)
+	}
+
+	rg.GET("/permissions", a.requirePermission(rbac.PermRoleManage), h.rbac.ListPermissions)
+}
+
+// registerAdminRoutes 注册管理员路由
+func (a *App) registerAdminRoutes(rg *gin.RouterGroup, h *handlers) {
+	adminGroup := rg.Group("/admin")
+	{
+		adminGroup.POST("/impersonate/:id", a.requirePermission(rbac.PermUserImpersonate), h.impersonate.Impersonate)
 	}
 }
 
@@ -204,6 +470,8 @@ func (a *App) Run() error {
 		zap.Duration("idle_timeout", a.config.Server.GetIdleTimeout()),
 	)
 
+	a.startWorkers()
+
 	// ListenAndServe 会阻塞直到出现错误或调用 Shutdown
 	if err := a.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 		logger.Error("Server start failed", zap.Error(err))
@@ -226,6 +494,18 @@ func (a *App) Shutdown(ctx context.Context) error {
 		logger.Info("HTTP server shutdown successfully")
 	}
 
+	// 停止后台任务（需在关闭数据库连接之前）
+	if a.stopWorkers != nil {
+		a.stopWorkers()
+		a.workers.Wait()
+		logger.Info("Background workers stopped")
+	}
+
+	// 等待后台发送的邮件
+	if a.container != nil {
+		a.container.AsyncMailer.Wait()
+	}
+
 	// 关闭数据库连接
 	if a.db != nil {
 		sqlDB, err := a.db.DB()
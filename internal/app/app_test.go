@@ -0,0 +1,76 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"power-supply-sys/internal/domain/lockout"
+	"power-supply-sys/internal/infra/repo"
+	"power-supply-sys/internal/service"
+	"power-supply-sys/pkg/common"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEngine_ClientIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// 模拟登录：先检查限制，再记录一次失败，按错误码返回状态
+	loginRoute := func(r *gin.Engine, limiter service.LoginLimiter) {
+		r.POST("/login", func(c *gin.Context) {
+			ctx := c.Request.Context()
+			err := limiter.Check(ctx, c.Query("username"), c.ClientIP())
+			if err == nil {
+				err = limiter.RecordFailure(ctx, c.Query("username"), c.ClientIP())
+			}
+			if common.HasErrorCode(err, common.ErrCodeTooManyRequests) {
+				c.Status(http.StatusTooManyRequests)
+				return
+			}
+			c.Status(http.StatusUnauthorized)
+		})
+	}
+	login := func(r *gin.Engine, i int, forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodPost, "/login?username=u"+strconv.Itoa(i), nil)
+		req.RemoteAddr = "203.0.113.7:40000"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+	policy := lockout.Policy{MaxFailures: 5, IPMaxFailures: 3, Window: time.Minute, LockDuration: time.Minute}
+
+	t.Run("默认不信任伪造的 X-Forwarded-For", func(t *testing.T) {
+		r, err := newEngine(&ServerConfig{})
+		require.NoError(t, err)
+		loginRoute(r, service.NewLoginLimiter(repo.NewMemoryLoginAttemptStore(), policy))
+
+		var code int
+		for i := 0; i < 3; i++ {
+			code = login(r, i, "198.51.100."+strconv.Itoa(i))
+		}
+		assert.Equal(t, http.StatusTooManyRequests, code)
+
+		// 换一个伪造的来源 IP 仍然受限
+		assert.Equal(t, http.StatusTooManyRequests, login(r, 9, "198.51.100.99"))
+	})
+
+	t.Run("采信可信代理转发的客户端 IP", func(t *testing.T) {
+		r, err := newEngine(&ServerConfig{TrustedProxies: []string{"203.0.113.0/24"}})
+		require.NoError(t, err)
+		loginRoute(r, service.NewLoginLimiter(repo.NewMemoryLoginAttemptStore(), policy))
+
+		for i := 0; i < 3; i++ {
+			assert.Equal(t, http.StatusUnauthorized, login(r, i, "198.51.100."+strconv.Itoa(i)))
+		}
+	})
+
+	t.Run("无效的代理配置", func(t *testing.T) {
+		_, err := newEngine(&ServerConfig{TrustedProxies: []string{"not-an-ip"}})
+		assert.Error(t, err)
+	})
+}
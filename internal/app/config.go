@@ -3,6 +3,10 @@ package app
 import (
 	"fmt"
 	"os"
+	"power-supply-sys/internal/domain/lockout"
+	"power-supply-sys/internal/domain/power"
+	"power-supply-sys/internal/domain/user"
+	"power-supply-sys/pkg/auth"
 	"time"
 
 	"github.com/spf13/viper"
@@ -10,12 +14,21 @@ import (
 
 // Config 应用配置结构
 type Config struct {
-	Debug  bool   `mapstructure:"debug"`
-	Addr   string `mapstructure:"addr"`
-	DB     DBConfig
-	JWT    JWTConfig
-	Log    LogConfig
-	Server ServerConfig
+	Debug             bool   `mapstructure:"debug"`
+	Addr              string `mapstructure:"addr"`
+	DB                DBConfig
+	JWT               JWTConfig
+	Mail              MailConfig
+	Password          PasswordConfig
+	EmailVerification EmailVerificationConfig `mapstructure:"email_verification"`
+	Reservation       ReservationConfig
+	Pricing           PricingConfig
+	Recommend         RecommendConfig
+	Lockout           LockoutConfig
+	TwoFactor         TwoFactorConfig `mapstructure:"two_factor"`
+	OIDC              OIDCConfig      `mapstructure:"oidc"`
+	Log               LogConfig
+	Server            ServerConfig
 }
 
 // DBConfig 数据库配置
@@ -28,8 +41,126 @@ type DBConfig struct {
 
 // JWTConfig JWT配置
 type JWTConfig struct {
-	Secret      string `mapstructure:"secret"`
-	ExpireHours int    `mapstructure:"expire_hours"`
+	Secret              string         `mapstructure:"secret"`
+	Algorithm           string         `mapstructure:"algorithm"`             // 签名算法：HS256（默认）、RS256 或 EdDSA
+	KeyID               string         `mapstructure:"key_id"`                // 当前密钥的 kid，非对称密钥为空时使用公钥指纹
+	PrivateKeyFile      string         `mapstructure:"private_key_file"`      // RS256/EdDSA 私钥 PEM 文件
+	PreviousKeys        []JWTKeyConfig `mapstructure:"previous_keys"`         // 轮换后仍需验签的旧密钥
+	GracePeriodMinutes  int            `mapstructure:"grace_period_minutes"`  // 旧密钥退役后的验签宽限期（分钟）
+	AccessExpireMinutes int            `mapstructure:"access_expire_minutes"` // access token 有效期（分钟）
+	RefreshExpireHours  int            `mapstructure:"refresh_expire_hours"`  // refresh token 有效期（小时）
+	SlidingSession      bool           `mapstructure:"sliding_session"`       // 是否启用滑动会话（临近过期时自动续期 access token）
+	SlidingRenewAfter   float64        `mapstructure:"sliding_renew_after"`   // 有效期已过该比例时续期（0-1）
+}
+
+// JWTKeyConfig 轮换后保留的验签密钥
+type JWTKeyConfig struct {
+	KeyID         string `mapstructure:"key_id"`
+	PublicKeyFile string `mapstructure:"public_key_file"` // 公钥 PEM 文件
+	RetiredAt     string `mapstructure:"retired_at"`      // 退役时间（RFC 3339），为空表示长期有效
+}
+
+// MailConfig 邮件配置
+type MailConfig struct {
+	Driver    string `mapstructure:"driver"` // smtp 或 outbox
+	Host      string `mapstructure:"host"`
+	Port      int    `mapstructure:"port"`
+	Username  string `mapstructure:"username"`
+	Password  string `mapstructure:"password"`
+	From      string `mapstructure:"from"`
+	OutboxDir string `mapstructure:"outbox_dir"` // outbox 驱动写入邮件的目录
+}
+
+// PasswordConfig 密码相关配置
+type PasswordConfig struct {
+	ResetExpireMinutes int                  `mapstructure:"reset_expire_minutes"` // 重置令牌有效期（分钟）
+	ResetURL           string               `mapstructure:"reset_url"`            // 前端重置密码页面地址
+	Hash               PasswordHashConfig   `mapstructure:"hash"`
+	Policy             PasswordPolicyConfig `mapstructure:"policy"`
+}
+
+// PasswordHashConfig 密码摘要配置
+type PasswordHashConfig struct {
+	Algorithm         string `mapstructure:"algorithm"`          // 新摘要使用的算法：argon2id（默认）或 bcrypt
+	Argon2MemoryKiB   uint32 `mapstructure:"argon2_memory_kib"`  // argon2id 内存开销（KiB）
+	Argon2Iterations  uint32 `mapstructure:"argon2_iterations"`  // argon2id 迭代次数
+	Argon2Parallelism uint8  `mapstructure:"argon2_parallelism"` // argon2id 并行度
+	BcryptCost        int    `mapstructure:"bcrypt_cost"`        // bcrypt 代价因子
+}
+
+// PasswordPolicyConfig 密码策略配置
+type PasswordPolicyConfig struct {
+	MinLength        int    `mapstructure:"min_length"`
+	MaxLength        int    `mapstructure:"max_length"`
+	RequireUpper     bool   `mapstructure:"require_upper"`
+	RequireLower     bool   `mapstructure:"require_lower"`
+	RequireDigit     bool   `mapstructure:"require_digit"`
+	RequireSymbol    bool   `mapstructure:"require_symbol"`
+	BreachedListFile string `mapstructure:"breached_list_file"` // 泄露密码列表文件，每行一个密码
+}
+
+// EmailVerificationConfig 邮箱验证配置
+type EmailVerificationConfig struct {
+	Secret      string `mapstructure:"secret"`       // 验证令牌签名密钥，为空时使用 jwt.secret
+	ExpireHours int    `mapstructure:"expire_hours"` // 验证链接有效期（小时）
+	VerifyURL   string `mapstructure:"verify_url"`   // 前端验证邮箱页面地址
+}
+
+// ReservationConfig 库存预留配置
+type ReservationConfig struct {
+	TTLMinutes             int `mapstructure:"ttl_minutes"`              // 预留有效期（分钟）
+	ReleaseIntervalSeconds int `mapstructure:"release_interval_seconds"` // 后台释放过期预留的间隔（秒）
+}
+
+// PricingConfig 价格配置
+type PricingConfig struct {
+	ApplyIntervalSeconds int `mapstructure:"apply_interval_seconds"` // 后台应用计划调价的间隔（秒）
+}
+
+// RecommendConfig 电源推荐配置，未配置的项使用默认值
+type RecommendConfig struct {
+	Headroom           float64 `mapstructure:"headroom"`             // 持续功耗之上预留的余量比例
+	CPUTransientFactor float64 `mapstructure:"cpu_transient_factor"` // CPU 瞬时峰值倍数
+	GPUTransientFactor float64 `mapstructure:"gpu_transient_factor"` // 显卡瞬时峰值倍数
+	OverclockFactor    float64 `mapstructure:"overclock_factor"`     // 超频功耗放大倍数
+	PlatformWatts      int     `mapstructure:"platform_watts"`       // 平台基础功耗(W)
+	DriveWatts         int     `mapstructure:"drive_watts"`          // 每块硬盘功耗(W)
+	FanWatts           int     `mapstructure:"fan_watts"`            // 每个风扇功耗(W)
+	MaxOversize        float64 `mapstructure:"max_oversize"`         // 候选电源额定功率上限为推荐功率的倍数
+	Limit              int     `mapstructure:"limit"`                // 默认返回的推荐数量
+}
+
+// LockoutConfig 登录保护配置
+type LockoutConfig struct {
+	Store         string `mapstructure:"store"`           // 计数存储：memory 或 db，多实例部署应使用 db
+	MaxFailures   int    `mapstructure:"max_failures"`    // 同一用户名允许的失败次数
+	IPMaxFailures int    `mapstructure:"ip_max_failures"` // 同一 IP 允许的失败次数
+	WindowMinutes int    `mapstructure:"window_minutes"`  // 统计窗口（分钟）
+	LockMinutes   int    `mapstructure:"lock_minutes"`    // 锁定时长（分钟）
+}
+
+// TwoFactorConfig 两步验证配置
+type TwoFactorConfig struct {
+	Issuer                 string `mapstructure:"issuer"`                   // 验证器应用中显示的签发方名称
+	ChallengeExpireMinutes int    `mapstructure:"challenge_expire_minutes"` // 两步登录挑战有效期（分钟）
+}
+
+// OIDCConfig 单点登录配置
+type OIDCConfig struct {
+	StateExpireMinutes int                  `mapstructure:"state_expire_minutes"` // 发起登录后完成回调的时限（分钟）
+	Providers          []OIDCProviderConfig `mapstructure:"providers"`
+}
+
+// OIDCProviderConfig 身份提供方配置
+type OIDCProviderConfig struct {
+	Name         string   `mapstructure:"name"`          // 提供方名称，发起登录时通过 provider 参数选择
+	Issuer       string   `mapstructure:"issuer"`        // 签发方地址
+	ClientID     string   `mapstructure:"client_id"`     // 客户端 ID
+	ClientSecret string   `mapstructure:"client_secret"` // 客户端密钥
+	RedirectURL  string   `mapstructure:"redirect_url"`  // 回调地址，需与提供方登记的一致
+	Scopes       []string `mapstructure:"scopes"`        // 请求的 scope，默认 openid profile email
+	AutoCreate   bool     `mapstructure:"auto_create"`   // 首次登录时自动创建本地用户
+	LinkByEmail  bool     `mapstructure:"link_by_email"` // 首次登录时按已验证邮箱绑定已有用户
 }
 
 // LogConfig 日志配置
@@ -43,6 +174,9 @@ type ServerConfig struct {
 	ReadTimeout  int `mapstructure:"read_timeout"`  // 秒
 	WriteTimeout int `mapstructure:"write_timeout"` // 秒
 	IdleTimeout  int `mapstructure:"idle_timeout"`  // 秒
+	// TrustedProxies 可信反向代理的 IP 或 CIDR，只有来自这些地址的请求才采信 X-Forwarded-For
+	// 默认为空，即直接使用连接的对端地址作为客户端 IP
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
 }
 
 // LoadConfig 加载配置文件
@@ -76,6 +210,184 @@ func (c *DBConfig) GetConnMaxLifetime() time.Duration {
 	return time.Duration(c.ConnMaxLifetime) * time.Second
 }
 
+// GetAccessExpire 获取 access token 有效期，默认 15 分钟
+func (j *JWTConfig) GetAccessExpire() time.Duration {
+	if j.AccessExpireMinutes <= 0 {
+		return 15 * time.Minute
+	}
+	return time.Duration(j.AccessExpireMinutes) * time.Minute
+}
+
+// GetRefreshExpire 获取 refresh token 有效期，默认 7 天
+func (j *JWTConfig) GetRefreshExpire() time.Duration {
+	if j.RefreshExpireHours <= 0 {
+		return 7 * 24 * time.Hour
+	}
+	return time.Duration(j.RefreshExpireHours) * time.Hour
+}
+
+// GetGracePeriod 获取旧密钥退役后的验签宽限期，默认与 access token 有效期相同
+func (j *JWTConfig) GetGracePeriod() time.Duration {
+	if j.GracePeriodMinutes <= 0 {
+		return j.GetAccessExpire()
+	}
+	return time.Duration(j.GracePeriodMinutes) * time.Minute
+}
+
+// GetSlidingRenewAfter 获取滑动会话的续期阈值，未配置或超出范围时默认 0.5
+func (j *JWTConfig) GetSlidingRenewAfter() float64 {
+	if j.SlidingRenewAfter <= 0 || j.SlidingRenewAfter >= 1 {
+		return 0.5
+	}
+	return j.SlidingRenewAfter
+}
+
+// GetResetExpire 获取密码重置令牌有效期，默认 30 分钟
+func (p *PasswordConfig) GetResetExpire() time.Duration {
+	if p.ResetExpireMinutes <= 0 {
+		return 30 * time.Minute
+	}
+	return time.Duration(p.ResetExpireMinutes) * time.Minute
+}
+
+// GetExpire 获取邮箱验证链接有效期，默认 24 小时
+func (e *EmailVerificationConfig) GetExpire() time.Duration {
+	if e.ExpireHours <= 0 {
+		return 24 * time.Hour
+	}
+	return time.Duration(e.ExpireHours) * time.Hour
+}
+
+// GetTTL 获取库存预留有效期，默认 15 分钟
+func (r *ReservationConfig) GetTTL() time.Duration {
+	if r.TTLMinutes <= 0 {
+		return 15 * time.Minute
+	}
+	return time.Duration(r.TTLMinutes) * time.Minute
+}
+
+// GetReleaseInterval 获取后台释放过期预留的间隔，默认 1 分钟
+func (r *ReservationConfig) GetReleaseInterval() time.Duration {
+	if r.ReleaseIntervalSeconds <= 0 {
+		return time.Minute
+	}
+	return time.Duration(r.ReleaseIntervalSeconds) * time.Second
+}
+
+// GetApplyInterval 获取后台应用计划调价的间隔，默认 1 分钟
+func (p *PricingConfig) GetApplyInterval() time.Duration {
+	if p.ApplyIntervalSeconds <= 0 {
+		return time.Minute
+	}
+	return time.Duration(p.ApplyIntervalSeconds) * time.Second
+}
+
+// GetPolicy 获取电源推荐策略，未配置的项使用默认值
+func (r *RecommendConfig) GetPolicy() power.RecommendPolicy {
+	policy := power.DefaultRecommendPolicy()
+	if r.Headroom > 0 {
+		policy.Headroom = r.Headroom
+	}
+	if r.CPUTransientFactor > 0 {
+		policy.CPUTransientFactor = r.CPUTransientFactor
+	}
+	if r.GPUTransientFactor > 0 {
+		policy.GPUTransientFactor = r.GPUTransientFactor
+	}
+	if r.OverclockFactor > 0 {
+		policy.OverclockFactor = r.OverclockFactor
+	}
+	if r.PlatformWatts > 0 {
+		policy.PlatformWatts = r.PlatformWatts
+	}
+	if r.DriveWatts > 0 {
+		policy.DriveWatts = r.DriveWatts
+	}
+	if r.FanWatts > 0 {
+		policy.FanWatts = r.FanWatts
+	}
+	if r.MaxOversize > 0 {
+		policy.MaxOversize = r.MaxOversize
+	}
+	if r.Limit > 0 {
+		policy.Limit = r.Limit
+	}
+	return policy
+}
+
+// GetArgon2idParams 获取 argon2id 参数，未配置的项使用默认值
+func (h *PasswordHashConfig) GetArgon2idParams() auth.Argon2idParams {
+	params := auth.DefaultArgon2idParams()
+	if h.Argon2MemoryKiB > 0 {
+		params.Memory = h.Argon2MemoryKiB
+	}
+	if h.Argon2Iterations > 0 {
+		params.Iterations = h.Argon2Iterations
+	}
+	if h.Argon2Parallelism > 0 {
+		params.Parallelism = h.Argon2Parallelism
+	}
+	return params
+}
+
+// GetPolicy 获取密码策略（不含泄露密码列表），未配置的长度使用默认值
+func (p *PasswordPolicyConfig) GetPolicy() user.PasswordPolicy {
+	policy := user.DefaultPasswordPolicy()
+	if p.MinLength > 0 {
+		policy.MinLength = p.MinLength
+	}
+	if p.MaxLength > 0 {
+		policy.MaxLength = p.MaxLength
+	}
+	policy.RequireUpper = p.RequireUpper
+	policy.RequireLower = p.RequireLower
+	policy.RequireDigit = p.RequireDigit
+	policy.RequireSymbol = p.RequireSymbol
+	return policy
+}
+
+// GetPolicy 获取登录保护策略，未配置的项使用默认值
+func (l *LockoutConfig) GetPolicy() lockout.Policy {
+	policy := lockout.DefaultPolicy()
+	if l.MaxFailures > 0 {
+		policy.MaxFailures = l.MaxFailures
+	}
+	if l.IPMaxFailures > 0 {
+		policy.IPMaxFailures = l.IPMaxFailures
+	}
+	if l.WindowMinutes > 0 {
+		policy.Window = time.Duration(l.WindowMinutes) * time.Minute
+	}
+	if l.LockMinutes > 0 {
+		policy.LockDuration = time.Duration(l.LockMinutes) * time.Minute
+	}
+	return policy
+}
+
+// GetIssuer 获取 TOTP 签发方名称，默认 Power Supply System
+func (t *TwoFactorConfig) GetIssuer() string {
+	if t.Issuer == "" {
+		return "Power Supply System"
+	}
+	return t.Issuer
+}
+
+// GetChallengeExpire 获取两步登录挑战有效期，默认 5 分钟
+func (t *TwoFactorConfig) GetChallengeExpire() time.Duration {
+	if t.ChallengeExpireMinutes <= 0 {
+		return 5 * time.Minute
+	}
+	return time.Duration(t.ChallengeExpireMinutes) * time.Minute
+}
+
+// GetStateExpire 获取单点登录请求有效期，默认 10 分钟
+func (o *OIDCConfig) GetStateExpire() time.Duration {
+	if o.StateExpireMinutes <= 0 {
+		return 10 * time.Minute
+	}
+	return time.Duration(o.StateExpireMinutes) * time.Minute
+}
+
 // GetReadTimeout 获取读超时时间，默认 15 秒
 func (s *ServerConfig) GetReadTimeout() time.Duration {
 	if s.ReadTimeout <= 0 {
@@ -1,52 +1,369 @@
 package app
 
 import (
+	"fmt"
+	"os"
+	"power-supply-sys/internal/domain/apikey"
+	"power-supply-sys/internal/domain/audit"
+	"power-supply-sys/internal/domain/catalog"
+	"power-supply-sys/internal/domain/component"
+	"power-supply-sys/internal/domain/identity"
+	"power-supply-sys/internal/domain/inventory"
+	"power-supply-sys/internal/domain/lockout"
+	"power-supply-sys/internal/domain/mfa"
+	"power-supply-sys/internal/domain/order"
 	"power-supply-sys/internal/domain/power"
+	"power-supply-sys/internal/domain/purchase"
+	"power-supply-sys/internal/domain/rbac"
+	"power-supply-sys/internal/domain/supplier"
+	"power-supply-sys/internal/domain/token"
 	"power-supply-sys/internal/domain/user"
+	"power-supply-sys/internal/domain/warehouse"
 	"power-supply-sys/internal/infra/repo"
 	"power-supply-sys/internal/service"
 	"power-supply-sys/pkg/auth"
+	"power-supply-sys/pkg/common"
+	"power-supply-sys/pkg/logger"
+	"power-supply-sys/pkg/mail"
+	"power-supply-sys/pkg/oidc"
+	"strings"
+	"time"
 
+	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
 // Container 依赖容器
 type Container struct {
 	// 数据库
-	DB *gorm.DB
+	DB         *gorm.DB
+	Transactor common.Transactor
 
 	// Repositories
-	UserRepo  user.Repository
-	PowerRepo power.Repository
+	UserRepo            user.Repository
+	PowerRepo           power.Repository
+	RefreshTokenRepo    token.Repository
+	PasswordResetRepo   token.PasswordResetRepository
+	RBACRepo            rbac.Repository
+	LoginAttemptStore   lockout.Store
+	MFARepo             mfa.Repository
+	APIKeyRepo          apikey.Repository
+	IdentityRepo        identity.Repository
+	AuditRepo           audit.Repository
+	StockMovementRepo   inventory.Repository
+	ReservationRepo     inventory.ReservationRepository
+	OrderRepo           order.Repository
+	WarehouseRepo       warehouse.Repository
+	StockLevelRepo      warehouse.StockLevelRepository
+	SupplierRepo        supplier.Repository
+	SupplierProductRepo supplier.ProductRepository
+	PurchaseOrderRepo   purchase.Repository
+	PriceHistoryRepo    power.PriceHistoryRepository
+	PriceScheduleRepo   power.PriceScheduleRepository
+	ComponentRepo       component.Repository
+	CategoryRepo        catalog.CategoryRepository
+	TagRepo             catalog.TagRepository
 
 	// Services
-	UserService  service.UserService
-	PowerService service.PowerService
+	UserService              service.UserService
+	PowerService             service.PowerService
+	PricingService           service.PricingService
+	CatalogService           service.CatalogService
+	ComponentService         service.ComponentService
+	RecommendService         service.RecommendService
+	InventoryService         service.InventoryService
+	ReservationService       service.ReservationService
+	OrderService             service.OrderService
+	WarehouseService         service.WarehouseService
+	SupplierService          service.SupplierService
+	PurchaseOrderService     service.PurchaseOrderService
+	AuthService              service.AuthService
+	RBACService              service.RBACService
+	PasswordService          service.PasswordService
+	TwoFactorService         service.TwoFactorService
+	APIKeyService            service.APIKeyService
+	OIDCService              service.OIDCService
+	EmailVerificationService service.EmailVerificationService
+	ImpersonationService     service.ImpersonationService
 
 	// Auth
 	JWTManager *auth.JWTManager
+
+	// Mail
+	Mailer mail.Mailer
+	// AsyncMailer 后台发送邮件，关闭时等待发送完成
+	AsyncMailer *mail.AsyncMailer
 }
 
 // NewContainer 创建依赖容器
-func NewContainer(cfg *Config, database *gorm.DB) *Container {
+func NewContainer(cfg *Config, database *gorm.DB) (*Container, error) {
+	transactor := common.NewTransactor(database)
+
 	// 创建 Repositories
 	userRepo := repo.NewUserRepository(database)
 	powerRepo := repo.NewPowerRepository(database)
-
-	// 创建 Services
-	userService := service.NewUserService(userRepo)
-	powerService := service.NewPowerService(powerRepo)
+	refreshTokenRepo := repo.NewRefreshTokenRepository(database)
+	passwordResetRepo := repo.NewPasswordResetRepository(database)
+	rbacRepo := repo.NewRBACRepository(database)
+	loginAttemptStore := newLoginAttemptStore(&cfg.Lockout, database)
+	mfaRepo := repo.NewMFARepository(database)
+	apiKeyRepo := repo.NewAPIKeyRepository(database)
+	identityRepo := repo.NewIdentityRepository(database)
+	auditRepo := repo.NewAuditRepository(database)
+	stockMovementRepo := repo.NewStockMovementRepository(database)
+	reservationRepo := repo.NewReservationRepository(database)
+	orderRepo := repo.NewOrderRepository(database)
+	warehouseRepo := repo.NewWarehouseRepository(database)
+	stockLevelRepo := repo.NewStockLevelRepository(database)
+	supplierRepo := repo.NewSupplierRepository(database)
+	supplierProductRepo := repo.NewSupplierProductRepository(database)
+	purchaseOrderRepo := repo.NewPurchaseOrderRepository(database)
+	priceHistoryRepo := repo.NewPriceHistoryRepository(database)
+	priceScheduleRepo := repo.NewPriceScheduleRepository(database)
+	componentRepo := repo.NewComponentRepository(database)
+	categoryRepo := repo.NewCategoryRepository(database)
+	tagRepo := repo.NewTagRepository(database)
 
 	// 创建 JWT Manager
-	jwtManager := auth.NewJWTManager(cfg.JWT.Secret, cfg.JWT.ExpireHours)
+	jwtManager, err := newJWTManager(&cfg.JWT)
+	if err != nil {
+		return nil, fmt.Errorf("加载 JWT 签名密钥失败: %w", err)
+	}
+
+	// 创建单点登录提供方
+	oidcProviders, err := newOIDCProviders(&cfg.OIDC)
+	if err != nil {
+		return nil, fmt.Errorf("加载单点登录配置失败: %w", err)
+	}
+
+	// 创建密码摘要器与密码策略
+	passwordHasher, err := newPasswordHasher(&cfg.Password.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("加载密码摘要配置失败: %w", err)
+	}
+	passwordPolicy, err := newPasswordPolicy(&cfg.Password.Policy)
+	if err != nil {
+		return nil, fmt.Errorf("加载密码策略失败: %w", err)
+	}
+
+	// 创建邮箱验证令牌签发器
+	emailTokenSigner, err := newEmailTokenSigner(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// 创建邮件发送器
+	mailer := newMailer(&cfg.Mail)
+	// 找回密码的邮件在后台发送，响应结果与耗时不反映邮箱是否已注册
+	asyncMailer := mail.NewAsyncMailer(mailer, func(msg *mail.Message, err error) {
+		logger.Error("Failed to send mail", zap.String("subject", msg.Subject), zap.Error(err))
+	})
+
+	// 创建 Services
+	rbacService := service.NewRBACService(rbacRepo, userRepo)
+	loginLimiter := service.NewLoginLimiter(loginAttemptStore, cfg.Lockout.GetPolicy())
+	userService := service.NewUserService(userRepo, refreshTokenRepo, rbacService, loginLimiter, transactor, passwordHasher, passwordPolicy)
+	inventoryService := service.NewInventoryService(stockMovementRepo, stockLevelRepo, warehouseRepo, powerRepo, transactor)
+	pricingService := service.NewPricingService(powerRepo, priceHistoryRepo, priceScheduleRepo, transactor)
+	catalogService := service.NewCatalogService(categoryRepo, tagRepo, powerRepo, transactor)
+	powerService := service.NewPowerService(powerRepo, inventoryService, pricingService, catalogService, transactor)
+	componentService := service.NewComponentService(componentRepo, transactor)
+	recommendService := service.NewRecommendService(powerRepo, componentRepo, cfg.Recommend.GetPolicy())
+	reservationService := service.NewReservationService(reservationRepo, powerRepo, inventoryService, transactor, cfg.Reservation.GetTTL())
+	warehouseService := service.NewWarehouseService(warehouseRepo, transactor)
+	supplierService := service.NewSupplierService(supplierRepo, supplierProductRepo, powerRepo, purchaseOrderRepo, transactor)
+	purchaseOrderService := service.NewPurchaseOrderService(purchaseOrderRepo, supplierRepo, supplierProductRepo, warehouseRepo, inventoryService, transactor)
+	orderService := service.NewOrderService(orderRepo, powerRepo, reservationService, inventoryService, rbacService, transactor)
+	authService := service.NewAuthService(refreshTokenRepo, userRepo, jwtManager, transactor, cfg.JWT.GetRefreshExpire())
+	passwordService := service.NewPasswordService(userRepo, passwordResetRepo, refreshTokenRepo, asyncMailer, transactor, cfg.Password.GetResetExpire(), cfg.Password.ResetURL, passwordHasher, passwordPolicy)
+	twoFactorService := service.NewTwoFactorService(mfaRepo, userRepo, loginAttemptStore, cfg.Lockout.GetPolicy(), transactor, cfg.TwoFactor.GetIssuer(), cfg.TwoFactor.GetChallengeExpire())
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, userRepo, rbacService)
+	emailVerificationService := service.NewEmailVerificationService(userRepo, userService, emailTokenSigner, mailer, cfg.EmailVerification.VerifyURL)
+	oidcService := service.NewOIDCService(identityRepo, userRepo, transactor, passwordHasher, oidcProviders, cfg.OIDC.GetStateExpire())
+	impersonationService := service.NewImpersonationService(auditRepo, userRepo, rbacService, jwtManager)
 
 	return &Container{
-		DB:           database,
-		UserRepo:     userRepo,
-		PowerRepo:    powerRepo,
-		UserService:  userService,
-		PowerService: powerService,
-		JWTManager:   jwtManager,
+		DB:                       database,
+		Transactor:               transactor,
+		UserRepo:                 userRepo,
+		PowerRepo:                powerRepo,
+		RefreshTokenRepo:         refreshTokenRepo,
+		PasswordResetRepo:        passwordResetRepo,
+		RBACRepo:                 rbacRepo,
+		LoginAttemptStore:        loginAttemptStore,
+		MFARepo:                  mfaRepo,
+		APIKeyRepo:               apiKeyRepo,
+		IdentityRepo:             identityRepo,
+		AuditRepo:                auditRepo,
+		StockMovementRepo:        stockMovementRepo,
+		ReservationRepo:          reservationRepo,
+		OrderRepo:                orderRepo,
+		WarehouseRepo:            warehouseRepo,
+		StockLevelRepo:           stockLevelRepo,
+		SupplierRepo:             supplierRepo,
+		SupplierProductRepo:      supplierProductRepo,
+		PurchaseOrderRepo:        purchaseOrderRepo,
+		PriceHistoryRepo:         priceHistoryRepo,
+		PriceScheduleRepo:        priceScheduleRepo,
+		ComponentRepo:            componentRepo,
+		CategoryRepo:             categoryRepo,
+		TagRepo:                  tagRepo,
+		UserService:              userService,
+		PowerService:             powerService,
+		PricingService:           pricingService,
+		CatalogService:           catalogService,
+		ComponentService:         componentService,
+		RecommendService:         recommendService,
+		InventoryService:         inventoryService,
+		ReservationService:       reservationService,
+		OrderService:             orderService,
+		WarehouseService:         warehouseService,
+		SupplierService:          supplierService,
+		PurchaseOrderService:     purchaseOrderService,
+		AuthService:              authService,
+		RBACService:              rbacService,
+		PasswordService:          passwordService,
+		TwoFactorService:         twoFactorService,
+		APIKeyService:            apiKeyService,
+		OIDCService:              oidcService,
+		EmailVerificationService: emailVerificationService,
+		ImpersonationService:     impersonationService,
+		JWTManager:               jwtManager,
+		Mailer:                   mailer,
+		AsyncMailer:              asyncMailer,
+	}, nil
+}
+
+// newJWTManager 根据配置创建 JWT 管理器
+// 非对称算法从 PEM 文件加载私钥，previous_keys 中的旧公钥在退役后的宽限期内仍可验签
+func newJWTManager(cfg *JWTConfig) (*auth.JWTManager, error) {
+	var current *auth.SigningKey
+	switch algorithm := strings.ToUpper(cfg.Algorithm); algorithm {
+	case "", "HS256":
+		current = auth.NewHMACKey(cfg.KeyID, cfg.Secret)
+	case "RS256", "EDDSA":
+		key, err := auth.LoadPrivateKeyFile(cfg.KeyID, cfg.PrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		if strings.ToUpper(key.Method.Alg()) != algorithm {
+			return nil, fmt.Errorf("私钥算法 %s 与配置的 %s 不一致", key.Method.Alg(), cfg.Algorithm)
+		}
+		current = key
+	default:
+		return nil, fmt.Errorf("不支持的签名算法: %s", cfg.Algorithm)
 	}
+
+	previous := make([]*auth.SigningKey, 0, len(cfg.PreviousKeys))
+	for _, kc := range cfg.PreviousKeys {
+		key, err := auth.LoadPublicKeyFile(kc.KeyID, kc.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		if kc.RetiredAt != "" {
+			retiredAt, err := time.Parse(time.RFC3339, kc.RetiredAt)
+			if err != nil {
+				return nil, fmt.Errorf("密钥 %s 的退役时间格式错误: %w", key.ID, err)
+			}
+			key.ExpiresAt = retiredAt.Add(cfg.GetGracePeriod())
+		}
+		previous = append(previous, key)
+	}
+
+	return auth.NewJWTManagerWithKeys(current, cfg.GetAccessExpire(), previous...), nil
 }
 
+// newOIDCProviders 根据配置创建单点登录提供方（发现配置在首次登录时获取）
+func newOIDCProviders(cfg *OIDCConfig) ([]*service.OIDCProvider, error) {
+	providers := make([]*service.OIDCProvider, 0, len(cfg.Providers))
+	names := make(map[string]bool, len(cfg.Providers))
+	for _, pc := range cfg.Providers {
+		if pc.Name == "" || pc.Issuer == "" || pc.ClientID == "" || pc.RedirectURL == "" {
+			return nil, fmt.Errorf("提供方 %q 缺少 name、issuer、client_id 或 redirect_url", pc.Name)
+		}
+		if names[pc.Name] {
+			return nil, fmt.Errorf("提供方名称重复: %s", pc.Name)
+		}
+		names[pc.Name] = true
+
+		providers = append(providers, &service.OIDCProvider{
+			Client: oidc.NewProvider(oidc.Config{
+				Name:         pc.Name,
+				Issuer:       pc.Issuer,
+				ClientID:     pc.ClientID,
+				ClientSecret: pc.ClientSecret,
+				RedirectURL:  pc.RedirectURL,
+				Scopes:       pc.Scopes,
+			}, nil),
+			AutoCreate:  pc.AutoCreate,
+			LinkByEmail: pc.LinkByEmail,
+		})
+	}
+	return providers, nil
+}
+
+// newPasswordHasher 根据配置创建密码摘要器，默认使用 argon2id
+func newPasswordHasher(cfg *PasswordHashConfig) (auth.PasswordHasher, error) {
+	switch cfg.Algorithm {
+	case "", auth.HashArgon2id:
+		return auth.NewArgon2idHasher(cfg.GetArgon2idParams()), nil
+	case auth.HashBcrypt:
+		return auth.NewBcryptHasher(cfg.BcryptCost), nil
+	default:
+		return nil, fmt.Errorf("不支持的密码摘要算法: %s", cfg.Algorithm)
+	}
+}
+
+// newPasswordPolicy 根据配置创建密码策略并加载泄露密码列表
+// 列表文件每行一个密码，忽略空行与 # 开头的注释，比较时不区分大小写
+func newPasswordPolicy(cfg *PasswordPolicyConfig) (user.PasswordPolicy, error) {
+	policy := cfg.GetPolicy()
+	if policy.MinLength > policy.MaxLength {
+		return policy, fmt.Errorf("min_length 不能大于 max_length")
+	}
+	if cfg.BreachedListFile == "" {
+		return policy, nil
+	}
+
+	data, err := os.ReadFile(cfg.BreachedListFile)
+	if err != nil {
+		return policy, err
+	}
+	policy.Breached = make(map[string]struct{})
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		policy.Breached[strings.ToLower(line)] = struct{}{}
+	}
+	return policy, nil
+}
+
+// newEmailTokenSigner 创建邮箱验证令牌签发器，未单独配置密钥时使用 jwt.secret
+func newEmailTokenSigner(cfg *Config) (*auth.EmailTokenSigner, error) {
+	secret := cfg.EmailVerification.Secret
+	if secret == "" {
+		secret = cfg.JWT.Secret
+	}
+	if secret == "" {
+		return nil, fmt.Errorf("未配置邮箱验证令牌签名密钥 email_verification.secret")
+	}
+	return auth.NewEmailTokenSigner(secret, cfg.EmailVerification.GetExpire()), nil
+}
+
+// newMailer 根据配置创建邮件发送器，未配置 smtp 时使用发件箱
+func newMailer(cfg *MailConfig) mail.Mailer {
+	if cfg.Driver == "smtp" {
+		return mail.NewSMTPMailer(cfg.Host, cfg.Port, cfg.Username, cfg.Password, cfg.From)
+	}
+	return mail.NewOutboxMailer(cfg.OutboxDir, cfg.From)
+}
+
+// newLoginAttemptStore 根据配置创建登录失败计数存储，默认使用内存存储
+func newLoginAttemptStore(cfg *LockoutConfig, database *gorm.DB) lockout.Store {
+	if cfg.Store == "db" {
+		return repo.NewLoginAttemptRepository(database)
+	}
+	return repo.NewMemoryLoginAttemptStore()
+}
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"context"
+	"power-supply-sys/pkg/logger"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// startWorkers 启动后台任务，Shutdown 时停止
+func (a *App) startWorkers() {
+	ctx, cancel := context.WithCancel(context.Background())
+	a.stopWorkers = cancel
+
+	a.workers.Add(1)
+	go func() {
+		defer a.workers.Done()
+		a.runReservationReleaser(ctx, a.config.Reservation.GetReleaseInterval())
+	}()
+
+	a.workers.Add(1)
+	go func() {
+		defer a.workers.Done()
+		a.runPriceScheduler(ctx, a.config.Pricing.GetApplyInterval())
+	}()
+}
+
+// runReservationReleaser 定期释放过期的库存预留，并取消预留过期的待支付订单
+func (a *App) runReservationReleaser(ctx context.Context, interval time.Duration) {
+	logger.Info("Reservation releaser started", zap.Duration("interval", interval))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("Reservation releaser stopped")
+			return
+		case <-ticker.C:
+			released, err := a.container.ReservationService.ReleaseExpired(ctx)
+			if err != nil {
+				logger.Error("Failed to release expired reservations", zap.Error(err))
+			}
+			if released > 0 {
+				logger.Info("Expired reservations released", zap.Int("count", released))
+			}
+
+			// 预留过期的待支付订单随之取消
+			cancelled, err := a.container.OrderService.CancelExpired(ctx)
+			if err != nil {
+				logger.Error("Failed to cancel expired orders", zap.Error(err))
+			}
+			if cancelled > 0 {
+				logger.Info("Expired orders cancelled", zap.Int("count", cancelled))
+			}
+		}
+	}
+}
+
+// runPriceScheduler 定期应用已到生效时间的计划调价
+func (a *App) runPriceScheduler(ctx context.Context, interval time.Duration) {
+	logger.Info("Price scheduler started", zap.Duration("interval", interval))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("Price scheduler stopped")
+			return
+		case <-ticker.C:
+			applied, err := a.container.PricingService.ApplyDue(ctx)
+			if err != nil {
+				logger.Error("Failed to apply scheduled price changes", zap.Error(err))
+			}
+			if applied > 0 {
+				logger.Info("Scheduled price changes applied", zap.Int("count", applied))
+			}
+		}
+	}
+}
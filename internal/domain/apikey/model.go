@@ -0,0 +1,56 @@
+package apikey
+
+import (
+	"power-supply-sys/internal/domain/rbac"
+	"slices"
+	"strings"
+	"time"
+)
+
+// KeyPrefix API Key 原文前缀，便于识别与密钥扫描
+const KeyPrefix = "psk_"
+
+// AllowedScopes API Key 可授予的权限范围（仅限电源目录）
+var AllowedScopes = []string{rbac.PermPowerRead, rbac.PermPowerWrite}
+
+// APIKey API Key 模型（只保存摘要，不保存原文）
+type APIKey struct {
+	ID         uint       `gorm:"primarykey" json:"id"`
+	UserID     uint       `gorm:"index;not null" json:"user_id"`
+	Name       string     `gorm:"size:100;not null" json:"name"`
+	Prefix     string     `gorm:"size:16;comment:原文前几位，用于识别" json:"prefix"`
+	KeyHash    string     `gorm:"uniqueIndex;size:64;not null" json:"-"`
+	Scopes     string     `gorm:"size:255;comment:权限范围，逗号分隔" json:"-"`
+	ExpiresAt  time.Time  `gorm:"comment:过期时间" json:"expires_at"`
+	LastUsedAt *time.Time `gorm:"comment:最近使用时间" json:"last_used_at"`
+	RevokedAt  *time.Time `gorm:"comment:吊销时间" json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// TableName 指定表名
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// ScopeList 返回权限范围列表
+func (k *APIKey) ScopeList() []string {
+	if k.Scopes == "" {
+		return []string{}
+	}
+	return strings.Split(k.Scopes, ",")
+}
+
+// IsExpired 是否已过期
+func (k *APIKey) IsExpired(now time.Time) bool {
+	return !now.Before(k.ExpiresAt)
+}
+
+// IsActive 是否可用（未吊销且未过期）
+func (k *APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && !k.IsExpired(now)
+}
+
+// IsAllowedScope 是否为可授予的权限范围
+func IsAllowedScope(scope string) bool {
+	return slices.Contains(AllowedScopes, scope)
+}
@@ -0,0 +1,27 @@
+package apikey
+
+import (
+	"context"
+	"time"
+)
+
+// Reader 读取操作接口（接口隔离原则）
+type Reader interface {
+	FindByHash(ctx context.Context, keyHash string) (*APIKey, error)
+	ListByUserID(ctx context.Context, userID uint) ([]*APIKey, error)
+}
+
+// Writer 写入操作接口（接口隔离原则）
+type Writer interface {
+	Create(ctx context.Context, k *APIKey) error
+	// Revoke 吊销用户自己的 API Key，返回 false 表示不存在或已吊销
+	Revoke(ctx context.Context, userID, id uint, revokedAt time.Time) (bool, error)
+	// TouchLastUsed 更新最近使用时间
+	TouchLastUsed(ctx context.Context, id uint, usedAt time.Time) error
+}
+
+// Repository API Key 仓储接口（组合 Reader 和 Writer）
+type Repository interface {
+	Reader
+	Writer
+}
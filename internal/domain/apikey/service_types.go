@@ -0,0 +1,25 @@
+package apikey
+
+// Service 层使用的请求与返回类型
+
+// CreateRequest Service 层创建 API Key 请求
+type CreateRequest struct {
+	Name          string
+	Scopes        []string
+	ExpiresInDays int
+}
+
+// CreatedKey 新建的 API Key（原文仅在创建时返回一次）
+type CreatedKey struct {
+	Key    *APIKey
+	RawKey string
+}
+
+// Principal API Key 认证通过后的身份信息
+type Principal struct {
+	KeyID    uint
+	UserID   uint
+	Username string
+	Role     string
+	Scopes   []string
+}
@@ -0,0 +1,31 @@
+package audit
+
+import (
+	"time"
+)
+
+// 审计动作
+const (
+	ActionImpersonate = "impersonate"       // 开始模拟登录
+	ActionWrite       = "impersonate.write" // 模拟登录期间的写操作
+)
+
+// Log 审计日志（只追加），同时记录实际操作者与生效用户
+type Log struct {
+	ID            uint      `gorm:"primarykey" json:"id"`
+	Action        string    `gorm:"size:50;index;not null" json:"action"`
+	ActorID       uint      `gorm:"index;not null;comment:实际操作者" json:"actor_id"`
+	ActorUsername string    `gorm:"size:50" json:"actor_username"`
+	UserID        uint      `gorm:"index;not null;comment:生效用户（被模拟的用户）" json:"user_id"`
+	Username      string    `gorm:"size:50" json:"username"`
+	Method        string    `gorm:"size:10" json:"method"`
+	Path          string    `gorm:"size:255" json:"path"`
+	StatusCode    int       `json:"status_code"`
+	IP            string    `gorm:"size:64" json:"ip"`
+	CreatedAt     time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName 指定表名
+func (Log) TableName() string {
+	return "audit_logs"
+}
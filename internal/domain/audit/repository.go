@@ -0,0 +1,22 @@
+package audit
+
+import (
+	"context"
+)
+
+// Reader 读取操作接口（接口隔离原则）
+type Reader interface {
+	// ListByActor 查询实际操作者的审计日志（最新的在前）
+	ListByActor(ctx context.Context, actorID uint) ([]*Log, error)
+}
+
+// Writer 写入操作接口（审计日志只追加，不提供修改与删除）
+type Writer interface {
+	Create(ctx context.Context, l *Log) error
+}
+
+// Repository 审计日志仓储接口（组合 Reader 和 Writer）
+type Repository interface {
+	Reader
+	Writer
+}
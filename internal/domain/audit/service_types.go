@@ -0,0 +1,23 @@
+package audit
+
+import (
+	"power-supply-sys/internal/domain/user"
+)
+
+// Service 层使用的请求与返回类型
+
+// ImpersonateRequest Service 层模拟登录请求
+type ImpersonateRequest struct {
+	ActorID   uint   // 发起模拟的管理员
+	SessionID string // 管理员当前的登录会话，模拟令牌随该会话一起失效
+	TargetID  uint   // 被模拟的用户
+	ClientIP  string
+}
+
+// Impersonation 模拟登录结果
+type Impersonation struct {
+	AccessToken string
+	ExpiresIn   int64 // access token 有效期（秒）
+	Actor       *user.User
+	User        *user.User
+}
@@ -0,0 +1,75 @@
+package catalog
+
+import (
+	"time"
+)
+
+// 标签匹配方式
+const (
+	TagMatchAny = "any" // 包含任一标签
+	TagMatchAll = "all" // 包含全部标签
+)
+
+// Category 电源分类（树形结构，ParentID 为空表示顶级分类）
+type Category struct {
+	ID        uint        `gorm:"primarykey" json:"id"`
+	ParentID  *uint       `gorm:"index" json:"parent_id"`
+	Name      string      `gorm:"size:50;not null" json:"name"`
+	SortOrder int         `gorm:"default:0;comment:同级排序，越小越靠前" json:"sort_order"`
+	Children  []*Category `gorm:"-" json:"children,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Category) TableName() string {
+	return "categories"
+}
+
+// Tag 电源标签（名称不区分大小写唯一）
+type Tag struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	Name      string    `gorm:"uniqueIndex;size:50;not null" json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Tag) TableName() string {
+	return "tags"
+}
+
+// BuildTree 将分类列表组装为分类树，返回顶级分类（保持列表中的顺序）
+func BuildTree(list []*Category) []*Category {
+	byID := make(map[uint]*Category, len(list))
+	for _, c := range list {
+		c.Children = nil
+		byID[c.ID] = c
+	}
+	roots := make([]*Category, 0)
+	for _, c := range list {
+		if c.ParentID != nil {
+			if parent, ok := byID[*c.ParentID]; ok {
+				parent.Children = append(parent.Children, c)
+				continue
+			}
+		}
+		roots = append(roots, c)
+	}
+	return roots
+}
+
+// Descendants 返回分类 id 及其全部子孙分类的 ID
+func Descendants(list []*Category, id uint) []uint {
+	children := make(map[uint][]uint, len(list))
+	for _, c := range list {
+		if c.ParentID != nil {
+			children[*c.ParentID] = append(children[*c.ParentID], c.ID)
+		}
+	}
+	ids := []uint{id}
+	for i := 0; i < len(ids); i++ {
+		ids = append(ids, children[ids[i]]...)
+	}
+	return ids
+}
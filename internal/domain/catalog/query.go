@@ -0,0 +1,8 @@
+package catalog
+
+// TagQueryOptions 标签查询选项（保留在领域层，属于领域概念）
+type TagQueryOptions struct {
+	Name     string
+	Page     int
+	PageSize int
+}
@@ -0,0 +1,31 @@
+package catalog
+
+import (
+	"context"
+)
+
+// CategoryRepository 分类仓储接口
+type CategoryRepository interface {
+	FindByID(ctx context.Context, id uint) (*Category, error)
+	// ListAll 查询全部分类（按排序值与 ID 排序）
+	ListAll(ctx context.Context) ([]*Category, error)
+	// ExistsByName 检查同一父分类下是否已有同名分类，parentID 为空表示顶级分类
+	ExistsByName(ctx context.Context, parentID *uint, name string) (bool, error)
+	HasChildren(ctx context.Context, id uint) (bool, error)
+	Create(ctx context.Context, c *Category) error
+	UpdateByID(ctx context.Context, id uint, updates map[string]any) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// TagRepository 标签仓储接口
+type TagRepository interface {
+	FindByID(ctx context.Context, id uint) (*Tag, error)
+	// FindByNames 按名称批量查询标签（不区分大小写）
+	FindByNames(ctx context.Context, names []string) ([]*Tag, error)
+	List(ctx context.Context, query *TagQueryOptions) ([]*Tag, error)
+	Count(ctx context.Context, query *TagQueryOptions) (int64, error)
+	Create(ctx context.Context, t *Tag) error
+	UpdateByID(ctx context.Context, id uint, updates map[string]any) error
+	// Delete 删除标签及其与电源的关联（需在事务中调用）
+	Delete(ctx context.Context, id uint) error
+}
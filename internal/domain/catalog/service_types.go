@@ -0,0 +1,24 @@
+package catalog
+
+// Service 层使用的请求类型（从 DTO 转换而来）
+
+// CategoryCreateRequest Service 层创建分类请求
+type CategoryCreateRequest struct {
+	ParentID  *uint
+	Name      string
+	SortOrder int
+}
+
+// CategoryUpdateRequest Service 层更新分类请求
+type CategoryUpdateRequest struct {
+	Name      string
+	ParentID  *uint // 不为空时移动到该分类下，为 0 时移动为顶级分类
+	SortOrder *int
+}
+
+// TagQueryRequest Service 层查询标签请求
+type TagQueryRequest struct {
+	Page     int
+	PageSize int
+	Name     string
+}
@@ -0,0 +1,62 @@
+package component
+
+import (
+	"time"
+)
+
+// 组件类型
+const (
+	TypeCPU = "cpu"
+	TypeGPU = "gpu"
+)
+
+// Component 组件功耗档案：CPU 与显卡的典型满载功耗，用于估算整机功耗并推荐电源
+type Component struct {
+	ID              uint      `gorm:"primarykey" json:"id"`
+	Type            string    `gorm:"size:10;uniqueIndex:idx_components_type_name;not null" json:"type"`
+	Name            string    `gorm:"size:100;uniqueIndex:idx_components_type_name;not null;comment:型号" json:"name"`
+	Brand           string    `gorm:"size:50" json:"brand"`
+	Watts           int       `gorm:"not null;comment:满载功耗(W)，CPU 为 TDP/PPT，显卡为 TGP/TBP" json:"watts"`
+	TransientFactor float64   `gorm:"type:decimal(4,2);default:0;comment:瞬时峰值倍数，0 表示使用默认值" json:"transient_factor"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Component) TableName() string {
+	return "components"
+}
+
+// IsValidType 判断组件类型是否有效
+func IsValidType(t string) bool {
+	switch t {
+	case TypeCPU, TypeGPU:
+		return true
+	default:
+		return false
+	}
+}
+
+// DefaultComponents 内置的常见 CPU 与显卡功耗档案（数据库为空时导入）
+func DefaultComponents() []*Component {
+	return []*Component{
+		{Type: TypeCPU, Brand: "Intel", Name: "Core i3-14100", Watts: 110},
+		{Type: TypeCPU, Brand: "Intel", Name: "Core i5-14400", Watts: 148},
+		{Type: TypeCPU, Brand: "Intel", Name: "Core i5-14600K", Watts: 181},
+		{Type: TypeCPU, Brand: "Intel", Name: "Core i7-14700K", Watts: 253},
+		{Type: TypeCPU, Brand: "Intel", Name: "Core i9-14900K", Watts: 253, TransientFactor: 1.3},
+		{Type: TypeCPU, Brand: "AMD", Name: "Ryzen 5 7600", Watts: 88},
+		{Type: TypeCPU, Brand: "AMD", Name: "Ryzen 7 7800X3D", Watts: 162},
+		{Type: TypeCPU, Brand: "AMD", Name: "Ryzen 9 7950X", Watts: 230},
+		{Type: TypeGPU, Brand: "NVIDIA", Name: "GeForce RTX 4060", Watts: 115},
+		{Type: TypeGPU, Brand: "NVIDIA", Name: "GeForce RTX 4070", Watts: 200},
+		{Type: TypeGPU, Brand: "NVIDIA", Name: "GeForce RTX 4070 Ti SUPER", Watts: 285},
+		{Type: TypeGPU, Brand: "NVIDIA", Name: "GeForce RTX 4080 SUPER", Watts: 320},
+		{Type: TypeGPU, Brand: "NVIDIA", Name: "GeForce RTX 4090", Watts: 450},
+		{Type: TypeGPU, Brand: "NVIDIA", Name: "GeForce RTX 3090", Watts: 350, TransientFactor: 2.0},
+		{Type: TypeGPU, Brand: "AMD", Name: "Radeon RX 7600", Watts: 165},
+		{Type: TypeGPU, Brand: "AMD", Name: "Radeon RX 7800 XT", Watts: 263},
+		{Type: TypeGPU, Brand: "AMD", Name: "Radeon RX 7900 XTX", Watts: 355},
+		{Type: TypeGPU, Brand: "AMD", Name: "Radeon RX 6900 XT", Watts: 300, TransientFactor: 1.8},
+	}
+}
@@ -0,0 +1,10 @@
+package component
+
+// QueryOptions 查询选项（保留在领域层，属于领域概念）
+type QueryOptions struct {
+	Type     string
+	Name     string // 按型号模糊匹配
+	Brand    string
+	Page     int
+	PageSize int
+}
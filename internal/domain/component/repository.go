@@ -0,0 +1,29 @@
+package component
+
+import (
+	"context"
+)
+
+// Reader 读取操作接口（接口隔离原则）
+type Reader interface {
+	FindByID(ctx context.Context, id uint) (*Component, error)
+	// FindByName 按类型与型号查询组件（型号不区分大小写）
+	FindByName(ctx context.Context, componentType, name string) (*Component, error)
+	List(ctx context.Context, query *QueryOptions) ([]*Component, error)
+	Count(ctx context.Context, query *QueryOptions) (int64, error)
+}
+
+// Writer 写入操作接口（接口隔离原则）
+type Writer interface {
+	Create(ctx context.Context, c *Component) error
+	UpdateByID(ctx context.Context, id uint, updates map[string]any) error
+	Delete(ctx context.Context, id uint) error
+	// Save 创建或更新组件（按类型与型号唯一），用于批量导入
+	Save(ctx context.Context, c *Component) error
+}
+
+// Repository 组件仓储接口（组合 Reader 和 Writer）
+type Repository interface {
+	Reader
+	Writer
+}
@@ -0,0 +1,29 @@
+package component
+
+// Service 层使用的请求类型（从 DTO 转换而来）
+
+// CreateRequest Service 层创建组件请求
+type CreateRequest struct {
+	Type            string
+	Name            string
+	Brand           string
+	Watts           int
+	TransientFactor float64
+}
+
+// UpdateRequest Service 层更新组件请求
+type UpdateRequest struct {
+	Name            string
+	Brand           *string
+	Watts           *int
+	TransientFactor *float64
+}
+
+// QueryRequest Service 层查询组件请求
+type QueryRequest struct {
+	Page     int
+	PageSize int
+	Type     string
+	Name     string
+	Brand    string
+}
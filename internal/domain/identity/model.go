@@ -0,0 +1,44 @@
+package identity
+
+import (
+	"time"
+)
+
+// ExternalIdentity 外部身份（单点登录账号）与本地用户的绑定关系
+// 同一提供方下 Subject 唯一，一个本地用户可以绑定多个提供方的身份
+type ExternalIdentity struct {
+	ID          uint       `gorm:"primarykey" json:"id"`
+	UserID      uint       `gorm:"index;not null" json:"user_id"`
+	Provider    string     `gorm:"size:50;not null;uniqueIndex:idx_provider_subject;comment:身份提供方名称" json:"provider"`
+	Subject     string     `gorm:"size:255;not null;uniqueIndex:idx_provider_subject;comment:提供方中的用户标识(sub)" json:"subject"`
+	Email       string     `gorm:"size:100;comment:提供方返回的邮箱" json:"email"`
+	LastLoginAt *time.Time `gorm:"comment:最近登录时间" json:"last_login_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// TableName 指定表名
+func (ExternalIdentity) TableName() string {
+	return "external_identities"
+}
+
+// LoginState 单点登录发起时保存的授权请求状态（一次性使用，只保存 state 的摘要）
+type LoginState struct {
+	ID           uint       `gorm:"primarykey" json:"id"`
+	Provider     string     `gorm:"size:50;not null" json:"provider"`
+	StateHash    string     `gorm:"uniqueIndex;size:64;not null" json:"-"`
+	Nonce        string     `gorm:"size:64;not null" json:"-"`
+	CodeVerifier string     `gorm:"size:128;not null;comment:PKCE校验码" json:"-"`
+	ExpiresAt    time.Time  `gorm:"comment:过期时间" json:"expires_at"`
+	UsedAt       *time.Time `gorm:"comment:使用时间" json:"used_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// TableName 指定表名
+func (LoginState) TableName() string {
+	return "oidc_login_states"
+}
+
+// IsExpired 是否已过期
+func (s *LoginState) IsExpired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
@@ -0,0 +1,28 @@
+package identity
+
+import (
+	"context"
+	"time"
+)
+
+// Reader 读取操作接口（接口隔离原则）
+type Reader interface {
+	FindIdentity(ctx context.Context, provider, subject string) (*ExternalIdentity, error)
+	FindStateByHash(ctx context.Context, stateHash string) (*LoginState, error)
+}
+
+// Writer 写入操作接口（接口隔离原则）
+type Writer interface {
+	CreateIdentity(ctx context.Context, i *ExternalIdentity) error
+	// TouchIdentity 记录最近登录时间与提供方返回的邮箱
+	TouchIdentity(ctx context.Context, id uint, email string, loginAt time.Time) error
+	CreateState(ctx context.Context, s *LoginState) error
+	// MarkStateUsed 将未使用的登录状态标记为已使用，返回 false 表示已被使用过
+	MarkStateUsed(ctx context.Context, id uint, usedAt time.Time) (bool, error)
+}
+
+// Repository 外部身份仓储接口（组合 Reader 和 Writer）
+type Repository interface {
+	Reader
+	Writer
+}
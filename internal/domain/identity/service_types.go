@@ -0,0 +1,19 @@
+package identity
+
+// Service 层使用的类型
+
+// LoginRedirect 发起单点登录的结果
+type LoginRedirect struct {
+	AuthorizationURL string // 跳转到身份提供方的授权地址
+	ExpiresIn        int64  // 登录请求有效期（秒）
+	State            string // 本次登录的 state，由调用方绑定到发起登录的浏览器（如 Cookie）
+}
+
+// CallbackRequest 身份提供方回调参数
+type CallbackRequest struct {
+	Code             string
+	State            string
+	BrowserState     string // 发起登录的浏览器保存的 state，必须与 State 一致
+	Error            string // 提供方返回的错误（如用户拒绝授权）
+	ErrorDescription string
+}
@@ -0,0 +1,55 @@
+package inventory
+
+import (
+	"time"
+)
+
+// 库存流水类型
+const (
+	MovementReceipt    = "receipt"    // 入库
+	MovementSale       = "sale"       // 销售出库
+	MovementAdjustment = "adjustment" // 盘点调整
+	MovementReturn     = "return"     // 退货入库
+	MovementTransfer   = "transfer"   // 仓库调拨（调出为负数、调入为正数，不改变总库存）
+)
+
+// MovementTypes 可直接登记的库存流水类型（调拨流水只能通过调拨产生）
+var MovementTypes = []string{MovementReceipt, MovementSale, MovementAdjustment, MovementReturn}
+
+// Movement 库存流水（只追加），电源的库存数量、仓库库存与流水在同一事务中同步更新
+type Movement struct {
+	ID            uint      `gorm:"primarykey" json:"id"`
+	PowerSupplyID uint      `gorm:"index;not null" json:"power_supply_id"`
+	WarehouseID   uint      `gorm:"index;comment:库存变动的仓库" json:"warehouse_id"`
+	Type          string    `gorm:"size:20;index;not null" json:"type"`
+	Quantity      int       `gorm:"not null;comment:库存变动数量，出库为负数" json:"quantity"`
+	BalanceAfter  int       `gorm:"not null;comment:变动后的总库存数量" json:"balance_after"`
+	Reason        string    `gorm:"size:255" json:"reason"`
+	Reference     string    `gorm:"size:100;index;comment:关联单据号" json:"reference"`
+	ActorID       uint      `gorm:"index;comment:操作人" json:"actor_id"`
+	CreatedAt     time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName 指定表名
+func (Movement) TableName() string {
+	return "stock_movements"
+}
+
+// IsValidType 判断是否为有效的流水类型
+func IsValidType(movementType string) bool {
+	for _, t := range MovementTypes {
+		if t == movementType {
+			return true
+		}
+	}
+	return false
+}
+
+// SignedQuantity 按流水类型换算库存变动数量
+// 入库与退货增加库存，销售减少库存，quantity 均为正数；盘点调整直接使用带符号的 quantity
+func SignedQuantity(movementType string, quantity int) int {
+	if movementType == MovementSale {
+		return -quantity
+	}
+	return quantity
+}
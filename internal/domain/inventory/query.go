@@ -0,0 +1,11 @@
+package inventory
+
+// QueryOptions 库存流水查询选项
+type QueryOptions struct {
+	PowerSupplyID uint
+	WarehouseID   uint
+	Type          string
+	Reference     string
+	Page          int
+	PageSize      int
+}
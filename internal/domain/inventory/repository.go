@@ -0,0 +1,35 @@
+package inventory
+
+import (
+	"context"
+	"time"
+)
+
+// Reader 读取操作接口（接口隔离原则）
+type Reader interface {
+	// List 查询库存流水（最新的在前）
+	List(ctx context.Context, query *QueryOptions) ([]*Movement, error)
+	Count(ctx context.Context, query *QueryOptions) (int64, error)
+}
+
+// Writer 写入操作接口（库存流水只追加，不提供修改与删除）
+type Writer interface {
+	Create(ctx context.Context, m *Movement) error
+}
+
+// Repository 库存流水仓储接口（组合 Reader 和 Writer）
+type Repository interface {
+	Reader
+	Writer
+}
+
+// ReservationRepository 库存预留仓储接口
+type ReservationRepository interface {
+	Create(ctx context.Context, r *Reservation) error
+	FindByID(ctx context.Context, id uint) (*Reservation, error)
+	UpdateByID(ctx context.Context, id uint, updates map[string]any) error
+	// UpdateStatus 条件更新预留状态，并发流转（如确认与过期释放）时只有一个能成功
+	UpdateStatus(ctx context.Context, id uint, from, to string) (bool, error)
+	// ListExpired 查询已过期但仍处于预留中的记录
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]*Reservation, error)
+}
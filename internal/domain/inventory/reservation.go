@@ -0,0 +1,38 @@
+package inventory
+
+import (
+	"time"
+)
+
+// 库存预留状态
+const (
+	ReservationPending   = "pending"   // 预留中
+	ReservationConfirmed = "confirmed" // 已确认（转为销售出库）
+	ReservationCancelled = "cancelled" // 已取消
+	ReservationExpired   = "expired"   // 已过期（由后台任务释放）
+)
+
+// Reservation 库存预留：下单结算期间暂时占用可售库存，不改变库存数量
+// 确认后登记为销售出库流水，取消或过期后释放预留数量
+type Reservation struct {
+	ID            uint      `gorm:"primarykey" json:"id"`
+	PowerSupplyID uint      `gorm:"index;not null" json:"power_supply_id"`
+	Quantity      int       `gorm:"not null" json:"quantity"`
+	Status        string    `gorm:"size:20;index:idx_reservation_status_expires;not null" json:"status"`
+	Reference     string    `gorm:"size:100;index;comment:关联单据号" json:"reference"`
+	ActorID       uint      `gorm:"index;comment:操作人" json:"actor_id"`
+	MovementID    *uint     `gorm:"comment:确认后生成的销售流水" json:"movement_id,omitempty"`
+	ExpiresAt     time.Time `gorm:"index:idx_reservation_status_expires;not null" json:"expires_at"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Reservation) TableName() string {
+	return "stock_reservations"
+}
+
+// IsExpired 判断预留是否已超过有效期
+func (r *Reservation) IsExpired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
@@ -0,0 +1,42 @@
+package inventory
+
+// Service 层使用的请求类型
+
+// MovementRequest Service 层登记库存流水请求
+type MovementRequest struct {
+	PowerSupplyID uint
+	Type          string
+	Quantity      int  // 入库、销售、退货为正数；盘点调整为带符号的变动数量
+	WarehouseID   uint // 为 0 时入库进入默认仓库，出库按库存从多到少依次从各仓库扣减
+	Reason        string
+	Reference     string
+	ActorID       uint
+}
+
+// TransferRequest Service 层仓库调拨请求
+type TransferRequest struct {
+	PowerSupplyID   uint
+	FromWarehouseID uint
+	ToWarehouseID   uint
+	Quantity        int
+	Reason          string
+	Reference       string
+	ActorID         uint
+}
+
+// MovementQueryRequest Service 层查询库存流水请求
+type MovementQueryRequest struct {
+	PowerSupplyID uint
+	WarehouseID   uint
+	Type          string
+	Page          int
+	PageSize      int
+}
+
+// ReserveRequest Service 层预留库存请求
+type ReserveRequest struct {
+	PowerSupplyID uint
+	Quantity      int
+	Reference     string
+	ActorID       uint
+}
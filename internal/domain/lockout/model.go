@@ -0,0 +1,60 @@
+package lockout
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Attempt 登录失败计数（按 key 统计，key 为用户名或客户端 IP）
+type Attempt struct {
+	ID          uint       `gorm:"primarykey" json:"id"`
+	Key         string     `gorm:"column:attempt_key;uniqueIndex;size:191;not null;comment:统计维度" json:"key"`
+	Failures    int        `gorm:"not null;default:0;comment:窗口内失败次数" json:"failures"`
+	WindowStart time.Time  `gorm:"comment:统计窗口开始时间" json:"window_start"`
+	LockedUntil *time.Time `gorm:"comment:锁定截止时间" json:"locked_until"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Attempt) TableName() string {
+	return "login_attempts"
+}
+
+// IsLocked 是否处于锁定状态
+func (a *Attempt) IsLocked(now time.Time) bool {
+	return a.LockedUntil != nil && now.Before(*a.LockedUntil)
+}
+
+// UserKey 用户名维度的统计 key（不区分大小写）
+func UserKey(username string) string {
+	return "user:" + strings.ToLower(username)
+}
+
+// IPKey 客户端 IP 维度的统计 key
+func IPKey(ip string) string {
+	return "ip:" + ip
+}
+
+// TwoFactorKey 两步验证维度的统计 key（密码正确后验证码的失败次数单独统计）
+func TwoFactorKey(userID uint) string {
+	return "2fa:" + strconv.FormatUint(uint64(userID), 10)
+}
+
+// Policy 登录保护策略
+type Policy struct {
+	MaxFailures   int           // 同一用户名在统计窗口内允许的失败次数，达到后锁定账户
+	IPMaxFailures int           // 同一 IP 在统计窗口内允许的失败次数，达到后限制该 IP 登录
+	Window        time.Duration // 统计窗口
+	LockDuration  time.Duration // 锁定时长
+}
+
+// DefaultPolicy 默认策略：15 分钟内用户名失败 5 次或 IP 失败 20 次，锁定 15 分钟
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxFailures:   5,
+		IPMaxFailures: 20,
+		Window:        15 * time.Minute,
+		LockDuration:  15 * time.Minute,
+	}
+}
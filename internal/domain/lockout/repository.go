@@ -0,0 +1,18 @@
+package lockout
+
+import (
+	"context"
+	"time"
+)
+
+// Store 登录失败计数存储接口（提供内存与数据库两种实现）
+type Store interface {
+	// Get 查询计数，不存在时返回 nil
+	Get(ctx context.Context, key string) (*Attempt, error)
+	// Increment 累加失败次数并返回累加后的计数，统计窗口已过期时重新计数
+	Increment(ctx context.Context, key string, now time.Time, window time.Duration) (*Attempt, error)
+	// Lock 锁定至指定时间，并清零失败次数
+	Lock(ctx context.Context, key string, until time.Time) error
+	// Reset 清除计数与锁定
+	Reset(ctx context.Context, key string) error
+}
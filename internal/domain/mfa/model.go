@@ -0,0 +1,58 @@
+package mfa
+
+import (
+	"time"
+)
+
+// TwoFactor 用户的 TOTP 两步验证配置
+// 登记后处于未启用状态，验证首个验证码后启用
+type TwoFactor struct {
+	ID           uint       `gorm:"primarykey" json:"id"`
+	UserID       uint       `gorm:"uniqueIndex;not null" json:"user_id"`
+	Secret       string     `gorm:"size:64;not null;comment:TOTP密钥" json:"-"`
+	Enabled      bool       `gorm:"default:false;comment:是否已启用" json:"enabled"`
+	LastUsedStep int64      `gorm:"default:0;comment:最近一次使用的时间步（防重放）" json:"-"`
+	ConfirmedAt  *time.Time `gorm:"comment:启用时间" json:"confirmed_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (TwoFactor) TableName() string {
+	return "user_two_factors"
+}
+
+// RecoveryCode 一次性恢复码（只保存摘要）
+type RecoveryCode struct {
+	ID        uint       `gorm:"primarykey" json:"id"`
+	UserID    uint       `gorm:"index;not null" json:"user_id"`
+	CodeHash  string     `gorm:"size:64;not null" json:"-"`
+	UsedAt    *time.Time `gorm:"comment:使用时间" json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName 指定表名
+func (RecoveryCode) TableName() string {
+	return "user_recovery_codes"
+}
+
+// Challenge 两步登录挑战：密码验证通过后签发，凭挑战令牌和验证码换取正式令牌
+type Challenge struct {
+	ID        uint       `gorm:"primarykey" json:"id"`
+	UserID    uint       `gorm:"index;not null" json:"user_id"`
+	TokenHash string     `gorm:"uniqueIndex;size:64;not null" json:"-"`
+	Attempts  int        `gorm:"default:0;comment:验证失败次数" json:"attempts"`
+	ExpiresAt time.Time  `gorm:"comment:过期时间" json:"expires_at"`
+	UsedAt    *time.Time `gorm:"comment:使用时间" json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName 指定表名
+func (Challenge) TableName() string {
+	return "two_factor_challenges"
+}
+
+// IsExpired 是否已过期
+func (c *Challenge) IsExpired(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
@@ -0,0 +1,38 @@
+package mfa
+
+import (
+	"context"
+	"time"
+)
+
+// Reader 读取操作接口（接口隔离原则）
+type Reader interface {
+	FindByUserID(ctx context.Context, userID uint) (*TwoFactor, error)
+	CountUnusedRecoveryCodes(ctx context.Context, userID uint) (int64, error)
+	FindChallengeByHash(ctx context.Context, tokenHash string) (*Challenge, error)
+}
+
+// Writer 写入操作接口（接口隔离原则）
+type Writer interface {
+	// Save 创建或覆盖用户的两步验证配置
+	Save(ctx context.Context, tf *TwoFactor) error
+	Enable(ctx context.Context, userID uint, confirmedAt time.Time) error
+	// Delete 删除用户的两步验证配置及恢复码
+	Delete(ctx context.Context, userID uint) error
+	// UseStep 记录已使用的时间步，返回 false 表示该时间步已用过（重放）
+	UseStep(ctx context.Context, userID uint, step int64) (bool, error)
+	// ReplaceRecoveryCodes 作废旧恢复码并写入新恢复码
+	ReplaceRecoveryCodes(ctx context.Context, userID uint, codeHashes []string) error
+	// UseRecoveryCode 使用一个未使用的恢复码，返回 false 表示恢复码无效
+	UseRecoveryCode(ctx context.Context, userID uint, codeHash string, usedAt time.Time) (bool, error)
+	CreateChallenge(ctx context.Context, c *Challenge) error
+	IncrementChallengeAttempts(ctx context.Context, id uint) error
+	// MarkChallengeUsed 将挑战标记为已使用，返回 false 表示挑战已被使用过
+	MarkChallengeUsed(ctx context.Context, id uint, usedAt time.Time) (bool, error)
+}
+
+// Repository 两步验证仓储接口（组合 Reader 和 Writer）
+type Repository interface {
+	Reader
+	Writer
+}
@@ -0,0 +1,21 @@
+package mfa
+
+// Service 层使用的类型
+
+// Enrollment 登记两步验证返回的信息
+type Enrollment struct {
+	Secret          string
+	ProvisioningURI string
+}
+
+// Status 两步验证状态
+type Status struct {
+	Enabled                bool
+	RecoveryCodesRemaining int64
+}
+
+// ChallengeTicket 两步登录挑战令牌
+type ChallengeTicket struct {
+	Token     string
+	ExpiresIn int64 // 有效期（秒）
+}
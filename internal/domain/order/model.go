@@ -0,0 +1,47 @@
+package order
+
+import (
+	"time"
+)
+
+// Order 订单模型
+type Order struct {
+	ID          uint       `gorm:"primarykey" json:"id"`
+	OrderNo     string     `gorm:"uniqueIndex;size:32;not null" json:"order_no"`
+	UserID      uint       `gorm:"index;not null" json:"user_id"`
+	Status      string     `gorm:"size:20;index;not null" json:"status"`
+	TotalAmount float64    `gorm:"type:decimal(10,2);comment:订单总金额" json:"total_amount"`
+	Remark      string     `gorm:"size:255" json:"remark"`
+	Items       []*Item    `gorm:"foreignKey:OrderID" json:"items"`
+	PaidAt      *time.Time `json:"paid_at,omitempty"`
+	ShippedAt   *time.Time `json:"shipped_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
+	RefundedAt  *time.Time `json:"refunded_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Order) TableName() string {
+	return "orders"
+}
+
+// Item 订单明细，下单时快照电源的名称、品牌、型号与单价
+type Item struct {
+	ID            uint    `gorm:"primarykey" json:"id"`
+	OrderID       uint    `gorm:"index;not null" json:"order_id"`
+	PowerSupplyID uint    `gorm:"index;not null" json:"power_supply_id"`
+	Name          string  `gorm:"size:100;not null" json:"name"`
+	Brand         string  `gorm:"size:50" json:"brand"`
+	Model         string  `gorm:"size:50" json:"model"`
+	UnitPrice     float64 `gorm:"type:decimal(10,2);comment:下单时单价" json:"unit_price"`
+	Quantity      int     `gorm:"not null" json:"quantity"`
+	Subtotal      float64 `gorm:"type:decimal(10,2)" json:"subtotal"`
+	ReservationID *uint   `gorm:"comment:下单时的库存预留" json:"reservation_id,omitempty"`
+}
+
+// TableName 指定表名
+func (Item) TableName() string {
+	return "order_items"
+}
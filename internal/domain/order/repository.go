@@ -0,0 +1,31 @@
+package order
+
+import (
+	"context"
+	"time"
+)
+
+// Reader 读取操作接口（接口隔离原则）
+type Reader interface {
+	// FindByID 查询订单（包含订单明细）
+	FindByID(ctx context.Context, id uint) (*Order, error)
+	// List 查询订单列表（包含订单明细，最新的在前）
+	List(ctx context.Context, query *QueryOptions) ([]*Order, error)
+	Count(ctx context.Context, query *QueryOptions) (int64, error)
+	// ListReservationExpired 查询库存预留已过期或已释放的待支付订单（包含订单明细，最早的在前）
+	ListReservationExpired(ctx context.Context, now time.Time, limit int) ([]*Order, error)
+}
+
+// Writer 写入操作接口（接口隔离原则）
+type Writer interface {
+	// Create 创建订单及订单明细
+	Create(ctx context.Context, o *Order) error
+	// UpdateStatus 条件更新订单状态并记录流转时间，并发流转时只有一个能成功
+	UpdateStatus(ctx context.Context, id uint, from, to string, at time.Time) (bool, error)
+}
+
+// Repository 订单仓储接口（组合 Reader 和 Writer）
+type Repository interface {
+	Reader
+	Writer
+}
@@ -0,0 +1,23 @@
+package order
+
+// Service 层使用的请求类型（从 DTO 转换而来）
+
+// ItemRequest Service 层订单明细请求
+type ItemRequest struct {
+	PowerSupplyID uint
+	Quantity      int
+}
+
+// CreateRequest Service 层创建订单请求
+type CreateRequest struct {
+	Items  []ItemRequest
+	Remark string
+}
+
+// QueryRequest Service 层查询订单请求
+type QueryRequest struct {
+	Page     int
+	PageSize int
+	UserID   *uint // 仅拥有 order:read 权限时生效，否则只能查询本人的订单
+	Status   string
+}
@@ -0,0 +1,61 @@
+package order
+
+// 订单状态
+const (
+	StatusCreated   = "created"   // 已创建：库存已预留，等待支付
+	StatusPaid      = "paid"      // 已支付：库存已扣减
+	StatusShipped   = "shipped"   // 已发货
+	StatusCompleted = "completed" // 已完成
+	StatusCancelled = "cancelled" // 已取消：终态
+	StatusRefunded  = "refunded"  // 已退款：终态
+)
+
+// Statuses 全部订单状态
+var Statuses = []string{StatusCreated, StatusPaid, StatusShipped, StatusCompleted, StatusCancelled, StatusRefunded}
+
+// statusTransitions 允许的状态流转，已取消与已退款为终态
+var statusTransitions = map[string][]string{
+	StatusCreated:   {StatusPaid, StatusCancelled},
+	StatusPaid:      {StatusShipped, StatusCancelled, StatusRefunded},
+	StatusShipped:   {StatusCompleted, StatusRefunded},
+	StatusCompleted: {StatusRefunded},
+}
+
+// statusTimeColumns 流转到各状态时记录时间的字段
+var statusTimeColumns = map[string]string{
+	StatusPaid:      "paid_at",
+	StatusShipped:   "shipped_at",
+	StatusCompleted: "completed_at",
+	StatusCancelled: "cancelled_at",
+	StatusRefunded:  "refunded_at",
+}
+
+// IsValidStatus 判断订单状态是否有效
+func IsValidStatus(status string) bool {
+	for _, s := range Statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// CanTransition 判断状态能否从 from 流转到 to
+func CanTransition(from, to string) bool {
+	for _, next := range statusTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusTimeColumn 返回流转到 status 时记录时间的字段，没有对应字段时返回空字符串
+func StatusTimeColumn(status string) string {
+	return statusTimeColumns[status]
+}
+
+// StockDeducted 判断处于该状态的订单是否已扣减库存（已支付、已发货、已完成）
+func StockDeducted(status string) bool {
+	return status == StatusPaid || status == StatusShipped || status == StatusCompleted
+}
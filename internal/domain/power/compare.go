@@ -0,0 +1,21 @@
+package power
+
+// MaxCompareItems 一次最多对比的电源数量
+const MaxCompareItems = 5
+
+// Comparison 电源对比矩阵：每一行为一项属性，Values 与 Items 按列一一对应
+type Comparison struct {
+	Items []*PowerSupply   `json:"items"`
+	Rows  []*ComparisonRow `json:"rows"`
+}
+
+// ComparisonRow 对比矩阵中的一项属性
+type ComparisonRow struct {
+	Key      string `json:"key"`
+	Label    string `json:"label"`
+	Unit     string `json:"unit,omitempty"`
+	Computed bool   `json:"computed"` // 是否为计算得出的指标（如每瓦价格）
+	Values   []any  `json:"values"`   // 未填写的值为 null
+	Differs  bool   `json:"differs"`  // 各电源的值是否不同
+	Best     []uint `json:"best"`     // 该项最优的电源 ID，值相同或无优劣之分时为空
+}
@@ -0,0 +1,160 @@
+package power
+
+import (
+	"strings"
+)
+
+// Efficiency 能效认证等级（80 PLUS 或 Cybenetics ETA），存储为规范化的代码
+type Efficiency string
+
+// 能效认证等级
+const (
+	Efficiency80Plus         Efficiency = "80plus"
+	Efficiency80PlusBronze   Efficiency = "80plus_bronze"
+	Efficiency80PlusSilver   Efficiency = "80plus_silver"
+	Efficiency80PlusGold     Efficiency = "80plus_gold"
+	Efficiency80PlusPlatinum Efficiency = "80plus_platinum"
+	Efficiency80PlusTitanium Efficiency = "80plus_titanium"
+
+	EfficiencyCyberneticsBronze   Efficiency = "cybenetics_bronze"
+	EfficiencyCyberneticsSilver   Efficiency = "cybenetics_silver"
+	EfficiencyCyberneticsGold     Efficiency = "cybenetics_gold"
+	EfficiencyCyberneticsPlatinum Efficiency = "cybenetics_platinum"
+	EfficiencyCyberneticsTitanium Efficiency = "cybenetics_titanium"
+	EfficiencyCyberneticsDiamond  Efficiency = "cybenetics_diamond"
+)
+
+// EfficiencyTier 能效档位，数值越大能效越高；80 PLUS 与 Cybenetics 的同名等级属于同一档位
+type EfficiencyTier int
+
+// 能效档位
+const (
+	TierStandard EfficiencyTier = iota + 1
+	TierBronze
+	TierSilver
+	TierGold
+	TierPlatinum
+	TierTitanium
+	TierDiamond
+)
+
+// efficiencyInfo 能效认证等级的档位与展示名称
+type efficiencyInfo struct {
+	tier  EfficiencyTier
+	label string
+}
+
+// efficiencies 全部能效认证等级（按认证体系与档位排序）
+var efficiencies = []Efficiency{
+	Efficiency80Plus,
+	Efficiency80PlusBronze,
+	Efficiency80PlusSilver,
+	Efficiency80PlusGold,
+	Efficiency80PlusPlatinum,
+	Efficiency80PlusTitanium,
+	EfficiencyCyberneticsBronze,
+	EfficiencyCyberneticsSilver,
+	EfficiencyCyberneticsGold,
+	EfficiencyCyberneticsPlatinum,
+	EfficiencyCyberneticsTitanium,
+	EfficiencyCyberneticsDiamond,
+}
+
+var efficiencyInfos = map[Efficiency]efficiencyInfo{
+	Efficiency80Plus:              {TierStandard, "80 PLUS"},
+	Efficiency80PlusBronze:        {TierBronze, "80 PLUS Bronze"},
+	Efficiency80PlusSilver:        {TierSilver, "80 PLUS Silver"},
+	Efficiency80PlusGold:          {TierGold, "80 PLUS Gold"},
+	Efficiency80PlusPlatinum:      {TierPlatinum, "80 PLUS Platinum"},
+	Efficiency80PlusTitanium:      {TierTitanium, "80 PLUS Titanium"},
+	EfficiencyCyberneticsBronze:   {TierBronze, "Cybenetics Bronze"},
+	EfficiencyCyberneticsSilver:   {TierSilver, "Cybenetics Silver"},
+	EfficiencyCyberneticsGold:     {TierGold, "Cybenetics Gold"},
+	EfficiencyCyberneticsPlatinum: {TierPlatinum, "Cybenetics Platinum"},
+	EfficiencyCyberneticsTitanium: {TierTitanium, "Cybenetics Titanium"},
+	EfficiencyCyberneticsDiamond:  {TierDiamond, "Cybenetics Diamond"},
+}
+
+// tierNames 档位名称（含常见中文写法），用于解析输入
+var tierNames = map[string]EfficiencyTier{
+	"standard": TierStandard, "white": TierStandard, "白牌": TierStandard, "标准": TierStandard,
+	"bronze": TierBronze, "铜牌": TierBronze,
+	"silver": TierSilver, "银牌": TierSilver,
+	"gold": TierGold, "金牌": TierGold,
+	"platinum": TierPlatinum, "白金": TierPlatinum, "白金牌": TierPlatinum,
+	"titanium": TierTitanium, "钛金": TierTitanium, "钛金牌": TierTitanium,
+	"diamond": TierDiamond, "钻石": TierDiamond,
+}
+
+// IsValid 判断是否为有效的能效认证等级
+func (e Efficiency) IsValid() bool {
+	_, ok := efficiencyInfos[e]
+	return ok
+}
+
+// Tier 能效档位，无效等级返回 0
+func (e Efficiency) Tier() EfficiencyTier {
+	return efficiencyInfos[e].tier
+}
+
+// Label 展示名称，如 "80 PLUS Gold"
+func (e Efficiency) Label() string {
+	return efficiencyInfos[e].label
+}
+
+// ParseEfficiency 将各种写法（如 "80Plus Gold"、"80+ gold"、"Gold"、"80Plus金牌"、"Cybenetics Platinum"）
+// 规范化为能效认证等级；未注明认证体系时按 80 PLUS 处理
+func ParseEfficiency(s string) (Efficiency, bool) {
+	v := strings.ToLower(strings.TrimSpace(s))
+	if v == "" {
+		return "", false
+	}
+	if e := Efficiency(v); e.IsValid() {
+		return e, true
+	}
+
+	cybenetics := strings.Contains(v, "cybenetics") || strings.Contains(v, "eta")
+	rest := v
+	for _, token := range []string{"cybenetics", "eta", "80plus", "80 plus", "80+", "80", "plus", " ", "-", "_"} {
+		rest = strings.ReplaceAll(rest, token, "")
+	}
+
+	tier, ok := tierNames[rest]
+	if !ok {
+		// 只有认证体系名称（如 "80 PLUS"）时为基础档位
+		if rest != "" || cybenetics {
+			return "", false
+		}
+		tier = TierStandard
+	}
+	for _, e := range efficiencies {
+		if e.Tier() == tier && strings.HasPrefix(string(e), "cybenetics") == cybenetics {
+			return e, true
+		}
+	}
+	return "", false
+}
+
+// ParseEfficiencyTier 解析档位名称（如 "gold"），也接受完整的能效认证等级
+func ParseEfficiencyTier(s string) (EfficiencyTier, bool) {
+	v := strings.ToLower(strings.TrimSpace(s))
+	if tier, ok := tierNames[v]; ok {
+		return tier, true
+	}
+	if e, ok := ParseEfficiency(v); ok {
+		return e.Tier(), true
+	}
+	return 0, false
+}
+
+// EfficienciesInTiers 返回档位在 [min, max] 之间的能效认证等级，为 0 的边界不限制
+func EfficienciesInTiers(min, max EfficiencyTier) []Efficiency {
+	var list []Efficiency
+	for _, e := range efficiencies {
+		tier := e.Tier()
+		if (min == 0 || tier >= min) && (max == 0 || tier <= max) {
+			list = append(list, e)
+		}
+	}
+	return list
+}
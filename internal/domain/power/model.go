@@ -1,24 +1,31 @@
 package power
 
 import (
+	"power-supply-sys/internal/domain/catalog"
+	"power-supply-sys/internal/domain/warehouse"
 	"time"
 )
 
 // PowerSupply 电源模型
 type PowerSupply struct {
-	ID          uint      `gorm:"primarykey" json:"id"`
-	Name        string    `gorm:"size:100;not null" json:"name"`
-	Brand       string    `gorm:"size:50" json:"brand"`
-	Model       string    `gorm:"size:50" json:"model"`
-	Power       int       `gorm:"comment:功率(W)" json:"power"`
-	Efficiency  string    `gorm:"size:20;comment:能效等级" json:"efficiency"`
-	Modular     bool      `gorm:"comment:是否模组化" json:"modular"`
-	Price       float64   `gorm:"type:decimal(10,2)" json:"price"`
-	Stock       int       `gorm:"default:0;comment:库存数量" json:"stock"`
-	Description string    `gorm:"type:text" json:"description"`
-	Status      int       `gorm:"default:1;comment:状态 1-上架 0-下架" json:"status"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uint                    `gorm:"primarykey" json:"id"`
+	Name        string                  `gorm:"size:100;not null" json:"name"`
+	Brand       string                  `gorm:"size:50" json:"brand"`
+	Model       string                  `gorm:"size:50" json:"model"`
+	Power       int                     `gorm:"comment:功率(W)" json:"power"`
+	Efficiency  Efficiency              `gorm:"size:20;index;comment:能效认证等级" json:"efficiency"`
+	Modular     bool                    `gorm:"comment:是否模组化" json:"modular"`
+	Spec        Spec                    `gorm:"embedded" json:"spec"`
+	Price       float64                 `gorm:"type:decimal(10,2)" json:"price"`
+	Stock       int                     `gorm:"default:0;comment:库存数量（各仓库之和）" json:"stock"`
+	Reserved    int                     `gorm:"default:0;comment:已预留数量" json:"reserved"`
+	Description string                  `gorm:"type:text" json:"description"`
+	Status      int                     `gorm:"default:1;comment:状态 1-上架 0-下架" json:"status"`
+	CategoryID  *uint                   `gorm:"index;comment:分类" json:"category_id"`
+	Tags        []*catalog.Tag          `gorm:"many2many:power_supply_tags;constraint:OnDelete:CASCADE" json:"tags,omitempty"`
+	Locations   []*warehouse.StockLevel `gorm:"foreignKey:PowerSupplyID;constraint:OnDelete:CASCADE" json:"locations,omitempty"`
+	CreatedAt   time.Time               `json:"created_at"`
+	UpdatedAt   time.Time               `json:"updated_at"`
 }
 
 // TableName 指定表名
@@ -26,3 +33,7 @@ func (PowerSupply) TableName() string {
 	return "power_supplies"
 }
 
+// Available 可售库存（库存数量减去已预留数量）
+func (ps *PowerSupply) Available() int {
+	return ps.Stock - ps.Reserved
+}
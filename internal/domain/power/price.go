@@ -0,0 +1,60 @@
+package power
+
+import (
+	"time"
+)
+
+// 计划调价状态
+const (
+	ScheduleStatusPending   = "pending"   // 待生效
+	ScheduleStatusApplied   = "applied"   // 已生效（由后台任务应用）
+	ScheduleStatusCancelled = "cancelled" // 已取消
+)
+
+// PriceHistory 价格历史：每次调价追加一条记录，生效区间为 [EffectiveFrom, EffectiveTo)
+// EffectiveTo 为空表示当前生效的价格
+type PriceHistory struct {
+	ID            uint       `gorm:"primarykey" json:"id"`
+	PowerSupplyID uint       `gorm:"index:idx_price_history_effective;not null" json:"power_supply_id"`
+	Price         float64    `gorm:"type:decimal(10,2);not null" json:"price"`
+	EffectiveFrom time.Time  `gorm:"index:idx_price_history_effective;not null" json:"effective_from"`
+	EffectiveTo   *time.Time `json:"effective_to"`
+	ActorID       uint       `gorm:"index;comment:操作人" json:"actor_id"`
+	Reason        string     `gorm:"size:255" json:"reason"`
+	ScheduleID    *uint      `gorm:"comment:来源计划调价" json:"schedule_id,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// TableName 指定表名
+func (PriceHistory) TableName() string {
+	return "power_price_history"
+}
+
+// PriceSchedule 计划调价：到达生效时间后由后台任务应用到电源价格
+type PriceSchedule struct {
+	ID            uint       `gorm:"primarykey" json:"id"`
+	PowerSupplyID uint       `gorm:"index;not null" json:"power_supply_id"`
+	Price         float64    `gorm:"type:decimal(10,2);not null" json:"price"`
+	EffectiveAt   time.Time  `gorm:"index:idx_price_schedule_status_effective;not null" json:"effective_at"`
+	Status        string     `gorm:"size:20;index:idx_price_schedule_status_effective;not null" json:"status"`
+	ActorID       uint       `gorm:"index;comment:操作人" json:"actor_id"`
+	Reason        string     `gorm:"size:255" json:"reason"`
+	AppliedAt     *time.Time `json:"applied_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (PriceSchedule) TableName() string {
+	return "power_price_schedules"
+}
+
+// IsValidScheduleStatus 判断计划调价状态是否有效
+func IsValidScheduleStatus(status string) bool {
+	switch status {
+	case ScheduleStatusPending, ScheduleStatusApplied, ScheduleStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
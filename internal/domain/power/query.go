@@ -2,15 +2,22 @@ package power
 
 // QueryOptions 查询选项（保留在领域层，属于领域概念）
 type QueryOptions struct {
-	Name       string
-	Brand      string
-	MinPower   *int
-	MaxPower   *int
-	MinPrice   *float64
-	MaxPrice   *float64
-	Efficiency string
-	Status     *int
-	Page       int
-	PageSize   int
+	Name        string
+	Brand       string
+	MinPower    *int
+	MaxPower    *int
+	MinPrice    *float64
+	MaxPrice    *float64
+	Efficiency  Efficiency
+	MinTier     EfficiencyTier // 能效档位下限，为 0 时不限制
+	MaxTier     EfficiencyTier // 能效档位上限，为 0 时不限制
+	Status      *int
+	WarehouseID *uint // 只查询在该仓库有库存记录的电源
+	InStock     *bool // 指定仓库时按该仓库的库存判断，否则按可售库存判断
+	Spec        SpecFilter
+	CategoryIDs []uint   // 只查询属于这些分类的电源，为空时不限制
+	Tags        []string // 标签名称（小写），为空时不限制
+	AllTags     bool     // 为 true 时要求包含全部标签，否则包含任一标签即可
+	Page        int
+	PageSize    int
 }
-
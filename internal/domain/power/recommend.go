@@ -0,0 +1,66 @@
+package power
+
+// RecommendPolicy 电源推荐策略：整机功耗估算参数与排序权重
+type RecommendPolicy struct {
+	Headroom           float64 // 持续功耗之上预留的余量比例，如 0.3 表示额定功率至少为持续功耗的 1.3 倍
+	CPUTransientFactor float64 // CPU 瞬时峰值倍数（组件档案未指定时使用）
+	GPUTransientFactor float64 // 显卡瞬时峰值倍数（组件档案未指定时使用）
+	OverclockFactor    float64 // 超频时 CPU 与显卡功耗的放大倍数
+	PlatformWatts      int     // 主板、内存等平台基础功耗(W)
+	DriveWatts         int     // 每块硬盘功耗(W)
+	FanWatts           int     // 每个风扇功耗(W)
+	MaxOversize        float64 // 候选电源额定功率上限为推荐功率的倍数，避免推荐过大的电源
+	Limit              int     // 默认返回的推荐数量
+}
+
+// DefaultRecommendPolicy 默认推荐策略：预留 30% 余量，显卡瞬时峰值按 1.6 倍、CPU 按 1.2 倍估算
+func DefaultRecommendPolicy() RecommendPolicy {
+	return RecommendPolicy{
+		Headroom:           0.3,
+		CPUTransientFactor: 1.2,
+		GPUTransientFactor: 1.6,
+		OverclockFactor:    1.2,
+		PlatformWatts:      50,
+		DriveWatts:         8,
+		FanWatts:           3,
+		MaxOversize:        2,
+		Limit:              10,
+	}
+}
+
+// RecommendRequest Service 层电源推荐请求（描述一台整机配置）
+// CPU 与显卡可按型号从组件功耗档案查询，也可直接给出功耗；同时给出时以功耗为准
+type RecommendRequest struct {
+	CPUModel    string
+	CPUTDP      int
+	GPUModel    string
+	GPUTGP      int
+	GPUCount    int // 显卡数量，指定了显卡且为 0 时按 1 块计算
+	Drives      int
+	Fans        int
+	Overclocked bool
+	Limit       int // 返回的推荐数量，为 0 时使用策略默认值
+}
+
+// PowerEstimate 整机功耗估算结果(W)
+type PowerEstimate struct {
+	CPUWatts         int `json:"cpu_watts"`
+	GPUWatts         int `json:"gpu_watts"` // 全部显卡之和
+	OtherWatts       int `json:"other_watts"`
+	SustainedWatts   int `json:"sustained_watts"`
+	PeakWatts        int `json:"peak_watts"`
+	RecommendedWatts int `json:"recommended_watts"`
+}
+
+// RecommendedItem 推荐的电源及评分
+type RecommendedItem struct {
+	PowerSupply *PowerSupply `json:"power_supply"`
+	Score       float64      `json:"score"`        // 综合评分 0~1，越高越推荐
+	LoadPercent float64      `json:"load_percent"` // 持续功耗占额定功率的百分比
+}
+
+// Recommendation 电源推荐结果
+type Recommendation struct {
+	Estimate PowerEstimate      `json:"estimate"`
+	Items    []*RecommendedItem `json:"items"`
+}
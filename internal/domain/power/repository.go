@@ -2,13 +2,20 @@ package power
 
 import (
 	"context"
+	"power-supply-sys/internal/domain/catalog"
 	"power-supply-sys/pkg/common"
+	"time"
 )
 
 // Reader 读取操作接口（接口隔离原则）
 type Reader interface {
 	FindByID(ctx context.Context, id uint) (*PowerSupply, error)
+	// FindDetail 查询电源详情（含各仓库库存）
+	FindDetail(ctx context.Context, id uint) (*PowerSupply, error)
+	// FindByIDs 批量查询电源（含各仓库库存），不存在的 ID 不在结果中
+	FindByIDs(ctx context.Context, ids []uint) ([]*PowerSupply, error)
 	FindOne(ctx context.Context, opts ...common.QueryOption) (*PowerSupply, error)
+	// List 查询电源列表（含各仓库库存）
 	List(ctx context.Context, query *QueryOptions) ([]*PowerSupply, error)
 	Count(ctx context.Context, query *QueryOptions) (int64, error)
 	Exists(ctx context.Context, opts ...common.QueryOption) (bool, error)
@@ -20,6 +27,16 @@ type Writer interface {
 	Update(ctx context.Context, ps *PowerSupply, updates map[string]any) error
 	UpdateByID(ctx context.Context, id uint, updates map[string]any) error
 	Delete(ctx context.Context, id uint) error
+	// ReplaceTags 将电源的标签整体替换为 tags
+	ReplaceTags(ctx context.Context, ps *PowerSupply, tags []*catalog.Tag) error
+	// AdjustStock 原子地调整库存，扣减后库存低于已预留数量时不更新并返回 false
+	AdjustStock(ctx context.Context, id uint, delta int) (bool, error)
+	// AdjustStockFrom 仅当库存仍为 expected 时原子地调整库存，库存已变化或调整后低于已预留数量时返回 false
+	AdjustStockFrom(ctx context.Context, id uint, expected, delta int) (bool, error)
+	// Reserve 原子地增加预留数量，可售库存不足时不更新并返回 false
+	Reserve(ctx context.Context, id uint, quantity int) (bool, error)
+	// Release 原子地减少预留数量，预留数量不足时不更新并返回 false
+	Release(ctx context.Context, id uint, quantity int) (bool, error)
 }
 
 // Repository 电源仓储接口（组合 Reader 和 Writer）
@@ -28,3 +45,28 @@ type Repository interface {
 	Writer
 }
 
+// PriceHistoryRepository 价格历史仓储接口（只追加，关闭生效区间除外）
+type PriceHistoryRepository interface {
+	Create(ctx context.Context, h *PriceHistory) error
+	// CloseOpen 将当前生效的价格记录的生效结束时间设置为 at
+	CloseOpen(ctx context.Context, powerSupplyID uint, at time.Time) error
+	// List 查询价格历史（最新生效的在前）
+	List(ctx context.Context, powerSupplyID uint, page, pageSize int) ([]*PriceHistory, error)
+	Count(ctx context.Context, powerSupplyID uint) (int64, error)
+	// PricesAt 查询各电源在 at 时刻生效的价格，没有历史记录的电源不在结果中
+	PricesAt(ctx context.Context, ids []uint, at time.Time) (map[uint]float64, error)
+}
+
+// PriceScheduleRepository 计划调价仓储接口
+type PriceScheduleRepository interface {
+	Create(ctx context.Context, s *PriceSchedule) error
+	FindByID(ctx context.Context, id uint) (*PriceSchedule, error)
+	// List 查询电源的计划调价（按生效时间排序），status 为空时不过滤
+	List(ctx context.Context, powerSupplyID uint, status string) ([]*PriceSchedule, error)
+	// ListDue 查询已到生效时间但仍待生效的计划（最早生效的在前）
+	ListDue(ctx context.Context, now time.Time, limit int) ([]*PriceSchedule, error)
+	// UpdateStatus 条件更新计划状态，并发流转（如应用与取消）时只有一个能成功
+	UpdateStatus(ctx context.Context, id uint, from, to string, at time.Time) (bool, error)
+	// PendingPricesAt 查询各电源在 at 时刻之前最后生效的待生效计划价格
+	PendingPricesAt(ctx context.Context, ids []uint, at time.Time) (map[uint]float64, error)
+}
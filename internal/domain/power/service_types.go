@@ -1,5 +1,7 @@
 package power
 
+import "time"
+
 // Service 层使用的请求类型（从 DTO 转换而来）
 // 这些类型用于 Service 层接口，保持 Service 层与传输层解耦
 
@@ -9,11 +11,16 @@ type PowerSupplyCreateRequest struct {
 	Brand       string
 	Model       string
 	Power       int
-	Efficiency  string
+	Efficiency  string // 能效认证等级，按 ParseEfficiency 规范化
 	Modular     bool
+	Spec        Spec
 	Price       float64
-	Stock       int
+	Stock       int  // 初始库存，登记为入库流水
+	WarehouseID uint // 初始库存所在仓库，为 0 时进入默认仓库
 	Description string
+	CategoryID  *uint
+	Tags        []string // 标签名称，不存在的标签自动创建
+	ActorID     uint     // 操作人（记录到库存流水）
 }
 
 // PowerSupplyUpdateRequest Service 层更新电源请求
@@ -22,25 +29,63 @@ type PowerSupplyUpdateRequest struct {
 	Brand       string
 	Model       string
 	Power       *int
-	Efficiency  string
+	Efficiency  string // 能效认证等级，按 ParseEfficiency 规范化
 	Modular     *bool
+	Spec        *Spec // 不为空时整体替换技术规格
 	Price       *float64
-	Stock       *int
+	Stock       *int // 目标库存，差额登记为盘点调整流水
 	Description string
 	Status      *int
+	CategoryID  *uint     // 不为空时修改分类，为 0 时清除分类
+	Tags        *[]string // 不为空时整体替换标签，不存在的标签自动创建
+	ActorID     uint      // 操作人（记录到库存流水）
 }
 
 // PowerSupplyQueryRequest Service 层查询电源请求
 type PowerSupplyQueryRequest struct {
-	Page       int
-	PageSize   int
-	Name       string
-	Brand      string
-	MinPower   *int
-	MaxPower   *int
-	MinPrice   *float64
-	MaxPrice   *float64
-	Efficiency string
-	Status     *int
+	Page          int
+	PageSize      int
+	Name          string
+	Brand         string
+	MinPower      *int
+	MaxPower      *int
+	MinPrice      *float64
+	MaxPrice      *float64
+	Efficiency    string // 能效认证等级，按 ParseEfficiency 规范化
+	MinEfficiency string // 能效档位下限，如 "gold"
+	MaxEfficiency string // 能效档位上限
+	Status        *int
+	WarehouseID   *uint
+	InStock       *bool
+	Spec          SpecFilter
+	CategoryID    *uint
+	SubCategories bool       // 为 true 时同时查询子孙分类下的电源
+	Tags          []string   // 标签名称（不区分大小写）
+	TagMode       string     // 标签匹配方式：any（默认，包含任一标签）或 all（包含全部标签）
+	AsOf          *time.Time // 返回该时刻生效的价格（不能与价格区间同时使用）
+}
+
+// PriceChangeRequest Service 层调价请求
+type PriceChangeRequest struct {
+	PowerSupplyID uint
+	Price         float64
+	Reason        string
+	ActorID       uint  // 操作人（记录到价格历史）
+	ScheduleID    *uint // 由计划调价应用时关联的计划
 }
 
+// PriceScheduleRequest Service 层创建计划调价请求
+type PriceScheduleRequest struct {
+	PowerSupplyID uint
+	Price         float64
+	EffectiveAt   time.Time
+	Reason        string
+	ActorID       uint
+}
+
+// PriceHistoryQueryRequest Service 层查询价格历史请求
+type PriceHistoryQueryRequest struct {
+	PowerSupplyID uint
+	Page          int
+	PageSize      int
+}
@@ -0,0 +1,74 @@
+package power
+
+// 电源版型
+const (
+	FormFactorATX  = "ATX"
+	FormFactorSFX  = "SFX"
+	FormFactorSFXL = "SFX-L"
+	FormFactorTFX  = "TFX"
+)
+
+// Spec 电源技术规格（与电源存储在同一张表）
+// 数值为 0 表示未填写，FanSizeMM 为 0 同时表示被动散热
+type Spec struct {
+	FormFactor         string  `gorm:"column:form_factor;size:10;index;comment:版型" json:"form_factor"`
+	ATX3               bool    `gorm:"column:atx3;comment:是否符合 ATX 3.x 规范" json:"atx3"`
+	Connectors12VHPWR  int     `gorm:"column:connectors_12vhpwr;default:0;comment:12VHPWR/12V-2x6 接口数" json:"connectors_12vhpwr"`
+	EPSConnectors      int     `gorm:"column:eps_connectors;default:0;comment:EPS 8pin 接口数" json:"eps_connectors"`
+	PCIe8PinConnectors int     `gorm:"column:pcie_8pin_connectors;default:0;comment:PCIe 8pin 接口数" json:"pcie_8pin_connectors"`
+	SATAConnectors     int     `gorm:"column:sata_connectors;default:0;comment:SATA 接口数" json:"sata_connectors"`
+	MolexConnectors    int     `gorm:"column:molex_connectors;default:0;comment:Molex 接口数" json:"molex_connectors"`
+	Rail12VAmps        float64 `gorm:"column:rail_12v_amps;type:decimal(6,1);default:0;comment:+12V 输出电流(A)" json:"rail_12v_amps"`
+	FanSizeMM          int     `gorm:"column:fan_size_mm;default:0;comment:风扇尺寸(mm)" json:"fan_size_mm"`
+	NoiseDBA           float64 `gorm:"column:noise_dba;type:decimal(4,1);default:0;comment:噪音(dBA)" json:"noise_dba"`
+	LengthMM           int     `gorm:"column:length_mm;default:0;comment:长度(mm)" json:"length_mm"`
+	WidthMM            int     `gorm:"column:width_mm;default:0;comment:宽度(mm)" json:"width_mm"`
+	HeightMM           int     `gorm:"column:height_mm;default:0;comment:高度(mm)" json:"height_mm"`
+}
+
+// Columns 返回规格各字段对应的列，用于整体更新规格
+func (s *Spec) Columns() map[string]any {
+	return map[string]any{
+		"form_factor":          s.FormFactor,
+		"atx3":                 s.ATX3,
+		"connectors_12vhpwr":   s.Connectors12VHPWR,
+		"eps_connectors":       s.EPSConnectors,
+		"pcie_8pin_connectors": s.PCIe8PinConnectors,
+		"sata_connectors":      s.SATAConnectors,
+		"molex_connectors":     s.MolexConnectors,
+		"rail_12v_amps":        s.Rail12VAmps,
+		"fan_size_mm":          s.FanSizeMM,
+		"noise_dba":            s.NoiseDBA,
+		"length_mm":            s.LengthMM,
+		"width_mm":             s.WidthMM,
+		"height_mm":            s.HeightMM,
+	}
+}
+
+// IsValidFormFactor 判断版型是否有效
+func IsValidFormFactor(formFactor string) bool {
+	switch formFactor {
+	case FormFactorATX, FormFactorSFX, FormFactorSFXL, FormFactorTFX:
+		return true
+	default:
+		return false
+	}
+}
+
+// SpecFilter 按技术规格过滤的条件，为空的条件不参与过滤
+// 最大值条件不匹配未填写（为 0）的规格
+type SpecFilter struct {
+	FormFactor  string
+	ATX3        *bool
+	Min12VHPWR  *int
+	MinEPS      *int
+	MinPCIe8Pin *int
+	MinSATA     *int
+	MinMolex    *int
+	Min12VAmps  *float64
+	FanSizeMM   *int
+	MaxNoiseDBA *float64
+	MaxLengthMM *int
+	MaxWidthMM  *int
+	MaxHeightMM *int
+}
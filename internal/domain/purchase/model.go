@@ -0,0 +1,58 @@
+package purchase
+
+import (
+	"time"
+)
+
+// Order 采购单模型
+type Order struct {
+	ID          uint       `gorm:"primarykey" json:"id"`
+	OrderNo     string     `gorm:"uniqueIndex;size:32;not null" json:"order_no"`
+	SupplierID  uint       `gorm:"index;not null" json:"supplier_id"`
+	WarehouseID uint       `gorm:"not null;comment:收货仓库" json:"warehouse_id"`
+	Status      string     `gorm:"size:20;index;not null" json:"status"`
+	TotalAmount float64    `gorm:"type:decimal(12,2);comment:采购总金额" json:"total_amount"`
+	Remark      string     `gorm:"size:255" json:"remark"`
+	CreatedBy   uint       `gorm:"comment:创建人" json:"created_by"`
+	Lines       []*Line    `gorm:"foreignKey:PurchaseOrderID;constraint:OnDelete:CASCADE" json:"lines"`
+	SentAt      *time.Time `json:"sent_at,omitempty"`
+	ReceivedAt  *time.Time `json:"received_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Order) TableName() string {
+	return "purchase_orders"
+}
+
+// Line 采购单明细
+type Line struct {
+	ID               uint    `gorm:"primarykey" json:"id"`
+	PurchaseOrderID  uint    `gorm:"index;not null" json:"purchase_order_id"`
+	PowerSupplyID    uint    `gorm:"index;not null" json:"power_supply_id"`
+	Quantity         int     `gorm:"not null;comment:采购数量" json:"quantity"`
+	ReceivedQuantity int     `gorm:"not null;default:0;comment:已收货数量" json:"received_quantity"`
+	UnitCost         float64 `gorm:"type:decimal(10,2);comment:采购单价" json:"unit_cost"`
+	Subtotal         float64 `gorm:"type:decimal(12,2)" json:"subtotal"`
+}
+
+// TableName 指定表名
+func (Line) TableName() string {
+	return "purchase_order_lines"
+}
+
+// Remaining 未收货数量
+func (l *Line) Remaining() int {
+	return l.Quantity - l.ReceivedQuantity
+}
+
+// FullyReceived 判断采购单的全部明细是否已收货完成
+func (o *Order) FullyReceived() bool {
+	for _, line := range o.Lines {
+		if line.Remaining() > 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,9 @@
+package purchase
+
+// QueryOptions 查询选项（保留在领域层，属于领域概念）
+type QueryOptions struct {
+	SupplierID *uint
+	Status     string
+	Page       int
+	PageSize   int
+}
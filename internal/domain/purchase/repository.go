@@ -0,0 +1,36 @@
+package purchase
+
+import (
+	"context"
+	"time"
+)
+
+// Reader 读取操作接口（接口隔离原则）
+type Reader interface {
+	// FindByID 查询采购单（包含采购明细）
+	FindByID(ctx context.Context, id uint) (*Order, error)
+	// List 查询采购单列表（包含采购明细，最新的在前）
+	List(ctx context.Context, query *QueryOptions) ([]*Order, error)
+	Count(ctx context.Context, query *QueryOptions) (int64, error)
+	// ExistsBySupplier 检查供应商是否有采购单
+	ExistsBySupplier(ctx context.Context, supplierID uint) (bool, error)
+}
+
+// Writer 写入操作接口（接口隔离原则）
+type Writer interface {
+	// Create 创建采购单及采购明细
+	Create(ctx context.Context, o *Order) error
+	UpdateByID(ctx context.Context, id uint, updates map[string]any) error
+	// ReplaceLines 替换采购单的明细并更新采购单字段（需在事务中调用）
+	ReplaceLines(ctx context.Context, id uint, lines []*Line, updates map[string]any) error
+	// UpdateStatus 条件更新采购单状态并记录流转时间，并发流转时只有一个能成功
+	UpdateStatus(ctx context.Context, id uint, from, to string, at time.Time) (bool, error)
+	// ReceiveLine 原子地增加明细的已收货数量，超过采购数量时不更新并返回 false
+	ReceiveLine(ctx context.Context, lineID uint, quantity int) (bool, error)
+}
+
+// Repository 采购单仓储接口（组合 Reader 和 Writer）
+type Repository interface {
+	Reader
+	Writer
+}
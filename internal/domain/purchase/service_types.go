@@ -0,0 +1,46 @@
+package purchase
+
+// Service 层使用的请求类型（从 DTO 转换而来）
+
+// LineRequest Service 层采购明细请求
+type LineRequest struct {
+	PowerSupplyID uint
+	Quantity      int
+	UnitCost      *float64 // 为空时使用供应商的采购成本价
+}
+
+// CreateRequest Service 层创建采购单请求
+type CreateRequest struct {
+	SupplierID  uint
+	WarehouseID uint // 收货仓库，为 0 时使用默认仓库
+	Lines       []LineRequest
+	Remark      string
+	ActorID     uint
+}
+
+// UpdateRequest Service 层修改采购单请求（仅草稿）
+type UpdateRequest struct {
+	WarehouseID *uint
+	Lines       []LineRequest // 不为空时替换全部明细
+	Remark      *string
+}
+
+// ReceiveLineRequest Service 层明细收货请求
+type ReceiveLineRequest struct {
+	LineID   uint
+	Quantity int
+}
+
+// ReceiveRequest Service 层采购收货请求
+type ReceiveRequest struct {
+	Lines   []ReceiveLineRequest
+	ActorID uint
+}
+
+// QueryRequest Service 层查询采购单请求
+type QueryRequest struct {
+	Page       int
+	PageSize   int
+	SupplierID *uint
+	Status     string
+}
@@ -0,0 +1,55 @@
+package purchase
+
+// 采购单状态
+const (
+	StatusDraft             = "draft"              // 草稿：可以修改
+	StatusSent              = "sent"               // 已下发给供应商，等待收货
+	StatusPartiallyReceived = "partially_received" // 部分收货
+	StatusReceived          = "received"           // 已收货：终态
+)
+
+// Statuses 全部采购单状态
+var Statuses = []string{StatusDraft, StatusSent, StatusPartiallyReceived, StatusReceived}
+
+// statusTransitions 允许的状态流转，已收货为终态
+var statusTransitions = map[string][]string{
+	StatusDraft:             {StatusSent},
+	StatusSent:              {StatusPartiallyReceived, StatusReceived},
+	StatusPartiallyReceived: {StatusReceived},
+}
+
+// statusTimeColumns 流转到各状态时记录时间的字段
+var statusTimeColumns = map[string]string{
+	StatusSent:     "sent_at",
+	StatusReceived: "received_at",
+}
+
+// IsValidStatus 判断采购单状态是否有效
+func IsValidStatus(status string) bool {
+	for _, s := range Statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// CanTransition 判断状态能否从 from 流转到 to
+func CanTransition(from, to string) bool {
+	for _, next := range statusTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusTimeColumn 返回流转到 status 时记录时间的字段，没有对应字段时返回空字符串
+func StatusTimeColumn(status string) string {
+	return statusTimeColumns[status]
+}
+
+// Receivable 判断处于该状态的采购单能否收货（已下发或部分收货）
+func Receivable(status string) bool {
+	return status == StatusSent || status == StatusPartiallyReceived
+}
@@ -0,0 +1,94 @@
+package rbac
+
+import (
+	"time"
+)
+
+// 内置角色编码
+const (
+	RoleAdmin = "admin" // 管理员
+	RoleUser  = "user"  // 普通用户
+)
+
+// 权限编码（资源:操作）
+const (
+	PermUserRead        = "user:read"        // 查看用户
+	PermUserWrite       = "user:write"       // 修改用户
+	PermUserDelete      = "user:delete"      // 删除用户
+	PermUserManage      = "user:manage"      // 修改用户状态等特权字段
+	PermUserImpersonate = "user:impersonate" // 模拟用户登录
+	PermRoleManage      = "role:manage"      // 管理角色与授权
+	PermPowerRead       = "power:read"       // 查看电源
+	PermPowerWrite      = "power:write"      // 维护电源
+	PermOrderCreate     = "order:create"     // 下单
+	PermOrderRead       = "order:read"       // 查看全部订单
+	PermOrderManage     = "order:manage"     // 处理订单（发货、完成、退款及取消他人订单）
+	PermSupplierRead    = "supplier:read"    // 查看供应商
+	PermSupplierWrite   = "supplier:write"   // 维护供应商及其供应的电源
+	PermPurchaseRead    = "purchase:read"    // 查看采购单
+	PermPurchaseWrite   = "purchase:write"   // 创建、下发采购单及采购收货
+)
+
+// Permission 权限模型
+type Permission struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	Code      string    `gorm:"uniqueIndex;size:50;not null" json:"code"`
+	Name      string    `gorm:"size:100" json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (Permission) TableName() string {
+	return "permissions"
+}
+
+// Role 角色模型
+type Role struct {
+	ID          uint          `gorm:"primarykey" json:"id"`
+	Code        string        `gorm:"uniqueIndex;size:50;not null" json:"code"`
+	Name        string        `gorm:"size:50" json:"name"`
+	Description string        `gorm:"size:255" json:"description"`
+	BuiltIn     bool          `gorm:"default:false;comment:是否内置角色" json:"built_in"`
+	Permissions []*Permission `gorm:"many2many:role_permissions" json:"permissions,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Role) TableName() string {
+	return "roles"
+}
+
+// DefaultPermissions 内置权限列表（迁移时自动写入）
+var DefaultPermissions = []Permission{
+	{Code: PermUserRead, Name: "查看用户"},
+	{Code: PermUserWrite, Name: "修改用户"},
+	{Code: PermUserDelete, Name: "删除用户"},
+	{Code: PermUserManage, Name: "修改用户状态等特权字段"},
+	{Code: PermUserImpersonate, Name: "模拟用户登录"},
+	{Code: PermRoleManage, Name: "管理角色与授权"},
+	{Code: PermPowerRead, Name: "查看电源"},
+	{Code: PermPowerWrite, Name: "维护电源"},
+	{Code: PermOrderCreate, Name: "下单"},
+	{Code: PermOrderRead, Name: "查看全部订单"},
+	{Code: PermOrderManage, Name: "处理订单"},
+	{Code: PermSupplierRead, Name: "查看供应商"},
+	{Code: PermSupplierWrite, Name: "维护供应商"},
+	{Code: PermPurchaseRead, Name: "查看采购单"},
+	{Code: PermPurchaseWrite, Name: "管理采购单与采购收货"},
+}
+
+// DefaultRoles 内置角色及其默认权限（管理员始终拥有全部权限）
+var DefaultRoles = []struct {
+	Role        Role
+	Permissions []string
+}{
+	{
+		Role:        Role{Code: RoleAdmin, Name: "管理员", Description: "拥有全部权限", BuiltIn: true},
+		Permissions: nil,
+	},
+	{
+		Role:        Role{Code: RoleUser, Name: "普通用户", Description: "浏览电源目录并下单", BuiltIn: true},
+		Permissions: []string{PermPowerRead, PermOrderCreate},
+	},
+}
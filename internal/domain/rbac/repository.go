@@ -0,0 +1,33 @@
+package rbac
+
+import (
+	"context"
+)
+
+// PermissionChecker 权限校验接口
+type PermissionChecker interface {
+	HasPermission(ctx context.Context, role, permission string) (bool, error)
+}
+
+// Reader 读取操作接口（接口隔离原则）
+type Reader interface {
+	FindRoleByID(ctx context.Context, id uint) (*Role, error)
+	FindRoleByCode(ctx context.Context, code string) (*Role, error)
+	ListRoles(ctx context.Context) ([]*Role, error)
+	ListPermissions(ctx context.Context) ([]*Permission, error)
+	FindPermissionsByCodes(ctx context.Context, codes []string) ([]*Permission, error)
+	RoleExists(ctx context.Context, code string) (bool, error)
+	PermissionChecker
+}
+
+// Writer 写入操作接口（接口隔离原则）
+type Writer interface {
+	CreateRole(ctx context.Context, role *Role) error
+	ReplaceRolePermissions(ctx context.Context, role *Role, permissions []*Permission) error
+}
+
+// Repository 角色权限仓储接口（组合 Reader 和 Writer）
+type Repository interface {
+	Reader
+	Writer
+}
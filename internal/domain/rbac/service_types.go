@@ -0,0 +1,12 @@
+package rbac
+
+// Service 层使用的请求类型（从 DTO 转换而来）
+// 这些类型用于 Service 层接口，保持 Service 层与传输层解耦
+
+// RoleCreateRequest Service 层创建角色请求
+type RoleCreateRequest struct {
+	Code        string
+	Name        string
+	Description string
+	Permissions []string
+}
@@ -0,0 +1,41 @@
+package supplier
+
+import (
+	"time"
+)
+
+// Supplier 供应商模型
+type Supplier struct {
+	ID          uint       `gorm:"primarykey" json:"id"`
+	Name        string     `gorm:"uniqueIndex;size:100;not null" json:"name"`
+	ContactName string     `gorm:"size:50;comment:联系人" json:"contact_name"`
+	Phone       string     `gorm:"size:20" json:"phone"`
+	Email       string     `gorm:"size:100" json:"email"`
+	Address     string     `gorm:"size:255" json:"address"`
+	Remark      string     `gorm:"size:255" json:"remark"`
+	Products    []*Product `gorm:"foreignKey:SupplierID;constraint:OnDelete:CASCADE" json:"products,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Supplier) TableName() string {
+	return "suppliers"
+}
+
+// Product 供应商供应的电源型号及采购成本价与交货周期
+type Product struct {
+	ID            uint      `gorm:"primarykey" json:"id"`
+	SupplierID    uint      `gorm:"uniqueIndex:idx_supplier_products_item;not null" json:"supplier_id"`
+	PowerSupplyID uint      `gorm:"uniqueIndex:idx_supplier_products_item;index;not null" json:"power_supply_id"`
+	SupplierSKU   string    `gorm:"size:64;comment:供应商货号" json:"supplier_sku"`
+	CostPrice     float64   `gorm:"type:decimal(10,2);comment:采购成本价" json:"cost_price"`
+	LeadTimeDays  int       `gorm:"default:0;comment:交货周期(天)" json:"lead_time_days"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Product) TableName() string {
+	return "supplier_products"
+}
@@ -0,0 +1,9 @@
+package supplier
+
+// QueryOptions 查询选项（保留在领域层，属于领域概念）
+type QueryOptions struct {
+	Name          string
+	PowerSupplyID uint // 只查询供应该电源的供应商
+	Page          int
+	PageSize      int
+}
@@ -0,0 +1,38 @@
+package supplier
+
+import (
+	"context"
+)
+
+// Reader 读取操作接口（接口隔离原则）
+type Reader interface {
+	// FindByID 查询供应商（包含供应的电源）
+	FindByID(ctx context.Context, id uint) (*Supplier, error)
+	List(ctx context.Context, query *QueryOptions) ([]*Supplier, error)
+	Count(ctx context.Context, query *QueryOptions) (int64, error)
+	ExistsByName(ctx context.Context, name string) (bool, error)
+}
+
+// Writer 写入操作接口（接口隔离原则）
+type Writer interface {
+	Create(ctx context.Context, s *Supplier) error
+	UpdateByID(ctx context.Context, id uint, updates map[string]any) error
+	// Delete 删除供应商及其供应的电源
+	Delete(ctx context.Context, id uint) error
+}
+
+// Repository 供应商仓储接口（组合 Reader 和 Writer）
+type Repository interface {
+	Reader
+	Writer
+}
+
+// ProductRepository 供应商电源仓储接口
+type ProductRepository interface {
+	// FindBySupplier 查询供应商供应的指定电源
+	FindBySupplier(ctx context.Context, supplierID, powerSupplyID uint) (*Product, error)
+	// Save 创建或更新供应商供应的电源（按供应商与电源唯一）
+	Save(ctx context.Context, p *Product) error
+	// Remove 删除供应商供应的电源，记录不存在时返回 false
+	Remove(ctx context.Context, supplierID, powerSupplyID uint) (bool, error)
+}
@@ -0,0 +1,39 @@
+package supplier
+
+// Service 层使用的请求类型（从 DTO 转换而来）
+
+// CreateRequest Service 层创建供应商请求
+type CreateRequest struct {
+	Name        string
+	ContactName string
+	Phone       string
+	Email       string
+	Address     string
+	Remark      string
+}
+
+// UpdateRequest Service 层更新供应商请求
+type UpdateRequest struct {
+	Name        string
+	ContactName *string
+	Phone       *string
+	Email       *string
+	Address     *string
+	Remark      *string
+}
+
+// ProductRequest Service 层设置供应商电源请求
+type ProductRequest struct {
+	PowerSupplyID uint
+	SupplierSKU   string
+	CostPrice     float64
+	LeadTimeDays  int
+}
+
+// QueryRequest Service 层查询供应商请求
+type QueryRequest struct {
+	Page          int
+	PageSize      int
+	Name          string
+	PowerSupplyID uint
+}
@@ -0,0 +1,71 @@
+package token
+
+import (
+	"time"
+)
+
+// RefreshToken 刷新令牌模型（只保存摘要，不保存原文）
+// 同一次登录轮换出的令牌属于同一个令牌族（FamilyID），令牌族即一个登录会话
+type RefreshToken struct {
+	ID        uint       `gorm:"primarykey" json:"id"`
+	UserID    uint       `gorm:"index;not null" json:"user_id"`
+	FamilyID  string     `gorm:"size:64;index;not null;comment:令牌族ID" json:"family_id"`
+	TokenHash string     `gorm:"uniqueIndex;size:64;not null" json:"-"`
+	ExpiresAt time.Time  `gorm:"comment:过期时间" json:"expires_at"`
+	UsedAt    *time.Time `gorm:"comment:轮换时间" json:"used_at"`
+	RevokedAt *time.Time `gorm:"comment:吊销时间" json:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName 指定表名
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+// IsExpired 是否已过期
+func (t *RefreshToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+// PasswordResetToken 密码重置令牌（一次性使用，只保存摘要）
+type PasswordResetToken struct {
+	ID        uint       `gorm:"primarykey" json:"id"`
+	UserID    uint       `gorm:"index;not null" json:"user_id"`
+	TokenHash string     `gorm:"uniqueIndex;size:64;not null" json:"-"`
+	ExpiresAt time.Time  `gorm:"comment:过期时间" json:"expires_at"`
+	UsedAt    *time.Time `gorm:"comment:使用时间" json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName 指定表名
+func (PasswordResetToken) TableName() string {
+	return "password_reset_tokens"
+}
+
+// IsExpired 是否已过期
+func (t *PasswordResetToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+// Session 登录会话（与令牌族一一对应，SessionID 即令牌族 ID，也是 access token 中的 sid）
+type Session struct {
+	ID         uint       `gorm:"primarykey" json:"id"`
+	UserID     uint       `gorm:"index;not null" json:"user_id"`
+	SessionID  string     `gorm:"size:64;uniqueIndex;not null;comment:会话ID（令牌族ID）" json:"-"`
+	UserAgent  string     `gorm:"size:512;comment:登录时的User-Agent" json:"user_agent"`
+	IP         string     `gorm:"size:64;comment:登录IP" json:"ip"`
+	LastSeenAt time.Time  `gorm:"comment:最近活跃时间" json:"last_seen_at"`
+	ExpiresAt  time.Time  `gorm:"comment:过期时间（随刷新令牌轮换延长）" json:"expires_at"`
+	RevokedAt  *time.Time `gorm:"comment:终止时间" json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// TableName 指定表名
+func (Session) TableName() string {
+	return "sessions"
+}
+
+// IsActive 会话是否有效（未终止且未过期）
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
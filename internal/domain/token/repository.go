@@ -0,0 +1,49 @@
+package token
+
+import (
+	"context"
+	"time"
+)
+
+// Reader 读取操作接口（接口隔离原则）
+type Reader interface {
+	FindByHash(ctx context.Context, tokenHash string) (*RefreshToken, error)
+	HasActiveInFamily(ctx context.Context, familyID string, now time.Time) (bool, error)
+	FindSession(ctx context.Context, sessionID string) (*Session, error)
+	// FindUserSession 根据主键查询用户本人的会话
+	FindUserSession(ctx context.Context, userID, id uint) (*Session, error)
+	// ListActiveSessions 查询用户未终止且未过期的会话（按最近活跃时间倒序）
+	ListActiveSessions(ctx context.Context, userID uint, now time.Time) ([]*Session, error)
+}
+
+// Writer 写入操作接口（接口隔离原则）
+type Writer interface {
+	Create(ctx context.Context, t *RefreshToken) error
+	// MarkUsed 将未使用的令牌标记为已轮换，返回 false 表示令牌已被使用过
+	MarkUsed(ctx context.Context, id uint, usedAt time.Time) (bool, error)
+	// RevokeFamily 吊销整个令牌族并终止对应会话
+	RevokeFamily(ctx context.Context, familyID string, revokedAt time.Time) error
+	// RevokeByUserID 吊销用户的所有令牌族并终止所有会话
+	RevokeByUserID(ctx context.Context, userID uint, revokedAt time.Time) error
+	CreateSession(ctx context.Context, s *Session) error
+	// TouchSession 更新会话最近活跃时间
+	TouchSession(ctx context.Context, sessionID string, seenAt time.Time) error
+	// ExtendSession 刷新令牌轮换后更新会话的活跃时间与过期时间
+	ExtendSession(ctx context.Context, sessionID string, seenAt, expiresAt time.Time) error
+}
+
+// Repository 刷新令牌仓储接口（组合 Reader 和 Writer）
+type Repository interface {
+	Reader
+	Writer
+}
+
+// PasswordResetRepository 密码重置令牌仓储接口
+type PasswordResetRepository interface {
+	FindByHash(ctx context.Context, tokenHash string) (*PasswordResetToken, error)
+	Create(ctx context.Context, t *PasswordResetToken) error
+	// MarkUsed 将未使用的重置令牌标记为已使用，返回 false 表示令牌已被使用过
+	MarkUsed(ctx context.Context, id uint, usedAt time.Time) (bool, error)
+	// InvalidateByUserID 作废用户所有未使用的重置令牌
+	InvalidateByUserID(ctx context.Context, userID uint, usedAt time.Time) error
+}
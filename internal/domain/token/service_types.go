@@ -0,0 +1,17 @@
+package token
+
+// Service 层使用的类型
+
+// TokenPair 登录或刷新后下发的令牌对
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    int64 // access token 有效期（秒）
+	SessionID    string
+}
+
+// ClientInfo 登录客户端信息（记录到会话）
+type ClientInfo struct {
+	UserAgent string
+	IP        string
+}
@@ -6,20 +6,21 @@ import (
 
 // User 用户模型
 type User struct {
-	ID        uint      `gorm:"primarykey" json:"id"`
-	Username  string    `gorm:"uniqueIndex;size:50;not null" json:"username"`
-	Password  string    `gorm:"size:255;not null" json:"-"`
-	Email     string    `gorm:"uniqueIndex;size:100" json:"email"`
-	Phone     string    `gorm:"size:20" json:"phone"`
-	Nickname  string    `gorm:"size:50" json:"nickname"`
-	Avatar    string    `gorm:"size:255" json:"avatar"`
-	Status    int       `gorm:"default:1;comment:状态 1-正常 0-禁用" json:"status"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID           uint      `gorm:"primarykey" json:"id"`
+	Username     string    `gorm:"uniqueIndex;size:50;not null" json:"username"`
+	Password     string    `gorm:"size:255;not null" json:"-"`
+	Email        string    `gorm:"uniqueIndex;size:100" json:"email"`
+	PendingEmail string    `gorm:"size:100;comment:待验证的新邮箱，验证通过后替换 email" json:"pending_email,omitempty"`
+	Phone        string    `gorm:"size:20" json:"phone"`
+	Nickname     string    `gorm:"size:50" json:"nickname"`
+	Avatar       string    `gorm:"size:255" json:"avatar"`
+	Role         string    `gorm:"size:50;default:user;index;comment:角色编码" json:"role"`
+	Status       int       `gorm:"default:1;comment:状态 0-禁用 1-正常 2-待验证 3-锁定 4-已注销" json:"status"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
 }
 
 // TableName 指定表名
 func (User) TableName() string {
 	return "users"
 }
-
@@ -0,0 +1,70 @@
+package user
+
+import (
+	"fmt"
+	"power-supply-sys/pkg/common"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PasswordPolicy 密码策略，注册、修改密码与重置密码时校验
+type PasswordPolicy struct {
+	MinLength     int                 // 最小长度（按字符计）
+	MaxLength     int                 // 最大长度（按字符计），0 表示不限制
+	RequireUpper  bool                // 必须包含大写字母
+	RequireLower  bool                // 必须包含小写字母
+	RequireDigit  bool                // 必须包含数字
+	RequireSymbol bool                // 必须包含特殊字符
+	Breached      map[string]struct{} // 已泄露密码列表（小写）
+}
+
+// DefaultPasswordPolicy 默认策略：长度 8~128，不强制字符类别
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength: 8,
+		MaxLength: 128,
+	}
+}
+
+// Validate 校验密码是否符合策略
+func (p PasswordPolicy) Validate(password string) error {
+	length := utf8.RuneCountInString(password)
+	if length < p.MinLength {
+		return common.ErrInvalidParam(fmt.Sprintf("密码长度不能少于 %d 个字符", p.MinLength))
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		return common.ErrInvalidParam(fmt.Sprintf("密码长度不能超过 %d 个字符", p.MaxLength))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+	if p.RequireUpper && !hasUpper {
+		return common.ErrInvalidParam("密码必须包含大写字母")
+	}
+	if p.RequireLower && !hasLower {
+		return common.ErrInvalidParam("密码必须包含小写字母")
+	}
+	if p.RequireDigit && !hasDigit {
+		return common.ErrInvalidParam("密码必须包含数字")
+	}
+	if p.RequireSymbol && !hasSymbol {
+		return common.ErrInvalidParam("密码必须包含特殊字符")
+	}
+
+	if _, ok := p.Breached[strings.ToLower(password)]; ok {
+		return common.ErrInvalidParam("该密码已出现在泄露密码库中，请更换")
+	}
+	return nil
+}
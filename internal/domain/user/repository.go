@@ -20,6 +20,8 @@ type Writer interface {
 	Create(ctx context.Context, u *User) error
 	Update(ctx context.Context, u *User, updates map[string]any) error
 	UpdateByID(ctx context.Context, id uint, updates map[string]any) error
+	// UpdateStatus 仅当当前状态为 from 时更新为 to，返回是否更新成功
+	UpdateStatus(ctx context.Context, id uint, from, to int) (bool, error)
 	Delete(ctx context.Context, id uint) error
 }
 
@@ -3,6 +3,12 @@ package user
 // Service 层使用的请求类型（从 DTO 转换而来）
 // 这些类型用于 Service 层接口，保持 Service 层与传输层解耦
 
+// Actor 发起操作的用户（来自认证信息）
+type Actor struct {
+	ID   uint
+	Role string
+}
+
 // UserCreateRequest Service 层创建用户请求
 type UserCreateRequest struct {
 	Username string
@@ -35,5 +41,18 @@ type UserQueryRequest struct {
 type LoginRequest struct {
 	Username string
 	Password string
+	ClientIP string
+}
+
+// PasswordChangeRequest Service 层修改密码请求
+type PasswordChangeRequest struct {
+	OldPassword string
+	NewPassword string
+}
+
+// PasswordResetRequest Service 层重置密码请求
+type PasswordResetRequest struct {
+	Token       string
+	NewPassword string
 }
 
@@ -0,0 +1,65 @@
+package user
+
+import (
+	"power-supply-sys/pkg/common"
+)
+
+// 用户状态（保持 0 禁用、1 正常的历史取值）
+const (
+	StatusDisabled = 0 // 已禁用：管理员停用，可重新启用
+	StatusActive   = 1 // 正常
+	StatusPending  = 2 // 待验证：注册后尚未验证邮箱
+	StatusLocked   = 3 // 已锁定：因安全原因冻结，解锁后恢复正常
+	StatusDeleted  = 4 // 已注销：终态，保留记录但不再可用
+)
+
+// statusNames 状态名称
+var statusNames = map[int]string{
+	StatusDisabled: "disabled",
+	StatusActive:   "active",
+	StatusPending:  "pending",
+	StatusLocked:   "locked",
+	StatusDeleted:  "deleted",
+}
+
+// statusTransitions 允许的状态流转，已注销为终态
+var statusTransitions = map[int][]int{
+	StatusPending:  {StatusActive, StatusDisabled, StatusDeleted},
+	StatusActive:   {StatusDisabled, StatusLocked, StatusDeleted},
+	StatusDisabled: {StatusActive, StatusDeleted},
+	StatusLocked:   {StatusActive, StatusDisabled, StatusDeleted},
+}
+
+// StatusName 返回状态名称，未知状态返回 unknown
+func StatusName(status int) string {
+	if name, ok := statusNames[status]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// CanTransition 判断状态能否从 from 流转到 to
+func CanTransition(from, to int) bool {
+	for _, next := range statusTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusError 返回阻止用户登录或使用凭证的状态错误，状态正常时返回 nil
+func (u *User) StatusError() error {
+	switch u.Status {
+	case StatusActive:
+		return nil
+	case StatusPending:
+		return common.ErrAccountPending()
+	case StatusLocked:
+		return common.ErrAccountLocked("账户已被锁定，请联系管理员")
+	case StatusDeleted:
+		return common.ErrAccountDeleted()
+	default:
+		return common.ErrAccountDisabled()
+	}
+}
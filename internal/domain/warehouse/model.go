@@ -0,0 +1,42 @@
+package warehouse
+
+import (
+	"time"
+)
+
+// 默认仓库（迁移时自动创建，未指定仓库的入库进入默认仓库）
+const (
+	DefaultCode = "MAIN"
+	DefaultName = "主仓库"
+)
+
+// Warehouse 仓库模型
+type Warehouse struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	Code      string    `gorm:"uniqueIndex;size:32;not null" json:"code"`
+	Name      string    `gorm:"size:100;not null" json:"name"`
+	Address   string    `gorm:"size:255" json:"address"`
+	IsDefault bool      `gorm:"default:false;comment:是否默认仓库" json:"is_default"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Warehouse) TableName() string {
+	return "warehouses"
+}
+
+// StockLevel 电源在某个仓库的库存数量，各仓库之和等于电源的库存数量
+type StockLevel struct {
+	ID            uint       `gorm:"primarykey" json:"id"`
+	WarehouseID   uint       `gorm:"uniqueIndex:idx_stock_levels_location;not null" json:"warehouse_id"`
+	PowerSupplyID uint       `gorm:"uniqueIndex:idx_stock_levels_location;index;not null" json:"power_supply_id"`
+	Quantity      int        `gorm:"not null;default:0" json:"quantity"`
+	Warehouse     *Warehouse `json:"warehouse,omitempty"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (StockLevel) TableName() string {
+	return "stock_levels"
+}
@@ -0,0 +1,38 @@
+package warehouse
+
+import (
+	"context"
+)
+
+// Reader 读取操作接口（接口隔离原则）
+type Reader interface {
+	FindByID(ctx context.Context, id uint) (*Warehouse, error)
+	// FindDefault 查询默认仓库
+	FindDefault(ctx context.Context) (*Warehouse, error)
+	List(ctx context.Context) ([]*Warehouse, error)
+	ExistsByCode(ctx context.Context, code string) (bool, error)
+}
+
+// Writer 写入操作接口（接口隔离原则）
+type Writer interface {
+	Create(ctx context.Context, w *Warehouse) error
+	UpdateByID(ctx context.Context, id uint, updates map[string]any) error
+	// SetDefault 将指定仓库设为默认仓库，并取消其他仓库的默认标记
+	SetDefault(ctx context.Context, id uint) error
+}
+
+// Repository 仓库仓储接口（组合 Reader 和 Writer）
+type Repository interface {
+	Reader
+	Writer
+}
+
+// StockLevelRepository 仓库库存仓储接口
+type StockLevelRepository interface {
+	// ListByPowerSupply 查询电源在各仓库的库存
+	ListByPowerSupply(ctx context.Context, powerSupplyID uint) ([]*StockLevel, error)
+	// ListInStock 查询电源有库存的仓库库存记录（库存多的在前）
+	ListInStock(ctx context.Context, powerSupplyID uint) ([]*StockLevel, error)
+	// Adjust 原子地调整电源在仓库的库存，扣减后为负数时不更新并返回 false
+	Adjust(ctx context.Context, warehouseID, powerSupplyID uint, delta int) (bool, error)
+}
@@ -0,0 +1,18 @@
+package warehouse
+
+// Service 层使用的请求类型（从 DTO 转换而来）
+
+// CreateRequest Service 层创建仓库请求
+type CreateRequest struct {
+	Code      string
+	Name      string
+	Address   string
+	IsDefault bool
+}
+
+// UpdateRequest Service 层更新仓库请求
+type UpdateRequest struct {
+	Name      string
+	Address   *string
+	IsDefault *bool
+}
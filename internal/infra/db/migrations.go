@@ -1,8 +1,23 @@
 package db
 
 import (
+	"power-supply-sys/internal/domain/apikey"
+	"power-supply-sys/internal/domain/audit"
+	"power-supply-sys/internal/domain/catalog"
+	"power-supply-sys/internal/domain/component"
+	"power-supply-sys/internal/domain/identity"
+	"power-supply-sys/internal/domain/inventory"
+	"power-supply-sys/internal/domain/lockout"
+	"power-supply-sys/internal/domain/mfa"
+	"power-supply-sys/internal/domain/order"
 	"power-supply-sys/internal/domain/power"
+	"power-supply-sys/internal/domain/purchase"
+	"power-supply-sys/internal/domain/rbac"
+	"power-supply-sys/internal/domain/supplier"
+	"power-supply-sys/internal/domain/token"
 	"power-supply-sys/internal/domain/user"
+	"power-supply-sys/internal/domain/warehouse"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -14,11 +29,200 @@ func Migrate(db *gorm.DB) error {
 		return err
 	}
 
+	// 迁移电源分类与标签表（电源表关联标签，需先迁移）
+	if err := db.AutoMigrate(&catalog.Category{}, &catalog.Tag{}); err != nil {
+		return err
+	}
+
 	// 迁移电源表
 	if err := db.AutoMigrate(&power.PowerSupply{}); err != nil {
 		return err
 	}
+	if err := normalizeEfficiency(db); err != nil {
+		return err
+	}
+
+	// 迁移刷新令牌、登录会话与密码重置令牌表
+	if err := db.AutoMigrate(&token.RefreshToken{}, &token.Session{}, &token.PasswordResetToken{}); err != nil {
+		return err
+	}
+
+	// 迁移登录失败计数表
+	if err := db.AutoMigrate(&lockout.Attempt{}); err != nil {
+		return err
+	}
 
+	// 迁移两步验证相关表
+	if err := db.AutoMigrate(&mfa.TwoFactor{}, &mfa.RecoveryCode{}, &mfa.Challenge{}); err != nil {
+		return err
+	}
+
+	// 迁移 API Key 表
+	if err := db.AutoMigrate(&apikey.APIKey{}); err != nil {
+		return err
+	}
+
+	// 迁移外部身份与单点登录状态表
+	if err := db.AutoMigrate(&identity.ExternalIdentity{}, &identity.LoginState{}); err != nil {
+		return err
+	}
+
+	// 迁移审计日志表
+	if err := db.AutoMigrate(&audit.Log{}); err != nil {
+		return err
+	}
+
+	// 迁移价格历史与计划调价表
+	if err := db.AutoMigrate(&power.PriceHistory{}, &power.PriceSchedule{}); err != nil {
+		return err
+	}
+	if err := seedPriceHistory(db); err != nil {
+		return err
+	}
+
+	// 迁移库存流水与库存预留表
+	if err := db.AutoMigrate(&inventory.Movement{}, &inventory.Reservation{}); err != nil {
+		return err
+	}
+
+	// 迁移仓库与仓库库存表
+	if err := db.AutoMigrate(&warehouse.Warehouse{}, &warehouse.StockLevel{}); err != nil {
+		return err
+	}
+	if err := seedWarehouses(db); err != nil {
+		return err
+	}
+
+	// 迁移订单与订单明细表
+	if err := db.AutoMigrate(&order.Order{}, &order.Item{}); err != nil {
+		return err
+	}
+
+	// 迁移供应商与采购单表
+	if err := db.AutoMigrate(&supplier.Supplier{}, &supplier.Product{}, &purchase.Order{}, &purchase.Line{}); err != nil {
+		return err
+	}
+
+	// 迁移组件功耗档案表
+	if err := db.AutoMigrate(&component.Component{}); err != nil {
+		return err
+	}
+	if err := seedComponents(db); err != nil {
+		return err
+	}
+
+	// 迁移角色权限表
+	if err := db.AutoMigrate(&rbac.Permission{}, &rbac.Role{}); err != nil {
+		return err
+	}
+	if err := seedRBAC(db); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// seedWarehouses 创建默认仓库，并将尚未分配到仓库的库存归入默认仓库
+// 启用多仓库之前的库存与库存流水都视为默认仓库的库存
+func seedWarehouses(db *gorm.DB) error {
+	def := warehouse.Warehouse{Code: warehouse.DefaultCode, Name: warehouse.DefaultName, IsDefault: true}
+	var count int64
+	if err := db.Model(&warehouse.Warehouse{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		if err := db.Create(&def).Error; err != nil {
+			return err
+		}
+	} else if err := db.Where("is_default = ?", true).First(&def).Error; err != nil {
+		return err
+	}
+
+	if err := db.Exec(
+		"INSERT INTO stock_levels (warehouse_id, power_supply_id, quantity, updated_at) "+
+			"SELECT ?, id, stock, ? FROM power_supplies WHERE stock > 0 AND id NOT IN (SELECT power_supply_id FROM stock_levels)",
+		def.ID, time.Now(),
+	).Error; err != nil {
+		return err
+	}
+	return db.Model(&inventory.Movement{}).Where("warehouse_id = ?", 0).Update("warehouse_id", def.ID).Error
+}
+
+// seedComponents 组件功耗档案为空时导入内置的常见 CPU 与显卡功耗数据
+func seedComponents(db *gorm.DB) error {
+	var count int64
+	if err := db.Model(&component.Component{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	return db.Create(component.DefaultComponents()).Error
+}
+
+// normalizeEfficiency 将已有电源的能效等级规范化为能效认证等级代码（如 "80Plus金牌" 规范化为 "80plus_gold"）
+// 无法识别的能效等级清空
+func normalizeEfficiency(db *gorm.DB) error {
+	var values []string
+	if err := db.Model(&power.PowerSupply{}).Where("efficiency <> ?", "").Distinct().Pluck("efficiency", &values).Error; err != nil {
+		return err
+	}
+	for _, v := range values {
+		efficiency, ok := power.ParseEfficiency(v)
+		if ok && string(efficiency) == v {
+			continue
+		}
+		if err := db.Model(&power.PowerSupply{}).Where("efficiency = ?", v).Update("efficiency", efficiency).Error; err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// seedPriceHistory 为没有价格历史的电源补录当前价格，生效时间为电源创建时间
+func seedPriceHistory(db *gorm.DB) error {
+	return db.Exec(
+		"INSERT INTO power_price_history (power_supply_id, price, effective_from, reason, created_at) "+
+			"SELECT id, price, created_at, ?, ? FROM power_supplies WHERE id NOT IN (SELECT power_supply_id FROM power_price_history)",
+		"初始价格", time.Now(),
+	).Error
+}
+
+// seedRBAC 写入内置权限与内置角色
+// 内置角色缺失的默认权限会被补齐，管理员始终拥有全部权限
+func seedRBAC(db *gorm.DB) error {
+	permissions := make(map[string]*rbac.Permission, len(rbac.DefaultPermissions))
+	for _, p := range rbac.DefaultPermissions {
+		perm := p
+		if err := db.Where(rbac.Permission{Code: perm.Code}).FirstOrCreate(&perm).Error; err != nil {
+			return err
+		}
+		permissions[perm.Code] = &perm
+	}
+
+	for _, def := range rbac.DefaultRoles {
+		role := def.Role
+		if err := db.Where(rbac.Role{Code: role.Code}).Attrs(role).FirstOrCreate(&role).Error; err != nil {
+			return err
+		}
+
+		var perms []*rbac.Permission
+		if role.Code == rbac.RoleAdmin {
+			if err := db.Find(&perms).Error; err != nil {
+				return err
+			}
+		} else {
+			for _, code := range def.Permissions {
+				perms = append(perms, permissions[code])
+			}
+		}
+		if len(perms) == 0 {
+			continue
+		}
+		if err := db.Model(&role).Association("Permissions").Append(perms); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
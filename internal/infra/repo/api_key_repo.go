@@ -0,0 +1,51 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/apikey"
+	"power-supply-sys/pkg/common"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// apiKeyRepository API Key 数据访问层实现
+type apiKeyRepository struct {
+	*common.BaseRepository[apikey.APIKey]
+}
+
+// NewAPIKeyRepository 创建 API Key 仓储
+func NewAPIKeyRepository(db *gorm.DB) apikey.Repository {
+	return &apiKeyRepository{
+		BaseRepository: common.NewBaseRepository[apikey.APIKey](db),
+	}
+}
+
+// FindByHash 根据 Key 摘要查询
+func (r *apiKeyRepository) FindByHash(ctx context.Context, keyHash string) (*apikey.APIKey, error) {
+	return r.FindOne(ctx, common.Where("key_hash", keyHash))
+}
+
+// ListByUserID 查询用户的全部 API Key（最新创建的在前）
+func (r *apiKeyRepository) ListByUserID(ctx context.Context, userID uint) ([]*apikey.APIKey, error) {
+	return r.List(ctx, common.Where("user_id", userID), common.OrderByDesc("id"))
+}
+
+// Revoke 吊销用户自己的 API Key（条件更新，只影响本人未吊销的 Key）
+func (r *apiKeyRepository) Revoke(ctx context.Context, userID, id uint, revokedAt time.Time) (bool, error) {
+	affected, err := r.BatchUpdate(ctx, map[string]any{"revoked_at": revokedAt},
+		common.Where("id", id),
+		common.Where("user_id", userID),
+		common.WhereNull("revoked_at"),
+	)
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// TouchLastUsed 更新最近使用时间
+func (r *apiKeyRepository) TouchLastUsed(ctx context.Context, id uint, usedAt time.Time) error {
+	_, err := r.BatchUpdate(ctx, map[string]any{"last_used_at": usedAt}, common.Where("id", id))
+	return err
+}
@@ -0,0 +1,72 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/apikey"
+	dbpkg "power-supply-sys/internal/infra/db"
+	"power-supply-sys/pkg/common"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyRepository(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	repo := NewAPIKeyRepository(db)
+	ctx := context.Background()
+
+	first := &apikey.APIKey{UserID: 1, Name: "first", KeyHash: "hash-1", Scopes: "power:read", ExpiresAt: time.Now().Add(time.Hour)}
+	second := &apikey.APIKey{UserID: 1, Name: "second", KeyHash: "hash-2", Scopes: "power:read", ExpiresAt: time.Now().Add(time.Hour)}
+	require.NoError(t, repo.Create(ctx, first))
+	require.NoError(t, repo.Create(ctx, second))
+
+	t.Run("按摘要查询", func(t *testing.T) {
+		found, err := repo.FindByHash(ctx, "hash-1")
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, found.ID)
+
+		_, err = repo.FindByHash(ctx, "missing")
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+
+	t.Run("列表按创建倒序", func(t *testing.T) {
+		keys, err := repo.ListByUserID(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+		assert.Equal(t, "second", keys[0].Name)
+
+		keys, err = repo.ListByUserID(ctx, 2)
+		require.NoError(t, err)
+		assert.Empty(t, keys)
+	})
+
+	t.Run("只能吊销本人未吊销的 Key", func(t *testing.T) {
+		ok, err := repo.Revoke(ctx, 2, first.ID, time.Now())
+		assert.NoError(t, err)
+		assert.False(t, ok)
+
+		ok, err = repo.Revoke(ctx, 1, first.ID, time.Now())
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = repo.Revoke(ctx, 1, first.ID, time.Now())
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("更新最近使用时间", func(t *testing.T) {
+		err := repo.TouchLastUsed(ctx, second.ID, time.Now())
+		require.NoError(t, err)
+
+		found, err := repo.FindByHash(ctx, "hash-2")
+		require.NoError(t, err)
+		assert.NotNil(t, found.LastUsedAt)
+	})
+}
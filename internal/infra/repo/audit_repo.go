@@ -0,0 +1,26 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/audit"
+	"power-supply-sys/pkg/common"
+
+	"gorm.io/gorm"
+)
+
+// auditRepository 审计日志数据访问层实现
+type auditRepository struct {
+	*common.BaseRepository[audit.Log]
+}
+
+// NewAuditRepository 创建审计日志仓储
+func NewAuditRepository(db *gorm.DB) audit.Repository {
+	return &auditRepository{
+		BaseRepository: common.NewBaseRepository[audit.Log](db),
+	}
+}
+
+// ListByActor 查询实际操作者的审计日志（最新的在前）
+func (r *auditRepository) ListByActor(ctx context.Context, actorID uint) ([]*audit.Log, error) {
+	return r.List(ctx, common.Where("actor_id", actorID), common.OrderByDesc("id"))
+}
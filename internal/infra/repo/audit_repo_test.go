@@ -0,0 +1,34 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/audit"
+	dbpkg "power-supply-sys/internal/infra/db"
+	"power-supply-sys/pkg/common"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditRepository(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	repo := NewAuditRepository(db)
+	ctx := context.Background()
+
+	require.NoError(t, repo.Create(ctx, &audit.Log{Action: audit.ActionImpersonate, ActorID: 1, UserID: 2}))
+	require.NoError(t, repo.Create(ctx, &audit.Log{Action: audit.ActionWrite, ActorID: 1, UserID: 2, Method: "PUT", Path: "/api/v1/users/me", StatusCode: 200}))
+	require.NoError(t, repo.Create(ctx, &audit.Log{Action: audit.ActionImpersonate, ActorID: 3, UserID: 2}))
+
+	logs, err := repo.ListByActor(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+	assert.Equal(t, audit.ActionWrite, logs[0].Action)
+	assert.Equal(t, "PUT", logs[0].Method)
+	assert.Equal(t, audit.ActionImpersonate, logs[1].Action)
+}
@@ -0,0 +1,67 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/catalog"
+	"power-supply-sys/pkg/common"
+
+	"gorm.io/gorm"
+)
+
+// categoryRepository 电源分类数据访问层实现
+type categoryRepository struct {
+	*common.BaseRepository[catalog.Category]
+}
+
+// NewCategoryRepository 创建分类仓储
+func NewCategoryRepository(db *gorm.DB) catalog.CategoryRepository {
+	return &categoryRepository{
+		BaseRepository: common.NewBaseRepository[catalog.Category](db),
+	}
+}
+
+// FindByID 根据ID查询分类
+func (r *categoryRepository) FindByID(ctx context.Context, id uint) (*catalog.Category, error) {
+	c, err := r.BaseRepository.FindByID(ctx, id)
+	if err != nil && common.HasErrorCode(err, common.ErrCodeNotFound) {
+		return nil, common.ErrNotFound("分类")
+	}
+	return c, err
+}
+
+// ListAll 查询全部分类（按排序值与 ID 排序）
+func (r *categoryRepository) ListAll(ctx context.Context) ([]*catalog.Category, error) {
+	return r.BaseRepository.List(ctx, common.OrderByMulti("sort_order", "id"))
+}
+
+// ExistsByName 检查同一父分类下是否已有同名分类（不区分大小写）
+func (r *categoryRepository) ExistsByName(ctx context.Context, parentID *uint, name string) (bool, error) {
+	parent := common.WhereNull("parent_id")
+	if parentID != nil {
+		parent = common.Where("parent_id", *parentID)
+	}
+	return r.Exists(ctx, parent, common.WhereRaw("LOWER(name) = LOWER(?)", name))
+}
+
+// HasChildren 检查分类下是否有子分类
+func (r *categoryRepository) HasChildren(ctx context.Context, id uint) (bool, error) {
+	return r.Exists(ctx, common.Where("parent_id", id))
+}
+
+// UpdateByID 更新分类
+func (r *categoryRepository) UpdateByID(ctx context.Context, id uint, updates map[string]any) error {
+	err := r.BaseRepository.UpdateByID(ctx, id, updates)
+	if err != nil && common.HasErrorCode(err, common.ErrCodeNotFound) {
+		return common.ErrNotFound("分类")
+	}
+	return err
+}
+
+// Delete 删除分类
+func (r *categoryRepository) Delete(ctx context.Context, id uint) error {
+	err := r.BaseRepository.Delete(ctx, id)
+	if err != nil && common.HasErrorCode(err, common.ErrCodeNotFound) {
+		return common.ErrNotFound("分类")
+	}
+	return err
+}
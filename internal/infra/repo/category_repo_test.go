@@ -0,0 +1,75 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/catalog"
+	dbpkg "power-supply-sys/internal/infra/db"
+	"power-supply-sys/pkg/common"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCategoryRepository(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	repo := NewCategoryRepository(db)
+	ctx := context.Background()
+
+	consumer := &catalog.Category{Name: "Consumer", SortOrder: 2}
+	server := &catalog.Category{Name: "Server", SortOrder: 1}
+	require.NoError(t, repo.Create(ctx, consumer))
+	require.NoError(t, repo.Create(ctx, server))
+	atx := &catalog.Category{ParentID: &consumer.ID, Name: "ATX"}
+	require.NoError(t, repo.Create(ctx, atx))
+
+	t.Run("按排序值查询全部分类", func(t *testing.T) {
+		list, err := repo.ListAll(ctx)
+		require.NoError(t, err)
+		require.Len(t, list, 3)
+		assert.Equal(t, "ATX", list[0].Name)
+		assert.Equal(t, "Server", list[1].Name)
+
+		tree := catalog.BuildTree(list)
+		require.Len(t, tree, 2)
+		assert.Equal(t, "Server", tree[0].Name)
+		require.Len(t, tree[1].Children, 1)
+		assert.Equal(t, atx.ID, tree[1].Children[0].ID)
+	})
+
+	t.Run("同级名称检查", func(t *testing.T) {
+		exists, err := repo.ExistsByName(ctx, nil, "consumer")
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		exists, err = repo.ExistsByName(ctx, &consumer.ID, "Consumer")
+		require.NoError(t, err)
+		assert.False(t, exists)
+
+		exists, err = repo.ExistsByName(ctx, &consumer.ID, "atx")
+		require.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("子分类与删除", func(t *testing.T) {
+		has, err := repo.HasChildren(ctx, consumer.ID)
+		require.NoError(t, err)
+		assert.True(t, has)
+
+		require.NoError(t, repo.UpdateByID(ctx, atx.ID, map[string]any{"parent_id": nil}))
+		has, err = repo.HasChildren(ctx, consumer.ID)
+		require.NoError(t, err)
+		assert.False(t, has)
+
+		require.NoError(t, repo.Delete(ctx, atx.ID))
+		_, err = repo.FindByID(ctx, atx.ID)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+		err = repo.Delete(ctx, atx.ID)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+}
@@ -0,0 +1,87 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/component"
+	"power-supply-sys/pkg/common"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// componentRepository 组件功耗档案数据访问层实现
+type componentRepository struct {
+	*common.BaseRepository[component.Component]
+}
+
+// NewComponentRepository 创建组件仓储
+func NewComponentRepository(db *gorm.DB) component.Repository {
+	return &componentRepository{
+		BaseRepository: common.NewBaseRepository[component.Component](db),
+	}
+}
+
+// FindByID 根据ID查询组件
+func (r *componentRepository) FindByID(ctx context.Context, id uint) (*component.Component, error) {
+	c, err := r.BaseRepository.FindByID(ctx, id)
+	if err != nil && common.HasErrorCode(err, common.ErrCodeNotFound) {
+		return nil, common.ErrNotFound("组件")
+	}
+	return c, err
+}
+
+// FindByName 按类型与型号查询组件（型号不区分大小写）
+func (r *componentRepository) FindByName(ctx context.Context, componentType, name string) (*component.Component, error) {
+	c, err := r.FindOne(ctx,
+		common.Where("type", componentType),
+		common.WhereRaw("LOWER(name) = LOWER(?)", name),
+	)
+	if err != nil && common.HasErrorCode(err, common.ErrCodeNotFound) {
+		return nil, common.ErrNotFound("组件")
+	}
+	return c, err
+}
+
+// Count 统计组件数量
+func (r *componentRepository) Count(ctx context.Context, query *component.QueryOptions) (int64, error) {
+	return r.BaseRepository.Count(ctx, r.buildQueryOptions(query)...)
+}
+
+// List 查询组件列表（按类型与型号排序）
+func (r *componentRepository) List(ctx context.Context, query *component.QueryOptions) ([]*component.Component, error) {
+	opts := append(r.buildQueryOptions(query),
+		common.OrderByMulti("type", "name"),
+		common.Paginate(query.Page, query.PageSize),
+	)
+	return r.BaseRepository.List(ctx, opts...)
+}
+
+// Delete 删除组件
+func (r *componentRepository) Delete(ctx context.Context, id uint) error {
+	err := r.BaseRepository.Delete(ctx, id)
+	if err != nil && common.HasErrorCode(err, common.ErrCodeNotFound) {
+		return common.ErrNotFound("组件")
+	}
+	return err
+}
+
+// Save 创建或更新组件（按类型与型号唯一）
+func (r *componentRepository) Save(ctx context.Context, c *component.Component) error {
+	err := r.GetDB(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "type"}, {Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"brand", "watts", "transient_factor", "updated_at"}),
+	}).Create(c).Error
+	if err != nil {
+		return common.ErrDatabase(err)
+	}
+	return nil
+}
+
+// buildQueryOptions 构建查询条件
+func (r *componentRepository) buildQueryOptions(query *component.QueryOptions) []common.QueryOption {
+	return []common.QueryOption{
+		common.WhereIf(query.Type != "", "type", query.Type),
+		common.WhereLike("name", query.Name),
+		common.WhereLike("brand", query.Brand),
+	}
+}
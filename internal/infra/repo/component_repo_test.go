@@ -0,0 +1,63 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/component"
+	dbpkg "power-supply-sys/internal/infra/db"
+	"power-supply-sys/pkg/common"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComponentRepository(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	repo := NewComponentRepository(db)
+	ctx := context.Background()
+
+	t.Run("迁移导入内置功耗档案", func(t *testing.T) {
+		total, err := repo.Count(ctx, &component.QueryOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(component.DefaultComponents())), total)
+
+		gpus, err := repo.List(ctx, &component.QueryOptions{Type: component.TypeGPU, Brand: "AMD"})
+		require.NoError(t, err)
+		require.NotEmpty(t, gpus)
+		for _, c := range gpus {
+			assert.Equal(t, component.TypeGPU, c.Type)
+			assert.Equal(t, "AMD", c.Brand)
+		}
+	})
+
+	t.Run("按型号查询不区分大小写", func(t *testing.T) {
+		c, err := repo.FindByName(ctx, component.TypeGPU, "geforce rtx 3090")
+		require.NoError(t, err)
+		assert.Equal(t, "GeForce RTX 3090", c.Name)
+		assert.Equal(t, 2.0, c.TransientFactor)
+
+		// 类型不同时查询不到
+		_, err = repo.FindByName(ctx, component.TypeCPU, "GeForce RTX 3090")
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+
+	t.Run("按类型与型号覆盖", func(t *testing.T) {
+		require.NoError(t, repo.Save(ctx, &component.Component{Type: component.TypeCPU, Name: "Xeon W-3495X", Brand: "Intel", Watts: 350}))
+		require.NoError(t, repo.Save(ctx, &component.Component{Type: component.TypeCPU, Name: "Xeon W-3495X", Brand: "Intel", Watts: 420, TransientFactor: 1.3}))
+
+		list, err := repo.List(ctx, &component.QueryOptions{Name: "Xeon"})
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, 420, list[0].Watts)
+		assert.Equal(t, 1.3, list[0].TransientFactor)
+
+		require.NoError(t, repo.Delete(ctx, list[0].ID))
+		err = repo.Delete(ctx, list[0].ID)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+}
@@ -0,0 +1,65 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/identity"
+	"power-supply-sys/pkg/common"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// identityRepository 外部身份数据访问层实现
+type identityRepository struct {
+	identities *common.BaseRepository[identity.ExternalIdentity]
+	states     *common.BaseRepository[identity.LoginState]
+}
+
+// NewIdentityRepository 创建外部身份仓储
+func NewIdentityRepository(db *gorm.DB) identity.Repository {
+	return &identityRepository{
+		identities: common.NewBaseRepository[identity.ExternalIdentity](db),
+		states:     common.NewBaseRepository[identity.LoginState](db),
+	}
+}
+
+// FindIdentity 根据提供方与 subject 查询外部身份
+func (r *identityRepository) FindIdentity(ctx context.Context, provider, subject string) (*identity.ExternalIdentity, error) {
+	i, err := r.identities.FindOne(ctx, common.Where("provider", provider), common.Where("subject", subject))
+	if err != nil && common.HasErrorCode(err, common.ErrCodeNotFound) {
+		return nil, common.ErrNotFound("外部身份")
+	}
+	return i, err
+}
+
+// FindStateByHash 根据 state 摘要查询登录状态
+func (r *identityRepository) FindStateByHash(ctx context.Context, stateHash string) (*identity.LoginState, error) {
+	return r.states.FindOne(ctx, common.Where("state_hash", stateHash))
+}
+
+// CreateIdentity 创建外部身份绑定
+func (r *identityRepository) CreateIdentity(ctx context.Context, i *identity.ExternalIdentity) error {
+	return r.identities.Create(ctx, i)
+}
+
+// TouchIdentity 记录最近登录时间与提供方返回的邮箱
+func (r *identityRepository) TouchIdentity(ctx context.Context, id uint, email string, loginAt time.Time) error {
+	return r.identities.UpdateByID(ctx, id, map[string]any{"email": email, "last_login_at": loginAt})
+}
+
+// CreateState 保存登录状态
+func (r *identityRepository) CreateState(ctx context.Context, s *identity.LoginState) error {
+	return r.states.Create(ctx, s)
+}
+
+// MarkStateUsed 将登录状态标记为已使用（条件更新，保证只能使用一次）
+func (r *identityRepository) MarkStateUsed(ctx context.Context, id uint, usedAt time.Time) (bool, error) {
+	affected, err := r.states.BatchUpdate(ctx, map[string]any{"used_at": usedAt},
+		common.Where("id", id),
+		common.WhereNull("used_at"),
+	)
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
@@ -0,0 +1,82 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/identity"
+	dbpkg "power-supply-sys/internal/infra/db"
+	"power-supply-sys/pkg/common"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdentityRepository_Identities(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	repo := NewIdentityRepository(db)
+	ctx := context.Background()
+
+	linked := &identity.ExternalIdentity{UserID: 1, Provider: "corp", Subject: "sub-1", Email: "old@example.com"}
+	require.NoError(t, repo.CreateIdentity(ctx, linked))
+
+	t.Run("按提供方与 subject 查询", func(t *testing.T) {
+		found, err := repo.FindIdentity(ctx, "corp", "sub-1")
+		require.NoError(t, err)
+		assert.Equal(t, uint(1), found.UserID)
+
+		_, err = repo.FindIdentity(ctx, "other", "sub-1")
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+
+	t.Run("同一提供方的 subject 唯一", func(t *testing.T) {
+		err := repo.CreateIdentity(ctx, &identity.ExternalIdentity{UserID: 2, Provider: "corp", Subject: "sub-1"})
+		assert.Error(t, err)
+
+		err = repo.CreateIdentity(ctx, &identity.ExternalIdentity{UserID: 2, Provider: "other", Subject: "sub-1"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("记录最近登录", func(t *testing.T) {
+		now := time.Now()
+		require.NoError(t, repo.TouchIdentity(ctx, linked.ID, "new@example.com", now))
+
+		found, err := repo.FindIdentity(ctx, "corp", "sub-1")
+		require.NoError(t, err)
+		assert.Equal(t, "new@example.com", found.Email)
+		require.NotNil(t, found.LastLoginAt)
+		assert.WithinDuration(t, now, *found.LastLoginAt, time.Second)
+	})
+}
+
+func TestIdentityRepository_States(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	repo := NewIdentityRepository(db)
+	ctx := context.Background()
+
+	state := &identity.LoginState{Provider: "corp", StateHash: "hash-1", Nonce: "nonce", CodeVerifier: "verifier", ExpiresAt: time.Now().Add(time.Minute)}
+	require.NoError(t, repo.CreateState(ctx, state))
+
+	found, err := repo.FindStateByHash(ctx, "hash-1")
+	require.NoError(t, err)
+	assert.Equal(t, "verifier", found.CodeVerifier)
+
+	ok, err := repo.MarkStateUsed(ctx, state.ID, time.Now())
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// 登录状态只能使用一次
+	ok, err = repo.MarkStateUsed(ctx, state.ID, time.Now())
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
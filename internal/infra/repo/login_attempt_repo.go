@@ -0,0 +1,163 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/lockout"
+	"power-supply-sys/pkg/common"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// loginAttemptRepository 登录失败计数数据库实现（多实例部署时共享计数）
+type loginAttemptRepository struct {
+	*common.BaseRepository[lockout.Attempt]
+}
+
+// NewLoginAttemptRepository 创建数据库登录失败计数存储
+func NewLoginAttemptRepository(db *gorm.DB) lockout.Store {
+	return &loginAttemptRepository{
+		BaseRepository: common.NewBaseRepository[lockout.Attempt](db),
+	}
+}
+
+// Get 查询计数，不存在时返回 nil
+func (r *loginAttemptRepository) Get(ctx context.Context, key string) (*lockout.Attempt, error) {
+	attempt, err := r.FindOne(ctx, common.Where("attempt_key", key))
+	if err != nil {
+		if common.HasErrorCode(err, common.ErrCodeNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return attempt, nil
+}
+
+// Increment 累加失败次数（使用 upsert 保证并发下计数准确）
+func (r *loginAttemptRepository) Increment(ctx context.Context, key string, now time.Time, window time.Duration) (*lockout.Attempt, error) {
+	expiredBefore := now.Add(-window)
+	attempt := &lockout.Attempt{Key: key, Failures: 1, WindowStart: now}
+
+	// failures 必须先于 window_start 更新：MySQL 按顺序求值，后面的表达式会读取前面已更新的值
+	err := r.GetDB(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "attempt_key"}},
+		DoUpdates: clause.Set{
+			{Column: clause.Column{Name: "failures"}, Value: gorm.Expr("CASE WHEN window_start < ? THEN 1 ELSE failures + 1 END", expiredBefore)},
+			{Column: clause.Column{Name: "window_start"}, Value: gorm.Expr("CASE WHEN window_start < ? THEN ? ELSE window_start END", expiredBefore, now)},
+			{Column: clause.Column{Name: "updated_at"}, Value: now},
+		},
+	}).Create(attempt).Error
+	if err != nil {
+		return nil, common.ErrDatabase(err)
+	}
+
+	return r.FindOne(ctx, common.Where("attempt_key", key))
+}
+
+// Lock 锁定至指定时间，并清零失败次数
+func (r *loginAttemptRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	_, err := r.BatchUpdate(ctx, map[string]any{"locked_until": until, "failures": 0},
+		common.Where("attempt_key", key),
+	)
+	return err
+}
+
+// Reset 清除计数与锁定
+func (r *loginAttemptRepository) Reset(ctx context.Context, key string) error {
+	err := r.DeleteByCondition(ctx, common.Where("attempt_key", key))
+	if err != nil && !common.HasErrorCode(err, common.ErrCodeNotFound) {
+		return err
+	}
+	return nil
+}
+
+// memoryLoginAttemptStore 登录失败计数内存实现（单实例部署或测试使用）
+// 每经过一个统计窗口清理一次窗口与锁定都已过期的计数，避免大量不同的用户名与 IP 占满内存
+type memoryLoginAttemptStore struct {
+	mu        sync.Mutex
+	attempts  map[string]*lockout.Attempt
+	lastSweep time.Time
+}
+
+// NewMemoryLoginAttemptStore 创建内存登录失败计数存储
+func NewMemoryLoginAttemptStore() lockout.Store {
+	return &memoryLoginAttemptStore{
+		attempts: make(map[string]*lockout.Attempt),
+	}
+}
+
+// Get 查询计数，不存在时返回 nil
+func (s *memoryLoginAttemptStore) Get(ctx context.Context, key string) (*lockout.Attempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempt, ok := s.attempts[key]
+	if !ok {
+		return nil, nil
+	}
+	// 锁定时失败次数已清零，锁定过期后计数不再有意义
+	if attempt.Failures == 0 && attempt.LockedUntil != nil && !attempt.IsLocked(time.Now()) {
+		delete(s.attempts, key)
+		return nil, nil
+	}
+	copied := *attempt
+	return &copied, nil
+}
+
+// Increment 累加失败次数，统计窗口已过期时重新计数
+func (s *memoryLoginAttemptStore) Increment(ctx context.Context, key string, now time.Time, window time.Duration) (*lockout.Attempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) >= window {
+		s.sweep(now, window)
+	}
+
+	attempt, ok := s.attempts[key]
+	if !ok {
+		attempt = &lockout.Attempt{Key: key, WindowStart: now}
+		s.attempts[key] = attempt
+	}
+	if attempt.WindowStart.Before(now.Add(-window)) {
+		attempt.Failures = 0
+		attempt.WindowStart = now
+	}
+	attempt.Failures++
+	attempt.UpdatedAt = now
+
+	copied := *attempt
+	return &copied, nil
+}
+
+// sweep 删除统计窗口与锁定都已过期的计数，调用方需持有锁
+func (s *memoryLoginAttemptStore) sweep(now time.Time, window time.Duration) {
+	for key, attempt := range s.attempts {
+		if attempt.WindowStart.Before(now.Add(-window)) && !attempt.IsLocked(now) {
+			delete(s.attempts, key)
+		}
+	}
+	s.lastSweep = now
+}
+
+// Lock 锁定至指定时间，并清零失败次数
+func (s *memoryLoginAttemptStore) Lock(ctx context.Context, key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if attempt, ok := s.attempts[key]; ok {
+		attempt.LockedUntil = &until
+		attempt.Failures = 0
+	}
+	return nil
+}
+
+// Reset 清除计数与锁定
+func (s *memoryLoginAttemptStore) Reset(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.attempts, key)
+	return nil
+}
@@ -0,0 +1,94 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/lockout"
+	dbpkg "power-supply-sys/internal/infra/db"
+	"power-supply-sys/pkg/common"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginAttemptStores(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	stores := map[string]lockout.Store{
+		"内存存储":  NewMemoryLoginAttemptStore(),
+		"数据库存储": NewLoginAttemptRepository(db),
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			now := time.Now()
+			key := lockout.UserKey("Alice")
+
+			attempt, err := store.Get(ctx, key)
+			require.NoError(t, err)
+			assert.Nil(t, attempt)
+
+			// 窗口内累加
+			for i := 1; i <= 3; i++ {
+				attempt, err = store.Increment(ctx, key, now, time.Minute)
+				require.NoError(t, err)
+				assert.Equal(t, i, attempt.Failures)
+			}
+
+			// 窗口过期后重新计数
+			attempt, err = store.Increment(ctx, key, now.Add(2*time.Minute), time.Minute)
+			require.NoError(t, err)
+			assert.Equal(t, 1, attempt.Failures)
+
+			// 锁定并清零失败次数
+			err = store.Lock(ctx, key, now.Add(time.Hour))
+			require.NoError(t, err)
+			attempt, err = store.Get(ctx, key)
+			require.NoError(t, err)
+			require.NotNil(t, attempt)
+			assert.True(t, attempt.IsLocked(now))
+			assert.Equal(t, 0, attempt.Failures)
+
+			// 清除计数与锁定（重复清除不报错）
+			require.NoError(t, store.Reset(ctx, key))
+			require.NoError(t, store.Reset(ctx, key))
+			attempt, err = store.Get(ctx, key)
+			require.NoError(t, err)
+			assert.Nil(t, attempt)
+		})
+	}
+}
+
+func TestMemoryLoginAttemptStore_Sweep(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryLoginAttemptStore().(*memoryLoginAttemptStore)
+	now := time.Now()
+
+	for _, ip := range []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"} {
+		_, err := store.Increment(ctx, lockout.IPKey(ip), now, time.Minute)
+		require.NoError(t, err)
+	}
+	require.NoError(t, store.Lock(ctx, lockout.IPKey("10.0.0.3"), now.Add(time.Hour)))
+	assert.Len(t, store.attempts, 3)
+
+	// 窗口过期后清理，仍在锁定中的计数保留
+	_, err := store.Increment(ctx, lockout.IPKey("10.0.0.4"), now.Add(2*time.Minute), time.Minute)
+	require.NoError(t, err)
+	assert.Len(t, store.attempts, 2)
+	attempt, err := store.Get(ctx, lockout.IPKey("10.0.0.1"))
+	require.NoError(t, err)
+	assert.Nil(t, attempt)
+
+	// 锁定过期后查询时删除
+	require.NoError(t, store.Lock(ctx, lockout.IPKey("10.0.0.3"), now.Add(-time.Second)))
+	attempt, err = store.Get(ctx, lockout.IPKey("10.0.0.3"))
+	require.NoError(t, err)
+	assert.Nil(t, attempt)
+	assert.Len(t, store.attempts, 1)
+}
@@ -0,0 +1,147 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/mfa"
+	"power-supply-sys/pkg/common"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// mfaRepository 两步验证数据访问层实现
+type mfaRepository struct {
+	factors    *common.BaseRepository[mfa.TwoFactor]
+	codes      *common.BaseRepository[mfa.RecoveryCode]
+	challenges *common.BaseRepository[mfa.Challenge]
+}
+
+// NewMFARepository 创建两步验证仓储
+func NewMFARepository(db *gorm.DB) mfa.Repository {
+	return &mfaRepository{
+		factors:    common.NewBaseRepository[mfa.TwoFactor](db),
+		codes:      common.NewBaseRepository[mfa.RecoveryCode](db),
+		challenges: common.NewBaseRepository[mfa.Challenge](db),
+	}
+}
+
+// FindByUserID 查询用户的两步验证配置
+func (r *mfaRepository) FindByUserID(ctx context.Context, userID uint) (*mfa.TwoFactor, error) {
+	tf, err := r.factors.FindOne(ctx, common.Where("user_id", userID))
+	if err != nil && common.HasErrorCode(err, common.ErrCodeNotFound) {
+		return nil, common.ErrNotFound("两步验证配置")
+	}
+	return tf, err
+}
+
+// CountUnusedRecoveryCodes 统计未使用的恢复码数量
+func (r *mfaRepository) CountUnusedRecoveryCodes(ctx context.Context, userID uint) (int64, error) {
+	return r.codes.Count(ctx, common.Where("user_id", userID), common.WhereNull("used_at"))
+}
+
+// FindChallengeByHash 根据令牌摘要查询登录挑战
+func (r *mfaRepository) FindChallengeByHash(ctx context.Context, tokenHash string) (*mfa.Challenge, error) {
+	return r.challenges.FindOne(ctx, common.Where("token_hash", tokenHash))
+}
+
+// Save 创建或覆盖用户的两步验证配置（覆盖时重置为未启用状态）
+func (r *mfaRepository) Save(ctx context.Context, tf *mfa.TwoFactor) error {
+	existing, err := r.factors.FindOne(ctx, common.Where("user_id", tf.UserID))
+	if err != nil {
+		if common.HasErrorCode(err, common.ErrCodeNotFound) {
+			return r.factors.Create(ctx, tf)
+		}
+		return err
+	}
+
+	tf.ID = existing.ID
+	tf.CreatedAt = existing.CreatedAt
+	return r.factors.Update(ctx, existing, map[string]any{
+		"secret":         tf.Secret,
+		"enabled":        tf.Enabled,
+		"last_used_step": tf.LastUsedStep,
+		"confirmed_at":   tf.ConfirmedAt,
+	})
+}
+
+// Enable 启用两步验证
+func (r *mfaRepository) Enable(ctx context.Context, userID uint, confirmedAt time.Time) error {
+	_, err := r.factors.BatchUpdate(ctx, map[string]any{"enabled": true, "confirmed_at": confirmedAt},
+		common.Where("user_id", userID),
+	)
+	return err
+}
+
+// Delete 删除用户的两步验证配置及恢复码
+func (r *mfaRepository) Delete(ctx context.Context, userID uint) error {
+	if err := r.codes.DeleteByCondition(ctx, common.Where("user_id", userID)); err != nil && !common.HasErrorCode(err, common.ErrCodeNotFound) {
+		return err
+	}
+	if err := r.factors.DeleteByCondition(ctx, common.Where("user_id", userID)); err != nil && !common.HasErrorCode(err, common.ErrCodeNotFound) {
+		return err
+	}
+	return nil
+}
+
+// UseStep 记录已使用的时间步（条件更新，同一时间步只能使用一次）
+func (r *mfaRepository) UseStep(ctx context.Context, userID uint, step int64) (bool, error) {
+	affected, err := r.factors.BatchUpdate(ctx, map[string]any{"last_used_step": step},
+		common.Where("user_id", userID),
+		common.WhereLT("last_used_step", step),
+	)
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// ReplaceRecoveryCodes 删除旧恢复码并写入新恢复码
+func (r *mfaRepository) ReplaceRecoveryCodes(ctx context.Context, userID uint, codeHashes []string) error {
+	if err := r.codes.DeleteByCondition(ctx, common.Where("user_id", userID)); err != nil && !common.HasErrorCode(err, common.ErrCodeNotFound) {
+		return err
+	}
+
+	codes := make([]*mfa.RecoveryCode, 0, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes = append(codes, &mfa.RecoveryCode{UserID: userID, CodeHash: hash})
+	}
+	return r.codes.BatchCreate(ctx, codes)
+}
+
+// UseRecoveryCode 使用一个未使用的恢复码（条件更新，保证只能使用一次）
+func (r *mfaRepository) UseRecoveryCode(ctx context.Context, userID uint, codeHash string, usedAt time.Time) (bool, error) {
+	affected, err := r.codes.BatchUpdate(ctx, map[string]any{"used_at": usedAt},
+		common.Where("user_id", userID),
+		common.Where("code_hash", codeHash),
+		common.WhereNull("used_at"),
+	)
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// CreateChallenge 创建登录挑战
+func (r *mfaRepository) CreateChallenge(ctx context.Context, c *mfa.Challenge) error {
+	return r.challenges.Create(ctx, c)
+}
+
+// IncrementChallengeAttempts 累加登录挑战的验证失败次数
+func (r *mfaRepository) IncrementChallengeAttempts(ctx context.Context, id uint) error {
+	_, err := r.challenges.BatchUpdate(ctx, map[string]any{"attempts": gorm.Expr("attempts + 1")},
+		common.Where("id", id),
+	)
+	return err
+}
+
+// MarkChallengeUsed 将登录挑战标记为已使用（条件更新，保证只能使用一次）
+func (r *mfaRepository) MarkChallengeUsed(ctx context.Context, id uint, usedAt time.Time) (bool, error) {
+	affected, err := r.challenges.BatchUpdate(ctx, map[string]any{"used_at": usedAt},
+		common.Where("id", id),
+		common.WhereNull("used_at"),
+	)
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
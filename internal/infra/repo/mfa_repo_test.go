@@ -0,0 +1,89 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/mfa"
+	dbpkg "power-supply-sys/internal/infra/db"
+	"power-supply-sys/pkg/common"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMFARepository_Factor(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	repo := NewMFARepository(db)
+	ctx := context.Background()
+
+	t.Run("未登记时返回不存在", func(t *testing.T) {
+		_, err := repo.FindByUserID(ctx, 1)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+
+	t.Run("重新登记覆盖旧密钥", func(t *testing.T) {
+		require.NoError(t, repo.Save(ctx, &mfa.TwoFactor{UserID: 1, Secret: "OLD"}))
+		require.NoError(t, repo.Enable(ctx, 1, time.Now()))
+		require.NoError(t, repo.Save(ctx, &mfa.TwoFactor{UserID: 1, Secret: "NEW"}))
+
+		tf, err := repo.FindByUserID(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "NEW", tf.Secret)
+		assert.False(t, tf.Enabled)
+	})
+
+	t.Run("时间步只能递增使用", func(t *testing.T) {
+		ok, err := repo.UseStep(ctx, 1, 100)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = repo.UseStep(ctx, 1, 100)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+
+		ok, err = repo.UseStep(ctx, 1, 99)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func TestMFARepository_RecoveryCodes(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	repo := NewMFARepository(db)
+	ctx := context.Background()
+
+	require.NoError(t, repo.ReplaceRecoveryCodes(ctx, 1, []string{"a", "b", "c"}))
+
+	ok, err := repo.UseRecoveryCode(ctx, 1, "a", time.Now())
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = repo.UseRecoveryCode(ctx, 1, "a", time.Now())
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	count, err := repo.CountUnusedRecoveryCodes(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	// 重新生成后旧恢复码作废
+	require.NoError(t, repo.ReplaceRecoveryCodes(ctx, 1, []string{"d"}))
+	ok, err = repo.UseRecoveryCode(ctx, 1, "b", time.Now())
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	count, err = repo.CountUnusedRecoveryCodes(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
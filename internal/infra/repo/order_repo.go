@@ -0,0 +1,86 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/inventory"
+	"power-supply-sys/internal/domain/order"
+	"power-supply-sys/pkg/common"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// orderRepository 订单数据访问层实现
+type orderRepository struct {
+	*common.BaseRepository[order.Order]
+}
+
+// NewOrderRepository 创建订单仓储
+func NewOrderRepository(db *gorm.DB) order.Repository {
+	return &orderRepository{
+		BaseRepository: common.NewBaseRepository[order.Order](db),
+	}
+}
+
+// FindByID 根据ID查询订单（含订单明细）
+func (r *orderRepository) FindByID(ctx context.Context, id uint) (*order.Order, error) {
+	o, err := r.FindOne(ctx, common.Where("id", id), common.Preload("Items"))
+	if err != nil && common.HasErrorCode(err, common.ErrCodeNotFound) {
+		return nil, common.ErrNotFound("订单")
+	}
+	return o, err
+}
+
+// Count 统计订单数量
+func (r *orderRepository) Count(ctx context.Context, query *order.QueryOptions) (int64, error) {
+	return r.BaseRepository.Count(ctx, r.buildQueryOptions(query)...)
+}
+
+// List 查询订单列表（含订单明细，最新的在前）
+func (r *orderRepository) List(ctx context.Context, query *order.QueryOptions) ([]*order.Order, error) {
+	opts := append(r.buildQueryOptions(query),
+		common.Preload("Items"),
+		common.OrderByDesc("id"),
+		common.Paginate(query.Page, query.PageSize),
+	)
+	return r.BaseRepository.List(ctx, opts...)
+}
+
+// ListReservationExpired 查询库存预留已过期或已释放的待支付订单（含订单明细，最早的在前）
+func (r *orderRepository) ListReservationExpired(ctx context.Context, now time.Time, limit int) ([]*order.Order, error) {
+	return r.BaseRepository.List(ctx,
+		common.Where("status", order.StatusCreated),
+		common.WhereRaw(`EXISTS (SELECT 1 FROM order_items
+			LEFT JOIN stock_reservations ON stock_reservations.id = order_items.reservation_id
+			WHERE order_items.order_id = orders.id
+			AND (stock_reservations.id IS NULL OR stock_reservations.status <> ? OR stock_reservations.expires_at <= ?))`,
+			inventory.ReservationPending, now),
+		common.Preload("Items"),
+		common.OrderBy("id"),
+		common.Limit(limit),
+	)
+}
+
+// UpdateStatus 条件更新订单状态并记录流转时间，并发流转时只有一个能成功
+func (r *orderRepository) UpdateStatus(ctx context.Context, id uint, from, to string, at time.Time) (bool, error) {
+	updates := map[string]any{"status": to}
+	if column := order.StatusTimeColumn(to); column != "" {
+		updates[column] = at
+	}
+	affected, err := r.BatchUpdate(ctx, updates,
+		common.Where("id", id),
+		common.Where("status", from),
+	)
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// buildQueryOptions 构建查询条件
+func (r *orderRepository) buildQueryOptions(query *order.QueryOptions) []common.QueryOption {
+	return []common.QueryOption{
+		common.WhereIfNotNil("user_id", query.UserID),
+		common.WhereIf(query.Status != "", "status", query.Status),
+	}
+}
@@ -0,0 +1,80 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/order"
+	dbpkg "power-supply-sys/internal/infra/db"
+	"power-supply-sys/pkg/common"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderRepository(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	repo := NewOrderRepository(db)
+	ctx := context.Background()
+
+	first := &order.Order{
+		OrderNo:     "PS0001",
+		UserID:      1,
+		Status:      order.StatusCreated,
+		TotalAmount: 1798,
+		Items: []*order.Item{
+			{PowerSupplyID: 1, Name: "RM850x", UnitPrice: 899, Quantity: 2, Subtotal: 1798},
+		},
+	}
+	second := &order.Order{OrderNo: "PS0002", UserID: 2, Status: order.StatusCreated}
+	for _, o := range []*order.Order{first, second} {
+		require.NoError(t, repo.Create(ctx, o))
+	}
+
+	t.Run("查询订单包含明细", func(t *testing.T) {
+		found, err := repo.FindByID(ctx, first.ID)
+		require.NoError(t, err)
+		require.Len(t, found.Items, 1)
+		assert.Equal(t, first.ID, found.Items[0].OrderID)
+		assert.Equal(t, 899.0, found.Items[0].UnitPrice)
+
+		_, err = repo.FindByID(ctx, 9999)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+
+	t.Run("按用户过滤", func(t *testing.T) {
+		userID := uint(2)
+		query := &order.QueryOptions{UserID: &userID, Page: 1, PageSize: 10}
+		list, err := repo.List(ctx, query)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, second.ID, list[0].ID)
+
+		total, err := repo.Count(ctx, &order.QueryOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+	})
+
+	t.Run("条件更新状态并记录时间", func(t *testing.T) {
+		now := time.Now()
+		ok, err := repo.UpdateStatus(ctx, first.ID, order.StatusCreated, order.StatusPaid, now)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		// 状态已变化，再次流转失败
+		ok, err = repo.UpdateStatus(ctx, first.ID, order.StatusCreated, order.StatusCancelled, now)
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		found, err := repo.FindByID(ctx, first.ID)
+		require.NoError(t, err)
+		assert.Equal(t, order.StatusPaid, found.Status)
+		assert.NotNil(t, found.PaidAt)
+		assert.Nil(t, found.CancelledAt)
+	})
+}
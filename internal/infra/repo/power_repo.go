@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"power-supply-sys/internal/domain/catalog"
 	"power-supply-sys/internal/domain/power"
 	"power-supply-sys/pkg/common"
 
@@ -20,31 +21,108 @@ func NewPowerRepository(db *gorm.DB) power.Repository {
 	}
 }
 
+// AdjustStock 原子地调整库存，扣减后库存低于已预留数量时不更新并返回 false
+func (r *powerRepository) AdjustStock(ctx context.Context, id uint, delta int) (bool, error) {
+	opts := []common.QueryOption{common.Where("id", id)}
+	if delta < 0 {
+		opts = append(opts, common.WhereRaw("stock - reserved >= ?", -delta))
+	}
+	affected, err := r.BatchUpdate(ctx, map[string]any{"stock": gorm.Expr("stock + ?", delta)}, opts...)
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// AdjustStockFrom 仅当库存仍为 expected 时原子地调整库存，库存已变化或调整后低于已预留数量时返回 false
+func (r *powerRepository) AdjustStockFrom(ctx context.Context, id uint, expected, delta int) (bool, error) {
+	affected, err := r.BatchUpdate(ctx, map[string]any{"stock": gorm.Expr("stock + ?", delta)},
+		common.Where("id", id),
+		common.Where("stock", expected),
+		common.WhereLTE("reserved", expected+delta),
+	)
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// Reserve 原子地增加预留数量，可售库存不足时不更新并返回 false
+// 条件更新保证并发预留时可售库存不会为负数
+func (r *powerRepository) Reserve(ctx context.Context, id uint, quantity int) (bool, error) {
+	affected, err := r.BatchUpdate(ctx, map[string]any{"reserved": gorm.Expr("reserved + ?", quantity)},
+		common.Where("id", id),
+		common.WhereRaw("stock - reserved >= ?", quantity),
+	)
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// Release 原子地减少预留数量，预留数量不足时不更新并返回 false
+func (r *powerRepository) Release(ctx context.Context, id uint, quantity int) (bool, error) {
+	affected, err := r.BatchUpdate(ctx, map[string]any{"reserved": gorm.Expr("reserved - ?", quantity)},
+		common.Where("id", id),
+		common.WhereGTE("reserved", quantity),
+	)
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// ReplaceTags 将电源的标签整体替换为 tags
+func (r *powerRepository) ReplaceTags(ctx context.Context, ps *power.PowerSupply, tags []*catalog.Tag) error {
+	if tags == nil {
+		tags = []*catalog.Tag{}
+	}
+	if err := r.GetDB(ctx).Model(ps).Association("Tags").Replace(tags); err != nil {
+		return common.ErrDatabase(err)
+	}
+	return nil
+}
+
+// FindDetail 查询电源详情（含各仓库库存与标签）
+func (r *powerRepository) FindDetail(ctx context.Context, id uint) (*power.PowerSupply, error) {
+	return r.FindOne(ctx, common.Where("id", id), preloadLocations(), preloadTags())
+}
+
+// FindByIDs 批量查询电源（含各仓库库存与标签），不存在的 ID 不在结果中
+func (r *powerRepository) FindByIDs(ctx context.Context, ids []uint) ([]*power.PowerSupply, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return r.BaseRepository.List(ctx, common.WhereIn("id", ids), preloadLocations(), preloadTags(), common.OrderBy("id"))
+}
+
 // Count 统计电源数量
 func (r *powerRepository) Count(ctx context.Context, query *power.QueryOptions) (int64, error) {
 	if query == nil {
 		return r.BaseRepository.Count(ctx)
 	}
 
-	return r.BaseRepository.Count(ctx,
-		common.WhereLike("name", query.Name),
-		common.WhereLike("brand", query.Brand),
-		common.WhereGTEIfNotNil("power", query.MinPower),
-		common.WhereLTEIfNotNil("power", query.MaxPower),
-		common.WhereGTEIfNotNil("price", query.MinPrice),
-		common.WhereLTEIfNotNil("price", query.MaxPrice),
-		common.WhereIf(query.Efficiency != "", "efficiency", query.Efficiency),
-		common.WhereIfNotNil("status", query.Status),
-	)
+	return r.BaseRepository.Count(ctx, r.buildQueryOptions(query)...)
 }
 
-// List 查询电源列表
+// List 查询电源列表（含各仓库库存与标签）
 func (r *powerRepository) List(ctx context.Context, query *power.QueryOptions) ([]*power.PowerSupply, error) {
 	if query == nil {
-		return r.BaseRepository.List(ctx, common.OrderByDesc("id"))
+		return r.BaseRepository.List(ctx, preloadLocations(), preloadTags(), common.OrderByDesc("id"))
 	}
 
-	return r.BaseRepository.List(ctx,
+	opts := append(r.buildQueryOptions(query),
+		preloadLocations(),
+		preloadTags(),
+		common.OrderByDesc("id"),
+		common.Paginate(query.Page, query.PageSize),
+	)
+	return r.BaseRepository.List(ctx, opts...)
+}
+
+// buildQueryOptions 构建查询条件
+func (r *powerRepository) buildQueryOptions(query *power.QueryOptions) []common.QueryOption {
+	opts := []common.QueryOption{
 		common.WhereLike("name", query.Name),
 		common.WhereLike("brand", query.Brand),
 		common.WhereGTEIfNotNil("power", query.MinPower),
@@ -52,8 +130,110 @@ func (r *powerRepository) List(ctx context.Context, query *power.QueryOptions) (
 		common.WhereGTEIfNotNil("price", query.MinPrice),
 		common.WhereLTEIfNotNil("price", query.MaxPrice),
 		common.WhereIf(query.Efficiency != "", "efficiency", query.Efficiency),
+		efficiencyTierFilter(query.MinTier, query.MaxTier),
 		common.WhereIfNotNil("status", query.Status),
-		common.OrderByDesc("id"),
-		common.Paginate(query.Page, query.PageSize),
+		categoryFilter(query.CategoryIDs),
+		tagFilter(query.Tags, query.AllTags),
+	}
+	opts = append(opts, specFilters(&query.Spec)...)
+	return append(opts, stockFilters(query)...)
+}
+
+// efficiencyTierFilter 按能效档位范围过滤，两端都为 0 时不过滤
+func efficiencyTierFilter(min, max power.EfficiencyTier) common.QueryOption {
+	if min == 0 && max == 0 {
+		return common.Combine()
+	}
+	return common.WhereIn("efficiency", power.EfficienciesInTiers(min, max))
+}
+
+// categoryFilter 按分类过滤，ids 为空时不过滤
+func categoryFilter(ids []uint) common.QueryOption {
+	if len(ids) == 0 {
+		return common.Combine()
+	}
+	return common.WhereIn("category_id", ids)
+}
+
+// tagFilter 按标签过滤，tags 为空时不过滤
+// all 为 true 时要求电源包含全部标签，否则包含任一标签即可
+func tagFilter(tags []string, all bool) common.QueryOption {
+	if len(tags) == 0 {
+		return common.Combine()
+	}
+	sub := "SELECT pst.power_supply_id FROM power_supply_tags pst JOIN tags t ON t.id = pst.tag_id WHERE LOWER(t.name) IN ?"
+	if all {
+		sub += " GROUP BY pst.power_supply_id HAVING COUNT(DISTINCT pst.tag_id) = ?"
+		return common.WhereRaw("id IN ("+sub+")", tags, len(tags))
+	}
+	return common.WhereRaw("id IN ("+sub+")", tags)
+}
+
+// specFilters 构建技术规格过滤条件
+// 最大值条件排除未填写（为 0）的规格，避免缺少数据的电源被当作满足条件
+func specFilters(spec *power.SpecFilter) []common.QueryOption {
+	opts := []common.QueryOption{
+		common.WhereIf(spec.FormFactor != "", "form_factor", spec.FormFactor),
+		common.WhereIfNotNil("atx3", spec.ATX3),
+		common.WhereGTEIfNotNil("connectors_12vhpwr", spec.Min12VHPWR),
+		common.WhereGTEIfNotNil("eps_connectors", spec.MinEPS),
+		common.WhereGTEIfNotNil("pcie_8pin_connectors", spec.MinPCIe8Pin),
+		common.WhereGTEIfNotNil("sata_connectors", spec.MinSATA),
+		common.WhereGTEIfNotNil("molex_connectors", spec.MinMolex),
+		common.WhereGTEIfNotNil("rail_12v_amps", spec.Min12VAmps),
+		common.WhereIfNotNil("fan_size_mm", spec.FanSizeMM),
+	}
+	if spec.MaxNoiseDBA != nil {
+		opts = append(opts, common.WhereGT("noise_dba", 0), common.WhereLTE("noise_dba", *spec.MaxNoiseDBA))
+	}
+	if spec.MaxLengthMM != nil {
+		opts = append(opts, common.WhereGT("length_mm", 0), common.WhereLTE("length_mm", *spec.MaxLengthMM))
+	}
+	if spec.MaxWidthMM != nil {
+		opts = append(opts, common.WhereGT("width_mm", 0), common.WhereLTE("width_mm", *spec.MaxWidthMM))
+	}
+	if spec.MaxHeightMM != nil {
+		opts = append(opts, common.WhereGT("height_mm", 0), common.WhereLTE("height_mm", *spec.MaxHeightMM))
+	}
+	return opts
+}
+
+// stockFilters 构建仓库与库存过滤条件
+// 指定仓库时按该仓库的库存判断是否有货，否则按可售库存（库存减去已预留数量）判断
+func stockFilters(query *power.QueryOptions) []common.QueryOption {
+	if query.WarehouseID != nil {
+		sub := "SELECT power_supply_id FROM stock_levels WHERE warehouse_id = ?"
+		if query.InStock != nil {
+			if *query.InStock {
+				sub += " AND quantity > 0"
+			} else {
+				sub += " AND quantity <= 0"
+			}
+		}
+		return []common.QueryOption{common.WhereRaw("id IN ("+sub+")", *query.WarehouseID)}
+	}
+	if query.InStock != nil {
+		if *query.InStock {
+			return []common.QueryOption{common.WhereRaw("stock - reserved > 0")}
+		}
+		return []common.QueryOption{common.WhereRaw("stock - reserved <= 0")}
+	}
+	return nil
+}
+
+// preloadLocations 预加载各仓库库存（按仓库排序）
+func preloadLocations() common.QueryOption {
+	return common.Combine(
+		common.Preload("Locations", func(db *gorm.DB) *gorm.DB {
+			return db.Order("warehouse_id")
+		}),
+		common.Preload("Locations.Warehouse"),
 	)
 }
+
+// preloadTags 预加载标签（按名称排序，不区分大小写）
+func preloadTags() common.QueryOption {
+	return common.Preload("Tags", func(db *gorm.DB) *gorm.DB {
+		return db.Order("LOWER(tags.name)")
+	})
+}
@@ -2,10 +2,12 @@ package repo
 
 import (
 	"context"
+	"power-supply-sys/internal/domain/catalog"
 	"power-supply-sys/internal/domain/power"
 	dbpkg "power-supply-sys/internal/infra/db"
 	"power-supply-sys/pkg/common"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -28,7 +30,7 @@ func TestPowerRepository_Create(t *testing.T) {
 			Brand:      "Test Brand",
 			Model:      "TP-1000",
 			Power:      1000,
-			Efficiency: "80Plus Gold",
+			Efficiency: power.Efficiency80PlusGold,
 			Modular:    true,
 			Price:      299.99,
 			Stock:      10,
@@ -78,6 +80,34 @@ func TestPowerRepository_FindByID(t *testing.T) {
 	})
 }
 
+func TestPowerRepository_FindByIDs(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	repo := NewPowerRepository(db)
+	ctx := context.Background()
+
+	var ids []uint
+	for _, name := range []string{"A", "B", "C"} {
+		ps := &power.PowerSupply{Name: name, Power: 650, Status: 1}
+		require.NoError(t, repo.Create(ctx, ps))
+		ids = append(ids, ps.ID)
+	}
+
+	list, err := repo.FindByIDs(ctx, []uint{ids[2], ids[0], 99999})
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, ids[0], list[0].ID)
+	assert.Equal(t, ids[2], list[1].ID)
+
+	list, err = repo.FindByIDs(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
+
 func TestPowerRepository_Update(t *testing.T) {
 	db := common.SetupTestDB(t)
 	defer common.TeardownTestDB(t, db)
@@ -231,6 +261,62 @@ func TestPowerRepository_List(t *testing.T) {
 	})
 }
 
+func TestPowerRepository_StockFilters(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	repo := NewPowerRepository(db)
+	levelRepo := NewStockLevelRepository(db)
+	ctx := context.Background()
+
+	inStock := &power.PowerSupply{Name: "In Stock", Brand: "Brand A", Power: 650, Price: 99.99, Stock: 5, Status: 1}
+	soldOut := &power.PowerSupply{Name: "Sold Out", Brand: "Brand A", Power: 750, Price: 129.99, Stock: 2, Reserved: 2, Status: 1}
+	require.NoError(t, repo.Create(ctx, inStock))
+	require.NoError(t, repo.Create(ctx, soldOut))
+	_, err = levelRepo.Adjust(ctx, 1, inStock.ID, 5)
+	require.NoError(t, err)
+	_, err = levelRepo.Adjust(ctx, 1, soldOut.ID, 2)
+	require.NoError(t, err)
+
+	yes, no := true, false
+
+	t.Run("按可售库存筛选", func(t *testing.T) {
+		psList, err := repo.List(ctx, &power.QueryOptions{InStock: &yes})
+		require.NoError(t, err)
+		require.Len(t, psList, 1)
+		assert.Equal(t, inStock.ID, psList[0].ID)
+		require.Len(t, psList[0].Locations, 1)
+		assert.Equal(t, 5, psList[0].Locations[0].Quantity)
+
+		total, err := repo.Count(ctx, &power.QueryOptions{InStock: &no})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+	})
+
+	t.Run("按仓库筛选", func(t *testing.T) {
+		warehouseID := uint(1)
+		psList, err := repo.List(ctx, &power.QueryOptions{WarehouseID: &warehouseID, InStock: &yes})
+		require.NoError(t, err)
+		assert.Len(t, psList, 2)
+
+		otherID := uint(2)
+		total, err := repo.Count(ctx, &power.QueryOptions{WarehouseID: &otherID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), total)
+	})
+
+	t.Run("查询详情包含各仓库库存", func(t *testing.T) {
+		found, err := repo.FindDetail(ctx, inStock.ID)
+		require.NoError(t, err)
+		require.Len(t, found.Locations, 1)
+		require.NotNil(t, found.Locations[0].Warehouse)
+		assert.Equal(t, "MAIN", found.Locations[0].Warehouse.Code)
+	})
+}
+
 func TestPowerRepository_Count(t *testing.T) {
 	db := common.SetupTestDB(t)
 	defer common.TeardownTestDB(t, db)
@@ -269,3 +355,295 @@ func TestPowerRepository_Count(t *testing.T) {
 		assert.GreaterOrEqual(t, count, int64(2))
 	})
 }
+
+func TestPowerRepository_AdjustStock(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	repo := NewPowerRepository(db)
+	ctx := context.Background()
+
+	ps := &power.PowerSupply{Name: "Stock Power Supply", Power: 650, Price: 99.99, Stock: 5, Status: 1}
+	require.NoError(t, repo.Create(ctx, ps))
+
+	t.Run("增加库存", func(t *testing.T) {
+		ok, err := repo.AdjustStock(ctx, ps.ID, 3)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("扣减库存", func(t *testing.T) {
+		ok, err := repo.AdjustStock(ctx, ps.ID, -8)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("库存不足时不扣减", func(t *testing.T) {
+		ok, err := repo.AdjustStock(ctx, ps.ID, -1)
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		found, err := repo.FindByID(ctx, ps.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 0, found.Stock)
+	})
+
+	t.Run("电源不存在", func(t *testing.T) {
+		ok, err := repo.AdjustStock(ctx, 99999, 1)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("库存与预期不符时不调整", func(t *testing.T) {
+		ok, err := repo.AdjustStockFrom(ctx, ps.ID, 3, 4)
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		ok, err = repo.AdjustStockFrom(ctx, ps.ID, 0, 4)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		found, err := repo.FindByID(ctx, ps.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 4, found.Stock)
+	})
+}
+
+func TestPowerRepository_Reserve(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	repo := NewPowerRepository(db)
+	ctx := context.Background()
+
+	ps := &power.PowerSupply{Name: "Reserve Power Supply", Power: 650, Price: 99.99, Stock: 5, Status: 1}
+	require.NoError(t, repo.Create(ctx, ps))
+
+	ok, err := repo.Reserve(ctx, ps.ID, 4)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	t.Run("可售库存不足时不预留", func(t *testing.T) {
+		ok, err := repo.Reserve(ctx, ps.ID, 2)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("已预留的库存不能扣减", func(t *testing.T) {
+		ok, err := repo.AdjustStock(ctx, ps.ID, -2)
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		ok, err = repo.AdjustStock(ctx, ps.ID, -1)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("释放预留", func(t *testing.T) {
+		ok, err := repo.Release(ctx, ps.ID, 5)
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		ok, err = repo.Release(ctx, ps.ID, 4)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		found, err := repo.FindByID(ctx, ps.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 4, found.Stock)
+		assert.Equal(t, 0, found.Reserved)
+		assert.Equal(t, 4, found.Available())
+	})
+}
+
+func TestPowerRepository_SpecFilters(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	repo := NewPowerRepository(db)
+	ctx := context.Background()
+
+	atx := &power.PowerSupply{Name: "RM1000x", Power: 1000, Price: 1299, Status: 1, Spec: power.Spec{
+		FormFactor: power.FormFactorATX, ATX3: true, Connectors12VHPWR: 1, EPSConnectors: 2, PCIe8PinConnectors: 4,
+		SATAConnectors: 14, MolexConnectors: 4, Rail12VAmps: 83.3, FanSizeMM: 135, NoiseDBA: 24.5,
+		LengthMM: 180, WidthMM: 150, HeightMM: 86,
+	}}
+	sfx := &power.PowerSupply{Name: "SF750", Power: 750, Price: 999, Status: 1, Spec: power.Spec{
+		FormFactor: power.FormFactorSFX, EPSConnectors: 2, PCIe8PinConnectors: 4, SATAConnectors: 8,
+		Rail12VAmps: 62.5, FanSizeMM: 92, LengthMM: 100, WidthMM: 125, HeightMM: 64,
+	}}
+	// 未填写规格的电源
+	plain := &power.PowerSupply{Name: "Plain 500", Power: 500, Price: 199, Status: 1}
+	for _, ps := range []*power.PowerSupply{atx, sfx, plain} {
+		require.NoError(t, repo.Create(ctx, ps))
+	}
+
+	intPtr := func(v int) *int { return &v }
+	floatPtr := func(v float64) *float64 { return &v }
+	yes := true
+
+	tests := []struct {
+		name   string
+		filter power.SpecFilter
+		want   []uint
+	}{
+		{"按版型筛选", power.SpecFilter{FormFactor: power.FormFactorSFX}, []uint{sfx.ID}},
+		{"按 ATX 3.x 筛选", power.SpecFilter{ATX3: &yes}, []uint{atx.ID}},
+		{"按 12VHPWR 接口筛选", power.SpecFilter{Min12VHPWR: intPtr(1)}, []uint{atx.ID}},
+		{"按接口数量筛选", power.SpecFilter{MinEPS: intPtr(2), MinPCIe8Pin: intPtr(4)}, []uint{sfx.ID, atx.ID}},
+		{"按 SATA 与 Molex 筛选", power.SpecFilter{MinSATA: intPtr(10), MinMolex: intPtr(1)}, []uint{atx.ID}},
+		{"按 +12V 电流筛选", power.SpecFilter{Min12VAmps: floatPtr(60)}, []uint{sfx.ID, atx.ID}},
+		{"按风扇尺寸筛选", power.SpecFilter{FanSizeMM: intPtr(92)}, []uint{sfx.ID}},
+		{"最大噪音排除未填写", power.SpecFilter{MaxNoiseDBA: floatPtr(30)}, []uint{atx.ID}},
+		{"按尺寸筛选", power.SpecFilter{MaxLengthMM: intPtr(130), MaxWidthMM: intPtr(130), MaxHeightMM: intPtr(70)}, []uint{sfx.ID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			psList, err := repo.List(ctx, &power.QueryOptions{Spec: tt.filter, Page: 1, PageSize: 10})
+			require.NoError(t, err)
+			ids := make([]uint, 0, len(psList))
+			for _, ps := range psList {
+				ids = append(ids, ps.ID)
+			}
+			assert.Equal(t, tt.want, ids)
+
+			total, err := repo.Count(ctx, &power.QueryOptions{Spec: tt.filter})
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tt.want)), total)
+		})
+	}
+}
+
+func TestPowerRepository_EfficiencyFilters(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	repo := NewPowerRepository(db)
+	ctx := context.Background()
+
+	// 规范化之前的写法
+	for _, v := range []string{"80Plus金牌", "80+ gold", "Gold", "80 PLUS Bronze", "Cybenetics Platinum", "unknown"} {
+		require.NoError(t, db.Exec("INSERT INTO power_supplies (name, efficiency, price, status, created_at, updated_at) VALUES (?, ?, 99, 1, ?, ?)", v, v, time.Now(), time.Now()).Error)
+	}
+
+	t.Run("迁移规范化已有的能效等级", func(t *testing.T) {
+		require.NoError(t, dbpkg.Migrate(db))
+
+		psList, err := repo.List(ctx, &power.QueryOptions{Efficiency: power.Efficiency80PlusGold})
+		require.NoError(t, err)
+		assert.Len(t, psList, 3)
+
+		found, err := repo.FindOne(ctx, common.Where("name", "unknown"))
+		require.NoError(t, err)
+		assert.Empty(t, found.Efficiency)
+	})
+
+	t.Run("按能效档位范围筛选", func(t *testing.T) {
+		total, err := repo.Count(ctx, &power.QueryOptions{MinTier: power.TierGold})
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), total)
+
+		psList, err := repo.List(ctx, &power.QueryOptions{MinTier: power.TierPlatinum})
+		require.NoError(t, err)
+		require.Len(t, psList, 1)
+		assert.Equal(t, power.EfficiencyCyberneticsPlatinum, psList[0].Efficiency)
+
+		psList, err = repo.List(ctx, &power.QueryOptions{MaxTier: power.TierSilver})
+		require.NoError(t, err)
+		require.Len(t, psList, 1)
+		assert.Equal(t, power.Efficiency80PlusBronze, psList[0].Efficiency)
+
+		total, err = repo.Count(ctx, &power.QueryOptions{MinTier: power.TierBronze, MaxTier: power.TierGold})
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), total)
+	})
+}
+
+func TestPowerRepository_CatalogFilters(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	repo := NewPowerRepository(db)
+	ctx := context.Background()
+
+	tags := map[string]*catalog.Tag{}
+	for _, name := range []string{"Quiet", "RGB", "White"} {
+		tags[name] = &catalog.Tag{Name: name}
+		require.NoError(t, db.Create(tags[name]).Error)
+	}
+	categoryA, categoryB := uint(1), uint(2)
+	create := func(name string, categoryID *uint, tagNames ...string) *power.PowerSupply {
+		ps := &power.PowerSupply{Name: name, Power: 750, Price: 599, Status: 1, CategoryID: categoryID}
+		require.NoError(t, repo.Create(ctx, ps))
+		list := make([]*catalog.Tag, 0, len(tagNames))
+		for _, tagName := range tagNames {
+			list = append(list, tags[tagName])
+		}
+		require.NoError(t, repo.ReplaceTags(ctx, ps, list))
+		return ps
+	}
+	quietWhite := create("Quiet White", &categoryA, "Quiet", "White")
+	rgbWhite := create("RGB White", &categoryB, "RGB", "White")
+	create("Plain", nil)
+
+	names := func(list []*power.PowerSupply) []string {
+		result := make([]string, len(list))
+		for i, ps := range list {
+			result[i] = ps.Name
+		}
+		return result
+	}
+
+	t.Run("按分类筛选", func(t *testing.T) {
+		psList, err := repo.List(ctx, &power.QueryOptions{CategoryIDs: []uint{categoryA}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Quiet White"}, names(psList))
+
+		total, err := repo.Count(ctx, &power.QueryOptions{CategoryIDs: []uint{categoryA, categoryB}})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+	})
+
+	t.Run("按标签筛选", func(t *testing.T) {
+		psList, err := repo.List(ctx, &power.QueryOptions{Tags: []string{"quiet", "rgb"}})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"Quiet White", "RGB White"}, names(psList))
+
+		psList, err = repo.List(ctx, &power.QueryOptions{Tags: []string{"white", "rgb"}, AllTags: true})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"RGB White"}, names(psList))
+
+		total, err := repo.Count(ctx, &power.QueryOptions{Tags: []string{"white"}, AllTags: true})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+	})
+
+	t.Run("替换标签并预加载", func(t *testing.T) {
+		require.NoError(t, repo.ReplaceTags(ctx, rgbWhite, []*catalog.Tag{tags["Quiet"]}))
+		require.NoError(t, repo.ReplaceTags(ctx, quietWhite, nil))
+
+		found, err := repo.FindDetail(ctx, rgbWhite.ID)
+		require.NoError(t, err)
+		require.Len(t, found.Tags, 1)
+		assert.Equal(t, "Quiet", found.Tags[0].Name)
+
+		found, err = repo.FindDetail(ctx, quietWhite.ID)
+		require.NoError(t, err)
+		assert.Empty(t, found.Tags)
+	})
+}
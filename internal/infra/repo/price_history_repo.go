@@ -0,0 +1,66 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/power"
+	"power-supply-sys/pkg/common"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// priceHistoryRepository 价格历史数据访问层实现
+type priceHistoryRepository struct {
+	*common.BaseRepository[power.PriceHistory]
+}
+
+// NewPriceHistoryRepository 创建价格历史仓储
+func NewPriceHistoryRepository(db *gorm.DB) power.PriceHistoryRepository {
+	return &priceHistoryRepository{
+		BaseRepository: common.NewBaseRepository[power.PriceHistory](db),
+	}
+}
+
+// CloseOpen 将当前生效的价格记录的生效结束时间设置为 at
+func (r *priceHistoryRepository) CloseOpen(ctx context.Context, powerSupplyID uint, at time.Time) error {
+	_, err := r.BatchUpdate(ctx, map[string]any{"effective_to": at},
+		common.Where("power_supply_id", powerSupplyID),
+		common.WhereNull("effective_to"),
+	)
+	return err
+}
+
+// List 查询价格历史（最新生效的在前）
+func (r *priceHistoryRepository) List(ctx context.Context, powerSupplyID uint, page, pageSize int) ([]*power.PriceHistory, error) {
+	return r.BaseRepository.List(ctx,
+		common.Where("power_supply_id", powerSupplyID),
+		common.OrderByMulti("effective_from DESC", "id DESC"),
+		common.Paginate(page, pageSize),
+	)
+}
+
+// Count 统计价格历史数量
+func (r *priceHistoryRepository) Count(ctx context.Context, powerSupplyID uint) (int64, error) {
+	return r.BaseRepository.Count(ctx, common.Where("power_supply_id", powerSupplyID))
+}
+
+// PricesAt 查询各电源在 at 时刻生效的价格，没有历史记录的电源不在结果中
+func (r *priceHistoryRepository) PricesAt(ctx context.Context, ids []uint, at time.Time) (map[uint]float64, error) {
+	prices := make(map[uint]float64, len(ids))
+	if len(ids) == 0 {
+		return prices, nil
+	}
+	records, err := r.BaseRepository.List(ctx,
+		common.WhereIn("power_supply_id", ids),
+		common.WhereLTE("effective_from", at),
+		common.WhereRaw("(effective_to IS NULL OR effective_to > ?)", at),
+		common.OrderBy("id"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range records {
+		prices[h.PowerSupplyID] = h.Price
+	}
+	return prices, nil
+}
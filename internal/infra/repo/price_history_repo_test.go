@@ -0,0 +1,68 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/power"
+	dbpkg "power-supply-sys/internal/infra/db"
+	"power-supply-sys/pkg/common"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceHistoryRepository(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	repo := NewPriceHistoryRepository(db)
+	ctx := context.Background()
+	base := time.Now().Add(-3 * time.Hour)
+
+	// changePrice 关闭当前价格并追加新价格
+	changePrice := func(psID uint, price float64, at time.Time) {
+		require.NoError(t, repo.CloseOpen(ctx, psID, at))
+		require.NoError(t, repo.Create(ctx, &power.PriceHistory{PowerSupplyID: psID, Price: price, EffectiveFrom: at}))
+	}
+	changePrice(1, 100, base)
+	changePrice(1, 90, base.Add(time.Hour))
+	changePrice(1, 95, base.Add(2*time.Hour))
+	changePrice(2, 200, base.Add(time.Hour))
+
+	t.Run("关闭上一条价格记录", func(t *testing.T) {
+		list, err := repo.List(ctx, 1, 1, 10)
+		require.NoError(t, err)
+		require.Len(t, list, 3)
+		assert.Equal(t, 95.0, list[0].Price)
+		assert.Nil(t, list[0].EffectiveTo)
+		require.NotNil(t, list[1].EffectiveTo)
+		assert.True(t, list[1].EffectiveTo.Equal(list[0].EffectiveFrom))
+
+		total, err := repo.Count(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+	})
+
+	t.Run("查询指定时刻生效的价格", func(t *testing.T) {
+		prices, err := repo.PricesAt(ctx, []uint{1, 2}, base.Add(90*time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, map[uint]float64{1: 90, 2: 200}, prices)
+
+		// 区间左闭右开
+		prices, err = repo.PricesAt(ctx, []uint{1, 2}, base.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, map[uint]float64{1: 90, 2: 200}, prices)
+
+		prices, err = repo.PricesAt(ctx, []uint{1, 2}, base.Add(30*time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, map[uint]float64{1: 100}, prices)
+
+		prices, err = repo.PricesAt(ctx, nil, time.Now())
+		require.NoError(t, err)
+		assert.Empty(t, prices)
+	})
+}
@@ -0,0 +1,88 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/power"
+	"power-supply-sys/pkg/common"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// priceScheduleRepository 计划调价数据访问层实现
+type priceScheduleRepository struct {
+	*common.BaseRepository[power.PriceSchedule]
+}
+
+// NewPriceScheduleRepository 创建计划调价仓储
+func NewPriceScheduleRepository(db *gorm.DB) power.PriceScheduleRepository {
+	return &priceScheduleRepository{
+		BaseRepository: common.NewBaseRepository[power.PriceSchedule](db),
+	}
+}
+
+// FindByID 根据ID查询计划调价
+func (r *priceScheduleRepository) FindByID(ctx context.Context, id uint) (*power.PriceSchedule, error) {
+	s, err := r.BaseRepository.FindByID(ctx, id)
+	if err != nil && common.HasErrorCode(err, common.ErrCodeNotFound) {
+		return nil, common.ErrNotFound("计划调价")
+	}
+	return s, err
+}
+
+// List 查询电源的计划调价（按生效时间排序），status 为空时不过滤
+func (r *priceScheduleRepository) List(ctx context.Context, powerSupplyID uint, status string) ([]*power.PriceSchedule, error) {
+	return r.BaseRepository.List(ctx,
+		common.Where("power_supply_id", powerSupplyID),
+		common.WhereIf(status != "", "status", status),
+		common.OrderByMulti("effective_at", "id"),
+	)
+}
+
+// ListDue 查询已到生效时间但仍待生效的计划（最早生效的在前）
+func (r *priceScheduleRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]*power.PriceSchedule, error) {
+	return r.BaseRepository.List(ctx,
+		common.Where("status", power.ScheduleStatusPending),
+		common.WhereLTE("effective_at", now),
+		common.OrderByMulti("effective_at", "id"),
+		common.Limit(limit),
+	)
+}
+
+// UpdateStatus 条件更新计划状态，应用时记录应用时间
+func (r *priceScheduleRepository) UpdateStatus(ctx context.Context, id uint, from, to string, at time.Time) (bool, error) {
+	updates := map[string]any{"status": to}
+	if to == power.ScheduleStatusApplied {
+		updates["applied_at"] = at
+	}
+	affected, err := r.BatchUpdate(ctx, updates,
+		common.Where("id", id),
+		common.Where("status", from),
+	)
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// PendingPricesAt 查询各电源在 at 时刻之前最后生效的待生效计划价格
+func (r *priceScheduleRepository) PendingPricesAt(ctx context.Context, ids []uint, at time.Time) (map[uint]float64, error) {
+	prices := make(map[uint]float64, len(ids))
+	if len(ids) == 0 {
+		return prices, nil
+	}
+	schedules, err := r.BaseRepository.List(ctx,
+		common.WhereIn("power_supply_id", ids),
+		common.Where("status", power.ScheduleStatusPending),
+		common.WhereLTE("effective_at", at),
+		common.OrderByMulti("effective_at", "id"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	// 按生效时间升序覆盖，保留每个电源最后生效的价格
+	for _, s := range schedules {
+		prices[s.PowerSupplyID] = s.Price
+	}
+	return prices, nil
+}
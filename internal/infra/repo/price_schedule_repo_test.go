@@ -0,0 +1,75 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/power"
+	dbpkg "power-supply-sys/internal/infra/db"
+	"power-supply-sys/pkg/common"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceScheduleRepository(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	repo := NewPriceScheduleRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	create := func(psID uint, price float64, at time.Time) *power.PriceSchedule {
+		s := &power.PriceSchedule{PowerSupplyID: psID, Price: price, EffectiveAt: at, Status: power.ScheduleStatusPending}
+		require.NoError(t, repo.Create(ctx, s))
+		return s
+	}
+	due := create(1, 90, now.Add(-time.Minute))
+	create(1, 80, now.Add(2*time.Hour))
+	create(1, 85, now.Add(time.Hour))
+	create(2, 150, now.Add(3*time.Hour))
+
+	t.Run("查询到期的计划", func(t *testing.T) {
+		list, err := repo.ListDue(ctx, now, 10)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, due.ID, list[0].ID)
+	})
+
+	t.Run("推算未来时刻的计划价格", func(t *testing.T) {
+		prices, err := repo.PendingPricesAt(ctx, []uint{1, 2}, now.Add(90*time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, map[uint]float64{1: 85}, prices)
+
+		prices, err = repo.PendingPricesAt(ctx, []uint{1, 2}, now.Add(4*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, map[uint]float64{1: 80, 2: 150}, prices)
+	})
+
+	t.Run("条件更新状态", func(t *testing.T) {
+		ok, err := repo.UpdateStatus(ctx, due.ID, power.ScheduleStatusPending, power.ScheduleStatusApplied, now)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = repo.UpdateStatus(ctx, due.ID, power.ScheduleStatusPending, power.ScheduleStatusCancelled, now)
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		found, err := repo.FindByID(ctx, due.ID)
+		require.NoError(t, err)
+		assert.Equal(t, power.ScheduleStatusApplied, found.Status)
+		assert.NotNil(t, found.AppliedAt)
+
+		list, err := repo.List(ctx, 1, power.ScheduleStatusPending)
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		assert.Equal(t, 85.0, list[0].Price)
+
+		_, err = repo.FindByID(ctx, 9999)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+}
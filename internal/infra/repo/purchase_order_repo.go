@@ -0,0 +1,108 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/purchase"
+	"power-supply-sys/pkg/common"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// purchaseOrderRepository 采购单数据访问层实现
+type purchaseOrderRepository struct {
+	*common.BaseRepository[purchase.Order]
+}
+
+// NewPurchaseOrderRepository 创建采购单仓储
+func NewPurchaseOrderRepository(db *gorm.DB) purchase.Repository {
+	return &purchaseOrderRepository{
+		BaseRepository: common.NewBaseRepository[purchase.Order](db),
+	}
+}
+
+// FindByID 根据ID查询采购单（含采购明细）
+func (r *purchaseOrderRepository) FindByID(ctx context.Context, id uint) (*purchase.Order, error) {
+	o, err := r.FindOne(ctx, common.Where("id", id), preloadPurchaseLines())
+	if err != nil && common.HasErrorCode(err, common.ErrCodeNotFound) {
+		return nil, common.ErrNotFound("采购单")
+	}
+	return o, err
+}
+
+// Count 统计采购单数量
+func (r *purchaseOrderRepository) Count(ctx context.Context, query *purchase.QueryOptions) (int64, error) {
+	return r.BaseRepository.Count(ctx, r.buildQueryOptions(query)...)
+}
+
+// List 查询采购单列表（含采购明细，最新的在前）
+func (r *purchaseOrderRepository) List(ctx context.Context, query *purchase.QueryOptions) ([]*purchase.Order, error) {
+	opts := append(r.buildQueryOptions(query),
+		preloadPurchaseLines(),
+		common.OrderByDesc("id"),
+		common.Paginate(query.Page, query.PageSize),
+	)
+	return r.BaseRepository.List(ctx, opts...)
+}
+
+// ExistsBySupplier 检查供应商是否有采购单
+func (r *purchaseOrderRepository) ExistsBySupplier(ctx context.Context, supplierID uint) (bool, error) {
+	return r.Exists(ctx, common.Where("supplier_id", supplierID))
+}
+
+// ReplaceLines 替换采购单的明细并更新采购单字段（需在事务中调用）
+func (r *purchaseOrderRepository) ReplaceLines(ctx context.Context, id uint, lines []*purchase.Line, updates map[string]any) error {
+	db := r.GetDB(ctx)
+	if err := db.Where("purchase_order_id = ?", id).Delete(&purchase.Line{}).Error; err != nil {
+		return common.ErrDatabase(err)
+	}
+	for _, line := range lines {
+		line.PurchaseOrderID = id
+	}
+	if err := db.Create(&lines).Error; err != nil {
+		return common.ErrDatabase(err)
+	}
+	return r.UpdateByID(ctx, id, updates)
+}
+
+// UpdateStatus 条件更新采购单状态并记录流转时间，并发流转时只有一个能成功
+func (r *purchaseOrderRepository) UpdateStatus(ctx context.Context, id uint, from, to string, at time.Time) (bool, error) {
+	updates := map[string]any{"status": to}
+	if column := purchase.StatusTimeColumn(to); column != "" {
+		updates[column] = at
+	}
+	affected, err := r.BatchUpdate(ctx, updates,
+		common.Where("id", id),
+		common.Where("status", from),
+	)
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// ReceiveLine 原子地增加明细的已收货数量，超过采购数量时不更新并返回 false
+func (r *purchaseOrderRepository) ReceiveLine(ctx context.Context, lineID uint, quantity int) (bool, error) {
+	result := r.GetDB(ctx).Model(&purchase.Line{}).
+		Where("id = ? AND received_quantity + ? <= quantity", lineID, quantity).
+		Update("received_quantity", gorm.Expr("received_quantity + ?", quantity))
+	if result.Error != nil {
+		return false, common.ErrDatabase(result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// buildQueryOptions 构建查询条件
+func (r *purchaseOrderRepository) buildQueryOptions(query *purchase.QueryOptions) []common.QueryOption {
+	return []common.QueryOption{
+		common.WhereIfNotNil("supplier_id", query.SupplierID),
+		common.WhereIf(query.Status != "", "status", query.Status),
+	}
+}
+
+// preloadPurchaseLines 预加载采购明细（按明细ID排序）
+func preloadPurchaseLines() common.QueryOption {
+	return common.Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	})
+}
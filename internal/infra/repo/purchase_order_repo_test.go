@@ -0,0 +1,106 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/purchase"
+	dbpkg "power-supply-sys/internal/infra/db"
+	"power-supply-sys/pkg/common"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurchaseOrderRepository(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	repo := NewPurchaseOrderRepository(db)
+	ctx := context.Background()
+
+	o := &purchase.Order{
+		OrderNo:     "PO-1",
+		SupplierID:  1,
+		WarehouseID: 1,
+		Status:      purchase.StatusDraft,
+		Lines: []*purchase.Line{
+			{PowerSupplyID: 1, Quantity: 10, UnitCost: 300, Subtotal: 3000},
+			{PowerSupplyID: 2, Quantity: 5, UnitCost: 500, Subtotal: 2500},
+		},
+	}
+	require.NoError(t, repo.Create(ctx, o))
+
+	t.Run("查询包含采购明细", func(t *testing.T) {
+		found, err := repo.FindByID(ctx, o.ID)
+		require.NoError(t, err)
+		require.Len(t, found.Lines, 2)
+		assert.Equal(t, 10, found.Lines[0].Quantity)
+
+		exists, err := repo.ExistsBySupplier(ctx, 1)
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		supplierID := uint(2)
+		total, err := repo.Count(ctx, &purchase.QueryOptions{SupplierID: &supplierID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), total)
+	})
+
+	t.Run("替换采购明细", func(t *testing.T) {
+		err := repo.ReplaceLines(ctx, o.ID, []*purchase.Line{
+			{PowerSupplyID: 3, Quantity: 4, UnitCost: 100, Subtotal: 400},
+		}, map[string]any{"total_amount": 400})
+		require.NoError(t, err)
+
+		found, err := repo.FindByID(ctx, o.ID)
+		require.NoError(t, err)
+		require.Len(t, found.Lines, 1)
+		assert.Equal(t, uint(3), found.Lines[0].PowerSupplyID)
+		assert.Equal(t, 400.0, found.TotalAmount)
+	})
+
+	t.Run("条件更新状态", func(t *testing.T) {
+		ok, err := repo.UpdateStatus(ctx, o.ID, purchase.StatusDraft, purchase.StatusSent, time.Now())
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = repo.UpdateStatus(ctx, o.ID, purchase.StatusDraft, purchase.StatusSent, time.Now())
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		found, err := repo.FindByID(ctx, o.ID)
+		require.NoError(t, err)
+		assert.NotNil(t, found.SentAt)
+
+		list, err := repo.List(ctx, &purchase.QueryOptions{Status: purchase.StatusSent})
+		require.NoError(t, err)
+		assert.Len(t, list, 1)
+	})
+
+	t.Run("收货数量不能超过采购数量", func(t *testing.T) {
+		found, err := repo.FindByID(ctx, o.ID)
+		require.NoError(t, err)
+		lineID := found.Lines[0].ID
+
+		ok, err := repo.ReceiveLine(ctx, lineID, 3)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = repo.ReceiveLine(ctx, lineID, 2)
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		ok, err = repo.ReceiveLine(ctx, lineID, 1)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		found, err = repo.FindByID(ctx, o.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 4, found.Lines[0].ReceivedQuantity)
+		assert.True(t, found.FullyReceived())
+	})
+}
@@ -0,0 +1,90 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/rbac"
+	"power-supply-sys/pkg/common"
+
+	"gorm.io/gorm"
+)
+
+// rbacRepository 角色权限数据访问层实现
+type rbacRepository struct {
+	roles       *common.BaseRepository[rbac.Role]
+	permissions *common.BaseRepository[rbac.Permission]
+}
+
+// NewRBACRepository 创建角色权限仓储
+func NewRBACRepository(db *gorm.DB) rbac.Repository {
+	return &rbacRepository{
+		roles:       common.NewBaseRepository[rbac.Role](db),
+		permissions: common.NewBaseRepository[rbac.Permission](db),
+	}
+}
+
+// FindRoleByID 根据ID查询角色（含权限）
+func (r *rbacRepository) FindRoleByID(ctx context.Context, id uint) (*rbac.Role, error) {
+	role, err := r.roles.FindOne(ctx, common.Where("id", id), common.Preload("Permissions"))
+	if err != nil && common.HasErrorCode(err, common.ErrCodeNotFound) {
+		return nil, common.ErrNotFound("角色")
+	}
+	return role, err
+}
+
+// FindRoleByCode 根据编码查询角色（含权限）
+func (r *rbacRepository) FindRoleByCode(ctx context.Context, code string) (*rbac.Role, error) {
+	role, err := r.roles.FindOne(ctx, common.Where("code", code), common.Preload("Permissions"))
+	if err != nil && common.HasErrorCode(err, common.ErrCodeNotFound) {
+		return nil, common.ErrNotFound("角色")
+	}
+	return role, err
+}
+
+// ListRoles 查询全部角色（含权限）
+func (r *rbacRepository) ListRoles(ctx context.Context) ([]*rbac.Role, error) {
+	return r.roles.List(ctx, common.Preload("Permissions"), common.OrderBy("id"))
+}
+
+// ListPermissions 查询全部权限
+func (r *rbacRepository) ListPermissions(ctx context.Context) ([]*rbac.Permission, error) {
+	return r.permissions.List(ctx, common.OrderBy("id"))
+}
+
+// FindPermissionsByCodes 根据编码批量查询权限
+func (r *rbacRepository) FindPermissionsByCodes(ctx context.Context, codes []string) ([]*rbac.Permission, error) {
+	if len(codes) == 0 {
+		return []*rbac.Permission{}, nil
+	}
+	return r.permissions.List(ctx, common.WhereIn("code", codes))
+}
+
+// RoleExists 角色是否存在
+func (r *rbacRepository) RoleExists(ctx context.Context, code string) (bool, error) {
+	return r.roles.Exists(ctx, common.Where("code", code))
+}
+
+// HasPermission 角色是否拥有指定权限
+func (r *rbacRepository) HasPermission(ctx context.Context, role, permission string) (bool, error) {
+	if role == "" || permission == "" {
+		return false, nil
+	}
+	return r.permissions.Exists(ctx,
+		common.Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id"),
+		common.Joins("JOIN roles ON roles.id = role_permissions.role_id"),
+		common.Where("roles.code", role),
+		common.Where("permissions.code", permission),
+	)
+}
+
+// CreateRole 创建角色（同时写入关联权限）
+func (r *rbacRepository) CreateRole(ctx context.Context, role *rbac.Role) error {
+	return r.roles.Create(ctx, role)
+}
+
+// ReplaceRolePermissions 替换角色的权限集合
+func (r *rbacRepository) ReplaceRolePermissions(ctx context.Context, role *rbac.Role, permissions []*rbac.Permission) error {
+	if err := r.roles.GetDB(ctx).Model(role).Association("Permissions").Replace(permissions); err != nil {
+		return common.ErrDatabase(err)
+	}
+	return nil
+}
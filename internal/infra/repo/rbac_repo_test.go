@@ -0,0 +1,96 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/rbac"
+	dbpkg "power-supply-sys/internal/infra/db"
+	"power-supply-sys/pkg/common"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRBACRepository_Seed(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	// 重复迁移不应产生重复数据
+	require.NoError(t, dbpkg.Migrate(db))
+	require.NoError(t, dbpkg.Migrate(db))
+
+	repo := NewRBACRepository(db)
+	ctx := context.Background()
+
+	permissions, err := repo.ListPermissions(ctx)
+	require.NoError(t, err)
+	assert.Len(t, permissions, len(rbac.DefaultPermissions))
+
+	admin, err := repo.FindRoleByCode(ctx, rbac.RoleAdmin)
+	require.NoError(t, err)
+	assert.Len(t, admin.Permissions, len(rbac.DefaultPermissions))
+	assert.True(t, admin.BuiltIn)
+
+	userRole, err := repo.FindRoleByCode(ctx, rbac.RoleUser)
+	require.NoError(t, err)
+	require.Len(t, userRole.Permissions, 2)
+	assert.Equal(t, rbac.PermPowerRead, userRole.Permissions[0].Code)
+	assert.Equal(t, rbac.PermOrderCreate, userRole.Permissions[1].Code)
+}
+
+func TestRBACRepository_HasPermission(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	repo := NewRBACRepository(db)
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		role       string
+		permission string
+		want       bool
+	}{
+		{name: "管理员拥有写权限", role: rbac.RoleAdmin, permission: rbac.PermPowerWrite, want: true},
+		{name: "普通用户拥有读权限", role: rbac.RoleUser, permission: rbac.PermPowerRead, want: true},
+		{name: "普通用户没有写权限", role: rbac.RoleUser, permission: rbac.PermPowerWrite, want: false},
+		{name: "未知角色", role: "unknown", permission: rbac.PermPowerRead, want: false},
+		{name: "空角色", role: "", permission: rbac.PermPowerRead, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.HasPermission(ctx, tt.role, tt.permission)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRBACRepository_ReplaceRolePermissions(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	repo := NewRBACRepository(db)
+	ctx := context.Background()
+
+	perms, err := repo.FindPermissionsByCodes(ctx, []string{rbac.PermPowerRead})
+	require.NoError(t, err)
+
+	role := &rbac.Role{Code: "editor", Name: "编辑", Permissions: perms}
+	require.NoError(t, repo.CreateRole(ctx, role))
+
+	newPerms, err := repo.FindPermissionsByCodes(ctx, []string{rbac.PermPowerRead, rbac.PermPowerWrite})
+	require.NoError(t, err)
+	require.NoError(t, repo.ReplaceRolePermissions(ctx, role, newPerms))
+
+	ok, err := repo.HasPermission(ctx, "editor", rbac.PermPowerWrite)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
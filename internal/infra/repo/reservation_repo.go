@@ -0,0 +1,44 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/inventory"
+	"power-supply-sys/pkg/common"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// reservationRepository 库存预留数据访问层实现
+type reservationRepository struct {
+	*common.BaseRepository[inventory.Reservation]
+}
+
+// NewReservationRepository 创建库存预留仓储
+func NewReservationRepository(db *gorm.DB) inventory.ReservationRepository {
+	return &reservationRepository{
+		BaseRepository: common.NewBaseRepository[inventory.Reservation](db),
+	}
+}
+
+// UpdateStatus 条件更新预留状态，并发流转时只有一个能成功
+func (r *reservationRepository) UpdateStatus(ctx context.Context, id uint, from, to string) (bool, error) {
+	affected, err := r.BatchUpdate(ctx, map[string]any{"status": to},
+		common.Where("id", id),
+		common.Where("status", from),
+	)
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// ListExpired 查询已过期但仍处于预留中的记录（最早过期的在前）
+func (r *reservationRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]*inventory.Reservation, error) {
+	return r.List(ctx,
+		common.Where("status", inventory.ReservationPending),
+		common.WhereLTE("expires_at", now),
+		common.OrderBy("expires_at"),
+		common.Limit(limit),
+	)
+}
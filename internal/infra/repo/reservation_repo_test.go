@@ -0,0 +1,54 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/inventory"
+	dbpkg "power-supply-sys/internal/infra/db"
+	"power-supply-sys/pkg/common"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReservationRepository(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	repo := NewReservationRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	expired := &inventory.Reservation{PowerSupplyID: 1, Quantity: 1, Status: inventory.ReservationPending, ExpiresAt: now.Add(-time.Minute)}
+	active := &inventory.Reservation{PowerSupplyID: 1, Quantity: 2, Status: inventory.ReservationPending, ExpiresAt: now.Add(time.Hour)}
+	cancelled := &inventory.Reservation{PowerSupplyID: 1, Quantity: 3, Status: inventory.ReservationCancelled, ExpiresAt: now.Add(-time.Hour)}
+	for _, r := range []*inventory.Reservation{expired, active, cancelled} {
+		require.NoError(t, repo.Create(ctx, r))
+	}
+
+	t.Run("查询已过期的预留", func(t *testing.T) {
+		list, err := repo.ListExpired(ctx, now, 10)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, expired.ID, list[0].ID)
+	})
+
+	t.Run("条件更新状态", func(t *testing.T) {
+		ok, err := repo.UpdateStatus(ctx, active.ID, inventory.ReservationPending, inventory.ReservationConfirmed)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		// 状态已变化，再次流转失败
+		ok, err = repo.UpdateStatus(ctx, active.ID, inventory.ReservationPending, inventory.ReservationCancelled)
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		found, err := repo.FindByID(ctx, active.ID)
+		require.NoError(t, err)
+		assert.Equal(t, inventory.ReservationConfirmed, found.Status)
+	})
+}
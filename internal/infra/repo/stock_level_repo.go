@@ -0,0 +1,75 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/warehouse"
+	"power-supply-sys/pkg/common"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// stockLevelRepository 仓库库存数据访问层实现
+type stockLevelRepository struct {
+	*common.BaseRepository[warehouse.StockLevel]
+}
+
+// NewStockLevelRepository 创建仓库库存仓储
+func NewStockLevelRepository(db *gorm.DB) warehouse.StockLevelRepository {
+	return &stockLevelRepository{
+		BaseRepository: common.NewBaseRepository[warehouse.StockLevel](db),
+	}
+}
+
+// ListByPowerSupply 查询电源在各仓库的库存
+func (r *stockLevelRepository) ListByPowerSupply(ctx context.Context, powerSupplyID uint) ([]*warehouse.StockLevel, error) {
+	return r.List(ctx,
+		common.Where("power_supply_id", powerSupplyID),
+		common.Preload("Warehouse"),
+		common.OrderBy("warehouse_id"),
+	)
+}
+
+// ListInStock 查询电源有库存的仓库库存记录（库存多的在前，相同时按仓库 ID 排序）
+func (r *stockLevelRepository) ListInStock(ctx context.Context, powerSupplyID uint) ([]*warehouse.StockLevel, error) {
+	return r.List(ctx,
+		common.Where("power_supply_id", powerSupplyID),
+		common.WhereGT("quantity", 0),
+		common.OrderByMulti("quantity DESC", "warehouse_id"),
+	)
+}
+
+// Adjust 原子地调整电源在仓库的库存，扣减后为负数时不更新并返回 false
+// 增加库存时记录不存在则创建
+func (r *stockLevelRepository) Adjust(ctx context.Context, warehouseID, powerSupplyID uint, delta int) (bool, error) {
+	if delta > 0 {
+		now := time.Now()
+		err := r.GetDB(ctx).Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "warehouse_id"}, {Name: "power_supply_id"}},
+			DoUpdates: clause.Set{
+				{Column: clause.Column{Name: "quantity"}, Value: gorm.Expr("quantity + ?", delta)},
+				{Column: clause.Column{Name: "updated_at"}, Value: now},
+			},
+		}).Create(&warehouse.StockLevel{
+			WarehouseID:   warehouseID,
+			PowerSupplyID: powerSupplyID,
+			Quantity:      delta,
+			UpdatedAt:     now,
+		}).Error
+		if err != nil {
+			return false, common.ErrDatabase(err)
+		}
+		return true, nil
+	}
+
+	affected, err := r.BatchUpdate(ctx, map[string]any{"quantity": gorm.Expr("quantity + ?", delta)},
+		common.Where("warehouse_id", warehouseID),
+		common.Where("power_supply_id", powerSupplyID),
+		common.WhereGTE("quantity", -delta),
+	)
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
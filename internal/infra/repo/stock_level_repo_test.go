@@ -0,0 +1,85 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/warehouse"
+	dbpkg "power-supply-sys/internal/infra/db"
+	"power-supply-sys/pkg/common"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStockLevelRepository(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	repo := NewStockLevelRepository(db)
+	warehouseRepo := NewWarehouseRepository(db)
+	ctx := context.Background()
+
+	main, err := warehouseRepo.FindDefault(ctx)
+	require.NoError(t, err)
+	second := &warehouse.Warehouse{Code: "SH", Name: "上海仓"}
+	require.NoError(t, warehouseRepo.Create(ctx, second))
+
+	t.Run("入库时创建并累加库存", func(t *testing.T) {
+		ok, err := repo.Adjust(ctx, main.ID, 1, 5)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		ok, err = repo.Adjust(ctx, main.ID, 1, 3)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		ok, err = repo.Adjust(ctx, second.ID, 1, 10)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		levels, err := repo.ListByPowerSupply(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, levels, 2)
+		assert.Equal(t, 8, levels[0].Quantity)
+		require.NotNil(t, levels[0].Warehouse)
+		assert.Equal(t, main.Code, levels[0].Warehouse.Code)
+		assert.Equal(t, 10, levels[1].Quantity)
+	})
+
+	t.Run("扣减不能为负", func(t *testing.T) {
+		ok, err := repo.Adjust(ctx, main.ID, 1, -9)
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		ok, err = repo.Adjust(ctx, main.ID, 1, -8)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		// 没有库存记录的仓库不能扣减
+		ok, err = repo.Adjust(ctx, second.ID, 2, -1)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("查询有库存的仓库", func(t *testing.T) {
+		// 主仓库存已扣减为 0，不在结果中
+		levels, err := repo.ListInStock(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, levels, 1)
+		assert.Equal(t, second.ID, levels[0].WarehouseID)
+
+		ok, err := repo.Adjust(ctx, main.ID, 1, 12)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		levels, err = repo.ListInStock(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, levels, 2)
+		assert.Equal(t, main.ID, levels[0].WarehouseID)
+		assert.Equal(t, second.ID, levels[1].WarehouseID)
+
+		levels, err = repo.ListInStock(ctx, 2)
+		require.NoError(t, err)
+		assert.Empty(t, levels)
+	})
+}
@@ -0,0 +1,43 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/inventory"
+	"power-supply-sys/pkg/common"
+
+	"gorm.io/gorm"
+)
+
+// stockMovementRepository 库存流水数据访问层实现
+type stockMovementRepository struct {
+	*common.BaseRepository[inventory.Movement]
+}
+
+// NewStockMovementRepository 创建库存流水仓储
+func NewStockMovementRepository(db *gorm.DB) inventory.Repository {
+	return &stockMovementRepository{
+		BaseRepository: common.NewBaseRepository[inventory.Movement](db),
+	}
+}
+
+// Count 统计库存流水数量
+func (r *stockMovementRepository) Count(ctx context.Context, query *inventory.QueryOptions) (int64, error) {
+	return r.BaseRepository.Count(ctx,
+		common.Where("power_supply_id", query.PowerSupplyID),
+		common.WhereIf(query.WarehouseID != 0, "warehouse_id", query.WarehouseID),
+		common.WhereIf(query.Type != "", "type", query.Type),
+		common.WhereIf(query.Reference != "", "reference", query.Reference),
+	)
+}
+
+// List 查询库存流水（最新的在前）
+func (r *stockMovementRepository) List(ctx context.Context, query *inventory.QueryOptions) ([]*inventory.Movement, error) {
+	return r.BaseRepository.List(ctx,
+		common.Where("power_supply_id", query.PowerSupplyID),
+		common.WhereIf(query.WarehouseID != 0, "warehouse_id", query.WarehouseID),
+		common.WhereIf(query.Type != "", "type", query.Type),
+		common.WhereIf(query.Reference != "", "reference", query.Reference),
+		common.OrderByDesc("id"),
+		common.Paginate(query.Page, query.PageSize),
+	)
+}
@@ -0,0 +1,46 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/inventory"
+	dbpkg "power-supply-sys/internal/infra/db"
+	"power-supply-sys/pkg/common"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStockMovementRepository(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	repo := NewStockMovementRepository(db)
+	ctx := context.Background()
+
+	require.NoError(t, repo.Create(ctx, &inventory.Movement{PowerSupplyID: 1, Type: inventory.MovementReceipt, Quantity: 10, BalanceAfter: 10}))
+	require.NoError(t, repo.Create(ctx, &inventory.Movement{PowerSupplyID: 1, Type: inventory.MovementSale, Quantity: -2, BalanceAfter: 8, Reference: "SO-1"}))
+	require.NoError(t, repo.Create(ctx, &inventory.Movement{PowerSupplyID: 2, Type: inventory.MovementReceipt, Quantity: 5, BalanceAfter: 5}))
+
+	t.Run("按电源查询", func(t *testing.T) {
+		movements, err := repo.List(ctx, &inventory.QueryOptions{PowerSupplyID: 1, Page: 1, PageSize: 10})
+		require.NoError(t, err)
+		require.Len(t, movements, 2)
+		assert.Equal(t, inventory.MovementSale, movements[0].Type)
+		assert.Equal(t, "SO-1", movements[0].Reference)
+
+		total, err := repo.Count(ctx, &inventory.QueryOptions{PowerSupplyID: 1})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+	})
+
+	t.Run("按类型过滤", func(t *testing.T) {
+		movements, err := repo.List(ctx, &inventory.QueryOptions{PowerSupplyID: 1, Type: inventory.MovementReceipt, Page: 1, PageSize: 10})
+		require.NoError(t, err)
+		require.Len(t, movements, 1)
+		assert.Equal(t, 10, movements[0].BalanceAfter)
+	})
+}
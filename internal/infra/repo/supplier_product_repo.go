@@ -0,0 +1,61 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/supplier"
+	"power-supply-sys/pkg/common"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// supplierProductRepository 供应商电源数据访问层实现
+type supplierProductRepository struct {
+	*common.BaseRepository[supplier.Product]
+}
+
+// NewSupplierProductRepository 创建供应商电源仓储
+func NewSupplierProductRepository(db *gorm.DB) supplier.ProductRepository {
+	return &supplierProductRepository{
+		BaseRepository: common.NewBaseRepository[supplier.Product](db),
+	}
+}
+
+// FindBySupplier 查询供应商供应的指定电源
+func (r *supplierProductRepository) FindBySupplier(ctx context.Context, supplierID, powerSupplyID uint) (*supplier.Product, error) {
+	p, err := r.FindOne(ctx,
+		common.Where("supplier_id", supplierID),
+		common.Where("power_supply_id", powerSupplyID),
+	)
+	if err != nil && common.HasErrorCode(err, common.ErrCodeNotFound) {
+		return nil, common.ErrNotFound("供应商电源")
+	}
+	return p, err
+}
+
+// Save 创建或更新供应商供应的电源（按供应商与电源唯一）
+func (r *supplierProductRepository) Save(ctx context.Context, p *supplier.Product) error {
+	err := r.GetDB(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "supplier_id"}, {Name: "power_supply_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"supplier_sku", "cost_price", "lead_time_days", "updated_at"}),
+	}).Create(p).Error
+	if err != nil {
+		return common.ErrDatabase(err)
+	}
+	return nil
+}
+
+// Remove 删除供应商供应的电源，记录不存在时返回 false
+func (r *supplierProductRepository) Remove(ctx context.Context, supplierID, powerSupplyID uint) (bool, error) {
+	err := r.DeleteByCondition(ctx,
+		common.Where("supplier_id", supplierID),
+		common.Where("power_supply_id", powerSupplyID),
+	)
+	if err != nil {
+		if common.HasErrorCode(err, common.ErrCodeNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
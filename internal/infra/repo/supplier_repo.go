@@ -0,0 +1,74 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/supplier"
+	"power-supply-sys/pkg/common"
+
+	"gorm.io/gorm"
+)
+
+// supplierRepository 供应商数据访问层实现
+type supplierRepository struct {
+	*common.BaseRepository[supplier.Supplier]
+}
+
+// NewSupplierRepository 创建供应商仓储
+func NewSupplierRepository(db *gorm.DB) supplier.Repository {
+	return &supplierRepository{
+		BaseRepository: common.NewBaseRepository[supplier.Supplier](db),
+	}
+}
+
+// FindByID 根据ID查询供应商（含供应的电源）
+func (r *supplierRepository) FindByID(ctx context.Context, id uint) (*supplier.Supplier, error) {
+	s, err := r.FindOne(ctx, common.Where("id", id), common.Preload("Products", func(db *gorm.DB) *gorm.DB {
+		return db.Order("power_supply_id")
+	}))
+	if err != nil && common.HasErrorCode(err, common.ErrCodeNotFound) {
+		return nil, common.ErrNotFound("供应商")
+	}
+	return s, err
+}
+
+// Count 统计供应商数量
+func (r *supplierRepository) Count(ctx context.Context, query *supplier.QueryOptions) (int64, error) {
+	return r.BaseRepository.Count(ctx, r.buildQueryOptions(query)...)
+}
+
+// List 查询供应商列表
+func (r *supplierRepository) List(ctx context.Context, query *supplier.QueryOptions) ([]*supplier.Supplier, error) {
+	opts := append(r.buildQueryOptions(query),
+		common.OrderBy("id"),
+		common.Paginate(query.Page, query.PageSize),
+	)
+	return r.BaseRepository.List(ctx, opts...)
+}
+
+// ExistsByName 检查供应商名称是否存在
+func (r *supplierRepository) ExistsByName(ctx context.Context, name string) (bool, error) {
+	return r.Exists(ctx, common.Where("name", name))
+}
+
+// Delete 删除供应商及其供应的电源（需在事务中调用）
+func (r *supplierRepository) Delete(ctx context.Context, id uint) error {
+	if err := r.GetDB(ctx).Where("supplier_id = ?", id).Delete(&supplier.Product{}).Error; err != nil {
+		return common.ErrDatabase(err)
+	}
+	err := r.BaseRepository.Delete(ctx, id)
+	if err != nil && common.HasErrorCode(err, common.ErrCodeNotFound) {
+		return common.ErrNotFound("供应商")
+	}
+	return err
+}
+
+// buildQueryOptions 构建查询条件
+func (r *supplierRepository) buildQueryOptions(query *supplier.QueryOptions) []common.QueryOption {
+	opts := []common.QueryOption{
+		common.WhereLike("name", query.Name),
+	}
+	if query.PowerSupplyID != 0 {
+		opts = append(opts, common.WhereRaw("id IN (SELECT supplier_id FROM supplier_products WHERE power_supply_id = ?)", query.PowerSupplyID))
+	}
+	return opts
+}
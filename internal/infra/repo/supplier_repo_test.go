@@ -0,0 +1,81 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/supplier"
+	dbpkg "power-supply-sys/internal/infra/db"
+	"power-supply-sys/pkg/common"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSupplierRepository(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	repo := NewSupplierRepository(db)
+	productRepo := NewSupplierProductRepository(db)
+	ctx := context.Background()
+
+	acme := &supplier.Supplier{Name: "Acme Power", ContactName: "张三"}
+	other := &supplier.Supplier{Name: "Other Trading"}
+	require.NoError(t, repo.Create(ctx, acme))
+	require.NoError(t, repo.Create(ctx, other))
+
+	t.Run("创建与覆盖供应商电源", func(t *testing.T) {
+		require.NoError(t, productRepo.Save(ctx, &supplier.Product{SupplierID: acme.ID, PowerSupplyID: 2, CostPrice: 500, LeadTimeDays: 7}))
+		require.NoError(t, productRepo.Save(ctx, &supplier.Product{SupplierID: acme.ID, PowerSupplyID: 1, CostPrice: 300, LeadTimeDays: 3}))
+		require.NoError(t, productRepo.Save(ctx, &supplier.Product{SupplierID: acme.ID, PowerSupplyID: 1, CostPrice: 280, LeadTimeDays: 5, SupplierSKU: "AC-1"}))
+
+		p, err := productRepo.FindBySupplier(ctx, acme.ID, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 280.0, p.CostPrice)
+		assert.Equal(t, 5, p.LeadTimeDays)
+		assert.Equal(t, "AC-1", p.SupplierSKU)
+
+		found, err := repo.FindByID(ctx, acme.ID)
+		require.NoError(t, err)
+		require.Len(t, found.Products, 2)
+		assert.Equal(t, uint(1), found.Products[0].PowerSupplyID)
+	})
+
+	t.Run("按名称与供应的电源筛选", func(t *testing.T) {
+		list, err := repo.List(ctx, &supplier.QueryOptions{Name: "Acme"})
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+
+		total, err := repo.Count(ctx, &supplier.QueryOptions{PowerSupplyID: 2})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+
+		exists, err := repo.ExistsByName(ctx, "Other Trading")
+		require.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("删除供应商电源", func(t *testing.T) {
+		removed, err := productRepo.Remove(ctx, acme.ID, 2)
+		require.NoError(t, err)
+		assert.True(t, removed)
+
+		removed, err = productRepo.Remove(ctx, acme.ID, 2)
+		require.NoError(t, err)
+		assert.False(t, removed)
+
+		_, err = productRepo.FindBySupplier(ctx, acme.ID, 2)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+
+	t.Run("删除供应商同时删除供应的电源", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, acme.ID))
+		_, err := repo.FindByID(ctx, acme.ID)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+		_, err = productRepo.FindBySupplier(ctx, acme.ID, 1)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+}
@@ -0,0 +1,78 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/catalog"
+	"power-supply-sys/pkg/common"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// tagRepository 电源标签数据访问层实现
+type tagRepository struct {
+	*common.BaseRepository[catalog.Tag]
+}
+
+// NewTagRepository 创建标签仓储
+func NewTagRepository(db *gorm.DB) catalog.TagRepository {
+	return &tagRepository{
+		BaseRepository: common.NewBaseRepository[catalog.Tag](db),
+	}
+}
+
+// FindByID 根据ID查询标签
+func (r *tagRepository) FindByID(ctx context.Context, id uint) (*catalog.Tag, error) {
+	t, err := r.BaseRepository.FindByID(ctx, id)
+	if err != nil && common.HasErrorCode(err, common.ErrCodeNotFound) {
+		return nil, common.ErrNotFound("标签")
+	}
+	return t, err
+}
+
+// FindByNames 按名称批量查询标签（不区分大小写）
+func (r *tagRepository) FindByNames(ctx context.Context, names []string) ([]*catalog.Tag, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	lower := make([]string, len(names))
+	for i, name := range names {
+		lower[i] = strings.ToLower(name)
+	}
+	return r.BaseRepository.List(ctx, common.WhereIn("LOWER(name)", lower), common.OrderBy("LOWER(name)"))
+}
+
+// Count 统计标签数量
+func (r *tagRepository) Count(ctx context.Context, query *catalog.TagQueryOptions) (int64, error) {
+	return r.BaseRepository.Count(ctx, common.WhereLike("name", query.Name))
+}
+
+// List 查询标签列表（按名称排序，不区分大小写）
+func (r *tagRepository) List(ctx context.Context, query *catalog.TagQueryOptions) ([]*catalog.Tag, error) {
+	return r.BaseRepository.List(ctx,
+		common.WhereLike("name", query.Name),
+		common.OrderBy("LOWER(name)"),
+		common.Paginate(query.Page, query.PageSize),
+	)
+}
+
+// UpdateByID 更新标签
+func (r *tagRepository) UpdateByID(ctx context.Context, id uint, updates map[string]any) error {
+	err := r.BaseRepository.UpdateByID(ctx, id, updates)
+	if err != nil && common.HasErrorCode(err, common.ErrCodeNotFound) {
+		return common.ErrNotFound("标签")
+	}
+	return err
+}
+
+// Delete 删除标签及其与电源的关联（需在事务中调用）
+func (r *tagRepository) Delete(ctx context.Context, id uint) error {
+	if err := r.GetDB(ctx).Exec("DELETE FROM power_supply_tags WHERE tag_id = ?", id).Error; err != nil {
+		return common.ErrDatabase(err)
+	}
+	err := r.BaseRepository.Delete(ctx, id)
+	if err != nil && common.HasErrorCode(err, common.ErrCodeNotFound) {
+		return common.ErrNotFound("标签")
+	}
+	return err
+}
@@ -0,0 +1,65 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/catalog"
+	"power-supply-sys/internal/domain/power"
+	dbpkg "power-supply-sys/internal/infra/db"
+	"power-supply-sys/pkg/common"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagRepository(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	repo := NewTagRepository(db)
+	powerRepo := NewPowerRepository(db)
+	ctx := context.Background()
+
+	for _, name := range []string{"white", "Quiet", "RGB"} {
+		require.NoError(t, repo.Create(ctx, &catalog.Tag{Name: name}))
+	}
+
+	t.Run("按名称查询不区分大小写", func(t *testing.T) {
+		tags, err := repo.FindByNames(ctx, []string{"quiet", "WHITE", "unknown"})
+		require.NoError(t, err)
+		require.Len(t, tags, 2)
+		assert.Equal(t, "Quiet", tags[0].Name)
+		assert.Equal(t, "white", tags[1].Name)
+	})
+
+	t.Run("分页查询", func(t *testing.T) {
+		total, err := repo.Count(ctx, &catalog.TagQueryOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+
+		tags, err := repo.List(ctx, &catalog.TagQueryOptions{Page: 2, PageSize: 2})
+		require.NoError(t, err)
+		require.Len(t, tags, 1)
+		assert.Equal(t, "white", tags[0].Name)
+	})
+
+	t.Run("删除标签同时删除电源关联", func(t *testing.T) {
+		tags, err := repo.FindByNames(ctx, []string{"RGB", "white"})
+		require.NoError(t, err)
+		ps := &power.PowerSupply{Name: "RGB 850", Power: 850, Price: 799, Status: 1}
+		require.NoError(t, powerRepo.Create(ctx, ps))
+		require.NoError(t, powerRepo.ReplaceTags(ctx, ps, tags))
+
+		require.NoError(t, repo.Delete(ctx, tags[0].ID))
+		found, err := powerRepo.FindDetail(ctx, ps.ID)
+		require.NoError(t, err)
+		require.Len(t, found.Tags, 1)
+		assert.Equal(t, "white", found.Tags[0].Name)
+
+		err = repo.Delete(ctx, tags[0].ID)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+}
@@ -0,0 +1,169 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/token"
+	"power-supply-sys/pkg/common"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// refreshTokenRepository 刷新令牌与登录会话数据访问层实现
+type refreshTokenRepository struct {
+	*common.BaseRepository[token.RefreshToken]
+	sessions *common.BaseRepository[token.Session]
+}
+
+// NewRefreshTokenRepository 创建刷新令牌仓储
+func NewRefreshTokenRepository(db *gorm.DB) token.Repository {
+	return &refreshTokenRepository{
+		BaseRepository: common.NewBaseRepository[token.RefreshToken](db),
+		sessions:       common.NewBaseRepository[token.Session](db),
+	}
+}
+
+// FindByHash 根据令牌摘要查询
+func (r *refreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*token.RefreshToken, error) {
+	return r.FindOne(ctx, common.Where("token_hash", tokenHash))
+}
+
+// HasActiveInFamily 令牌族中是否存在可用（未轮换、未吊销、未过期）的令牌
+func (r *refreshTokenRepository) HasActiveInFamily(ctx context.Context, familyID string, now time.Time) (bool, error) {
+	return r.Exists(ctx,
+		common.Where("family_id", familyID),
+		common.WhereNull("used_at"),
+		common.WhereNull("revoked_at"),
+		common.WhereGT("expires_at", now),
+	)
+}
+
+// MarkUsed 将令牌标记为已轮换（条件更新，保证并发下只有一次成功）
+func (r *refreshTokenRepository) MarkUsed(ctx context.Context, id uint, usedAt time.Time) (bool, error) {
+	affected, err := r.BatchUpdate(ctx, map[string]any{"used_at": usedAt},
+		common.Where("id", id),
+		common.WhereNull("used_at"),
+		common.WhereNull("revoked_at"),
+	)
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// FindSession 根据会话 ID 查询会话
+func (r *refreshTokenRepository) FindSession(ctx context.Context, sessionID string) (*token.Session, error) {
+	s, err := r.sessions.FindOne(ctx, common.Where("session_id", sessionID))
+	if err != nil && common.HasErrorCode(err, common.ErrCodeNotFound) {
+		return nil, common.ErrNotFound("会话")
+	}
+	return s, err
+}
+
+// FindUserSession 根据主键查询用户本人的会话
+func (r *refreshTokenRepository) FindUserSession(ctx context.Context, userID, id uint) (*token.Session, error) {
+	s, err := r.sessions.FindOne(ctx, common.Where("id", id), common.Where("user_id", userID))
+	if err != nil && common.HasErrorCode(err, common.ErrCodeNotFound) {
+		return nil, common.ErrNotFound("会话")
+	}
+	return s, err
+}
+
+// ListActiveSessions 查询用户未终止且未过期的会话
+func (r *refreshTokenRepository) ListActiveSessions(ctx context.Context, userID uint, now time.Time) ([]*token.Session, error) {
+	return r.sessions.List(ctx,
+		common.Where("user_id", userID),
+		common.WhereNull("revoked_at"),
+		common.WhereGT("expires_at", now),
+		common.OrderByDesc("last_seen_at"),
+	)
+}
+
+// CreateSession 创建登录会话
+func (r *refreshTokenRepository) CreateSession(ctx context.Context, s *token.Session) error {
+	return r.sessions.Create(ctx, s)
+}
+
+// TouchSession 更新会话最近活跃时间
+func (r *refreshTokenRepository) TouchSession(ctx context.Context, sessionID string, seenAt time.Time) error {
+	_, err := r.sessions.BatchUpdate(ctx, map[string]any{"last_seen_at": seenAt},
+		common.Where("session_id", sessionID),
+	)
+	return err
+}
+
+// ExtendSession 更新会话的活跃时间与过期时间
+func (r *refreshTokenRepository) ExtendSession(ctx context.Context, sessionID string, seenAt, expiresAt time.Time) error {
+	_, err := r.sessions.BatchUpdate(ctx, map[string]any{"last_seen_at": seenAt, "expires_at": expiresAt},
+		common.Where("session_id", sessionID),
+	)
+	return err
+}
+
+// RevokeFamily 吊销整个令牌族并终止对应会话（先终止会话，保证 access token 立即失效）
+func (r *refreshTokenRepository) RevokeFamily(ctx context.Context, familyID string, revokedAt time.Time) error {
+	if _, err := r.sessions.BatchUpdate(ctx, map[string]any{"revoked_at": revokedAt},
+		common.Where("session_id", familyID),
+		common.WhereNull("revoked_at"),
+	); err != nil {
+		return err
+	}
+	_, err := r.BatchUpdate(ctx, map[string]any{"revoked_at": revokedAt},
+		common.Where("family_id", familyID),
+		common.WhereNull("revoked_at"),
+	)
+	return err
+}
+
+// RevokeByUserID 吊销用户的所有令牌族并终止所有会话
+func (r *refreshTokenRepository) RevokeByUserID(ctx context.Context, userID uint, revokedAt time.Time) error {
+	if _, err := r.sessions.BatchUpdate(ctx, map[string]any{"revoked_at": revokedAt},
+		common.Where("user_id", userID),
+		common.WhereNull("revoked_at"),
+	); err != nil {
+		return err
+	}
+	_, err := r.BatchUpdate(ctx, map[string]any{"revoked_at": revokedAt},
+		common.Where("user_id", userID),
+		common.WhereNull("revoked_at"),
+	)
+	return err
+}
+
+// passwordResetRepository 密码重置令牌数据访问层实现
+type passwordResetRepository struct {
+	*common.BaseRepository[token.PasswordResetToken]
+}
+
+// NewPasswordResetRepository 创建密码重置令牌仓储
+func NewPasswordResetRepository(db *gorm.DB) token.PasswordResetRepository {
+	return &passwordResetRepository{
+		BaseRepository: common.NewBaseRepository[token.PasswordResetToken](db),
+	}
+}
+
+// FindByHash 根据令牌摘要查询
+func (r *passwordResetRepository) FindByHash(ctx context.Context, tokenHash string) (*token.PasswordResetToken, error) {
+	return r.FindOne(ctx, common.Where("token_hash", tokenHash))
+}
+
+// MarkUsed 将重置令牌标记为已使用（条件更新，保证只能使用一次）
+func (r *passwordResetRepository) MarkUsed(ctx context.Context, id uint, usedAt time.Time) (bool, error) {
+	affected, err := r.BatchUpdate(ctx, map[string]any{"used_at": usedAt},
+		common.Where("id", id),
+		common.WhereNull("used_at"),
+	)
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// InvalidateByUserID 作废用户所有未使用的重置令牌
+func (r *passwordResetRepository) InvalidateByUserID(ctx context.Context, userID uint, usedAt time.Time) error {
+	_, err := r.BatchUpdate(ctx, map[string]any{"used_at": usedAt},
+		common.Where("user_id", userID),
+		common.WhereNull("used_at"),
+	)
+	return err
+}
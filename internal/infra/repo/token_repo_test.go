@@ -0,0 +1,262 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/token"
+	dbpkg "power-supply-sys/internal/infra/db"
+	"power-supply-sys/pkg/common"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshTokenRepository_FindByHash(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	repo := NewRefreshTokenRepository(db)
+	ctx := context.Background()
+
+	rt := &token.RefreshToken{
+		UserID:    1,
+		FamilyID:  "family-1",
+		TokenHash: "hash-1",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	err = repo.Create(ctx, rt)
+	require.NoError(t, err)
+
+	t.Run("成功查找令牌", func(t *testing.T) {
+		found, err := repo.FindByHash(ctx, "hash-1")
+		assert.NoError(t, err)
+		assert.Equal(t, rt.ID, found.ID)
+		assert.Equal(t, "family-1", found.FamilyID)
+	})
+
+	t.Run("查找不存在的令牌", func(t *testing.T) {
+		found, err := repo.FindByHash(ctx, "missing")
+		assert.Error(t, err)
+		assert.Nil(t, found)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+}
+
+func TestRefreshTokenRepository_MarkUsed(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	repo := NewRefreshTokenRepository(db)
+	ctx := context.Background()
+
+	rt := &token.RefreshToken{
+		UserID:    1,
+		FamilyID:  "family-1",
+		TokenHash: "hash-1",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	err = repo.Create(ctx, rt)
+	require.NoError(t, err)
+
+	t.Run("首次标记成功", func(t *testing.T) {
+		ok, err := repo.MarkUsed(ctx, rt.ID, time.Now())
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("重复标记失败", func(t *testing.T) {
+		ok, err := repo.MarkUsed(ctx, rt.ID, time.Now())
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func TestRefreshTokenRepository_RevokeFamily(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	repo := NewRefreshTokenRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	tokens := []*token.RefreshToken{
+		{UserID: 1, FamilyID: "family-1", TokenHash: "hash-1", ExpiresAt: now.Add(time.Hour)},
+		{UserID: 1, FamilyID: "family-2", TokenHash: "hash-2", ExpiresAt: now.Add(time.Hour)},
+		{UserID: 1, FamilyID: "family-3", TokenHash: "hash-3", ExpiresAt: now.Add(-time.Hour)},
+	}
+	for _, rt := range tokens {
+		require.NoError(t, repo.Create(ctx, rt))
+	}
+
+	t.Run("吊销前令牌族有效", func(t *testing.T) {
+		active, err := repo.HasActiveInFamily(ctx, "family-1", now)
+		assert.NoError(t, err)
+		assert.True(t, active)
+	})
+
+	t.Run("吊销后令牌族失效", func(t *testing.T) {
+		err := repo.RevokeFamily(ctx, "family-1", now)
+		assert.NoError(t, err)
+
+		active, err := repo.HasActiveInFamily(ctx, "family-1", now)
+		assert.NoError(t, err)
+		assert.False(t, active)
+
+		// 其他令牌族不受影响
+		active, err = repo.HasActiveInFamily(ctx, "family-2", now)
+		assert.NoError(t, err)
+		assert.True(t, active)
+	})
+
+	t.Run("过期的令牌族无效", func(t *testing.T) {
+		active, err := repo.HasActiveInFamily(ctx, "family-3", now)
+		assert.NoError(t, err)
+		assert.False(t, active)
+	})
+}
+
+func TestRefreshTokenRepository_RevokeByUserID(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	repo := NewRefreshTokenRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	tokens := []*token.RefreshToken{
+		{UserID: 1, FamilyID: "family-a", TokenHash: "hash-a", ExpiresAt: now.Add(time.Hour)},
+		{UserID: 1, FamilyID: "family-b", TokenHash: "hash-b", ExpiresAt: now.Add(time.Hour)},
+		{UserID: 2, FamilyID: "family-c", TokenHash: "hash-c", ExpiresAt: now.Add(time.Hour)},
+	}
+	for _, rt := range tokens {
+		require.NoError(t, repo.Create(ctx, rt))
+	}
+
+	err = repo.RevokeByUserID(ctx, 1, now)
+	require.NoError(t, err)
+
+	for family, want := range map[string]bool{"family-a": false, "family-b": false, "family-c": true} {
+		active, err := repo.HasActiveInFamily(ctx, family, now)
+		assert.NoError(t, err)
+		assert.Equal(t, want, active, family)
+	}
+}
+
+func TestPasswordResetRepository_MarkUsed(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	repo := NewPasswordResetRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	first := &token.PasswordResetToken{UserID: 1, TokenHash: "reset-1", ExpiresAt: now.Add(time.Hour)}
+	second := &token.PasswordResetToken{UserID: 1, TokenHash: "reset-2", ExpiresAt: now.Add(time.Hour)}
+	require.NoError(t, repo.Create(ctx, first))
+	require.NoError(t, repo.Create(ctx, second))
+
+	t.Run("只能使用一次", func(t *testing.T) {
+		ok, err := repo.MarkUsed(ctx, first.ID, now)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = repo.MarkUsed(ctx, first.ID, now)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("作废用户所有重置令牌", func(t *testing.T) {
+		err := repo.InvalidateByUserID(ctx, 1, now)
+		assert.NoError(t, err)
+
+		found, err := repo.FindByHash(ctx, "reset-2")
+		require.NoError(t, err)
+		assert.NotNil(t, found.UsedAt)
+	})
+}
+
+func TestRefreshTokenRepository_Sessions(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	repo := NewRefreshTokenRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	for _, s := range []*token.Session{
+		{UserID: 1, SessionID: "family-1", LastSeenAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)},
+		{UserID: 1, SessionID: "family-2", LastSeenAt: now, ExpiresAt: now.Add(time.Hour)},
+		{UserID: 1, SessionID: "family-expired", LastSeenAt: now, ExpiresAt: now.Add(-time.Minute)},
+		{UserID: 2, SessionID: "family-other", LastSeenAt: now, ExpiresAt: now.Add(time.Hour)},
+	} {
+		require.NoError(t, repo.CreateSession(ctx, s))
+	}
+
+	t.Run("只列出有效会话，按最近活跃倒序", func(t *testing.T) {
+		sessions, err := repo.ListActiveSessions(ctx, 1, now)
+		require.NoError(t, err)
+		require.Len(t, sessions, 2)
+		assert.Equal(t, "family-2", sessions[0].SessionID)
+		assert.Equal(t, "family-1", sessions[1].SessionID)
+	})
+
+	t.Run("只能查询本人的会话", func(t *testing.T) {
+		other, err := repo.FindSession(ctx, "family-other")
+		require.NoError(t, err)
+
+		_, err = repo.FindUserSession(ctx, 1, other.ID)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+		found, err := repo.FindUserSession(ctx, 2, other.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "family-other", found.SessionID)
+	})
+
+	t.Run("更新活跃时间与过期时间", func(t *testing.T) {
+		later := now.Add(2 * time.Hour)
+		err := repo.ExtendSession(ctx, "family-1", now.Add(time.Minute), later)
+		require.NoError(t, err)
+
+		found, err := repo.FindSession(ctx, "family-1")
+		require.NoError(t, err)
+		assert.WithinDuration(t, later, found.ExpiresAt, time.Second)
+		assert.WithinDuration(t, now.Add(time.Minute), found.LastSeenAt, time.Second)
+	})
+
+	t.Run("吊销令牌族同时终止会话", func(t *testing.T) {
+		err := repo.RevokeFamily(ctx, "family-1", now)
+		require.NoError(t, err)
+
+		found, err := repo.FindSession(ctx, "family-1")
+		require.NoError(t, err)
+		assert.NotNil(t, found.RevokedAt)
+		assert.False(t, found.IsActive(now))
+	})
+
+	t.Run("吊销用户所有令牌族同时终止所有会话", func(t *testing.T) {
+		err := repo.RevokeByUserID(ctx, 2, now)
+		require.NoError(t, err)
+
+		sessions, err := repo.ListActiveSessions(ctx, 2, now)
+		require.NoError(t, err)
+		assert.Empty(t, sessions)
+	})
+}
@@ -25,6 +25,18 @@ func (r *userRepository) FindByUsername(ctx context.Context, username string) (*
 	return r.FindOne(ctx, common.Where("username", username))
 }
 
+// UpdateStatus 条件更新用户状态，并发流转时只有一个能成功
+func (r *userRepository) UpdateStatus(ctx context.Context, id uint, from, to int) (bool, error) {
+	affected, err := r.BatchUpdate(ctx, map[string]any{"status": to},
+		common.Where("id", id),
+		common.Where("status", from),
+	)
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
 // Count 统计用户数量
 func (r *userRepository) Count(ctx context.Context, query *user.QueryOptions) (int64, error) {
 	if query == nil {
@@ -34,7 +46,7 @@ func (r *userRepository) Count(ctx context.Context, query *user.QueryOptions) (i
 	return r.BaseRepository.Count(ctx,
 		common.WhereLike("username", query.Username),
 		common.WhereLike("email", query.Email),
-		common.WhereIfNotNil("status", query.Status),
+		statusFilter(query.Status),
 	)
 }
 
@@ -47,8 +59,16 @@ func (r *userRepository) List(ctx context.Context, query *user.QueryOptions) ([]
 	return r.BaseRepository.List(ctx,
 		common.WhereLike("username", query.Username),
 		common.WhereLike("email", query.Email),
-		common.WhereIfNotNil("status", query.Status),
+		statusFilter(query.Status),
 		common.OrderByDesc("id"),
 		common.Paginate(query.Page, query.PageSize),
 	)
 }
+
+// statusFilter 按状态过滤，未指定状态时排除已注销的用户
+func statusFilter(status *int) common.QueryOption {
+	if status == nil {
+		return common.WhereNot("status", user.StatusDeleted)
+	}
+	return common.Where("status", *status)
+}
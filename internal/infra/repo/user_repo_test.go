@@ -296,3 +296,48 @@ func TestUserRepository_Count(t *testing.T) {
 		assert.GreaterOrEqual(t, count, int64(2))
 	})
 }
+
+func TestUserRepository_UpdateStatus(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+
+	u := &user.User{Username: "statususer", Password: "pwd", Status: user.StatusPending, Email: "status@test.com"}
+	require.NoError(t, repo.Create(ctx, u))
+
+	t.Run("当前状态匹配时更新", func(t *testing.T) {
+		ok, err := repo.UpdateStatus(ctx, u.ID, user.StatusPending, user.StatusActive)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		found, err := repo.FindByID(ctx, u.ID)
+		require.NoError(t, err)
+		assert.Equal(t, user.StatusActive, found.Status)
+	})
+
+	t.Run("当前状态不匹配时不更新", func(t *testing.T) {
+		ok, err := repo.UpdateStatus(ctx, u.ID, user.StatusPending, user.StatusDisabled)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("未指定状态时列表排除已注销用户", func(t *testing.T) {
+		ok, err := repo.UpdateStatus(ctx, u.ID, user.StatusActive, user.StatusDeleted)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		users, err := repo.List(ctx, &user.QueryOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, users)
+
+		deleted := user.StatusDeleted
+		count, err := repo.Count(ctx, &user.QueryOptions{Status: &deleted})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+}
@@ -0,0 +1,60 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/warehouse"
+	"power-supply-sys/pkg/common"
+
+	"gorm.io/gorm"
+)
+
+// warehouseRepository 仓库数据访问层实现
+type warehouseRepository struct {
+	*common.BaseRepository[warehouse.Warehouse]
+}
+
+// NewWarehouseRepository 创建仓库仓储
+func NewWarehouseRepository(db *gorm.DB) warehouse.Repository {
+	return &warehouseRepository{
+		BaseRepository: common.NewBaseRepository[warehouse.Warehouse](db),
+	}
+}
+
+// FindByID 根据ID查询仓库
+func (r *warehouseRepository) FindByID(ctx context.Context, id uint) (*warehouse.Warehouse, error) {
+	w, err := r.BaseRepository.FindByID(ctx, id)
+	if err != nil && common.HasErrorCode(err, common.ErrCodeNotFound) {
+		return nil, common.ErrNotFound("仓库")
+	}
+	return w, err
+}
+
+// FindDefault 查询默认仓库
+func (r *warehouseRepository) FindDefault(ctx context.Context) (*warehouse.Warehouse, error) {
+	w, err := r.FindOne(ctx, common.Where("is_default", true))
+	if err != nil && common.HasErrorCode(err, common.ErrCodeNotFound) {
+		return nil, common.ErrNotFound("默认仓库")
+	}
+	return w, err
+}
+
+// List 查询全部仓库
+func (r *warehouseRepository) List(ctx context.Context) ([]*warehouse.Warehouse, error) {
+	return r.BaseRepository.List(ctx, common.OrderBy("id"))
+}
+
+// ExistsByCode 检查仓库编码是否存在
+func (r *warehouseRepository) ExistsByCode(ctx context.Context, code string) (bool, error) {
+	return r.Exists(ctx, common.Where("code", code))
+}
+
+// SetDefault 将指定仓库设为默认仓库，并取消其他仓库的默认标记（需在事务中调用）
+func (r *warehouseRepository) SetDefault(ctx context.Context, id uint) error {
+	if _, err := r.BatchUpdate(ctx, map[string]any{"is_default": false},
+		common.Where("is_default", true),
+		common.WhereNot("id", id),
+	); err != nil {
+		return err
+	}
+	return r.UpdateByID(ctx, id, map[string]any{"is_default": true})
+}
@@ -0,0 +1,54 @@
+package repo
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/warehouse"
+	dbpkg "power-supply-sys/internal/infra/db"
+	"power-supply-sys/pkg/common"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarehouseRepository(t *testing.T) {
+	db := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, db)
+
+	err := dbpkg.Migrate(db)
+	require.NoError(t, err)
+
+	repo := NewWarehouseRepository(db)
+	ctx := context.Background()
+
+	t.Run("迁移创建默认仓库", func(t *testing.T) {
+		w, err := repo.FindDefault(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, warehouse.DefaultCode, w.Code)
+
+		exists, err := repo.ExistsByCode(ctx, warehouse.DefaultCode)
+		require.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("切换默认仓库", func(t *testing.T) {
+		w := &warehouse.Warehouse{Code: "SZ", Name: "深圳仓"}
+		require.NoError(t, repo.Create(ctx, w))
+		require.NoError(t, repo.SetDefault(ctx, w.ID))
+
+		found, err := repo.FindDefault(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, w.ID, found.ID)
+
+		list, err := repo.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		assert.False(t, list[0].IsDefault)
+		assert.True(t, list[1].IsDefault)
+	})
+
+	t.Run("仓库不存在", func(t *testing.T) {
+		_, err := repo.FindByID(ctx, 9999)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/apikey"
+	"power-supply-sys/internal/domain/rbac"
+	"power-supply-sys/internal/domain/user"
+	"power-supply-sys/pkg/auth"
+	"power-supply-sys/pkg/common"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	defaultAPIKeyExpireDays = 90          // 未指定有效期时的默认天数
+	maxAPIKeyExpireDays     = 365         // 允许的最长有效期（天）
+	apiKeyDisplayPrefixLen  = 12          // 保存用于识别的原文前缀长度
+	apiKeyTouchInterval     = time.Minute // 最近使用时间的最小更新间隔，避免每次请求都写库
+)
+
+// APIKeyService API Key 服务接口
+type APIKeyService interface {
+	Create(ctx context.Context, actor *user.Actor, req *apikey.CreateRequest) (*apikey.CreatedKey, error)
+	List(ctx context.Context, userID uint) ([]*apikey.APIKey, error)
+	Revoke(ctx context.Context, userID, id uint) error
+	Authenticate(ctx context.Context, rawKey string) (*apikey.Principal, error)
+}
+
+// apiKeyService API Key 服务实现
+type apiKeyService struct {
+	repo        apikey.Repository
+	userRepo    user.Repository
+	permissions rbac.PermissionChecker
+}
+
+var _ APIKeyService = &apiKeyService{}
+
+// NewAPIKeyService 创建 API Key 服务
+func NewAPIKeyService(repo apikey.Repository, userRepo user.Repository, permissions rbac.PermissionChecker) APIKeyService {
+	return &apiKeyService{
+		repo:        repo,
+		userRepo:    userRepo,
+		permissions: permissions,
+	}
+}
+
+// Create 创建 API Key，只能授予本人角色已拥有的权限范围
+func (s *apiKeyService) Create(ctx context.Context, actor *user.Actor, req *apikey.CreateRequest) (*apikey.CreatedKey, error) {
+	scopes, err := s.normalizeScopes(ctx, actor.Role, req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	days := req.ExpiresInDays
+	if days == 0 {
+		days = defaultAPIKeyExpireDays
+	}
+	if days < 0 || days > maxAPIKeyExpireDays {
+		return nil, common.ErrInvalidParam("有效期需在 1 到 365 天之间")
+	}
+
+	// 摘要基于带前缀的完整原文计算
+	raw, _, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return nil, common.ErrInternal(err)
+	}
+	raw = apikey.KeyPrefix + raw
+
+	key := &apikey.APIKey{
+		UserID:    actor.ID,
+		Name:      req.Name,
+		Prefix:    raw[:apiKeyDisplayPrefixLen],
+		KeyHash:   auth.HashOpaqueToken(raw),
+		Scopes:    strings.Join(scopes, ","),
+		ExpiresAt: time.Now().AddDate(0, 0, days),
+	}
+	if err := s.repo.Create(ctx, key); err != nil {
+		return nil, err
+	}
+
+	return &apikey.CreatedKey{Key: key, RawKey: raw}, nil
+}
+
+// List 获取用户的 API Key 列表
+func (s *apiKeyService) List(ctx context.Context, userID uint) ([]*apikey.APIKey, error) {
+	return s.repo.ListByUserID(ctx, userID)
+}
+
+// Revoke 吊销用户自己的 API Key
+func (s *apiKeyService) Revoke(ctx context.Context, userID, id uint) error {
+	revoked, err := s.repo.Revoke(ctx, userID, id, time.Now())
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return common.ErrNotFound("API Key")
+	}
+	return nil
+}
+
+// Authenticate 校验 API Key 原文，返回 Key 所属用户的身份与权限范围
+func (s *apiKeyService) Authenticate(ctx context.Context, rawKey string) (*apikey.Principal, error) {
+	now := time.Now()
+
+	if !strings.HasPrefix(rawKey, apikey.KeyPrefix) {
+		return nil, errAPIKeyInvalid()
+	}
+	key, err := s.repo.FindByHash(ctx, auth.HashOpaqueToken(rawKey))
+	if err != nil {
+		if common.HasErrorCode(err, common.ErrCodeNotFound) {
+			return nil, errAPIKeyInvalid()
+		}
+		return nil, err
+	}
+	if !key.IsActive(now) {
+		return nil, errAPIKeyInvalid()
+	}
+
+	u, err := s.userRepo.FindByID(ctx, key.UserID)
+	if err != nil {
+		if common.HasErrorCode(err, common.ErrCodeNotFound) {
+			return nil, errAPIKeyInvalid()
+		}
+		return nil, err
+	}
+	if err := u.StatusError(); err != nil {
+		return nil, err
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+		if err := s.repo.TouchLastUsed(ctx, key.ID, now); err != nil {
+			return nil, err
+		}
+	}
+
+	return &apikey.Principal{
+		KeyID:    key.ID,
+		UserID:   u.ID,
+		Username: u.Username,
+		Role:     u.Role,
+		Scopes:   key.ScopeList(),
+	}, nil
+}
+
+// normalizeScopes 校验并去重权限范围
+func (s *apiKeyService) normalizeScopes(ctx context.Context, role string, requested []string) ([]string, error) {
+	scopes := make([]string, 0, len(requested))
+	for _, scope := range requested {
+		if !apikey.IsAllowedScope(scope) {
+			return nil, common.ErrInvalidParam("不支持的权限范围: " + scope)
+		}
+		if slices.Contains(scopes, scope) {
+			continue
+		}
+		allowed, err := s.permissions.HasPermission(ctx, role, scope)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, common.ErrForbidden("无权授予权限范围: " + scope)
+		}
+		scopes = append(scopes, scope)
+	}
+	if len(scopes) == 0 {
+		return nil, common.ErrInvalidParam("至少需要一个权限范围")
+	}
+	slices.Sort(scopes)
+	return scopes, nil
+}
+
+// errAPIKeyInvalid API Key 无效、已吊销或已过期
+func errAPIKeyInvalid() error {
+	return common.NewError(common.ErrCodeInvalidToken, "API Key 无效或已过期")
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/apikey"
+	"power-supply-sys/internal/domain/rbac"
+	"power-supply-sys/internal/domain/user"
+	"power-supply-sys/internal/infra/db"
+	"power-supply-sys/internal/infra/repo"
+	"power-supply-sys/pkg/common"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// setupAPIKeyService 创建 API Key 服务及测试用户（普通用户角色）
+func setupAPIKeyService(t *testing.T, gormDB *gorm.DB) (APIKeyService, *user.User) {
+	err := db.Migrate(gormDB)
+	require.NoError(t, err)
+
+	userRepo := repo.NewUserRepository(gormDB)
+	rbacRepo := repo.NewRBACRepository(gormDB)
+	svc := NewAPIKeyService(repo.NewAPIKeyRepository(gormDB), userRepo, rbacRepo)
+
+	u, err := NewUserService(userRepo, repo.NewRefreshTokenRepository(gormDB), rbacRepo, newTestLoginLimiter(), common.NewTransactor(gormDB), newTestPasswordHasher(), user.DefaultPasswordPolicy()).Create(context.Background(), &user.UserCreateRequest{
+		Username: "syncbot",
+		Password: "password123",
+	})
+	require.NoError(t, err)
+
+	return svc, u
+}
+
+func TestAPIKeyService_Create(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	svc, u := setupAPIKeyService(t, gormDB)
+	ctx := context.Background()
+	actor := &user.Actor{ID: u.ID, Role: rbac.RoleUser}
+
+	t.Run("成功创建只读 Key", func(t *testing.T) {
+		created, err := svc.Create(ctx, actor, &apikey.CreateRequest{
+			Name:   "inventory-sync",
+			Scopes: []string{rbac.PermPowerRead, rbac.PermPowerRead},
+		})
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(created.RawKey, apikey.KeyPrefix))
+		assert.Equal(t, created.RawKey[:len(created.Key.Prefix)], created.Key.Prefix)
+		assert.NotContains(t, created.Key.KeyHash, created.RawKey)
+		assert.Equal(t, []string{rbac.PermPowerRead}, created.Key.ScopeList())
+		assert.WithinDuration(t, time.Now().AddDate(0, 0, defaultAPIKeyExpireDays), created.Key.ExpiresAt, time.Minute)
+	})
+
+	t.Run("不能授予角色没有的权限", func(t *testing.T) {
+		_, err := svc.Create(ctx, actor, &apikey.CreateRequest{Name: "writer", Scopes: []string{rbac.PermPowerWrite}})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeForbidden))
+	})
+
+	t.Run("管理员可以创建写入 Key", func(t *testing.T) {
+		admin := &user.Actor{ID: u.ID, Role: rbac.RoleAdmin}
+		created, err := svc.Create(ctx, admin, &apikey.CreateRequest{
+			Name:          "writer",
+			Scopes:        []string{rbac.PermPowerWrite, rbac.PermPowerRead},
+			ExpiresInDays: 7,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{rbac.PermPowerRead, rbac.PermPowerWrite}, created.Key.ScopeList())
+	})
+
+	t.Run("参数校验", func(t *testing.T) {
+		_, err := svc.Create(ctx, actor, &apikey.CreateRequest{Name: "bad", Scopes: []string{rbac.PermUserRead}})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		_, err = svc.Create(ctx, actor, &apikey.CreateRequest{Name: "bad"})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		_, err = svc.Create(ctx, actor, &apikey.CreateRequest{Name: "bad", Scopes: []string{rbac.PermPowerRead}, ExpiresInDays: 400})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+	})
+}
+
+func TestAPIKeyService_Authenticate(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	svc, u := setupAPIKeyService(t, gormDB)
+	ctx := context.Background()
+	actor := &user.Actor{ID: u.ID, Role: rbac.RoleUser}
+
+	created, err := svc.Create(ctx, actor, &apikey.CreateRequest{Name: "sync", Scopes: []string{rbac.PermPowerRead}})
+	require.NoError(t, err)
+
+	t.Run("成功认证并记录使用时间", func(t *testing.T) {
+		principal, err := svc.Authenticate(ctx, created.RawKey)
+		require.NoError(t, err)
+		assert.Equal(t, u.ID, principal.UserID)
+		assert.Equal(t, rbac.RoleUser, principal.Role)
+		assert.Equal(t, []string{rbac.PermPowerRead}, principal.Scopes)
+
+		keys, err := svc.List(ctx, u.ID)
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		assert.NotNil(t, keys[0].LastUsedAt)
+	})
+
+	t.Run("无效 Key", func(t *testing.T) {
+		_, err := svc.Authenticate(ctx, apikey.KeyPrefix+"unknown")
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidToken))
+
+		_, err = svc.Authenticate(ctx, "not-a-key")
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidToken))
+	})
+
+	t.Run("过期 Key", func(t *testing.T) {
+		expired, err := svc.Create(ctx, actor, &apikey.CreateRequest{Name: "old", Scopes: []string{rbac.PermPowerRead}})
+		require.NoError(t, err)
+		gormDB.Model(&apikey.APIKey{}).Where("id = ?", expired.Key.ID).Update("expires_at", time.Now().Add(-time.Hour))
+
+		_, err = svc.Authenticate(ctx, expired.RawKey)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidToken))
+	})
+
+	t.Run("只能吊销自己的 Key", func(t *testing.T) {
+		err := svc.Revoke(ctx, u.ID+1, created.Key.ID)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+
+		err = svc.Revoke(ctx, u.ID, created.Key.ID)
+		require.NoError(t, err)
+
+		_, err = svc.Authenticate(ctx, created.RawKey)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidToken))
+
+		err = svc.Revoke(ctx, u.ID, created.Key.ID)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/token"
+	"power-supply-sys/internal/domain/user"
+	"power-supply-sys/pkg/auth"
+	"power-supply-sys/pkg/common"
+	"time"
+)
+
+const (
+	sessionTouchInterval = time.Minute // 会话最近活跃时间的最小更新间隔，避免每次请求都写库
+	maxUserAgentLen      = 512         // 会话记录的 User-Agent 最大长度（与字段长度一致）
+)
+
+// AuthService 认证服务接口（令牌签发、轮换与吊销，登录会话管理）
+type AuthService interface {
+	IssueTokens(ctx context.Context, u *user.User, client *token.ClientInfo) (*token.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (*token.TokenPair, error)
+	Logout(ctx context.Context, refreshToken string) error
+	IsSessionActive(ctx context.Context, sessionID string) (bool, error)
+	ListSessions(ctx context.Context, userID uint) ([]*token.Session, error)
+	TerminateSession(ctx context.Context, userID, id uint) error
+}
+
+// authService 认证服务实现
+type authService struct {
+	tokenRepo     token.Repository
+	userRepo      user.Repository
+	jwtManager    *auth.JWTManager
+	transactor    common.Transactor
+	refreshExpire time.Duration
+}
+
+var _ AuthService = &authService{}
+
+// NewAuthService 创建认证服务
+func NewAuthService(tokenRepo token.Repository, userRepo user.Repository, jwtManager *auth.JWTManager, transactor common.Transactor, refreshExpire time.Duration) AuthService {
+	return &authService{
+		tokenRepo:     tokenRepo,
+		userRepo:      userRepo,
+		jwtManager:    jwtManager,
+		transactor:    transactor,
+		refreshExpire: refreshExpire,
+	}
+}
+
+// IssueTokens 为登录用户签发令牌对（开启新的令牌族，并记录登录会话）
+func (s *authService) IssueTokens(ctx context.Context, u *user.User, client *token.ClientInfo) (*token.TokenPair, error) {
+	familyID, err := auth.NewRandomID()
+	if err != nil {
+		return nil, common.ErrInternal(err)
+	}
+
+	now := time.Now()
+	session := &token.Session{
+		UserID:     u.ID,
+		SessionID:  familyID,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(s.refreshExpire),
+	}
+	if client != nil {
+		session.UserAgent = truncateRunes(client.UserAgent, maxUserAgentLen)
+		session.IP = client.IP
+	}
+
+	var pair *token.TokenPair
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.tokenRepo.CreateSession(ctx, session); err != nil {
+			return err
+		}
+		pair, err = s.issue(ctx, u, familyID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pair, nil
+}
+
+// Refresh 使用刷新令牌换取新的令牌对（刷新令牌一次性使用）
+// 已轮换过的令牌被再次使用时视为泄露，吊销整个令牌族
+func (s *authService) Refresh(ctx context.Context, refreshToken string) (*token.TokenPair, error) {
+	now := time.Now()
+
+	rt, err := s.tokenRepo.FindByHash(ctx, auth.HashOpaqueToken(refreshToken))
+	if err != nil {
+		if common.HasErrorCode(err, common.ErrCodeNotFound) {
+			return nil, errRefreshTokenInvalid()
+		}
+		return nil, err
+	}
+
+	if rt.RevokedAt != nil {
+		return nil, errRefreshTokenInvalid()
+	}
+	if rt.UsedAt != nil {
+		return nil, s.handleReuse(ctx, rt)
+	}
+	if rt.IsExpired(now) {
+		return nil, common.ErrTokenExpired()
+	}
+
+	u, err := s.userRepo.FindByID(ctx, rt.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if statusErr := u.StatusError(); statusErr != nil {
+		if err := s.tokenRepo.RevokeFamily(ctx, rt.FamilyID, now); err != nil {
+			return nil, err
+		}
+		return nil, statusErr
+	}
+
+	var pair *token.TokenPair
+	reused := false
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		ok, err := s.tokenRepo.MarkUsed(ctx, rt.ID, now)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			reused = true
+			return nil
+		}
+		if err := s.tokenRepo.ExtendSession(ctx, rt.FamilyID, now, now.Add(s.refreshExpire)); err != nil {
+			return err
+		}
+		pair, err = s.issue(ctx, u, rt.FamilyID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if reused {
+		return nil, s.handleReuse(ctx, rt)
+	}
+
+	return pair, nil
+}
+
+// Logout 注销：吊销刷新令牌所在的令牌族
+func (s *authService) Logout(ctx context.Context, refreshToken string) error {
+	rt, err := s.tokenRepo.FindByHash(ctx, auth.HashOpaqueToken(refreshToken))
+	if err != nil {
+		if common.HasErrorCode(err, common.ErrCodeNotFound) {
+			return errRefreshTokenInvalid()
+		}
+		return err
+	}
+	return s.tokenRepo.RevokeFamily(ctx, rt.FamilyID, time.Now())
+}
+
+// IsSessionActive 会话是否仍然有效（未终止、未过期），有效时顺带记录最近活跃时间
+func (s *authService) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	now := time.Now()
+
+	session, err := s.tokenRepo.FindSession(ctx, sessionID)
+	if err != nil {
+		// 会话记录上线前签发的令牌族没有会话记录，按令牌族状态判断
+		if common.HasErrorCode(err, common.ErrCodeNotFound) {
+			return s.tokenRepo.HasActiveInFamily(ctx, sessionID, now)
+		}
+		return false, err
+	}
+	if !session.IsActive(now) {
+		return false, nil
+	}
+
+	if now.Sub(session.LastSeenAt) >= sessionTouchInterval {
+		if err := s.tokenRepo.TouchSession(ctx, sessionID, now); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// ListSessions 获取用户当前有效的登录会话
+func (s *authService) ListSessions(ctx context.Context, userID uint) ([]*token.Session, error) {
+	return s.tokenRepo.ListActiveSessions(ctx, userID, time.Now())
+}
+
+// TerminateSession 终止用户本人的登录会话（吊销令牌族，该会话的 access token 立即失效）
+func (s *authService) TerminateSession(ctx context.Context, userID, id uint) error {
+	now := time.Now()
+
+	session, err := s.tokenRepo.FindUserSession(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !session.IsActive(now) {
+		return common.ErrNotFound("会话")
+	}
+	return s.tokenRepo.RevokeFamily(ctx, session.SessionID, now)
+}
+
+// issue 在指定令牌族中签发新的令牌对
+func (s *authService) issue(ctx context.Context, u *user.User, familyID string) (*token.TokenPair, error) {
+	raw, hash, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return nil, common.ErrInternal(err)
+	}
+
+	rt := &token.RefreshToken{
+		UserID:    u.ID,
+		FamilyID:  familyID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(s.refreshExpire),
+	}
+	if err := s.tokenRepo.Create(ctx, rt); err != nil {
+		return nil, err
+	}
+
+	accessToken, err := s.jwtManager.GenerateToken(u.ID, u.Username, auth.WithSessionID(familyID), auth.WithRole(u.Role))
+	if err != nil {
+		return nil, common.ErrInternal(err)
+	}
+
+	return &token.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: raw,
+		ExpiresIn:    int64(s.jwtManager.Expire().Seconds()),
+		SessionID:    familyID,
+	}, nil
+}
+
+// handleReuse 处理刷新令牌重放：吊销整个令牌族
+func (s *authService) handleReuse(ctx context.Context, rt *token.RefreshToken) error {
+	if err := s.tokenRepo.RevokeFamily(ctx, rt.FamilyID, time.Now()); err != nil {
+		return err
+	}
+	return errRefreshTokenInvalid()
+}
+
+// errRefreshTokenInvalid 刷新令牌无效错误
+func errRefreshTokenInvalid() *common.AppError {
+	return common.NewError(common.ErrCodeInvalidToken, "刷新令牌无效，请重新登录")
+}
+
+// truncateRunes 按字符截断字符串
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/token"
+	"power-supply-sys/internal/domain/user"
+	"power-supply-sys/internal/infra/db"
+	"power-supply-sys/internal/infra/repo"
+	"power-supply-sys/pkg/auth"
+	"power-supply-sys/pkg/common"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// setupAuthService 创建认证服务及测试用户
+func setupAuthService(t *testing.T, gormDB *gorm.DB) (AuthService, *user.User) {
+	err := db.Migrate(gormDB)
+	require.NoError(t, err)
+
+	userRepo := repo.NewUserRepository(gormDB)
+	tokenRepo := repo.NewRefreshTokenRepository(gormDB)
+	jwtManager := auth.NewJWTManager("test-secret", 15*time.Minute)
+	authSvc := NewAuthService(tokenRepo, userRepo, jwtManager, common.NewTransactor(gormDB), time.Hour)
+
+	u, err := NewUserService(userRepo, repo.NewRefreshTokenRepository(gormDB), repo.NewRBACRepository(gormDB), newTestLoginLimiter(), common.NewTransactor(gormDB), newTestPasswordHasher(), user.DefaultPasswordPolicy()).Create(context.Background(), &user.UserCreateRequest{
+		Username: "authuser",
+		Password: "password123",
+	})
+	require.NoError(t, err)
+
+	return authSvc, u
+}
+
+func TestAuthService_IssueTokens(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	authSvc, u := setupAuthService(t, gormDB)
+	ctx := context.Background()
+
+	pair, err := authSvc.IssueTokens(ctx, u, nil)
+	require.NoError(t, err)
+	assert.NotEmpty(t, pair.AccessToken)
+	assert.NotEmpty(t, pair.RefreshToken)
+	assert.NotEmpty(t, pair.SessionID)
+	assert.Equal(t, int64(900), pair.ExpiresIn)
+
+	active, err := authSvc.IsSessionActive(ctx, pair.SessionID)
+	assert.NoError(t, err)
+	assert.True(t, active)
+}
+
+func TestAuthService_Refresh(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	authSvc, u := setupAuthService(t, gormDB)
+	ctx := context.Background()
+
+	t.Run("成功轮换令牌", func(t *testing.T) {
+		pair, err := authSvc.IssueTokens(ctx, u, nil)
+		require.NoError(t, err)
+
+		rotated, err := authSvc.Refresh(ctx, pair.RefreshToken)
+		assert.NoError(t, err)
+		assert.NotEqual(t, pair.RefreshToken, rotated.RefreshToken)
+		assert.Equal(t, pair.SessionID, rotated.SessionID)
+
+		active, err := authSvc.IsSessionActive(ctx, rotated.SessionID)
+		assert.NoError(t, err)
+		assert.True(t, active)
+	})
+
+	t.Run("重放旧令牌吊销整个令牌族", func(t *testing.T) {
+		pair, err := authSvc.IssueTokens(ctx, u, nil)
+		require.NoError(t, err)
+
+		rotated, err := authSvc.Refresh(ctx, pair.RefreshToken)
+		require.NoError(t, err)
+
+		// 再次使用已轮换的旧令牌
+		_, err = authSvc.Refresh(ctx, pair.RefreshToken)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidToken))
+
+		// 新令牌也随令牌族一起失效
+		_, err = authSvc.Refresh(ctx, rotated.RefreshToken)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidToken))
+
+		active, err := authSvc.IsSessionActive(ctx, pair.SessionID)
+		assert.NoError(t, err)
+		assert.False(t, active)
+	})
+
+	t.Run("无效令牌", func(t *testing.T) {
+		_, err := authSvc.Refresh(ctx, "not-a-token")
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidToken))
+	})
+}
+
+func TestAuthService_Logout(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	authSvc, u := setupAuthService(t, gormDB)
+	ctx := context.Background()
+
+	pair, err := authSvc.IssueTokens(ctx, u, nil)
+	require.NoError(t, err)
+
+	err = authSvc.Logout(ctx, pair.RefreshToken)
+	assert.NoError(t, err)
+
+	active, err := authSvc.IsSessionActive(ctx, pair.SessionID)
+	assert.NoError(t, err)
+	assert.False(t, active)
+
+	_, err = authSvc.Refresh(ctx, pair.RefreshToken)
+	assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidToken))
+}
+
+func TestAuthService_Sessions(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	authSvc, u := setupAuthService(t, gormDB)
+	ctx := context.Background()
+
+	laptop, err := authSvc.IssueTokens(ctx, u, &token.ClientInfo{UserAgent: "Mozilla/5.0 (Macintosh)", IP: "10.0.0.1"})
+	require.NoError(t, err)
+	phone, err := authSvc.IssueTokens(ctx, u, &token.ClientInfo{UserAgent: "Mozilla/5.0 (iPhone)", IP: "10.0.0.2"})
+	require.NoError(t, err)
+
+	t.Run("列出所有登录会话", func(t *testing.T) {
+		sessions, err := authSvc.ListSessions(ctx, u.ID)
+		require.NoError(t, err)
+		require.Len(t, sessions, 2)
+
+		bySID := map[string]*token.Session{}
+		for _, s := range sessions {
+			bySID[s.SessionID] = s
+		}
+		require.Contains(t, bySID, laptop.SessionID)
+		assert.Equal(t, "Mozilla/5.0 (Macintosh)", bySID[laptop.SessionID].UserAgent)
+		assert.Equal(t, "10.0.0.1", bySID[laptop.SessionID].IP)
+		assert.False(t, bySID[laptop.SessionID].LastSeenAt.IsZero())
+	})
+
+	t.Run("终止其他设备的会话", func(t *testing.T) {
+		sessions, err := authSvc.ListSessions(ctx, u.ID)
+		require.NoError(t, err)
+		var phoneSession *token.Session
+		for _, s := range sessions {
+			if s.SessionID == phone.SessionID {
+				phoneSession = s
+			}
+		}
+		require.NotNil(t, phoneSession)
+
+		// 不能终止他人的会话
+		err = authSvc.TerminateSession(ctx, u.ID+1, phoneSession.ID)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+
+		err = authSvc.TerminateSession(ctx, u.ID, phoneSession.ID)
+		require.NoError(t, err)
+
+		active, err := authSvc.IsSessionActive(ctx, phone.SessionID)
+		assert.NoError(t, err)
+		assert.False(t, active)
+		_, err = authSvc.Refresh(ctx, phone.RefreshToken)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidToken))
+
+		// 其他会话不受影响
+		active, err = authSvc.IsSessionActive(ctx, laptop.SessionID)
+		assert.NoError(t, err)
+		assert.True(t, active)
+
+		sessions, err = authSvc.ListSessions(ctx, u.ID)
+		require.NoError(t, err)
+		require.Len(t, sessions, 1)
+		assert.Equal(t, laptop.SessionID, sessions[0].SessionID)
+
+		// 已终止的会话不能重复终止
+		err = authSvc.TerminateSession(ctx, u.ID, phoneSession.ID)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+
+	t.Run("刷新令牌延长会话", func(t *testing.T) {
+		before, err := authSvc.ListSessions(ctx, u.ID)
+		require.NoError(t, err)
+		require.Len(t, before, 1)
+
+		_, err = authSvc.Refresh(ctx, laptop.RefreshToken)
+		require.NoError(t, err)
+
+		after, err := authSvc.ListSessions(ctx, u.ID)
+		require.NoError(t, err)
+		require.Len(t, after, 1)
+		assert.False(t, after[0].ExpiresAt.Before(before[0].ExpiresAt))
+	})
+}
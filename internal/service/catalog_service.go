@@ -0,0 +1,378 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"power-supply-sys/internal/domain/catalog"
+	"power-supply-sys/internal/domain/power"
+	"power-supply-sys/pkg/common"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxCatalogNameLen 分类与标签名称的最大长度（与字段长度一致）
+const maxCatalogNameLen = 50
+
+// CatalogService 商品目录服务接口：维护电源分类树与标签
+type CatalogService interface {
+	CreateCategory(ctx context.Context, req *catalog.CategoryCreateRequest) (*catalog.Category, error)
+	// GetCategory 获取分类（含子分类树）
+	GetCategory(ctx context.Context, id uint) (*catalog.Category, error)
+	UpdateCategory(ctx context.Context, id uint, req *catalog.CategoryUpdateRequest) (*catalog.Category, error)
+	DeleteCategory(ctx context.Context, id uint) error
+	// CategoryTree 获取完整的分类树
+	CategoryTree(ctx context.Context) ([]*catalog.Category, error)
+	// CategoryScope 返回按分类过滤电源时使用的分类 ID，includeSub 为 true 时包含全部子孙分类
+	CategoryScope(ctx context.Context, id uint, includeSub bool) ([]uint, error)
+
+	CreateTag(ctx context.Context, name string) (*catalog.Tag, error)
+	UpdateTag(ctx context.Context, id uint, name string) (*catalog.Tag, error)
+	DeleteTag(ctx context.Context, id uint) error
+	ListTags(ctx context.Context, req *catalog.TagQueryRequest) ([]*catalog.Tag, int64, error)
+	// ResolveTags 按名称查询标签，不存在的标签自动创建（可在外部事务中调用）
+	ResolveTags(ctx context.Context, names []string) ([]*catalog.Tag, error)
+}
+
+// catalogService 商品目录服务实现
+type catalogService struct {
+	categoryRepo catalog.CategoryRepository
+	tagRepo      catalog.TagRepository
+	powerRepo    power.Repository
+	transactor   common.Transactor
+}
+
+var _ CatalogService = &catalogService{}
+
+// NewCatalogService 创建商品目录服务
+func NewCatalogService(categoryRepo catalog.CategoryRepository, tagRepo catalog.TagRepository, powerRepo power.Repository, transactor common.Transactor) CatalogService {
+	return &catalogService{
+		categoryRepo: categoryRepo,
+		tagRepo:      tagRepo,
+		powerRepo:    powerRepo,
+		transactor:   transactor,
+	}
+}
+
+// CreateCategory 创建分类，同一父分类下名称不能重复
+func (s *catalogService) CreateCategory(ctx context.Context, req *catalog.CategoryCreateRequest) (*catalog.Category, error) {
+	name, err := validateCatalogName(req.Name, "分类")
+	if err != nil {
+		return nil, err
+	}
+	parentID := req.ParentID
+	if parentID != nil && *parentID == 0 {
+		parentID = nil
+	}
+	if parentID != nil {
+		if _, err := s.categoryRepo.FindByID(ctx, *parentID); err != nil {
+			return nil, notFoundAs(err, "上级分类")
+		}
+	}
+	if err := s.ensureCategoryNameAvailable(ctx, parentID, name); err != nil {
+		return nil, err
+	}
+
+	c := &catalog.Category{ParentID: parentID, Name: name, SortOrder: req.SortOrder}
+	if err := s.categoryRepo.Create(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// GetCategory 获取分类（含子分类树）
+func (s *catalogService) GetCategory(ctx context.Context, id uint) (*catalog.Category, error) {
+	list, err := s.categoryRepo.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	catalog.BuildTree(list)
+	for _, c := range list {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return nil, common.ErrNotFound("分类")
+}
+
+// UpdateCategory 更新分类，移动分类时不能移动到自身或子孙分类下
+func (s *catalogService) UpdateCategory(ctx context.Context, id uint, req *catalog.CategoryUpdateRequest) (*catalog.Category, error) {
+	c, err := s.categoryRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]any)
+	name := c.Name
+	if req.Name != "" {
+		if name, err = validateCatalogName(req.Name, "分类"); err != nil {
+			return nil, err
+		}
+		if name != c.Name {
+			updates["name"] = name
+		}
+	}
+
+	parentID := c.ParentID
+	if req.ParentID != nil {
+		parentID = nil
+		if *req.ParentID != 0 {
+			parentID = req.ParentID
+			if err := s.ensureValidParent(ctx, id, *parentID); err != nil {
+				return nil, err
+			}
+		}
+		if !sameParent(parentID, c.ParentID) {
+			updates["parent_id"] = parentID
+		}
+	}
+	// 名称（不区分大小写）与父分类都未变化时无需检查重名
+	if !strings.EqualFold(name, c.Name) || !sameParent(parentID, c.ParentID) {
+		if err := s.ensureCategoryNameAvailable(ctx, parentID, name); err != nil {
+			return nil, err
+		}
+	}
+	if req.SortOrder != nil {
+		updates["sort_order"] = *req.SortOrder
+	}
+
+	if len(updates) > 0 {
+		if err := s.categoryRepo.UpdateByID(ctx, id, updates); err != nil {
+			return nil, err
+		}
+	}
+	return s.GetCategory(ctx, id)
+}
+
+// DeleteCategory 删除分类，有子分类或电源的分类不能删除
+func (s *catalogService) DeleteCategory(ctx context.Context, id uint) error {
+	if _, err := s.categoryRepo.FindByID(ctx, id); err != nil {
+		return err
+	}
+	hasChildren, err := s.categoryRepo.HasChildren(ctx, id)
+	if err != nil {
+		return err
+	}
+	if hasChildren {
+		return common.ErrInvalidParam("分类下有子分类，不能删除")
+	}
+	inUse, err := s.powerRepo.Exists(ctx, common.Where("category_id", id))
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return common.ErrInvalidParam("分类下有电源，不能删除")
+	}
+	return s.categoryRepo.Delete(ctx, id)
+}
+
+// CategoryTree 获取完整的分类树（同级按排序值排列）
+func (s *catalogService) CategoryTree(ctx context.Context) ([]*catalog.Category, error) {
+	list, err := s.categoryRepo.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return catalog.BuildTree(list), nil
+}
+
+// CategoryScope 返回按分类过滤电源时使用的分类 ID
+func (s *catalogService) CategoryScope(ctx context.Context, id uint, includeSub bool) ([]uint, error) {
+	if _, err := s.categoryRepo.FindByID(ctx, id); err != nil {
+		return nil, err
+	}
+	if !includeSub {
+		return []uint{id}, nil
+	}
+	list, err := s.categoryRepo.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return catalog.Descendants(list, id), nil
+}
+
+// CreateTag 创建标签，名称不区分大小写唯一
+func (s *catalogService) CreateTag(ctx context.Context, name string) (*catalog.Tag, error) {
+	name, err := validateTagName(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureTagNameAvailable(ctx, name); err != nil {
+		return nil, err
+	}
+	t := &catalog.Tag{Name: name}
+	if err := s.tagRepo.Create(ctx, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// UpdateTag 重命名标签
+func (s *catalogService) UpdateTag(ctx context.Context, id uint, name string) (*catalog.Tag, error) {
+	t, err := s.tagRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	name, err = validateTagName(name)
+	if err != nil {
+		return nil, err
+	}
+	if name == t.Name {
+		return t, nil
+	}
+	if !strings.EqualFold(name, t.Name) {
+		if err := s.ensureTagNameAvailable(ctx, name); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.tagRepo.UpdateByID(ctx, id, map[string]any{"name": name}); err != nil {
+		return nil, err
+	}
+	return s.tagRepo.FindByID(ctx, id)
+}
+
+// DeleteTag 删除标签，同时移除电源上的该标签
+func (s *catalogService) DeleteTag(ctx context.Context, id uint) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		return s.tagRepo.Delete(ctx, id)
+	})
+}
+
+// ListTags 查询标签列表
+func (s *catalogService) ListTags(ctx context.Context, req *catalog.TagQueryRequest) ([]*catalog.Tag, int64, error) {
+	page, pageSize := common.GetPageInfo(req.Page, req.PageSize)
+	query := &catalog.TagQueryOptions{Name: req.Name, Page: page, PageSize: pageSize}
+	total, err := s.tagRepo.Count(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	list, err := s.tagRepo.List(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+// ResolveTags 按名称查询标签（不区分大小写），不存在的标签按传入的名称创建
+func (s *catalogService) ResolveTags(ctx context.Context, names []string) ([]*catalog.Tag, error) {
+	names, err := normalizeTagNames(names)
+	if err != nil || len(names) == 0 {
+		return nil, err
+	}
+	tags, err := s.tagRepo.FindByNames(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		existing[strings.ToLower(t.Name)] = true
+	}
+	for _, name := range names {
+		if existing[strings.ToLower(name)] {
+			continue
+		}
+		t := &catalog.Tag{Name: name}
+		if err := s.tagRepo.Create(ctx, t); err != nil {
+			return nil, err
+		}
+		tags = append(tags, t)
+	}
+	return tags, nil
+}
+
+// ensureValidParent 检查上级分类存在，且不是分类自身或其子孙分类（避免形成环）
+func (s *catalogService) ensureValidParent(ctx context.Context, id, parentID uint) error {
+	if _, err := s.categoryRepo.FindByID(ctx, parentID); err != nil {
+		return notFoundAs(err, "上级分类")
+	}
+	list, err := s.categoryRepo.ListAll(ctx)
+	if err != nil {
+		return err
+	}
+	for _, descendant := range catalog.Descendants(list, id) {
+		if descendant == parentID {
+			return common.ErrInvalidParam("不能将分类移动到自身或其子分类下")
+		}
+	}
+	return nil
+}
+
+// ensureCategoryNameAvailable 检查同一父分类下名称是否可用
+func (s *catalogService) ensureCategoryNameAvailable(ctx context.Context, parentID *uint, name string) error {
+	exists, err := s.categoryRepo.ExistsByName(ctx, parentID, name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return common.ErrAlreadyExists("同级分类名称")
+	}
+	return nil
+}
+
+// ensureTagNameAvailable 检查标签名称是否可用
+func (s *catalogService) ensureTagNameAvailable(ctx context.Context, name string) error {
+	tags, err := s.tagRepo.FindByNames(ctx, []string{name})
+	if err != nil {
+		return err
+	}
+	if len(tags) > 0 {
+		return common.ErrAlreadyExists("标签")
+	}
+	return nil
+}
+
+// validateCatalogName 校验分类或标签名称，返回去除首尾空白后的名称
+func validateCatalogName(name, resource string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", common.ErrInvalidParam(resource + "名称不能为空")
+	}
+	if utf8.RuneCountInString(name) > maxCatalogNameLen {
+		return "", common.ErrInvalidParam(fmt.Sprintf("%s名称不能超过 %d 个字符", resource, maxCatalogNameLen))
+	}
+	return name, nil
+}
+
+// validateTagName 校验标签名称，标签名称不能包含逗号（查询时用逗号分隔多个标签）
+func validateTagName(name string) (string, error) {
+	name, err := validateCatalogName(name, "标签")
+	if err != nil {
+		return "", err
+	}
+	if strings.Contains(name, ",") {
+		return "", common.ErrInvalidParam("标签名称不能包含逗号")
+	}
+	return name, nil
+}
+
+// normalizeTagNames 校验标签名称并去重（不区分大小写，保留首次出现的写法）
+func normalizeTagNames(names []string) ([]string, error) {
+	result := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name, err := validateTagName(name)
+		if err != nil {
+			return nil, err
+		}
+		key := strings.ToLower(name)
+		if !seen[key] {
+			seen[key] = true
+			result = append(result, name)
+		}
+	}
+	return result, nil
+}
+
+// sameParent 判断两个父分类 ID 是否相同
+func sameParent(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// notFoundAs 将资源不存在错误替换为指定资源的不存在错误
+func notFoundAs(err error, resource string) error {
+	if common.HasErrorCode(err, common.ErrCodeNotFound) {
+		return common.ErrNotFound(resource)
+	}
+	return err
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/catalog"
+	"power-supply-sys/internal/domain/power"
+	"power-supply-sys/internal/infra/db"
+	"power-supply-sys/internal/infra/repo"
+	"power-supply-sys/pkg/common"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// newTestCatalogService 创建商品目录服务
+func newTestCatalogService(gormDB *gorm.DB) CatalogService {
+	return NewCatalogService(
+		repo.NewCategoryRepository(gormDB),
+		repo.NewTagRepository(gormDB),
+		repo.NewPowerRepository(gormDB),
+		common.NewTransactor(gormDB),
+	)
+}
+
+func TestCatalogService_Categories(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	require.NoError(t, db.Migrate(gormDB))
+	svc := newTestCatalogService(gormDB)
+	powerSvc := newTestPowerService(gormDB)
+	ctx := context.Background()
+
+	create := func(parentID *uint, name string, sortOrder int) *catalog.Category {
+		c, err := svc.CreateCategory(ctx, &catalog.CategoryCreateRequest{ParentID: parentID, Name: name, SortOrder: sortOrder})
+		require.NoError(t, err)
+		return c
+	}
+	consumer := create(nil, "Consumer", 1)
+	server := create(nil, "Server", 0)
+	atx := create(&consumer.ID, "ATX", 0)
+	modular := create(&atx.ID, "Fully modular", 0)
+	redundant := create(&server.ID, "Redundant", 0)
+
+	t.Run("分类树", func(t *testing.T) {
+		tree, err := svc.CategoryTree(ctx)
+		require.NoError(t, err)
+		require.Len(t, tree, 2)
+		// 同级按排序值排列
+		assert.Equal(t, "Server", tree[0].Name)
+		assert.Equal(t, redundant.ID, tree[0].Children[0].ID)
+		assert.Equal(t, "Consumer", tree[1].Name)
+		assert.Equal(t, modular.ID, tree[1].Children[0].Children[0].ID)
+
+		c, err := svc.GetCategory(ctx, atx.ID)
+		require.NoError(t, err)
+		require.Len(t, c.Children, 1)
+		assert.Equal(t, "Fully modular", c.Children[0].Name)
+
+		scope, err := svc.CategoryScope(ctx, consumer.ID, true)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []uint{consumer.ID, atx.ID, modular.ID}, scope)
+
+		scope, err = svc.CategoryScope(ctx, consumer.ID, false)
+		require.NoError(t, err)
+		assert.Equal(t, []uint{consumer.ID}, scope)
+
+		_, err = svc.CategoryScope(ctx, 9999, true)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+
+	t.Run("创建校验", func(t *testing.T) {
+		// 同级名称不区分大小写唯一，不同父分类下可以重名
+		_, err := svc.CreateCategory(ctx, &catalog.CategoryCreateRequest{ParentID: &consumer.ID, Name: "atx"})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeAlreadyExists))
+		serverATX := create(&server.ID, "ATX", 1)
+		assert.Equal(t, server.ID, *serverATX.ParentID)
+
+		_, err = svc.CreateCategory(ctx, &catalog.CategoryCreateRequest{Name: " "})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		missing := uint(9999)
+		_, err = svc.CreateCategory(ctx, &catalog.CategoryCreateRequest{ParentID: &missing, Name: "X"})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+
+		require.NoError(t, svc.DeleteCategory(ctx, serverATX.ID))
+	})
+
+	t.Run("移动分类", func(t *testing.T) {
+		// 不能移动到自身或子孙分类下
+		for _, parentID := range []uint{consumer.ID, modular.ID} {
+			_, err := svc.UpdateCategory(ctx, consumer.ID, &catalog.CategoryUpdateRequest{ParentID: &parentID})
+			assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+		}
+
+		c, err := svc.UpdateCategory(ctx, modular.ID, &catalog.CategoryUpdateRequest{ParentID: &server.ID, Name: "Modular"})
+		require.NoError(t, err)
+		assert.Equal(t, server.ID, *c.ParentID)
+		assert.Equal(t, "Modular", c.Name)
+
+		// 为 0 时移动为顶级分类
+		root := uint(0)
+		c, err = svc.UpdateCategory(ctx, modular.ID, &catalog.CategoryUpdateRequest{ParentID: &root})
+		require.NoError(t, err)
+		assert.Nil(t, c.ParentID)
+
+		_, err = svc.UpdateCategory(ctx, modular.ID, &catalog.CategoryUpdateRequest{Name: "consumer"})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeAlreadyExists))
+
+		_, err = svc.UpdateCategory(ctx, modular.ID, &catalog.CategoryUpdateRequest{ParentID: &atx.ID, Name: "Fully modular"})
+		require.NoError(t, err)
+	})
+
+	t.Run("删除分类", func(t *testing.T) {
+		err := svc.DeleteCategory(ctx, atx.ID)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		ps, err := powerSvc.Create(ctx, &power.PowerSupplyCreateRequest{Name: "RM850x", Power: 850, Price: 899, CategoryID: &modular.ID})
+		require.NoError(t, err)
+		err = svc.DeleteCategory(ctx, modular.ID)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		require.NoError(t, powerSvc.Delete(ctx, ps.ID))
+		require.NoError(t, svc.DeleteCategory(ctx, modular.ID))
+		err = svc.DeleteCategory(ctx, modular.ID)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+}
+
+func TestCatalogService_Tags(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	require.NoError(t, db.Migrate(gormDB))
+	svc := newTestCatalogService(gormDB)
+	powerSvc := newTestPowerService(gormDB)
+	ctx := context.Background()
+
+	t.Run("创建与重命名", func(t *testing.T) {
+		tag, err := svc.CreateTag(ctx, " Quiet ")
+		require.NoError(t, err)
+		assert.Equal(t, "Quiet", tag.Name)
+
+		_, err = svc.CreateTag(ctx, "quiet")
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeAlreadyExists))
+		_, err = svc.CreateTag(ctx, "a,b")
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		// 只修改大小写时允许
+		tag, err = svc.UpdateTag(ctx, tag.ID, "quiet")
+		require.NoError(t, err)
+		assert.Equal(t, "quiet", tag.Name)
+
+		other, err := svc.CreateTag(ctx, "White")
+		require.NoError(t, err)
+		_, err = svc.UpdateTag(ctx, other.ID, "QUIET")
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeAlreadyExists))
+
+		list, total, err := svc.ListTags(ctx, &catalog.TagQueryRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		assert.Equal(t, "White", list[1].Name)
+	})
+
+	t.Run("按名称解析并自动创建", func(t *testing.T) {
+		tags, err := svc.ResolveTags(ctx, []string{"QUIET", "RGB", "rgb"})
+		require.NoError(t, err)
+		require.Len(t, tags, 2)
+		names := []string{tags[0].Name, tags[1].Name}
+		assert.ElementsMatch(t, []string{"quiet", "RGB"}, names)
+
+		_, total, err := svc.ListTags(ctx, &catalog.TagQueryRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+	})
+
+	t.Run("删除标签同时移除电源上的标签", func(t *testing.T) {
+		ps, err := powerSvc.Create(ctx, &power.PowerSupplyCreateRequest{Name: "RM850x", Power: 850, Price: 899, Tags: []string{"quiet", "White"}})
+		require.NoError(t, err)
+		require.Len(t, ps.Tags, 2)
+
+		list, _, err := svc.ListTags(ctx, &catalog.TagQueryRequest{Name: "White"})
+		require.NoError(t, err)
+		require.NoError(t, svc.DeleteTag(ctx, list[0].ID))
+
+		found, err := powerSvc.GetByID(ctx, ps.ID)
+		require.NoError(t, err)
+		require.Len(t, found.Tags, 1)
+		assert.Equal(t, "quiet", found.Tags[0].Name)
+
+		err = svc.DeleteTag(ctx, list[0].ID)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"power-supply-sys/internal/domain/component"
+	"power-supply-sys/pkg/common"
+	"strings"
+)
+
+// maxTransientFactor 组件瞬时峰值倍数上限
+const maxTransientFactor = 3
+
+// ComponentService 组件功耗档案服务接口：维护电源推荐使用的 CPU 与显卡功耗数据
+type ComponentService interface {
+	Create(ctx context.Context, req *component.CreateRequest) (*component.Component, error)
+	GetByID(ctx context.Context, id uint) (*component.Component, error)
+	Update(ctx context.Context, id uint, req *component.UpdateRequest) (*component.Component, error)
+	Delete(ctx context.Context, id uint) error
+	List(ctx context.Context, req *component.QueryRequest) ([]*component.Component, int64, error)
+	// Import 批量导入组件（按类型与型号覆盖已有档案），返回导入的数量
+	Import(ctx context.Context, items []*component.CreateRequest) (int, error)
+}
+
+// componentService 组件功耗档案服务实现
+type componentService struct {
+	repo       component.Repository
+	transactor common.Transactor
+}
+
+var _ ComponentService = &componentService{}
+
+// NewComponentService 创建组件功耗档案服务
+func NewComponentService(repo component.Repository, transactor common.Transactor) ComponentService {
+	return &componentService{
+		repo:       repo,
+		transactor: transactor,
+	}
+}
+
+// Create 创建组件，同类型下型号不能重复
+func (s *componentService) Create(ctx context.Context, req *component.CreateRequest) (*component.Component, error) {
+	c, err := newComponent(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureNameAvailable(ctx, c.Type, c.Name); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// GetByID 获取组件
+func (s *componentService) GetByID(ctx context.Context, id uint) (*component.Component, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// Update 更新组件，类型不可修改
+func (s *componentService) Update(ctx context.Context, id uint, req *component.UpdateRequest) (*component.Component, error) {
+	c, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]any)
+	if name := strings.TrimSpace(req.Name); name != "" && name != c.Name {
+		if !strings.EqualFold(name, c.Name) {
+			if err := s.ensureNameAvailable(ctx, c.Type, name); err != nil {
+				return nil, err
+			}
+		}
+		updates["name"] = name
+	}
+	if req.Brand != nil {
+		updates["brand"] = *req.Brand
+	}
+	if req.Watts != nil {
+		if *req.Watts <= 0 {
+			return nil, common.ErrInvalidParam("功耗必须大于0")
+		}
+		updates["watts"] = *req.Watts
+	}
+	if req.TransientFactor != nil {
+		if err := validateTransientFactor(*req.TransientFactor); err != nil {
+			return nil, err
+		}
+		updates["transient_factor"] = *req.TransientFactor
+	}
+
+	if len(updates) > 0 {
+		if err := s.repo.UpdateByID(ctx, id, updates); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.FindByID(ctx, id)
+}
+
+// Delete 删除组件
+func (s *componentService) Delete(ctx context.Context, id uint) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// List 查询组件列表
+func (s *componentService) List(ctx context.Context, req *component.QueryRequest) ([]*component.Component, int64, error) {
+	if req.Type != "" && !component.IsValidType(req.Type) {
+		return nil, 0, common.ErrInvalidParam("组件类型无效")
+	}
+	page, pageSize := common.GetPageInfo(req.Page, req.PageSize)
+	query := &component.QueryOptions{
+		Type:     req.Type,
+		Name:     req.Name,
+		Brand:    req.Brand,
+		Page:     page,
+		PageSize: pageSize,
+	}
+	total, err := s.repo.Count(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	list, err := s.repo.List(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+// Import 批量导入组件，先校验全部条目，再在同一事务中按类型与型号创建或覆盖
+func (s *componentService) Import(ctx context.Context, items []*component.CreateRequest) (int, error) {
+	if len(items) == 0 {
+		return 0, common.ErrInvalidParam("导入的组件不能为空")
+	}
+
+	list := make([]*component.Component, 0, len(items))
+	seen := make(map[string]bool, len(items))
+	for i, item := range items {
+		c, err := newComponent(item)
+		if err != nil {
+			// 错误信息标明出错的条目
+			var appErr *common.AppError
+			if errors.As(err, &appErr) {
+				return 0, common.ErrInvalidParam(fmt.Sprintf("第 %d 项: %s", i+1, appErr.Message))
+			}
+			return 0, err
+		}
+		key := c.Type + "/" + strings.ToLower(c.Name)
+		if seen[key] {
+			return 0, common.ErrInvalidParam(fmt.Sprintf("第 %d 项: 型号重复", i+1))
+		}
+		seen[key] = true
+		list = append(list, c)
+	}
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, c := range list {
+			// 已有档案按原型号写法覆盖，避免大小写不同产生重复数据
+			existing, err := s.repo.FindByName(ctx, c.Type, c.Name)
+			if err != nil && !common.HasErrorCode(err, common.ErrCodeNotFound) {
+				return err
+			}
+			if existing != nil {
+				c.Name = existing.Name
+			}
+			if err := s.repo.Save(ctx, c); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(list), nil
+}
+
+// ensureNameAvailable 校验同类型下型号未被使用（不区分大小写）
+func (s *componentService) ensureNameAvailable(ctx context.Context, componentType, name string) error {
+	_, err := s.repo.FindByName(ctx, componentType, name)
+	if err == nil {
+		return common.ErrAlreadyExists("组件型号")
+	}
+	if common.HasErrorCode(err, common.ErrCodeNotFound) {
+		return nil
+	}
+	return err
+}
+
+// newComponent 校验创建请求并构造组件
+func newComponent(req *component.CreateRequest) (*component.Component, error) {
+	if !component.IsValidType(req.Type) {
+		return nil, common.ErrInvalidParam("组件类型无效")
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, common.ErrInvalidParam("组件型号不能为空")
+	}
+	if req.Watts <= 0 {
+		return nil, common.ErrInvalidParam("功耗必须大于0")
+	}
+	if err := validateTransientFactor(req.TransientFactor); err != nil {
+		return nil, err
+	}
+	return &component.Component{
+		Type:            req.Type,
+		Name:            name,
+		Brand:           strings.TrimSpace(req.Brand),
+		Watts:           req.Watts,
+		TransientFactor: req.TransientFactor,
+	}, nil
+}
+
+// validateTransientFactor 校验瞬时峰值倍数，0 表示使用推荐策略的默认值
+func validateTransientFactor(factor float64) error {
+	if factor != 0 && (factor < 1 || factor > maxTransientFactor) {
+		return common.ErrInvalidParam(fmt.Sprintf("瞬时峰值倍数必须在 1~%d 之间", maxTransientFactor))
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/component"
+	"power-supply-sys/internal/infra/db"
+	"power-supply-sys/internal/infra/repo"
+	"power-supply-sys/pkg/common"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComponentService(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	require.NoError(t, db.Migrate(gormDB))
+	svc := NewComponentService(repo.NewComponentRepository(gormDB), common.NewTransactor(gormDB))
+	ctx := context.Background()
+
+	t.Run("创建与校验", func(t *testing.T) {
+		c, err := svc.Create(ctx, &component.CreateRequest{Type: component.TypeGPU, Name: " Arc B580 ", Brand: "Intel", Watts: 190})
+		require.NoError(t, err)
+		assert.Equal(t, "Arc B580", c.Name)
+
+		_, err = svc.Create(ctx, &component.CreateRequest{Type: component.TypeGPU, Name: "arc b580", Watts: 190})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeAlreadyExists))
+
+		// 同一型号名称可以用于不同类型
+		_, err = svc.Create(ctx, &component.CreateRequest{Type: component.TypeCPU, Name: "Arc B580", Watts: 65})
+		require.NoError(t, err)
+
+		for _, req := range []*component.CreateRequest{
+			{Type: "psu", Name: "X", Watts: 100},
+			{Type: component.TypeCPU, Name: " ", Watts: 100},
+			{Type: component.TypeCPU, Name: "X", Watts: 0},
+			{Type: component.TypeCPU, Name: "X", Watts: 100, TransientFactor: 0.5},
+		} {
+			_, err := svc.Create(ctx, req)
+			assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam), req)
+		}
+	})
+
+	t.Run("更新", func(t *testing.T) {
+		c, err := svc.Create(ctx, &component.CreateRequest{Type: component.TypeCPU, Name: "Ryzen 5 9600X", Watts: 65})
+		require.NoError(t, err)
+
+		watts, factor := 88, 1.4
+		updated, err := svc.Update(ctx, c.ID, &component.UpdateRequest{Watts: &watts, TransientFactor: &factor})
+		require.NoError(t, err)
+		assert.Equal(t, 88, updated.Watts)
+		assert.Equal(t, 1.4, updated.TransientFactor)
+
+		_, err = svc.Update(ctx, c.ID, &component.UpdateRequest{Name: "Ryzen 9 7950X"})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeAlreadyExists))
+
+		// 仅修改大小写不视为重复
+		updated, err = svc.Update(ctx, c.ID, &component.UpdateRequest{Name: "RYZEN 5 9600X"})
+		require.NoError(t, err)
+		assert.Equal(t, "RYZEN 5 9600X", updated.Name)
+	})
+
+	t.Run("批量导入", func(t *testing.T) {
+		imported, err := svc.Import(ctx, []*component.CreateRequest{
+			{Type: component.TypeGPU, Name: "geforce rtx 4090", Brand: "NVIDIA", Watts: 450, TransientFactor: 1.7},
+			{Type: component.TypeGPU, Name: "GeForce RTX 5090", Brand: "NVIDIA", Watts: 575},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 2, imported)
+
+		// 已有档案保留原型号写法
+		list, total, err := svc.List(ctx, &component.QueryRequest{Type: component.TypeGPU, Name: "RTX 4090"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, "GeForce RTX 4090", list[0].Name)
+		assert.Equal(t, 1.7, list[0].TransientFactor)
+
+		// 任一条目无效时全部不导入
+		_, err = svc.Import(ctx, []*component.CreateRequest{
+			{Type: component.TypeGPU, Name: "Radeon RX 9070 XT", Watts: 304},
+			{Type: component.TypeGPU, Name: "Broken", Watts: -1},
+		})
+		require.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+		assert.Contains(t, err.Error(), "第 2 项")
+
+		_, err = svc.Import(ctx, []*component.CreateRequest{
+			{Type: component.TypeGPU, Name: "Radeon RX 9070 XT", Watts: 304},
+			{Type: component.TypeGPU, Name: "radeon rx 9070 xt", Watts: 304},
+		})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		_, total, err = svc.List(ctx, &component.QueryRequest{Name: "9070"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), total)
+	})
+}
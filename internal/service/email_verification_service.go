@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"power-supply-sys/internal/domain/user"
+	"power-supply-sys/pkg/auth"
+	"power-supply-sys/pkg/common"
+	"power-supply-sys/pkg/mail"
+)
+
+// EmailVerificationService 邮箱验证服务接口
+type EmailVerificationService interface {
+	Send(ctx context.Context, u *user.User) error
+	Resend(ctx context.Context, email string) error
+	Verify(ctx context.Context, token string) (*user.User, error)
+}
+
+// emailVerificationService 邮箱验证服务实现
+type emailVerificationService struct {
+	userRepo  user.Repository
+	users     UserService
+	signer    *auth.EmailTokenSigner
+	mailer    mail.Mailer
+	verifyURL string
+}
+
+var _ EmailVerificationService = &emailVerificationService{}
+
+// NewEmailVerificationService 创建邮箱验证服务
+// verifyURL 为前端验证邮箱页面地址，验证令牌以 token 查询参数附加在其后
+func NewEmailVerificationService(userRepo user.Repository, users UserService, signer *auth.EmailTokenSigner, mailer mail.Mailer, verifyURL string) EmailVerificationService {
+	return &emailVerificationService{
+		userRepo:  userRepo,
+		users:     users,
+		signer:    signer,
+		mailer:    mailer,
+		verifyURL: verifyURL,
+	}
+}
+
+// Send 发送邮箱验证链接：待验证用户验证注册邮箱，修改了邮箱的用户验证待生效的新邮箱
+func (s *emailVerificationService) Send(ctx context.Context, u *user.User) error {
+	var email string
+	switch {
+	case u.Status == user.StatusPending:
+		email = u.Email
+	case u.PendingEmail != "":
+		email = u.PendingEmail
+	default:
+		return common.ErrInvalidParam("没有待验证的邮箱")
+	}
+
+	raw, err := s.signer.Generate(u.ID, email)
+	if err != nil {
+		return common.ErrInternal(err)
+	}
+
+	msg := &mail.Message{
+		To:      []string{email},
+		Subject: "验证邮箱",
+		Body:    s.verifyMailBody(u, raw),
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		return common.NewErrorWithErr(common.ErrCodeServiceError, "邮件发送失败", err)
+	}
+	return nil
+}
+
+// Resend 重新发送验证邮件
+// 邮箱不存在或用户没有待验证的邮箱时静默返回，避免泄露账号是否存在
+func (s *emailVerificationService) Resend(ctx context.Context, email string) error {
+	u, err := s.userRepo.FindOne(ctx, common.Where("email", email))
+	if err != nil {
+		if common.HasErrorCode(err, common.ErrCodeNotFound) {
+			return nil
+		}
+		return err
+	}
+	if u.Status != user.StatusPending && u.PendingEmail == "" {
+		return nil
+	}
+	return s.Send(ctx, u)
+}
+
+// Verify 校验验证令牌：注册邮箱的令牌激活用户，待生效新邮箱的令牌将其替换为当前邮箱
+// 令牌签发后邮箱被修改时令牌失效；已激活的用户重复验证直接返回
+func (s *emailVerificationService) Verify(ctx context.Context, token string) (*user.User, error) {
+	claims, err := s.signer.Parse(token)
+	if err != nil {
+		return nil, errVerificationTokenInvalid()
+	}
+	userID, err := claims.UserID()
+	if err != nil {
+		return nil, errVerificationTokenInvalid()
+	}
+
+	u, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		if common.HasErrorCode(err, common.ErrCodeNotFound) {
+			return nil, errVerificationTokenInvalid()
+		}
+		return nil, err
+	}
+	if u.PendingEmail != "" && u.PendingEmail == claims.Email {
+		return s.users.ConfirmEmail(ctx, u.ID, claims.Email)
+	}
+	if u.Email != claims.Email {
+		return nil, errVerificationTokenInvalid()
+	}
+	if u.Status == user.StatusActive {
+		return u, nil
+	}
+	return s.users.Activate(ctx, u.ID)
+}
+
+// verifyMailBody 生成验证邮件正文
+func (s *emailVerificationService) verifyMailBody(u *user.User, raw string) string {
+	link := raw
+	if s.verifyURL != "" {
+		if parsed, err := url.Parse(s.verifyURL); err == nil {
+			query := parsed.Query()
+			query.Set("token", raw)
+			parsed.RawQuery = query.Encode()
+			link = parsed.String()
+		}
+	}
+	hours := int(s.signer.Expire().Hours())
+	if u.Status != user.StatusPending {
+		return fmt.Sprintf("%s，您好：\n\n您申请将账户邮箱修改为 %s，请在 %d 小时内通过以下链接确认，确认后新邮箱生效：\n\n%s\n\n如果这不是您本人的操作，请忽略此邮件。\n",
+			u.Username, u.PendingEmail, hours, link)
+	}
+	return fmt.Sprintf("%s，您好：\n\n感谢注册，请在 %d 小时内通过以下链接验证邮箱并激活账户：\n\n%s\n\n如果这不是您本人的操作，请忽略此邮件。\n",
+		u.Username, hours, link)
+}
+
+// errVerificationTokenInvalid 验证令牌无效错误
+func errVerificationTokenInvalid() *common.AppError {
+	return common.ErrInvalidParam("验证链接无效或已过期")
+}
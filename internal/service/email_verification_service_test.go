@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/user"
+	"power-supply-sys/internal/infra/db"
+	"power-supply-sys/internal/infra/repo"
+	"power-supply-sys/pkg/auth"
+	"power-supply-sys/pkg/common"
+	"power-supply-sys/pkg/mail"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailVerificationService(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	err := db.Migrate(gormDB)
+	require.NoError(t, err)
+
+	userRepo := repo.NewUserRepository(gormDB)
+	userSvc := NewUserService(userRepo, repo.NewRefreshTokenRepository(gormDB), repo.NewRBACRepository(gormDB), newTestLoginLimiter(), common.NewTransactor(gormDB), newTestPasswordHasher(), user.DefaultPasswordPolicy())
+	signer := auth.NewEmailTokenSigner("test-secret", 24*time.Hour)
+	outbox := mail.NewOutboxMailer("", "noreply@example.com")
+	svc := NewEmailVerificationService(userRepo, userSvc, signer, outbox, "https://example.com/verify")
+	ctx := context.Background()
+
+	u, err := userSvc.Register(ctx, &user.UserCreateRequest{Username: "pendinguser", Password: "password123", Email: "pending@example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, user.StatusPending, u.Status)
+
+	t.Run("注册需要邮箱且邮箱不能重复", func(t *testing.T) {
+		_, err := userSvc.Register(ctx, &user.UserCreateRequest{Username: "noemail", Password: "password123"})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		_, err = userSvc.Register(ctx, &user.UserCreateRequest{Username: "other", Password: "password123", Email: "pending@example.com"})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeAlreadyExists))
+	})
+
+	t.Run("未验证邮箱不能登录", func(t *testing.T) {
+		_, err := userSvc.Login(ctx, &user.LoginRequest{Username: "pendinguser", Password: "password123"})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeAccountPending))
+	})
+
+	t.Run("发送验证邮件", func(t *testing.T) {
+		require.NoError(t, svc.Send(ctx, u))
+
+		msg := outbox.Last()
+		require.NotNil(t, msg)
+		assert.Equal(t, []string{"pending@example.com"}, msg.To)
+		assert.Contains(t, msg.Body, "https://example.com/verify?token=")
+	})
+
+	t.Run("重新发送对未注册邮箱静默成功", func(t *testing.T) {
+		before := len(outbox.Messages())
+		require.NoError(t, svc.Resend(ctx, "missing@example.com"))
+		assert.Len(t, outbox.Messages(), before)
+
+		require.NoError(t, svc.Resend(ctx, "pending@example.com"))
+		assert.Len(t, outbox.Messages(), before+1)
+	})
+
+	t.Run("无效令牌", func(t *testing.T) {
+		_, err := svc.Verify(ctx, "not-a-token")
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		forged, err := auth.NewEmailTokenSigner("other-secret", time.Hour).Generate(u.ID, u.Email)
+		require.NoError(t, err)
+		_, err = svc.Verify(ctx, forged)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+	})
+
+	t.Run("邮箱已修改时令牌失效", func(t *testing.T) {
+		stale, err := signer.Generate(u.ID, "old@example.com")
+		require.NoError(t, err)
+		_, err = svc.Verify(ctx, stale)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+	})
+
+	t.Run("验证通过后激活并可登录", func(t *testing.T) {
+		raw, err := signer.Generate(u.ID, u.Email)
+		require.NoError(t, err)
+
+		activated, err := svc.Verify(ctx, raw)
+		require.NoError(t, err)
+		assert.Equal(t, user.StatusActive, activated.Status)
+
+		_, err = userSvc.Login(ctx, &user.LoginRequest{Username: "pendinguser", Password: "password123"})
+		assert.NoError(t, err)
+
+		// 重复验证直接返回
+		again, err := svc.Verify(ctx, raw)
+		require.NoError(t, err)
+		assert.Equal(t, user.StatusActive, again.Status)
+
+		// 已激活的用户不再发送验证邮件
+		before := len(outbox.Messages())
+		require.NoError(t, svc.Resend(ctx, "pending@example.com"))
+		assert.Len(t, outbox.Messages(), before)
+	})
+
+	t.Run("修改邮箱需验证新邮箱后生效", func(t *testing.T) {
+		actor := &user.Actor{ID: u.ID, Role: u.Role}
+		updated, err := userSvc.Update(ctx, actor, u.ID, &user.UserUpdateRequest{Email: "changed@example.com"})
+		require.NoError(t, err)
+		assert.Equal(t, "pending@example.com", updated.Email)
+		assert.Equal(t, "changed@example.com", updated.PendingEmail)
+
+		require.NoError(t, svc.Send(ctx, updated))
+		msg := outbox.Last()
+		require.NotNil(t, msg)
+		assert.Equal(t, []string{"changed@example.com"}, msg.To)
+
+		// 当前邮箱的令牌不会替换邮箱
+		old, err := signer.Generate(u.ID, "pending@example.com")
+		require.NoError(t, err)
+		same, err := svc.Verify(ctx, old)
+		require.NoError(t, err)
+		assert.Equal(t, "pending@example.com", same.Email)
+
+		raw, err := signer.Generate(u.ID, "changed@example.com")
+		require.NoError(t, err)
+		confirmed, err := svc.Verify(ctx, raw)
+		require.NoError(t, err)
+		assert.Equal(t, "changed@example.com", confirmed.Email)
+		assert.Empty(t, confirmed.PendingEmail)
+		assert.Equal(t, user.StatusActive, confirmed.Status)
+
+		// 邮箱替换后，原邮箱的令牌失效
+		_, err = svc.Verify(ctx, old)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+	})
+
+	t.Run("新邮箱在验证前被占用", func(t *testing.T) {
+		actor := &user.Actor{ID: u.ID, Role: u.Role}
+		_, err := userSvc.Update(ctx, actor, u.ID, &user.UserUpdateRequest{Email: "race@example.com"})
+		require.NoError(t, err)
+		_, err = userSvc.Register(ctx, &user.UserCreateRequest{Username: "racer", Password: "password123", Email: "race@example.com"})
+		require.NoError(t, err)
+
+		raw, err := signer.Generate(u.ID, "race@example.com")
+		require.NoError(t, err)
+		_, err = svc.Verify(ctx, raw)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeAlreadyExists))
+	})
+
+	t.Run("待验证用户修改邮箱直接替换注册邮箱", func(t *testing.T) {
+		other, err := userSvc.Register(ctx, &user.UserCreateRequest{Username: "typo", Password: "password123", Email: "typo@exmaple.com"})
+		require.NoError(t, err)
+
+		actor := &user.Actor{ID: other.ID, Role: other.Role}
+		updated, err := userSvc.Update(ctx, actor, other.ID, &user.UserUpdateRequest{Email: "typo@example.com"})
+		require.NoError(t, err)
+		assert.Equal(t, "typo@example.com", updated.Email)
+		assert.Empty(t, updated.PendingEmail)
+		assert.Equal(t, user.StatusPending, updated.Status)
+
+		require.NoError(t, svc.Send(ctx, updated))
+		assert.Equal(t, []string{"typo@example.com"}, outbox.Last().To)
+	})
+}
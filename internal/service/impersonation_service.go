@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/audit"
+	"power-supply-sys/internal/domain/rbac"
+	"power-supply-sys/internal/domain/user"
+	"power-supply-sys/pkg/auth"
+	"power-supply-sys/pkg/common"
+)
+
+// ImpersonationService 模拟登录服务接口（签发模拟令牌并记录审计日志）
+type ImpersonationService interface {
+	Impersonate(ctx context.Context, req *audit.ImpersonateRequest) (*audit.Impersonation, error)
+	RecordWrite(ctx context.Context, entry *audit.Log) error
+}
+
+// impersonationService 模拟登录服务实现
+type impersonationService struct {
+	auditRepo   audit.Repository
+	userRepo    user.Repository
+	permissions rbac.PermissionChecker
+	jwtManager  *auth.JWTManager
+}
+
+var _ ImpersonationService = &impersonationService{}
+
+// NewImpersonationService 创建模拟登录服务
+func NewImpersonationService(auditRepo audit.Repository, userRepo user.Repository, permissions rbac.PermissionChecker, jwtManager *auth.JWTManager) ImpersonationService {
+	return &impersonationService{
+		auditRepo:   auditRepo,
+		userRepo:    userRepo,
+		permissions: permissions,
+		jwtManager:  jwtManager,
+	}
+}
+
+// Impersonate 以指定用户的身份签发 access token，token 同时携带实际操作的管理员
+// 模拟令牌绑定管理员当前的登录会话且不签发刷新令牌，管理员退出后随之失效
+// 不能模拟自己，也不能模拟管理员或同样拥有模拟权限的用户
+func (s *impersonationService) Impersonate(ctx context.Context, req *audit.ImpersonateRequest) (*audit.Impersonation, error) {
+	if req.SessionID == "" {
+		return nil, common.ErrUnauthorized("")
+	}
+	if req.TargetID == req.ActorID {
+		return nil, common.ErrInvalidParam("不能模拟自己")
+	}
+
+	actor, err := s.userRepo.FindByID(ctx, req.ActorID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.userRepo.FindByID(ctx, req.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	if target.Status == user.StatusDeleted {
+		return nil, common.ErrNotFound("用户")
+	}
+	if err := s.checkTarget(ctx, target); err != nil {
+		return nil, err
+	}
+	if err := target.StatusError(); err != nil {
+		return nil, err
+	}
+
+	accessToken, err := s.jwtManager.GenerateToken(target.ID, target.Username,
+		auth.WithSessionID(req.SessionID),
+		auth.WithRole(target.Role),
+		auth.WithActor(actor.ID, actor.Username),
+	)
+	if err != nil {
+		return nil, common.ErrInternal(err)
+	}
+
+	err = s.auditRepo.Create(ctx, &audit.Log{
+		Action:        audit.ActionImpersonate,
+		ActorID:       actor.ID,
+		ActorUsername: actor.Username,
+		UserID:        target.ID,
+		Username:      target.Username,
+		IP:            req.ClientIP,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &audit.Impersonation{
+		AccessToken: accessToken,
+		ExpiresIn:   int64(s.jwtManager.Expire().Seconds()),
+		Actor:       actor,
+		User:        target,
+	}, nil
+}
+
+// RecordWrite 记录模拟登录期间的写操作
+func (s *impersonationService) RecordWrite(ctx context.Context, entry *audit.Log) error {
+	if entry.Action == "" {
+		entry.Action = audit.ActionWrite
+	}
+	return s.auditRepo.Create(ctx, entry)
+}
+
+// checkTarget 禁止模拟管理员及拥有模拟权限的用户
+func (s *impersonationService) checkTarget(ctx context.Context, target *user.User) error {
+	if target.Role == rbac.RoleAdmin {
+		return common.ErrForbidden("不能模拟管理员")
+	}
+	privileged, err := s.permissions.HasPermission(ctx, target.Role, rbac.PermUserImpersonate)
+	if err != nil {
+		return err
+	}
+	if privileged {
+		return common.ErrForbidden("不能模拟拥有模拟权限的用户")
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/audit"
+	"power-supply-sys/internal/domain/rbac"
+	"power-supply-sys/internal/domain/user"
+	"power-supply-sys/internal/infra/db"
+	"power-supply-sys/internal/infra/repo"
+	"power-supply-sys/pkg/auth"
+	"power-supply-sys/pkg/common"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImpersonationService_Impersonate(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	err := db.Migrate(gormDB)
+	require.NoError(t, err)
+
+	userRepo := repo.NewUserRepository(gormDB)
+	auditRepo := repo.NewAuditRepository(gormDB)
+	rbacSvc := NewRBACService(repo.NewRBACRepository(gormDB), userRepo)
+	userSvc := NewUserService(userRepo, repo.NewRefreshTokenRepository(gormDB), rbacSvc, newTestLoginLimiter(), common.NewTransactor(gormDB), newTestPasswordHasher(), user.DefaultPasswordPolicy())
+	jwtManager := auth.NewJWTManager("test-secret", 15*time.Minute)
+	svc := NewImpersonationService(auditRepo, userRepo, rbacSvc, jwtManager)
+	ctx := context.Background()
+
+	newUser := func(username, role string) *user.User {
+		u, err := userSvc.Create(ctx, &user.UserCreateRequest{Username: username, Password: "password123", Email: username + "@example.com"})
+		require.NoError(t, err)
+		if role != rbac.RoleUser {
+			u, err = rbacSvc.AssignUserRole(ctx, u.ID, role)
+			require.NoError(t, err)
+		}
+		return u
+	}
+
+	_, err = rbacSvc.CreateRole(ctx, &rbac.RoleCreateRequest{
+		Code:        "support",
+		Name:        "客服",
+		Permissions: []string{rbac.PermUserRead, rbac.PermUserImpersonate},
+	})
+	require.NoError(t, err)
+
+	admin := newUser("admin1", rbac.RoleAdmin)
+	otherAdmin := newUser("admin2", rbac.RoleAdmin)
+	support := newUser("support1", "support")
+	target := newUser("customer", rbac.RoleUser)
+
+	t.Run("签发携带双方身份的令牌并记录审计日志", func(t *testing.T) {
+		result, err := svc.Impersonate(ctx, &audit.ImpersonateRequest{
+			ActorID: admin.ID, SessionID: "sess-1", TargetID: target.ID, ClientIP: "10.0.0.1",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(900), result.ExpiresIn)
+		assert.Equal(t, target.ID, result.User.ID)
+		assert.Equal(t, admin.ID, result.Actor.ID)
+
+		claims, err := jwtManager.ParseToken(result.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, target.ID, claims.UserID)
+		assert.Equal(t, rbac.RoleUser, claims.Role)
+		assert.Equal(t, "sess-1", claims.SessionID)
+		require.True(t, claims.IsImpersonated())
+		assert.Equal(t, admin.ID, claims.Actor.UserID)
+		assert.Equal(t, "admin1", claims.Actor.Username)
+
+		logs, err := auditRepo.ListByActor(ctx, admin.ID)
+		require.NoError(t, err)
+		require.Len(t, logs, 1)
+		assert.Equal(t, audit.ActionImpersonate, logs[0].Action)
+		assert.Equal(t, target.ID, logs[0].UserID)
+		assert.Equal(t, "10.0.0.1", logs[0].IP)
+	})
+
+	t.Run("拥有模拟权限的非管理员也可以发起模拟", func(t *testing.T) {
+		_, err := svc.Impersonate(ctx, &audit.ImpersonateRequest{ActorID: support.ID, SessionID: "sess-2", TargetID: target.ID})
+		assert.NoError(t, err)
+	})
+
+	t.Run("不能模拟管理员", func(t *testing.T) {
+		_, err := svc.Impersonate(ctx, &audit.ImpersonateRequest{ActorID: admin.ID, SessionID: "sess-1", TargetID: otherAdmin.ID})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeForbidden))
+
+		_, err = svc.Impersonate(ctx, &audit.ImpersonateRequest{ActorID: admin.ID, SessionID: "sess-1", TargetID: support.ID})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeForbidden))
+	})
+
+	t.Run("不能模拟自己", func(t *testing.T) {
+		_, err := svc.Impersonate(ctx, &audit.ImpersonateRequest{ActorID: admin.ID, SessionID: "sess-1", TargetID: admin.ID})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+	})
+
+	t.Run("不能模拟已删除或已禁用的用户", func(t *testing.T) {
+		disabled := newUser("disabled", rbac.RoleUser)
+		status := user.StatusDisabled
+		_, err := userSvc.Update(ctx, &user.Actor{ID: admin.ID, Role: rbac.RoleAdmin}, disabled.ID, &user.UserUpdateRequest{Status: &status})
+		require.NoError(t, err)
+		_, err = svc.Impersonate(ctx, &audit.ImpersonateRequest{ActorID: admin.ID, SessionID: "sess-1", TargetID: disabled.ID})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeAccountDisabled))
+
+		deleted := newUser("deleted", rbac.RoleUser)
+		require.NoError(t, userSvc.Delete(ctx, &user.Actor{ID: admin.ID, Role: rbac.RoleAdmin}, deleted.ID))
+		_, err = svc.Impersonate(ctx, &audit.ImpersonateRequest{ActorID: admin.ID, SessionID: "sess-1", TargetID: deleted.ID})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+
+	t.Run("记录模拟期间的写操作", func(t *testing.T) {
+		err := svc.RecordWrite(ctx, &audit.Log{
+			ActorID: admin.ID, UserID: target.ID, Method: "PUT", Path: "/api/v1/users/me", StatusCode: 200,
+		})
+		require.NoError(t, err)
+
+		logs, err := auditRepo.ListByActor(ctx, admin.ID)
+		require.NoError(t, err)
+		require.Len(t, logs, 2)
+		assert.Equal(t, audit.ActionWrite, logs[0].Action)
+		assert.Equal(t, "/api/v1/users/me", logs[0].Path)
+	})
+}
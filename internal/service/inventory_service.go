@@ -0,0 +1,371 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"power-supply-sys/internal/domain/inventory"
+	"power-supply-sys/internal/domain/power"
+	"power-supply-sys/internal/domain/warehouse"
+	"power-supply-sys/pkg/common"
+)
+
+// setStockRetries 盘点设置库存时因并发修改重新读取的最大次数
+const setStockRetries = 3
+
+// errStockChanged 盘点读取库存后库存已被并发修改
+var errStockChanged = errors.New("stock changed concurrently")
+
+// InventoryService 库存服务接口（库存只通过库存流水变更）
+type InventoryService interface {
+	RecordMovement(ctx context.Context, req *inventory.MovementRequest) (*inventory.Movement, error)
+	SetStock(ctx context.Context, powerSupplyID uint, stock int, actorID uint, reason string) (*inventory.Movement, error)
+	Transfer(ctx context.Context, req *inventory.TransferRequest) ([]*inventory.Movement, error)
+	ReturnSale(ctx context.Context, req *inventory.MovementRequest) ([]*inventory.Movement, error)
+	ListMovements(ctx context.Context, req *inventory.MovementQueryRequest) ([]*inventory.Movement, int64, error)
+}
+
+// inventoryService 库存服务实现
+type inventoryService struct {
+	movementRepo   inventory.Repository
+	stockLevelRepo warehouse.StockLevelRepository
+	warehouseRepo  warehouse.Repository
+	powerRepo      power.Repository
+	transactor     common.Transactor
+}
+
+var _ InventoryService = &inventoryService{}
+
+// NewInventoryService 创建库存服务
+// 电源的库存数量为各仓库库存之和，两者与库存流水在同一事务中同步更新
+func NewInventoryService(movementRepo inventory.Repository, stockLevelRepo warehouse.StockLevelRepository, warehouseRepo warehouse.Repository, powerRepo power.Repository, transactor common.Transactor) InventoryService {
+	return &inventoryService{
+		movementRepo:   movementRepo,
+		stockLevelRepo: stockLevelRepo,
+		warehouseRepo:  warehouseRepo,
+		powerRepo:      powerRepo,
+		transactor:     transactor,
+	}
+}
+
+// RecordMovement 登记库存流水，并在同一事务中同步电源与仓库的库存数量
+// 扣减后库存低于已预留数量或仓库库存为负数时返回库存不足错误
+func (s *inventoryService) RecordMovement(ctx context.Context, req *inventory.MovementRequest) (*inventory.Movement, error) {
+	if !inventory.IsValidType(req.Type) {
+		return nil, common.ErrInvalidParam("无效的库存流水类型")
+	}
+	if req.Type == inventory.MovementAdjustment {
+		if req.Quantity == 0 {
+			return nil, common.ErrInvalidParam("调整数量不能为0")
+		}
+	} else if req.Quantity <= 0 {
+		return nil, common.ErrInvalidParam("数量必须大于0")
+	}
+
+	var movement *inventory.Movement
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		movement, err = s.record(ctx, req.PowerSupplyID, req.Type, inventory.SignedQuantity(req.Type, req.Quantity), req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return movement, nil
+}
+
+// SetStock 将库存设置为指定数量，差额登记为盘点调整流水；数量未变化时返回 nil
+// 差额按读取到的库存计算，库存在此期间被并发修改时重新读取，多次冲突后返回错误
+func (s *inventoryService) SetStock(ctx context.Context, powerSupplyID uint, stock int, actorID uint, reason string) (*inventory.Movement, error) {
+	if stock < 0 {
+		return nil, common.ErrInvalidParam("库存不能为负数")
+	}
+
+	for i := 0; i < setStockRetries; i++ {
+		movement, err := s.setStock(ctx, powerSupplyID, stock, &inventory.MovementRequest{
+			Reason:  reason,
+			ActorID: actorID,
+		})
+		if !errors.Is(err, errStockChanged) {
+			return movement, err
+		}
+	}
+	return nil, common.ErrInvalidParam("库存已被修改，请刷新后重试")
+}
+
+// setStock 读取当前库存并按差额登记盘点调整流水，库存已被并发修改时返回 errStockChanged
+func (s *inventoryService) setStock(ctx context.Context, powerSupplyID uint, stock int, req *inventory.MovementRequest) (*inventory.Movement, error) {
+	var movement *inventory.Movement
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		ps, err := s.powerRepo.FindByID(ctx, powerSupplyID)
+		if err != nil {
+			return err
+		}
+		if ps.Stock == stock {
+			return nil
+		}
+		delta := stock - ps.Stock
+		ok, err := s.powerRepo.AdjustStockFrom(ctx, powerSupplyID, ps.Stock, delta)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			current, err := s.powerRepo.FindByID(ctx, powerSupplyID)
+			if err != nil {
+				return err
+			}
+			if current.Stock != ps.Stock {
+				return errStockChanged
+			}
+			return common.ErrStockShortage()
+		}
+		movement, err = s.writeMovements(ctx, powerSupplyID, inventory.MovementAdjustment, delta, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return movement, nil
+}
+
+// Transfer 在仓库之间调拨库存：调出与调入各登记一条调拨流水，电源的总库存不变
+// 调出仓库库存不足时返回库存不足错误
+func (s *inventoryService) Transfer(ctx context.Context, req *inventory.TransferRequest) ([]*inventory.Movement, error) {
+	if req.Quantity <= 0 {
+		return nil, common.ErrInvalidParam("数量必须大于0")
+	}
+	if req.FromWarehouseID == req.ToWarehouseID {
+		return nil, common.ErrInvalidParam("调出仓库与调入仓库不能相同")
+	}
+	for _, id := range []uint{req.FromWarehouseID, req.ToWarehouseID} {
+		if _, err := s.warehouseRepo.FindByID(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	var movements []*inventory.Movement
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		ps, err := s.powerRepo.FindByID(ctx, req.PowerSupplyID)
+		if err != nil {
+			return err
+		}
+
+		legs := []struct {
+			warehouseID uint
+			delta       int
+		}{
+			{req.FromWarehouseID, -req.Quantity},
+			{req.ToWarehouseID, req.Quantity},
+		}
+		for _, leg := range legs {
+			ok, err := s.stockLevelRepo.Adjust(ctx, leg.warehouseID, ps.ID, leg.delta)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return common.ErrStockShortage()
+			}
+
+			movement := &inventory.Movement{
+				PowerSupplyID: ps.ID,
+				WarehouseID:   leg.warehouseID,
+				Type:          inventory.MovementTransfer,
+				Quantity:      leg.delta,
+				BalanceAfter:  ps.Stock,
+				Reason:        req.Reason,
+				Reference:     req.Reference,
+				ActorID:       req.ActorID,
+			}
+			if err := s.movementRepo.Create(ctx, movement); err != nil {
+				return err
+			}
+			movements = append(movements, movement)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
+
+// ReturnSale 登记退货入库：按关联单据的销售出库流水，将数量退回原出库仓库
+// 销售流水不足以覆盖退货数量时（如历史数据），剩余数量按 req.WarehouseID 登记
+func (s *inventoryService) ReturnSale(ctx context.Context, req *inventory.MovementRequest) ([]*inventory.Movement, error) {
+	if req.Quantity <= 0 {
+		return nil, common.ErrInvalidParam("数量必须大于0")
+	}
+	if req.Reference == "" {
+		return nil, common.ErrInvalidParam("退货需要关联单据号")
+	}
+
+	var movements []*inventory.Movement
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		sales, err := s.movementRepo.List(ctx, &inventory.QueryOptions{
+			PowerSupplyID: req.PowerSupplyID,
+			Type:          inventory.MovementSale,
+			Reference:     req.Reference,
+		})
+		if err != nil {
+			return err
+		}
+
+		remaining := req.Quantity
+		returnTo := func(warehouseID uint, quantity int) error {
+			leg := *req
+			leg.WarehouseID = warehouseID
+			movement, err := s.record(ctx, req.PowerSupplyID, inventory.MovementReturn, quantity, &leg)
+			if err != nil {
+				return err
+			}
+			movements = append(movements, movement)
+			remaining -= quantity
+			return nil
+		}
+		for _, sale := range sales {
+			if remaining == 0 {
+				break
+			}
+			if err := returnTo(sale.WarehouseID, min(-sale.Quantity, remaining)); err != nil {
+				return err
+			}
+		}
+		if remaining > 0 {
+			return returnTo(req.WarehouseID, remaining)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
+
+// ListMovements 查询电源的库存流水（最新的在前）
+func (s *inventoryService) ListMovements(ctx context.Context, req *inventory.MovementQueryRequest) ([]*inventory.Movement, int64, error) {
+	if _, err := s.powerRepo.FindByID(ctx, req.PowerSupplyID); err != nil {
+		return nil, 0, err
+	}
+
+	page, pageSize := common.GetPageInfo(req.Page, req.PageSize)
+	queryOpts := &inventory.QueryOptions{
+		PowerSupplyID: req.PowerSupplyID,
+		WarehouseID:   req.WarehouseID,
+		Type:          req.Type,
+		Page:          page,
+		PageSize:      pageSize,
+	}
+
+	total, err := s.movementRepo.Count(ctx, queryOpts)
+	if err != nil {
+		return nil, 0, err
+	}
+	movements, err := s.movementRepo.List(ctx, queryOpts)
+	if err != nil {
+		return nil, 0, err
+	}
+	return movements, total, nil
+}
+
+// stockLeg 库存变动在单个仓库中的部分
+type stockLeg struct {
+	warehouseID uint
+	delta       int
+}
+
+// record 原子地调整电源与仓库的库存并写入流水（需在事务中调用）
+// 出库跨多个仓库扣减时每个仓库登记一条流水，返回最后一条（其变动后库存即为最终库存）
+func (s *inventoryService) record(ctx context.Context, powerSupplyID uint, movementType string, delta int, req *inventory.MovementRequest) (*inventory.Movement, error) {
+	ok, err := s.powerRepo.AdjustStock(ctx, powerSupplyID, delta)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		// 区分电源不存在与库存不足
+		if _, err := s.powerRepo.FindByID(ctx, powerSupplyID); err != nil {
+			return nil, err
+		}
+		return nil, common.ErrStockShortage()
+	}
+	return s.writeMovements(ctx, powerSupplyID, movementType, delta, req)
+}
+
+// writeMovements 电源库存调整后，同步仓库库存并写入流水（需在事务中调用）
+func (s *inventoryService) writeMovements(ctx context.Context, powerSupplyID uint, movementType string, delta int, req *inventory.MovementRequest) (*inventory.Movement, error) {
+	legs, err := s.allocate(ctx, powerSupplyID, req.WarehouseID, delta)
+	if err != nil {
+		return nil, err
+	}
+	for _, leg := range legs {
+		ok, err := s.stockLevelRepo.Adjust(ctx, leg.warehouseID, powerSupplyID, leg.delta)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, common.ErrStockShortage()
+		}
+	}
+
+	ps, err := s.powerRepo.FindByID(ctx, powerSupplyID)
+	if err != nil {
+		return nil, err
+	}
+
+	var movement *inventory.Movement
+	balance := ps.Stock - delta
+	for _, leg := range legs {
+		balance += leg.delta
+		movement = &inventory.Movement{
+			PowerSupplyID: powerSupplyID,
+			WarehouseID:   leg.warehouseID,
+			Type:          movementType,
+			Quantity:      leg.delta,
+			BalanceAfter:  balance,
+			Reason:        req.Reason,
+			Reference:     req.Reference,
+			ActorID:       req.ActorID,
+		}
+		if err := s.movementRepo.Create(ctx, movement); err != nil {
+			return nil, err
+		}
+	}
+	return movement, nil
+}
+
+// allocate 确定库存变动涉及的仓库（需在事务中调用）
+// 未指定仓库时，入库进入默认仓库；出库按库存从多到少依次从各仓库扣减，
+// 单个仓库不足时由下一个仓库补足，因此只要总库存足够即可出库
+func (s *inventoryService) allocate(ctx context.Context, powerSupplyID, warehouseID uint, delta int) ([]stockLeg, error) {
+	if warehouseID != 0 {
+		w, err := s.warehouseRepo.FindByID(ctx, warehouseID)
+		if err != nil {
+			return nil, err
+		}
+		return []stockLeg{{warehouseID: w.ID, delta: delta}}, nil
+	}
+	if delta > 0 {
+		w, err := s.warehouseRepo.FindDefault(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return []stockLeg{{warehouseID: w.ID, delta: delta}}, nil
+	}
+
+	levels, err := s.stockLevelRepo.ListInStock(ctx, powerSupplyID)
+	if err != nil {
+		return nil, err
+	}
+	var legs []stockLeg
+	remaining := -delta
+	for _, level := range levels {
+		if remaining == 0 {
+			break
+		}
+		take := min(level.Quantity, remaining)
+		legs = append(legs, stockLeg{warehouseID: level.WarehouseID, delta: -take})
+		remaining -= take
+	}
+	if remaining > 0 {
+		return nil, common.ErrStockShortage()
+	}
+	return legs, nil
+}
@@ -0,0 +1,313 @@
+package service
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/inventory"
+	"power-supply-sys/internal/domain/power"
+	"power-supply-sys/internal/domain/warehouse"
+	"power-supply-sys/internal/infra/db"
+	"power-supply-sys/internal/infra/repo"
+	"power-supply-sys/pkg/common"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// newTestInventoryService 创建库存服务（库存按仓库维护）
+func newTestInventoryService(gormDB *gorm.DB) InventoryService {
+	return NewInventoryService(
+		repo.NewStockMovementRepository(gormDB),
+		repo.NewStockLevelRepository(gormDB),
+		repo.NewWarehouseRepository(gormDB),
+		repo.NewPowerRepository(gormDB),
+		common.NewTransactor(gormDB),
+	)
+}
+
+func TestInventoryService_RecordMovement(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	err := db.Migrate(gormDB)
+	require.NoError(t, err)
+
+	powerRepo := repo.NewPowerRepository(gormDB)
+	svc := newTestInventoryService(gormDB)
+	ctx := context.Background()
+
+	ps := &power.PowerSupply{Name: "Ledger PSU", Power: 750, Price: 129.99, Status: 1}
+	require.NoError(t, powerRepo.Create(ctx, ps))
+
+	stockOf := func() int {
+		found, err := powerRepo.FindByID(ctx, ps.ID)
+		require.NoError(t, err)
+		return found.Stock
+	}
+
+	t.Run("入库", func(t *testing.T) {
+		m, err := svc.RecordMovement(ctx, &inventory.MovementRequest{
+			PowerSupplyID: ps.ID, Type: inventory.MovementReceipt, Quantity: 10, Reference: "PO-1", ActorID: 7,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 10, m.Quantity)
+		assert.Equal(t, 10, m.BalanceAfter)
+		assert.Equal(t, uint(7), m.ActorID)
+		assert.Equal(t, 10, stockOf())
+	})
+
+	t.Run("销售出库为负数", func(t *testing.T) {
+		m, err := svc.RecordMovement(ctx, &inventory.MovementRequest{
+			PowerSupplyID: ps.ID, Type: inventory.MovementSale, Quantity: 3, Reference: "SO-1",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, -3, m.Quantity)
+		assert.Equal(t, 7, m.BalanceAfter)
+	})
+
+	t.Run("库存不足", func(t *testing.T) {
+		_, err := svc.RecordMovement(ctx, &inventory.MovementRequest{
+			PowerSupplyID: ps.ID, Type: inventory.MovementSale, Quantity: 8,
+		})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeStockShortage))
+
+		_, err = svc.RecordMovement(ctx, &inventory.MovementRequest{
+			PowerSupplyID: ps.ID, Type: inventory.MovementAdjustment, Quantity: -8, Reason: "盘亏",
+		})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeStockShortage))
+		assert.Equal(t, 7, stockOf())
+	})
+
+	t.Run("盘点调整与退货", func(t *testing.T) {
+		m, err := svc.RecordMovement(ctx, &inventory.MovementRequest{
+			PowerSupplyID: ps.ID, Type: inventory.MovementAdjustment, Quantity: -2, Reason: "盘亏",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 5, m.BalanceAfter)
+
+		m, err = svc.RecordMovement(ctx, &inventory.MovementRequest{
+			PowerSupplyID: ps.ID, Type: inventory.MovementReturn, Quantity: 1, Reference: "SO-1",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 6, m.BalanceAfter)
+	})
+
+	t.Run("参数校验", func(t *testing.T) {
+		_, err := svc.RecordMovement(ctx, &inventory.MovementRequest{PowerSupplyID: ps.ID, Type: "gift", Quantity: 1})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		_, err = svc.RecordMovement(ctx, &inventory.MovementRequest{PowerSupplyID: ps.ID, Type: inventory.MovementSale, Quantity: -1})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		_, err = svc.RecordMovement(ctx, &inventory.MovementRequest{PowerSupplyID: ps.ID, Type: inventory.MovementAdjustment})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+	})
+
+	t.Run("电源不存在", func(t *testing.T) {
+		_, err := svc.RecordMovement(ctx, &inventory.MovementRequest{PowerSupplyID: 99999, Type: inventory.MovementReceipt, Quantity: 1})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+
+	t.Run("设置库存登记差额", func(t *testing.T) {
+		m, err := svc.SetStock(ctx, ps.ID, 12, 7, "盘点")
+		require.NoError(t, err)
+		assert.Equal(t, inventory.MovementAdjustment, m.Type)
+		assert.Equal(t, 6, m.Quantity)
+		assert.Equal(t, 12, m.BalanceAfter)
+
+		// 数量未变化时不登记流水
+		m, err = svc.SetStock(ctx, ps.ID, 12, 7, "盘点")
+		require.NoError(t, err)
+		assert.Nil(t, m)
+	})
+
+	t.Run("查询流水", func(t *testing.T) {
+		movements, total, err := svc.ListMovements(ctx, &inventory.MovementQueryRequest{PowerSupplyID: ps.ID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), total)
+		require.Len(t, movements, 5)
+		assert.Equal(t, 12, movements[0].BalanceAfter)
+
+		// 流水的变动数量之和等于当前库存
+		sum := 0
+		for _, m := range movements {
+			sum += m.Quantity
+		}
+		assert.Equal(t, stockOf(), sum)
+
+		_, _, err = svc.ListMovements(ctx, &inventory.MovementQueryRequest{PowerSupplyID: 99999})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+}
+
+// racedPowerRepository 模拟盘点读取库存后、调整前，库存被其他请求修改
+type racedPowerRepository struct {
+	power.Repository
+	races int
+}
+
+func (r *racedPowerRepository) AdjustStockFrom(ctx context.Context, id uint, expected, delta int) (bool, error) {
+	if r.races > 0 {
+		r.races--
+		if _, err := r.Repository.AdjustStock(ctx, id, 1); err != nil {
+			return false, err
+		}
+	}
+	return r.Repository.AdjustStockFrom(ctx, id, expected, delta)
+}
+
+func TestInventoryService_SetStockConflict(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	require.NoError(t, db.Migrate(gormDB))
+	powerRepo := repo.NewPowerRepository(gormDB)
+	raced := &racedPowerRepository{Repository: powerRepo}
+	svc := NewInventoryService(repo.NewStockMovementRepository(gormDB), repo.NewStockLevelRepository(gormDB),
+		repo.NewWarehouseRepository(gormDB), raced, common.NewTransactor(gormDB))
+	ctx := context.Background()
+
+	ps := &power.PowerSupply{Name: "Stocktake PSU", Power: 650, Price: 99, Status: 1}
+	require.NoError(t, powerRepo.Create(ctx, ps))
+	_, err := svc.RecordMovement(ctx, &inventory.MovementRequest{PowerSupplyID: ps.ID, Type: inventory.MovementReceipt, Quantity: 10})
+	require.NoError(t, err)
+
+	t.Run("库存被修改后按最新库存重新计算差额", func(t *testing.T) {
+		raced.races = 1
+		m, err := svc.SetStock(ctx, ps.ID, 20, 7, "盘点")
+		require.NoError(t, err)
+		assert.Equal(t, 20, m.BalanceAfter)
+
+		found, err := powerRepo.FindByID(ctx, ps.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 20, found.Stock)
+	})
+
+	t.Run("持续冲突时返回错误", func(t *testing.T) {
+		raced.races = setStockRetries
+		_, err := svc.SetStock(ctx, ps.ID, 30, 7, "盘点")
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		found, err := powerRepo.FindByID(ctx, ps.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 20, found.Stock)
+	})
+}
+
+func TestInventoryService_Transfer(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	err := db.Migrate(gormDB)
+	require.NoError(t, err)
+
+	powerRepo := repo.NewPowerRepository(gormDB)
+	warehouseRepo := repo.NewWarehouseRepository(gormDB)
+	levelRepo := repo.NewStockLevelRepository(gormDB)
+	svc := newTestInventoryService(gormDB)
+	ctx := context.Background()
+
+	main, err := warehouseRepo.FindDefault(ctx)
+	require.NoError(t, err)
+	branch := &warehouse.Warehouse{Code: "GZ", Name: "广州仓"}
+	require.NoError(t, warehouseRepo.Create(ctx, branch))
+
+	ps := &power.PowerSupply{Name: "Transfer PSU", Power: 850, Price: 159.99, Status: 1}
+	require.NoError(t, powerRepo.Create(ctx, ps))
+	_, err = svc.RecordMovement(ctx, &inventory.MovementRequest{PowerSupplyID: ps.ID, Type: inventory.MovementReceipt, Quantity: 10})
+	require.NoError(t, err)
+
+	quantities := func() map[uint]int {
+		levels, err := levelRepo.ListByPowerSupply(ctx, ps.ID)
+		require.NoError(t, err)
+		result := make(map[uint]int)
+		for _, l := range levels {
+			result[l.WarehouseID] = l.Quantity
+		}
+		return result
+	}
+
+	t.Run("未指定仓库时入库到默认仓库", func(t *testing.T) {
+		assert.Equal(t, map[uint]int{main.ID: 10}, quantities())
+	})
+
+	t.Run("调拨不改变总库存", func(t *testing.T) {
+		movements, err := svc.Transfer(ctx, &inventory.TransferRequest{
+			PowerSupplyID: ps.ID, FromWarehouseID: main.ID, ToWarehouseID: branch.ID, Quantity: 4, Reference: "TR-1",
+		})
+		require.NoError(t, err)
+		require.Len(t, movements, 2)
+		assert.Equal(t, -4, movements[0].Quantity)
+		assert.Equal(t, main.ID, movements[0].WarehouseID)
+		assert.Equal(t, 4, movements[1].Quantity)
+		assert.Equal(t, branch.ID, movements[1].WarehouseID)
+		assert.Equal(t, inventory.MovementTransfer, movements[1].Type)
+		assert.Equal(t, 10, movements[1].BalanceAfter)
+
+		assert.Equal(t, map[uint]int{main.ID: 6, branch.ID: 4}, quantities())
+		found, err := powerRepo.FindByID(ctx, ps.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 10, found.Stock)
+	})
+
+	t.Run("调出仓库库存不足时整体回滚", func(t *testing.T) {
+		_, err := svc.Transfer(ctx, &inventory.TransferRequest{
+			PowerSupplyID: ps.ID, FromWarehouseID: branch.ID, ToWarehouseID: main.ID, Quantity: 5,
+		})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeStockShortage))
+		assert.Equal(t, map[uint]int{main.ID: 6, branch.ID: 4}, quantities())
+	})
+
+	t.Run("参数校验", func(t *testing.T) {
+		_, err := svc.Transfer(ctx, &inventory.TransferRequest{PowerSupplyID: ps.ID, FromWarehouseID: main.ID, ToWarehouseID: main.ID, Quantity: 1})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		_, err = svc.Transfer(ctx, &inventory.TransferRequest{PowerSupplyID: ps.ID, FromWarehouseID: main.ID, ToWarehouseID: branch.ID})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		_, err = svc.Transfer(ctx, &inventory.TransferRequest{PowerSupplyID: ps.ID, FromWarehouseID: main.ID, ToWarehouseID: 9999, Quantity: 1})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+
+		// 调拨类型不能直接登记
+		_, err = svc.RecordMovement(ctx, &inventory.MovementRequest{PowerSupplyID: ps.ID, Type: inventory.MovementTransfer, Quantity: 1})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+	})
+
+	t.Run("未指定仓库时从库存最多的仓库出库", func(t *testing.T) {
+		m, err := svc.RecordMovement(ctx, &inventory.MovementRequest{PowerSupplyID: ps.ID, Type: inventory.MovementSale, Quantity: 2})
+		require.NoError(t, err)
+		assert.Equal(t, main.ID, m.WarehouseID)
+
+		m, err = svc.RecordMovement(ctx, &inventory.MovementRequest{PowerSupplyID: ps.ID, Type: inventory.MovementSale, Quantity: 1, WarehouseID: branch.ID})
+		require.NoError(t, err)
+		assert.Equal(t, branch.ID, m.WarehouseID)
+		assert.Equal(t, map[uint]int{main.ID: 4, branch.ID: 3}, quantities())
+
+		movements, total, err := svc.ListMovements(ctx, &inventory.MovementQueryRequest{PowerSupplyID: ps.ID, WarehouseID: branch.ID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		assert.Len(t, movements, 2)
+	})
+
+	t.Run("单个仓库不足时跨仓库出库", func(t *testing.T) {
+		m, err := svc.RecordMovement(ctx, &inventory.MovementRequest{PowerSupplyID: ps.ID, Type: inventory.MovementSale, Quantity: 6, Reference: "SO-9"})
+		require.NoError(t, err)
+		assert.Equal(t, branch.ID, m.WarehouseID)
+		assert.Equal(t, -2, m.Quantity)
+		assert.Equal(t, 1, m.BalanceAfter)
+		assert.Equal(t, map[uint]int{main.ID: 0, branch.ID: 1}, quantities())
+
+		movements, _, err := svc.ListMovements(ctx, &inventory.MovementQueryRequest{PowerSupplyID: ps.ID, Type: inventory.MovementSale})
+		require.NoError(t, err)
+		require.Len(t, movements, 4)
+		assert.Equal(t, main.ID, movements[1].WarehouseID)
+		assert.Equal(t, -4, movements[1].Quantity)
+		assert.Equal(t, 3, movements[1].BalanceAfter)
+
+		// 总库存不足时不扣减任何仓库
+		_, err = svc.RecordMovement(ctx, &inventory.MovementRequest{PowerSupplyID: ps.ID, Type: inventory.MovementSale, Quantity: 2})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeStockShortage))
+		assert.Equal(t, map[uint]int{main.ID: 0, branch.ID: 1}, quantities())
+	})
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"power-supply-sys/internal/domain/lockout"
+	"power-supply-sys/pkg/common"
+	"time"
+)
+
+// LoginLimiter 登录保护接口（按用户名与客户端 IP 统计失败次数）
+type LoginLimiter interface {
+	// Check 登录前检查用户名或 IP 是否处于锁定状态
+	Check(ctx context.Context, username, ip string) error
+	// RecordFailure 记录一次登录失败，达到阈值时锁定并返回锁定错误
+	RecordFailure(ctx context.Context, username, ip string) error
+	// RecordSuccess 登录成功后清除用户名维度的失败计数
+	RecordSuccess(ctx context.Context, username string) error
+	// Unlock 解除账户锁定
+	Unlock(ctx context.Context, username string) error
+}
+
+// loginLimiter 登录保护实现
+type loginLimiter struct {
+	store  lockout.Store
+	policy lockout.Policy
+}
+
+var _ LoginLimiter = &loginLimiter{}
+
+// NewLoginLimiter 创建登录保护
+func NewLoginLimiter(store lockout.Store, policy lockout.Policy) LoginLimiter {
+	return &loginLimiter{
+		store:  store,
+		policy: policy,
+	}
+}
+
+// Check 登录前检查用户名或 IP 是否处于锁定状态
+func (l *loginLimiter) Check(ctx context.Context, username, ip string) error {
+	now := time.Now()
+
+	attempt, err := l.store.Get(ctx, lockout.UserKey(username))
+	if err != nil {
+		return err
+	}
+	if attempt != nil && attempt.IsLocked(now) {
+		return errAccountLocked(attempt.LockedUntil.Sub(now))
+	}
+
+	if ip == "" {
+		return nil
+	}
+	attempt, err = l.store.Get(ctx, lockout.IPKey(ip))
+	if err != nil {
+		return err
+	}
+	if attempt != nil && attempt.IsLocked(now) {
+		return errTooManyLoginAttempts(attempt.LockedUntil.Sub(now))
+	}
+	return nil
+}
+
+// RecordFailure 记录一次登录失败，达到阈值时锁定并返回锁定错误
+func (l *loginLimiter) RecordFailure(ctx context.Context, username, ip string) error {
+	now := time.Now()
+	until := now.Add(l.policy.LockDuration)
+
+	userKey := lockout.UserKey(username)
+	attempt, err := l.store.Increment(ctx, userKey, now, l.policy.Window)
+	if err != nil {
+		return err
+	}
+	userLocked := attempt.Failures >= l.policy.MaxFailures
+	if userLocked {
+		if err := l.store.Lock(ctx, userKey, until); err != nil {
+			return err
+		}
+	}
+
+	ipLocked := false
+	if ip != "" {
+		ipKey := lockout.IPKey(ip)
+		attempt, err = l.store.Increment(ctx, ipKey, now, l.policy.Window)
+		if err != nil {
+			return err
+		}
+		ipLocked = attempt.Failures >= l.policy.IPMaxFailures
+		if ipLocked {
+			if err := l.store.Lock(ctx, ipKey, until); err != nil {
+				return err
+			}
+		}
+	}
+
+	switch {
+	case userLocked:
+		return errAccountLocked(l.policy.LockDuration)
+	case ipLocked:
+		return errTooManyLoginAttempts(l.policy.LockDuration)
+	}
+	return nil
+}
+
+// RecordSuccess 登录成功后清除用户名维度的失败计数
+func (l *loginLimiter) RecordSuccess(ctx context.Context, username string) error {
+	return l.store.Reset(ctx, lockout.UserKey(username))
+}
+
+// Unlock 解除账户锁定
+func (l *loginLimiter) Unlock(ctx context.Context, username string) error {
+	return l.store.Reset(ctx, lockout.UserKey(username))
+}
+
+// errAccountLocked 账户锁定错误（附带剩余锁定秒数）
+func errAccountLocked(remaining time.Duration) *common.AppError {
+	err := common.ErrAccountLocked(fmt.Sprintf("登录失败次数过多，账户已锁定，请 %d 分钟后重试", retryMinutes(remaining)))
+	err.Data = map[string]any{"retry_after": retrySeconds(remaining)}
+	return err
+}
+
+// errTooManyLoginAttempts IP 登录受限错误（附带剩余限制秒数）
+func errTooManyLoginAttempts(remaining time.Duration) *common.AppError {
+	err := common.ErrTooManyRequests(fmt.Sprintf("登录尝试过于频繁，请 %d 分钟后重试", retryMinutes(remaining)))
+	err.Data = map[string]any{"retry_after": retrySeconds(remaining)}
+	return err
+}
+
+// retrySeconds 剩余等待秒数（向上取整）
+func retrySeconds(remaining time.Duration) int64 {
+	return int64(math.Ceil(remaining.Seconds()))
+}
+
+// retryMinutes 剩余等待分钟数（向上取整）
+func retryMinutes(remaining time.Duration) int64 {
+	return int64(math.Ceil(remaining.Minutes()))
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/lockout"
+	"power-supply-sys/internal/infra/repo"
+	"power-supply-sys/pkg/common"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginLimiter(t *testing.T) {
+	ctx := context.Background()
+	policy := lockout.Policy{MaxFailures: 3, IPMaxFailures: 5, Window: time.Minute, LockDuration: time.Minute}
+
+	t.Run("用户名达到阈值后锁定", func(t *testing.T) {
+		limiter := NewLoginLimiter(repo.NewMemoryLoginAttemptStore(), policy)
+
+		assert.NoError(t, limiter.RecordFailure(ctx, "alice", "10.0.0.1"))
+		assert.NoError(t, limiter.RecordFailure(ctx, "Alice", "10.0.0.1"))
+		err := limiter.RecordFailure(ctx, "alice", "10.0.0.1")
+		require.True(t, common.HasErrorCode(err, common.ErrCodeAccountLocked))
+		assert.Equal(t, int64(60), err.(*common.AppError).Data.(map[string]any)["retry_after"])
+
+		err = limiter.Check(ctx, "ALICE", "10.0.0.2")
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeAccountLocked))
+		assert.NoError(t, limiter.Check(ctx, "bob", "10.0.0.1"))
+
+		require.NoError(t, limiter.Unlock(ctx, "alice"))
+		assert.NoError(t, limiter.Check(ctx, "alice", "10.0.0.1"))
+	})
+
+	t.Run("同一 IP 尝试多个用户名后受限", func(t *testing.T) {
+		limiter := NewLoginLimiter(repo.NewMemoryLoginAttemptStore(), policy)
+
+		var err error
+		for _, username := range []string{"u1", "u2", "u3", "u4", "u5"} {
+			err = limiter.RecordFailure(ctx, username, "10.0.0.9")
+		}
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeTooManyRequests))
+
+		err = limiter.Check(ctx, "u6", "10.0.0.9")
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeTooManyRequests))
+		assert.NoError(t, limiter.Check(ctx, "u6", "10.0.0.10"))
+	})
+}
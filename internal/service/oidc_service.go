@@ -0,0 +1,309 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"power-supply-sys/internal/domain/identity"
+	"power-supply-sys/internal/domain/rbac"
+	"power-supply-sys/internal/domain/user"
+	"power-supply-sys/pkg/auth"
+	"power-supply-sys/pkg/common"
+	"power-supply-sys/pkg/oidc"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	maxSSOUsernameLen      = 40 // 自动创建用户时用户名的最大长度（预留冲突后缀）
+	maxSSOUsernameAttempts = 5  // 用户名冲突时追加随机后缀的重试次数
+)
+
+// OIDCProvider 单点登录提供方及其账号策略
+type OIDCProvider struct {
+	Client      *oidc.Provider
+	AutoCreate  bool // 首次登录且未绑定时自动创建本地用户
+	LinkByEmail bool // 首次登录时按已验证的邮箱绑定已有本地用户
+}
+
+// OIDCService 单点登录服务接口（OpenID Connect 授权码 + PKCE）
+type OIDCService interface {
+	BeginLogin(ctx context.Context, provider string) (*identity.LoginRedirect, error)
+	CompleteLogin(ctx context.Context, req *identity.CallbackRequest) (*user.User, error)
+}
+
+// oidcService 单点登录服务实现
+type oidcService struct {
+	repo        identity.Repository
+	userRepo    user.Repository
+	transactor  common.Transactor
+	hasher      auth.PasswordHasher
+	providers   map[string]*OIDCProvider
+	stateExpire time.Duration
+}
+
+var _ OIDCService = &oidcService{}
+
+// NewOIDCService 创建单点登录服务
+func NewOIDCService(repo identity.Repository, userRepo user.Repository, transactor common.Transactor, hasher auth.PasswordHasher, providers []*OIDCProvider, stateExpire time.Duration) OIDCService {
+	byName := make(map[string]*OIDCProvider, len(providers))
+	for _, p := range providers {
+		byName[p.Client.Name()] = p
+	}
+	return &oidcService{
+		repo:        repo,
+		userRepo:    userRepo,
+		transactor:  transactor,
+		hasher:      hasher,
+		providers:   byName,
+		stateExpire: stateExpire,
+	}
+}
+
+// BeginLogin 发起单点登录：生成 state、nonce 与 PKCE 校验码并返回授权地址
+func (s *oidcService) BeginLogin(ctx context.Context, provider string) (*identity.LoginRedirect, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return nil, common.ErrNotFound("身份提供方")
+	}
+
+	state, stateHash, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return nil, common.ErrInternal(err)
+	}
+	nonce, err := auth.NewRandomID()
+	if err != nil {
+		return nil, common.ErrInternal(err)
+	}
+	verifier, err := oidc.NewCodeVerifier()
+	if err != nil {
+		return nil, common.ErrInternal(err)
+	}
+
+	authURL, err := p.Client.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		return nil, errIdentityProvider(err)
+	}
+
+	if err := s.repo.CreateState(ctx, &identity.LoginState{
+		Provider:     provider,
+		StateHash:    stateHash,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(s.stateExpire),
+	}); err != nil {
+		return nil, err
+	}
+
+	return &identity.LoginRedirect{
+		AuthorizationURL: authURL,
+		ExpiresIn:        int64(s.stateExpire.Seconds()),
+		State:            state,
+	}, nil
+}
+
+// CompleteLogin 处理提供方回调：校验 state，兑换授权码并校验 ID token，返回绑定的本地用户
+func (s *oidcService) CompleteLogin(ctx context.Context, req *identity.CallbackRequest) (*user.User, error) {
+	now := time.Now()
+
+	if req.Error != "" {
+		return nil, common.ErrUnauthorized("身份提供方拒绝登录: " + req.Error)
+	}
+	if req.State == "" || req.Code == "" {
+		return nil, errLoginStateInvalid()
+	}
+	// state 必须来自同一浏览器发起的登录，防止攻击者诱导受害者完成攻击者的登录（登录 CSRF）
+	if subtle.ConstantTimeCompare([]byte(req.State), []byte(req.BrowserState)) != 1 {
+		return nil, errLoginStateInvalid()
+	}
+
+	state, err := s.repo.FindStateByHash(ctx, auth.HashOpaqueToken(req.State))
+	if err != nil {
+		if common.HasErrorCode(err, common.ErrCodeNotFound) {
+			return nil, errLoginStateInvalid()
+		}
+		return nil, err
+	}
+	if state.UsedAt != nil || state.IsExpired(now) {
+		return nil, errLoginStateInvalid()
+	}
+	p, ok := s.providers[state.Provider]
+	if !ok {
+		return nil, errLoginStateInvalid()
+	}
+
+	// state 一次性使用，防止回调被重放
+	used, err := s.repo.MarkStateUsed(ctx, state.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !used {
+		return nil, errLoginStateInvalid()
+	}
+
+	tokens, err := p.Client.Exchange(ctx, req.Code, state.CodeVerifier)
+	if err != nil {
+		return nil, errIdentityProvider(err)
+	}
+	claims, err := p.Client.VerifyIDToken(ctx, tokens.IDToken, state.Nonce)
+	if err != nil {
+		return nil, common.NewErrorWithErr(common.ErrCodeUnauthorized, "身份令牌校验失败", err)
+	}
+
+	u, err := s.resolveUser(ctx, state.Provider, p, claims, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.StatusError(); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// resolveUser 查找外部身份绑定的本地用户，未绑定时按提供方策略绑定或创建
+func (s *oidcService) resolveUser(ctx context.Context, provider string, p *OIDCProvider, claims *oidc.IDTokenClaims, now time.Time) (*user.User, error) {
+	linked, err := s.repo.FindIdentity(ctx, provider, claims.Subject)
+	if err == nil {
+		if err := s.repo.TouchIdentity(ctx, linked.ID, claims.Email, now); err != nil {
+			return nil, err
+		}
+		return s.userRepo.FindByID(ctx, linked.UserID)
+	}
+	if !common.HasErrorCode(err, common.ErrCodeNotFound) {
+		return nil, err
+	}
+
+	var u *user.User
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		u, err = s.findUserByEmail(ctx, p, claims)
+		if err != nil {
+			return err
+		}
+		if u == nil {
+			if !p.AutoCreate {
+				return common.ErrForbidden("该单点登录账号未绑定本地用户")
+			}
+			if u, err = s.createUser(ctx, claims); err != nil {
+				return err
+			}
+		}
+		return s.repo.CreateIdentity(ctx, &identity.ExternalIdentity{
+			UserID:      u.ID,
+			Provider:    provider,
+			Subject:     claims.Subject,
+			Email:       claims.Email,
+			LastLoginAt: &now,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// findUserByEmail 按已验证的邮箱查找可绑定的本地用户（提供方未启用按邮箱绑定时返回 nil）
+func (s *oidcService) findUserByEmail(ctx context.Context, p *OIDCProvider, claims *oidc.IDTokenClaims) (*user.User, error) {
+	if !p.LinkByEmail || claims.Email == "" || !claims.EmailVerified {
+		return nil, nil
+	}
+	u, err := s.userRepo.FindOne(ctx, common.Where("email", claims.Email))
+	if err != nil {
+		if common.HasErrorCode(err, common.ErrCodeNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return u, nil
+}
+
+// createUser 根据 ID token 信息创建本地用户
+// 本地密码为随机值，用户如需密码登录可通过找回密码设置
+func (s *oidcService) createUser(ctx context.Context, claims *oidc.IDTokenClaims) (*user.User, error) {
+	randomPassword, err := auth.NewRandomID()
+	if err != nil {
+		return nil, common.ErrInternal(err)
+	}
+	hashedPassword, err := s.hasher.Hash(randomPassword)
+	if err != nil {
+		return nil, common.ErrInternal(err)
+	}
+
+	// 本地用户的邮箱唯一，自动创建要求提供方返回未被占用的邮箱
+	if claims.Email == "" {
+		return nil, common.ErrForbidden("身份提供方未返回邮箱，无法自动创建用户")
+	}
+	taken, err := s.userRepo.Exists(ctx, common.Where("email", claims.Email))
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, common.ErrForbidden("邮箱已被其他账号使用，请联系管理员绑定")
+	}
+
+	username, err := s.availableUsername(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &user.User{
+		Username: username,
+		Password: hashedPassword,
+		Email:    claims.Email,
+		Nickname: truncateRunes(claims.Name, 50),
+		Role:     rbac.RoleUser,
+		Status:   user.StatusActive,
+	}
+	if err := s.userRepo.Create(ctx, u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// availableUsername 从 ID token 推导未被占用的用户名，冲突时追加随机后缀
+func (s *oidcService) availableUsername(ctx context.Context, claims *oidc.IDTokenClaims) (string, error) {
+	base := sanitizeUsername(claims.PreferredUsername)
+	if base == "" {
+		base = sanitizeUsername(strings.SplitN(claims.Email, "@", 2)[0])
+	}
+	if len(base) < 3 {
+		base = "sso_" + base
+	}
+	base = truncateRunes(base, maxSSOUsernameLen)
+
+	candidate := base
+	for i := 0; i < maxSSOUsernameAttempts; i++ {
+		taken, err := s.userRepo.Exists(ctx, common.Where("username", candidate))
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return candidate, nil
+		}
+		suffix, err := auth.NewRandomID()
+		if err != nil {
+			return "", common.ErrInternal(err)
+		}
+		candidate = base + "_" + suffix[:6]
+	}
+	return "", common.ErrAlreadyExists("用户名")
+}
+
+// sanitizeUsername 只保留字母、数字与 ._- 字符
+func sanitizeUsername(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '_' || r == '-') {
+			return r
+		}
+		return -1
+	}, name)
+}
+
+// errLoginStateInvalid 单点登录状态无效错误
+func errLoginStateInvalid() *common.AppError {
+	return common.ErrInvalidParam("登录请求无效或已过期，请重新发起登录")
+}
+
+// errIdentityProvider 身份提供方请求失败错误
+func errIdentityProvider(err error) *common.AppError {
+	return common.NewErrorWithErr(common.ErrCodeServiceError, "身份提供方请求失败", err)
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/identity"
+	"power-supply-sys/internal/domain/user"
+	"power-supply-sys/internal/infra/db"
+	"power-supply-sys/internal/infra/repo"
+	"power-supply-sys/pkg/common"
+	"power-supply-sys/pkg/oidc"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// setupOIDCService 创建连接本地模拟提供方的单点登录服务
+func setupOIDCService(t *testing.T, gormDB *gorm.DB, providers ...*OIDCProvider) OIDCService {
+	err := db.Migrate(gormDB)
+	require.NoError(t, err)
+
+	return NewOIDCService(repo.NewIdentityRepository(gormDB), repo.NewUserRepository(gormDB),
+		common.NewTransactor(gormDB), newTestPasswordHasher(), providers, 10*time.Minute)
+}
+
+// oidcLogin 走完整的授权码流程：发起登录、在提供方登录、处理回调
+func oidcLogin(t *testing.T, svc OIDCService, issuer *oidc.TestIssuer, provider string, id oidc.TestIdentity) (*user.User, error) {
+	ctx := context.Background()
+	redirect, err := svc.BeginLogin(ctx, provider)
+	require.NoError(t, err)
+
+	code, state := issuer.Authorize(redirect.AuthorizationURL, id)
+	return svc.CompleteLogin(ctx, &identity.CallbackRequest{Code: code, State: state, BrowserState: redirect.State})
+}
+
+func TestOIDCService_CompleteLogin(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	issuer := oidc.NewTestIssuer(t)
+	svc := setupOIDCService(t, gormDB, &OIDCProvider{
+		Client:     oidc.NewProvider(issuer.Config("corp"), nil),
+		AutoCreate: true,
+	})
+	alice := oidc.TestIdentity{Subject: "sub-alice", Email: "alice@corp.example", EmailVerified: true, Name: "Alice", PreferredUsername: "alice"}
+
+	t.Run("首次登录自动创建用户", func(t *testing.T) {
+		u, err := oidcLogin(t, svc, issuer, "corp", alice)
+		require.NoError(t, err)
+		assert.Equal(t, "alice", u.Username)
+		assert.Equal(t, "alice@corp.example", u.Email)
+		assert.Equal(t, "Alice", u.Nickname)
+		assert.Equal(t, "user", u.Role)
+	})
+
+	t.Run("再次登录找到已绑定的用户", func(t *testing.T) {
+		first, err := oidcLogin(t, svc, issuer, "corp", alice)
+		require.NoError(t, err)
+		second, err := oidcLogin(t, svc, issuer, "corp", alice)
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, second.ID)
+	})
+
+	t.Run("用户名冲突时追加后缀", func(t *testing.T) {
+		u, err := oidcLogin(t, svc, issuer, "corp", oidc.TestIdentity{Subject: "sub-other-alice", Email: "alice@other.example", PreferredUsername: "alice"})
+		require.NoError(t, err)
+		assert.Regexp(t, `^alice_[0-9a-f]{6}$`, u.Username)
+	})
+
+	t.Run("邮箱缺失或已被占用时不自动创建", func(t *testing.T) {
+		_, err := oidcLogin(t, svc, issuer, "corp", oidc.TestIdentity{Subject: "sub-no-email", PreferredUsername: "noemail"})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeForbidden))
+
+		_, err = oidcLogin(t, svc, issuer, "corp", oidc.TestIdentity{Subject: "sub-alice-2", Email: "alice@corp.example", EmailVerified: true})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeForbidden))
+	})
+
+	t.Run("被禁用的用户不能登录", func(t *testing.T) {
+		bob := oidc.TestIdentity{Subject: "sub-bob", Email: "bob@corp.example", PreferredUsername: "bob"}
+		u, err := oidcLogin(t, svc, issuer, "corp", bob)
+		require.NoError(t, err)
+		require.NoError(t, repo.NewUserRepository(gormDB).UpdateByID(context.Background(), u.ID, map[string]any{"status": 0}))
+
+		_, err = oidcLogin(t, svc, issuer, "corp", bob)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeAccountDisabled))
+	})
+}
+
+func TestOIDCService_LinkByEmail(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	issuer := oidc.NewTestIssuer(t)
+	svc := setupOIDCService(t, gormDB, &OIDCProvider{
+		Client:      oidc.NewProvider(issuer.Config("corp"), nil),
+		LinkByEmail: true,
+	})
+
+	local, err := NewUserService(repo.NewUserRepository(gormDB), repo.NewRefreshTokenRepository(gormDB), repo.NewRBACRepository(gormDB), newTestLoginLimiter(), common.NewTransactor(gormDB), newTestPasswordHasher(), user.DefaultPasswordPolicy()).Create(context.Background(), &user.UserCreateRequest{
+		Username: "carol",
+		Password: "password123",
+		Email:    "carol@corp.example",
+	})
+	require.NoError(t, err)
+
+	t.Run("未验证的邮箱不绑定", func(t *testing.T) {
+		_, err := oidcLogin(t, svc, issuer, "corp", oidc.TestIdentity{Subject: "sub-carol", Email: "carol@corp.example"})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeForbidden))
+	})
+
+	t.Run("已验证的邮箱绑定已有用户", func(t *testing.T) {
+		u, err := oidcLogin(t, svc, issuer, "corp", oidc.TestIdentity{Subject: "sub-carol", Email: "carol@corp.example", EmailVerified: true})
+		require.NoError(t, err)
+		assert.Equal(t, local.ID, u.ID)
+	})
+
+	t.Run("未开启自动创建时拒绝未绑定账号", func(t *testing.T) {
+		_, err := oidcLogin(t, svc, issuer, "corp", oidc.TestIdentity{Subject: "sub-dave", Email: "dave@corp.example", EmailVerified: true})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeForbidden))
+	})
+}
+
+func TestOIDCService_RejectsInvalidCallbacks(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	issuer := oidc.NewTestIssuer(t)
+	svc := setupOIDCService(t, gormDB, &OIDCProvider{
+		Client:     oidc.NewProvider(issuer.Config("corp"), nil),
+		AutoCreate: true,
+	})
+	ctx := context.Background()
+	id := oidc.TestIdentity{Subject: "sub-1", Email: "erin@corp.example", PreferredUsername: "erin"}
+
+	t.Run("未知提供方", func(t *testing.T) {
+		_, err := svc.BeginLogin(ctx, "unknown")
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+
+	t.Run("state 无效或被重放", func(t *testing.T) {
+		redirect, err := svc.BeginLogin(ctx, "corp")
+		require.NoError(t, err)
+		code, state := issuer.Authorize(redirect.AuthorizationURL, id)
+
+		_, err = svc.CompleteLogin(ctx, &identity.CallbackRequest{Code: code, State: "forged", BrowserState: "forged"})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		_, err = svc.CompleteLogin(ctx, &identity.CallbackRequest{Code: code, State: state, BrowserState: state})
+		require.NoError(t, err)
+		_, err = svc.CompleteLogin(ctx, &identity.CallbackRequest{Code: code, State: state, BrowserState: state})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+	})
+
+	t.Run("state 与发起登录的浏览器不一致", func(t *testing.T) {
+		// 攻击者发起登录并取得授权码，诱导受害者的浏览器完成回调
+		redirect, err := svc.BeginLogin(ctx, "corp")
+		require.NoError(t, err)
+		code, state := issuer.Authorize(redirect.AuthorizationURL, id)
+		assert.Equal(t, redirect.State, state)
+
+		victim, err := svc.BeginLogin(ctx, "corp")
+		require.NoError(t, err)
+		_, err = svc.CompleteLogin(ctx, &identity.CallbackRequest{Code: code, State: state, BrowserState: victim.State})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+		_, err = svc.CompleteLogin(ctx, &identity.CallbackRequest{Code: code, State: state})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		// 不一致的回调不消耗 state，发起登录的浏览器仍可完成登录
+		_, err = svc.CompleteLogin(ctx, &identity.CallbackRequest{Code: code, State: state, BrowserState: redirect.State})
+		require.NoError(t, err)
+	})
+
+	t.Run("提供方返回错误", func(t *testing.T) {
+		_, err := svc.CompleteLogin(ctx, &identity.CallbackRequest{Error: "access_denied"})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeUnauthorized))
+	})
+
+	t.Run("ID token 校验失败", func(t *testing.T) {
+		issuer.ClaimsHook = func(claims jwt.MapClaims) { claims["aud"] = "other-client" }
+		defer func() { issuer.ClaimsHook = nil }()
+
+		_, err := oidcLogin(t, svc, issuer, "corp", id)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeUnauthorized))
+	})
+}
@@ -0,0 +1,476 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math"
+	"math/big"
+	"power-supply-sys/internal/domain/inventory"
+	"power-supply-sys/internal/domain/order"
+	"power-supply-sys/internal/domain/power"
+	"power-supply-sys/internal/domain/rbac"
+	"power-supply-sys/internal/domain/user"
+	"power-supply-sys/pkg/common"
+	"time"
+)
+
+// maxOrderItems 单个订单的最大明细数量
+const maxOrderItems = 50
+
+// OrderService 订单服务接口
+type OrderService interface {
+	Create(ctx context.Context, actor *user.Actor, req *order.CreateRequest) (*order.Order, error)
+	GetByID(ctx context.Context, actor *user.Actor, id uint) (*order.Order, error)
+	List(ctx context.Context, actor *user.Actor, req *order.QueryRequest) ([]*order.Order, int64, error)
+	Pay(ctx context.Context, actor *user.Actor, id uint) (*order.Order, error)
+	Cancel(ctx context.Context, actor *user.Actor, id uint) (*order.Order, error)
+	Ship(ctx context.Context, actor *user.Actor, id uint) (*order.Order, error)
+	Complete(ctx context.Context, actor *user.Actor, id uint) (*order.Order, error)
+	Refund(ctx context.Context, actor *user.Actor, id uint) (*order.Order, error)
+	CancelExpired(ctx context.Context) (int, error)
+}
+
+// orderService 订单服务实现
+type orderService struct {
+	repo         order.Repository
+	powerRepo    power.Repository
+	reservations ReservationService
+	inventory    InventoryService
+	permissions  rbac.PermissionChecker
+	transactor   common.Transactor
+}
+
+var _ OrderService = &orderService{}
+
+// NewOrderService 创建订单服务
+// 下单时通过 reservations 预留库存，支付时确认预留扣减库存，取消或退款时登记退货入库流水
+func NewOrderService(repo order.Repository, powerRepo power.Repository, reservations ReservationService, inventory InventoryService, permissions rbac.PermissionChecker, transactor common.Transactor) OrderService {
+	return &orderService{
+		repo:         repo,
+		powerRepo:    powerRepo,
+		reservations: reservations,
+		inventory:    inventory,
+		permissions:  permissions,
+		transactor:   transactor,
+	}
+}
+
+// Create 创建订单：快照电源当前的名称与单价，并为每个明细预留库存
+// 任一明细库存不足时整单失败，已预留的库存随事务回滚
+func (s *orderService) Create(ctx context.Context, actor *user.Actor, req *order.CreateRequest) (*order.Order, error) {
+	if actor == nil {
+		return nil, common.ErrUnauthorized("")
+	}
+	if err := validateOrderItems(req.Items); err != nil {
+		return nil, err
+	}
+
+	orderNo, err := generateOrderNo()
+	if err != nil {
+		return nil, common.ErrInternal(err)
+	}
+
+	o := &order.Order{
+		OrderNo: orderNo,
+		UserID:  actor.ID,
+		Status:  order.StatusCreated,
+		Remark:  req.Remark,
+	}
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var total float64
+		for _, item := range req.Items {
+			ps, err := s.powerRepo.FindByID(ctx, item.PowerSupplyID)
+			if err != nil {
+				if common.HasErrorCode(err, common.ErrCodeNotFound) {
+					return common.ErrNotFound("电源")
+				}
+				return err
+			}
+
+			reservation, err := s.reservations.Reserve(ctx, &inventory.ReserveRequest{
+				PowerSupplyID: ps.ID,
+				Quantity:      item.Quantity,
+				Reference:     orderNo,
+				ActorID:       actor.ID,
+			})
+			if err != nil {
+				return err
+			}
+
+			subtotal := roundAmount(ps.Price * float64(item.Quantity))
+			total += subtotal
+			o.Items = append(o.Items, &order.Item{
+				PowerSupplyID: ps.ID,
+				Name:          ps.Name,
+				Brand:         ps.Brand,
+				Model:         ps.Model,
+				UnitPrice:     ps.Price,
+				Quantity:      item.Quantity,
+				Subtotal:      subtotal,
+				ReservationID: &reservation.ID,
+			})
+		}
+		o.TotalAmount = roundAmount(total)
+		return s.repo.Create(ctx, o)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, o.ID)
+}
+
+// GetByID 获取订单（本人或拥有 order:read 权限）
+func (s *orderService) GetByID(ctx context.Context, actor *user.Actor, id uint) (*order.Order, error) {
+	o, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, actor, o, rbac.PermOrderRead); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// List 查询订单列表，没有 order:read 权限时只返回本人的订单
+func (s *orderService) List(ctx context.Context, actor *user.Actor, req *order.QueryRequest) ([]*order.Order, int64, error) {
+	if actor == nil {
+		return nil, 0, common.ErrUnauthorized("")
+	}
+	if req.Status != "" && !order.IsValidStatus(req.Status) {
+		return nil, 0, common.ErrInvalidParam("订单状态无效")
+	}
+
+	userID := req.UserID
+	canReadAll, err := s.permissions.HasPermission(ctx, actor.Role, rbac.PermOrderRead)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !canReadAll {
+		userID = &actor.ID
+	}
+
+	page, pageSize := common.GetPageInfo(req.Page, req.PageSize)
+	query := &order.QueryOptions{
+		UserID:   userID,
+		Status:   req.Status,
+		Page:     page,
+		PageSize: pageSize,
+	}
+	total, err := s.repo.Count(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	list, err := s.repo.List(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+// Pay 确认订单已支付（需要 order:manage 权限，下单用户不能自行标记支付）
+// 确认各明细的库存预留，在同一事务中扣减库存并登记销售出库流水；预留已过期时订单自动取消
+func (s *orderService) Pay(ctx context.Context, actor *user.Actor, id uint) (*order.Order, error) {
+	if err := s.requirePermission(ctx, actor, rbac.PermOrderManage); err != nil {
+		return nil, err
+	}
+	o, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !order.CanTransition(o.Status, order.StatusPaid) {
+		return nil, errOrderStatus(o.Status)
+	}
+
+	expired, err := s.reservationsExpired(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+	if expired {
+		if err := s.transition(ctx, o, order.StatusCancelled, s.cancelReservations); err != nil {
+			return nil, err
+		}
+		return nil, common.ErrInvalidParam("订单支付超时，已自动取消")
+	}
+
+	err = s.transition(ctx, o, order.StatusPaid, func(ctx context.Context, o *order.Order) error {
+		for _, item := range o.Items {
+			if _, err := s.reservations.Confirm(ctx, *item.ReservationID, actor.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, id)
+}
+
+// Cancel 取消订单（本人或拥有 order:manage 权限）
+// 未支付的订单释放库存预留，已支付未发货的订单登记退货入库流水退回库存
+func (s *orderService) Cancel(ctx context.Context, actor *user.Actor, id uint) (*order.Order, error) {
+	o, err := s.load(ctx, actor, id, rbac.PermOrderManage)
+	if err != nil {
+		return nil, err
+	}
+	if !order.CanTransition(o.Status, order.StatusCancelled) {
+		return nil, errOrderStatus(o.Status)
+	}
+
+	restore := s.cancelReservations
+	if order.StockDeducted(o.Status) {
+		restore = s.restockFunc(actor.ID, "取消订单退回库存")
+	}
+	if err := s.transition(ctx, o, order.StatusCancelled, restore); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, id)
+}
+
+// CancelExpired 取消库存预留已过期的待支付订单（由后台任务定期调用），返回取消的数量
+// 未过期的预留一并释放；订单已被并发支付或取消时跳过
+func (s *orderService) CancelExpired(ctx context.Context) (int, error) {
+	cancelled := 0
+	for {
+		expired, err := s.repo.ListReservationExpired(ctx, time.Now(), releaseBatchSize)
+		if err != nil {
+			return cancelled, err
+		}
+		for _, o := range expired {
+			err := s.transition(ctx, o, order.StatusCancelled, s.cancelReservations)
+			if err != nil && !common.HasErrorCode(err, common.ErrCodeInvalidParam) {
+				return cancelled, err
+			}
+			if err == nil {
+				cancelled++
+			}
+		}
+		if len(expired) < releaseBatchSize {
+			return cancelled, nil
+		}
+	}
+}
+
+// Ship 订单发货（需要 order:manage 权限）
+func (s *orderService) Ship(ctx context.Context, actor *user.Actor, id uint) (*order.Order, error) {
+	if err := s.requirePermission(ctx, actor, rbac.PermOrderManage); err != nil {
+		return nil, err
+	}
+	return s.advance(ctx, id, order.StatusShipped)
+}
+
+// Complete 确认收货完成订单（本人或拥有 order:manage 权限）
+func (s *orderService) Complete(ctx context.Context, actor *user.Actor, id uint) (*order.Order, error) {
+	if _, err := s.load(ctx, actor, id, rbac.PermOrderManage); err != nil {
+		return nil, err
+	}
+	return s.advance(ctx, id, order.StatusCompleted)
+}
+
+// Refund 订单退款并退回库存（需要 order:manage 权限）
+func (s *orderService) Refund(ctx context.Context, actor *user.Actor, id uint) (*order.Order, error) {
+	if err := s.requirePermission(ctx, actor, rbac.PermOrderManage); err != nil {
+		return nil, err
+	}
+	o, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !order.CanTransition(o.Status, order.StatusRefunded) {
+		return nil, errOrderStatus(o.Status)
+	}
+
+	if err := s.transition(ctx, o, order.StatusRefunded, s.restockFunc(actor.ID, "订单退款退回库存")); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, id)
+}
+
+// advance 推进不涉及库存变化的状态流转
+func (s *orderService) advance(ctx context.Context, id uint, to string) (*order.Order, error) {
+	o, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !order.CanTransition(o.Status, to) {
+		return nil, errOrderStatus(o.Status)
+	}
+	if err := s.transition(ctx, o, to, nil); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, id)
+}
+
+// transition 将订单从当前状态流转到 to 状态，并在同一事务中执行库存变更
+// 状态条件更新保证并发操作同一订单时只有一个生效
+func (s *orderService) transition(ctx context.Context, o *order.Order, to string, fn func(ctx context.Context, o *order.Order) error) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		ok, err := s.repo.UpdateStatus(ctx, o.ID, o.Status, to, time.Now())
+		if err != nil {
+			return err
+		}
+		if !ok {
+			current, err := s.repo.FindByID(ctx, o.ID)
+			if err != nil {
+				return err
+			}
+			return errOrderStatus(current.Status)
+		}
+		if fn == nil {
+			return nil
+		}
+		return fn(ctx, o)
+	})
+}
+
+// cancelReservations 释放未支付订单的库存预留，已过期释放的预留直接跳过
+func (s *orderService) cancelReservations(ctx context.Context, o *order.Order) error {
+	for _, item := range o.Items {
+		if item.ReservationID == nil {
+			continue
+		}
+		_, err := s.reservations.Cancel(ctx, *item.ReservationID)
+		if err != nil && !common.HasErrorCode(err, common.ErrCodeInvalidParam) {
+			return err
+		}
+	}
+	return nil
+}
+
+// restockFunc 返回登记退货入库流水退回已扣减库存的函数，库存退回支付时出库的仓库
+func (s *orderService) restockFunc(actorID uint, reason string) func(ctx context.Context, o *order.Order) error {
+	return func(ctx context.Context, o *order.Order) error {
+		for _, item := range o.Items {
+			_, err := s.inventory.ReturnSale(ctx, &inventory.MovementRequest{
+				PowerSupplyID: item.PowerSupplyID,
+				Quantity:      item.Quantity,
+				Reason:        reason,
+				Reference:     o.OrderNo,
+				ActorID:       actorID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// reservationsExpired 判断订单是否有已过期或已被释放的库存预留
+func (s *orderService) reservationsExpired(ctx context.Context, o *order.Order) (bool, error) {
+	now := time.Now()
+	for _, item := range o.Items {
+		if item.ReservationID == nil {
+			return true, nil
+		}
+		r, err := s.reservations.GetByID(ctx, *item.ReservationID)
+		if err != nil {
+			return false, err
+		}
+		if r.Status != inventory.ReservationPending || r.IsExpired(now) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// load 查询订单并校验操作者为下单用户或拥有指定权限
+func (s *orderService) load(ctx context.Context, actor *user.Actor, id uint, permission string) (*order.Order, error) {
+	if actor == nil {
+		return nil, common.ErrUnauthorized("")
+	}
+	o, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, actor, o, permission); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// authorize 校验操作者为下单用户或拥有指定权限
+func (s *orderService) authorize(ctx context.Context, actor *user.Actor, o *order.Order, permission string) error {
+	if actor == nil {
+		return common.ErrUnauthorized("")
+	}
+	if actor.ID == o.UserID {
+		return nil
+	}
+	allowed, err := s.permissions.HasPermission(ctx, actor.Role, permission)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return common.ErrForbidden("无权操作该订单")
+	}
+	return nil
+}
+
+// requirePermission 校验操作者拥有指定权限
+func (s *orderService) requirePermission(ctx context.Context, actor *user.Actor, permission string) error {
+	if actor == nil {
+		return common.ErrUnauthorized("")
+	}
+	allowed, err := s.permissions.HasPermission(ctx, actor.Role, permission)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return common.ErrForbidden("")
+	}
+	return nil
+}
+
+// validateOrderItems 校验订单明细：不能为空、电源不能重复、数量必须大于0
+func validateOrderItems(items []order.ItemRequest) error {
+	if len(items) == 0 {
+		return common.ErrInvalidParam("订单明细不能为空")
+	}
+	if len(items) > maxOrderItems {
+		return common.ErrInvalidParam(fmt.Sprintf("订单明细不能超过%d项", maxOrderItems))
+	}
+	seen := make(map[uint]bool, len(items))
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return common.ErrInvalidParam("数量必须大于0")
+		}
+		if seen[item.PowerSupplyID] {
+			return common.ErrInvalidParam("订单明细中电源重复")
+		}
+		seen[item.PowerSupplyID] = true
+	}
+	return nil
+}
+
+// errOrderStatus 订单状态不允许操作的错误
+func errOrderStatus(status string) *common.AppError {
+	switch status {
+	case order.StatusCancelled:
+		return common.ErrInvalidParam("订单已取消")
+	case order.StatusRefunded:
+		return common.ErrInvalidParam("订单已退款")
+	default:
+		return common.ErrInvalidParam(fmt.Sprintf("订单状态为%s，不允许此操作", status))
+	}
+}
+
+// generateOrderNo 生成订单号
+func generateOrderNo() (string, error) {
+	return generateSerialNo("PS")
+}
+
+// generateSerialNo 生成单据号：前缀加创建时间（精确到秒）加 6 位随机数
+func generateSerialNo(prefix string) (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s%s%06d", prefix, time.Now().Format("20060102150405"), n.Int64()), nil
+}
+
+// roundAmount 金额保留两位小数
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
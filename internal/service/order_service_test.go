@@ -0,0 +1,355 @@
+package service
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/inventory"
+	"power-supply-sys/internal/domain/order"
+	"power-supply-sys/internal/domain/power"
+	"power-supply-sys/internal/domain/rbac"
+	"power-supply-sys/internal/domain/user"
+	"power-supply-sys/internal/domain/warehouse"
+	"power-supply-sys/internal/infra/db"
+	"power-supply-sys/internal/infra/repo"
+	"power-supply-sys/pkg/common"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// setupOrderTest 创建订单服务及两款测试电源（库存分别为 10 和 5）
+func setupOrderTest(t *testing.T, gormDB *gorm.DB, ttl time.Duration) (OrderService, power.Repository, inventory.Repository, []*power.PowerSupply) {
+	err := db.Migrate(gormDB)
+	require.NoError(t, err)
+
+	powerRepo := repo.NewPowerRepository(gormDB)
+	movementRepo := repo.NewStockMovementRepository(gormDB)
+	transactor := common.NewTransactor(gormDB)
+	inventorySvc := newTestInventoryService(gormDB)
+	reservationSvc := NewReservationService(repo.NewReservationRepository(gormDB), powerRepo, inventorySvc, transactor, ttl)
+	rbacSvc := NewRBACService(repo.NewRBACRepository(gormDB), repo.NewUserRepository(gormDB))
+	svc := NewOrderService(repo.NewOrderRepository(gormDB), powerRepo, reservationSvc, inventorySvc, rbacSvc, transactor)
+
+	products := []*power.PowerSupply{
+		{Name: "RM850x", Brand: "Corsair", Power: 850, Price: 899.90, Status: 1},
+		{Name: "Focus GX-650", Brand: "Seasonic", Power: 650, Price: 549.50, Status: 1},
+	}
+	for i, stock := range []int{10, 5} {
+		require.NoError(t, powerRepo.Create(context.Background(), products[i]))
+		_, err := inventorySvc.RecordMovement(context.Background(), &inventory.MovementRequest{
+			PowerSupplyID: products[i].ID, Type: inventory.MovementReceipt, Quantity: stock,
+		})
+		require.NoError(t, err)
+	}
+	return svc, powerRepo, movementRepo, products
+}
+
+func TestOrderService_Lifecycle(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	svc, powerRepo, movementRepo, products := setupOrderTest(t, gormDB, 15*time.Minute)
+	ctx := context.Background()
+	buyer := &user.Actor{ID: 1, Role: rbac.RoleUser}
+	other := &user.Actor{ID: 2, Role: rbac.RoleUser}
+	admin := &user.Actor{ID: 99, Role: rbac.RoleAdmin}
+
+	stockOf := func(id uint) *power.PowerSupply {
+		found, err := powerRepo.FindByID(ctx, id)
+		require.NoError(t, err)
+		return found
+	}
+	placeOrder := func(qty1, qty2 int) *order.Order {
+		o, err := svc.Create(ctx, buyer, &order.CreateRequest{Items: []order.ItemRequest{
+			{PowerSupplyID: products[0].ID, Quantity: qty1},
+			{PowerSupplyID: products[1].ID, Quantity: qty2},
+		}})
+		require.NoError(t, err)
+		return o
+	}
+
+	t.Run("下单快照价格并预留库存", func(t *testing.T) {
+		o := placeOrder(2, 1)
+		assert.Equal(t, order.StatusCreated, o.Status)
+		assert.Equal(t, buyer.ID, o.UserID)
+		assert.NotEmpty(t, o.OrderNo)
+		require.Len(t, o.Items, 2)
+		assert.Equal(t, 1799.80, o.Items[0].Subtotal)
+		assert.Equal(t, 2349.30, o.TotalAmount)
+
+		// 下单后改价不影响订单
+		require.NoError(t, powerRepo.UpdateByID(ctx, products[0].ID, map[string]any{"price": 999}))
+		found, err := svc.GetByID(ctx, buyer, o.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 899.90, found.Items[0].UnitPrice)
+
+		ps := stockOf(products[0].ID)
+		assert.Equal(t, 10, ps.Stock)
+		assert.Equal(t, 2, ps.Reserved)
+	})
+
+	t.Run("无效明细", func(t *testing.T) {
+		_, err := svc.Create(ctx, buyer, &order.CreateRequest{})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		_, err = svc.Create(ctx, buyer, &order.CreateRequest{Items: []order.ItemRequest{
+			{PowerSupplyID: products[0].ID, Quantity: 1},
+			{PowerSupplyID: products[0].ID, Quantity: 1},
+		}})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		_, err = svc.Create(ctx, buyer, &order.CreateRequest{Items: []order.ItemRequest{{PowerSupplyID: 9999, Quantity: 1}}})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+
+	t.Run("库存不足整单失败", func(t *testing.T) {
+		before := stockOf(products[0].ID)
+		_, err := svc.Create(ctx, buyer, &order.CreateRequest{Items: []order.ItemRequest{
+			{PowerSupplyID: products[0].ID, Quantity: 1},
+			{PowerSupplyID: products[1].ID, Quantity: 100},
+		}})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeStockShortage))
+
+		// 第一项的预留随事务回滚
+		assert.Equal(t, before.Reserved, stockOf(products[0].ID).Reserved)
+	})
+
+	t.Run("支付扣减库存", func(t *testing.T) {
+		o := placeOrder(3, 1)
+
+		// 下单用户不能自行标记支付
+		_, err := svc.Pay(ctx, buyer, o.ID)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeForbidden))
+		assert.Equal(t, 10, stockOf(products[0].ID).Stock)
+
+		paid, err := svc.Pay(ctx, admin, o.ID)
+		require.NoError(t, err)
+		assert.Equal(t, order.StatusPaid, paid.Status)
+		assert.NotNil(t, paid.PaidAt)
+
+		movements, err := movementRepo.List(ctx, &inventory.QueryOptions{PowerSupplyID: products[0].ID, Type: inventory.MovementSale})
+		require.NoError(t, err)
+		require.NotEmpty(t, movements)
+		assert.Equal(t, o.OrderNo, movements[0].Reference)
+
+		ps := stockOf(products[0].ID)
+		assert.Equal(t, 7, ps.Stock)
+
+		// 重复支付失败
+		_, err = svc.Pay(ctx, admin, o.ID)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+	})
+
+	t.Run("取消未支付订单释放预留", func(t *testing.T) {
+		o := placeOrder(1, 1)
+		before := stockOf(products[1].ID)
+
+		cancelled, err := svc.Cancel(ctx, buyer, o.ID)
+		require.NoError(t, err)
+		assert.Equal(t, order.StatusCancelled, cancelled.Status)
+
+		after := stockOf(products[1].ID)
+		assert.Equal(t, before.Stock, after.Stock)
+		assert.Equal(t, before.Reserved-1, after.Reserved)
+
+		_, err = svc.Pay(ctx, admin, o.ID)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+	})
+
+	t.Run("取消已支付订单退回库存", func(t *testing.T) {
+		o := placeOrder(1, 1)
+		_, err := svc.Pay(ctx, admin, o.ID)
+		require.NoError(t, err)
+		paidStock := stockOf(products[0].ID).Stock
+
+		_, err = svc.Cancel(ctx, buyer, o.ID)
+		require.NoError(t, err)
+		assert.Equal(t, paidStock+1, stockOf(products[0].ID).Stock)
+	})
+
+	t.Run("发货、完成与退款", func(t *testing.T) {
+		o := placeOrder(1, 1)
+		_, err := svc.Pay(ctx, admin, o.ID)
+		require.NoError(t, err)
+
+		// 普通用户不能发货
+		_, err = svc.Ship(ctx, buyer, o.ID)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeForbidden))
+
+		shipped, err := svc.Ship(ctx, admin, o.ID)
+		require.NoError(t, err)
+		assert.Equal(t, order.StatusShipped, shipped.Status)
+
+		// 已发货不能取消
+		_, err = svc.Cancel(ctx, buyer, o.ID)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		completed, err := svc.Complete(ctx, buyer, o.ID)
+		require.NoError(t, err)
+		assert.Equal(t, order.StatusCompleted, completed.Status)
+
+		_, err = svc.Refund(ctx, buyer, o.ID)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeForbidden))
+
+		stock := stockOf(products[0].ID).Stock
+		refunded, err := svc.Refund(ctx, admin, o.ID)
+		require.NoError(t, err)
+		assert.Equal(t, order.StatusRefunded, refunded.Status)
+		assert.NotNil(t, refunded.RefundedAt)
+		assert.Equal(t, stock+1, stockOf(products[0].ID).Stock)
+	})
+
+	t.Run("只能查看本人订单", func(t *testing.T) {
+		o := placeOrder(1, 1)
+
+		_, err := svc.GetByID(ctx, other, o.ID)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeForbidden))
+		_, err = svc.Cancel(ctx, other, o.ID)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeForbidden))
+
+		_, err = svc.GetByID(ctx, admin, o.ID)
+		assert.NoError(t, err)
+
+		// 普通用户的列表忽略用户过滤条件
+		list, total, err := svc.List(ctx, other, &order.QueryRequest{Page: 1, PageSize: 10, UserID: &buyer.ID})
+		require.NoError(t, err)
+		assert.Empty(t, list)
+		assert.Equal(t, int64(0), total)
+
+		list, total, err = svc.List(ctx, admin, &order.QueryRequest{Page: 1, PageSize: 100, UserID: &buyer.ID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(list)), total)
+		assert.NotEmpty(t, list)
+
+		_, _, err = svc.List(ctx, admin, &order.QueryRequest{Status: "unknown"})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+	})
+}
+
+func TestOrderService_PayExpired(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	svc, powerRepo, _, products := setupOrderTest(t, gormDB, -time.Minute)
+	ctx := context.Background()
+	buyer := &user.Actor{ID: 1, Role: rbac.RoleUser}
+	admin := &user.Actor{ID: 99, Role: rbac.RoleAdmin}
+
+	o, err := svc.Create(ctx, buyer, &order.CreateRequest{Items: []order.ItemRequest{{PowerSupplyID: products[0].ID, Quantity: 2}}})
+	require.NoError(t, err)
+
+	_, err = svc.Pay(ctx, admin, o.ID)
+	assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+	found, err := svc.GetByID(ctx, buyer, o.ID)
+	require.NoError(t, err)
+	assert.Equal(t, order.StatusCancelled, found.Status)
+
+	ps, err := powerRepo.FindByID(ctx, products[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, 10, ps.Stock)
+	assert.Equal(t, 0, ps.Reserved)
+}
+
+func TestOrderService_CancelExpired(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	svc, powerRepo, _, products := setupOrderTest(t, gormDB, -time.Minute)
+	ctx := context.Background()
+	buyer := &user.Actor{ID: 1, Role: rbac.RoleUser}
+
+	var orders []*order.Order
+	for i := 0; i < 2; i++ {
+		o, err := svc.Create(ctx, buyer, &order.CreateRequest{Items: []order.ItemRequest{
+			{PowerSupplyID: products[0].ID, Quantity: 2},
+			{PowerSupplyID: products[1].ID, Quantity: 1},
+		}})
+		require.NoError(t, err)
+		orders = append(orders, o)
+	}
+	_, err := svc.Cancel(ctx, buyer, orders[1].ID)
+	require.NoError(t, err)
+
+	// 只取消仍待支付的订单，并释放其库存预留
+	cancelled, err := svc.CancelExpired(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, cancelled)
+
+	found, err := svc.GetByID(ctx, buyer, orders[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, order.StatusCancelled, found.Status)
+	for _, ps := range products {
+		found, err := powerRepo.FindByID(ctx, ps.ID)
+		require.NoError(t, err)
+		assert.Zero(t, found.Reserved)
+	}
+
+	cancelled, err = svc.CancelExpired(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, cancelled)
+}
+
+func TestOrderService_CancelExpiredKeepsPendingOrders(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	svc, _, _, products := setupOrderTest(t, gormDB, 15*time.Minute)
+	ctx := context.Background()
+	buyer := &user.Actor{ID: 1, Role: rbac.RoleUser}
+
+	o, err := svc.Create(ctx, buyer, &order.CreateRequest{Items: []order.ItemRequest{{PowerSupplyID: products[0].ID, Quantity: 1}}})
+	require.NoError(t, err)
+
+	cancelled, err := svc.CancelExpired(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, cancelled)
+
+	found, err := svc.GetByID(ctx, buyer, o.ID)
+	require.NoError(t, err)
+	assert.Equal(t, order.StatusCreated, found.Status)
+}
+
+func TestOrderService_RestockToSourceWarehouse(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	svc, _, _, products := setupOrderTest(t, gormDB, 15*time.Minute)
+	ctx := context.Background()
+	buyer := &user.Actor{ID: 1, Role: rbac.RoleUser}
+	admin := &user.Actor{ID: 99, Role: rbac.RoleAdmin}
+
+	// 第二款电源在默认仓库与分仓各有 5 件库存
+	warehouseRepo := repo.NewWarehouseRepository(gormDB)
+	levelRepo := repo.NewStockLevelRepository(gormDB)
+	main, err := warehouseRepo.FindDefault(ctx)
+	require.NoError(t, err)
+	branch := &warehouse.Warehouse{Code: "CD", Name: "成都仓"}
+	require.NoError(t, warehouseRepo.Create(ctx, branch))
+	_, err = newTestInventoryService(gormDB).RecordMovement(ctx, &inventory.MovementRequest{
+		PowerSupplyID: products[1].ID, Type: inventory.MovementReceipt, Quantity: 5, WarehouseID: branch.ID,
+	})
+	require.NoError(t, err)
+
+	quantities := func() map[uint]int {
+		levels, err := levelRepo.ListByPowerSupply(ctx, products[1].ID)
+		require.NoError(t, err)
+		result := make(map[uint]int)
+		for _, l := range levels {
+			result[l.WarehouseID] = l.Quantity
+		}
+		return result
+	}
+
+	o, err := svc.Create(ctx, buyer, &order.CreateRequest{Items: []order.ItemRequest{{PowerSupplyID: products[1].ID, Quantity: 8}}})
+	require.NoError(t, err)
+	_, err = svc.Pay(ctx, admin, o.ID)
+	require.NoError(t, err)
+	assert.Equal(t, map[uint]int{main.ID: 0, branch.ID: 2}, quantities())
+
+	_, err = svc.Cancel(ctx, buyer, o.ID)
+	require.NoError(t, err)
+	assert.Equal(t, map[uint]int{main.ID: 5, branch.ID: 5}, quantities())
+}
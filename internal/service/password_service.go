@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"power-supply-sys/internal/domain/token"
+	"power-supply-sys/internal/domain/user"
+	"power-supply-sys/pkg/auth"
+	"power-supply-sys/pkg/common"
+	"power-supply-sys/pkg/mail"
+	"time"
+)
+
+// PasswordService 密码服务接口（修改密码与找回密码）
+type PasswordService interface {
+	ChangePassword(ctx context.Context, userID uint, req *user.PasswordChangeRequest) error
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, req *user.PasswordResetRequest) error
+}
+
+// passwordService 密码服务实现
+type passwordService struct {
+	userRepo    user.Repository
+	resetRepo   token.PasswordResetRepository
+	tokenRepo   token.Repository
+	mailer      mail.Mailer
+	transactor  common.Transactor
+	resetExpire time.Duration
+	resetURL    string
+	hasher      auth.PasswordHasher
+	policy      user.PasswordPolicy
+}
+
+var _ PasswordService = &passwordService{}
+
+// NewPasswordService 创建密码服务
+// resetURL 为前端重置密码页面地址，重置令牌以 token 查询参数附加在其后
+func NewPasswordService(userRepo user.Repository, resetRepo token.PasswordResetRepository, tokenRepo token.Repository, mailer mail.Mailer, transactor common.Transactor, resetExpire time.Duration, resetURL string, hasher auth.PasswordHasher, policy user.PasswordPolicy) PasswordService {
+	return &passwordService{
+		userRepo:    userRepo,
+		resetRepo:   resetRepo,
+		tokenRepo:   tokenRepo,
+		mailer:      mailer,
+		transactor:  transactor,
+		resetExpire: resetExpire,
+		resetURL:    resetURL,
+		hasher:      hasher,
+		policy:      policy,
+	}
+}
+
+// ChangePassword 修改密码（需要校验原密码）
+func (s *passwordService) ChangePassword(ctx context.Context, userID uint, req *user.PasswordChangeRequest) error {
+	u, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.hasher.Verify(u.Password, req.OldPassword); err != nil {
+		return common.ErrInvalidParam("原密码错误")
+	}
+	if req.OldPassword == req.NewPassword {
+		return common.ErrInvalidParam("新密码不能与原密码相同")
+	}
+
+	hashedPassword, err := hashNewPassword(s.hasher, s.policy, req.NewPassword)
+	if err != nil {
+		return err
+	}
+	return s.userRepo.UpdateByID(ctx, u.ID, map[string]any{"password": hashedPassword})
+}
+
+// ForgotPassword 发送密码重置邮件
+// 邮箱不存在、用户状态不正常或邮件发送失败时都静默返回，避免泄露账号是否存在
+func (s *passwordService) ForgotPassword(ctx context.Context, email string) error {
+	u, err := s.userRepo.FindOne(ctx, common.Where("email", email))
+	if err != nil {
+		if common.HasErrorCode(err, common.ErrCodeNotFound) {
+			return nil
+		}
+		return err
+	}
+	if u.Status != user.StatusActive {
+		return nil
+	}
+
+	raw, hash, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return common.ErrInternal(err)
+	}
+
+	now := time.Now()
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// 新的重置令牌签发后，之前的令牌全部作废
+		if err := s.resetRepo.InvalidateByUserID(ctx, u.ID, now); err != nil {
+			return err
+		}
+		return s.resetRepo.Create(ctx, &token.PasswordResetToken{
+			UserID:    u.ID,
+			TokenHash: hash,
+			ExpiresAt: now.Add(s.resetExpire),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	msg := &mail.Message{
+		To:      []string{u.Email},
+		Subject: "重置密码",
+		Body:    s.resetMailBody(u, raw),
+	}
+	// 发送失败同样返回成功，避免通过错误判断邮箱是否已注册
+	// 容器注入的是后台发送的邮件发送器，耗时不受发送影响，失败由其记录日志
+	_ = s.mailer.Send(ctx, msg)
+	return nil
+}
+
+// ResetPassword 使用重置令牌设置新密码，成功后吊销用户的所有会话
+func (s *passwordService) ResetPassword(ctx context.Context, req *user.PasswordResetRequest) error {
+	now := time.Now()
+
+	rt, err := s.resetRepo.FindByHash(ctx, auth.HashOpaqueToken(req.Token))
+	if err != nil {
+		if common.HasErrorCode(err, common.ErrCodeNotFound) {
+			return errResetTokenInvalid()
+		}
+		return err
+	}
+	if rt.UsedAt != nil || rt.IsExpired(now) {
+		return errResetTokenInvalid()
+	}
+
+	hashedPassword, err := hashNewPassword(s.hasher, s.policy, req.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		ok, err := s.resetRepo.MarkUsed(ctx, rt.ID, now)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errResetTokenInvalid()
+		}
+		if err := s.userRepo.UpdateByID(ctx, rt.UserID, map[string]any{"password": hashedPassword}); err != nil {
+			return err
+		}
+		if err := s.resetRepo.InvalidateByUserID(ctx, rt.UserID, now); err != nil {
+			return err
+		}
+		return s.tokenRepo.RevokeByUserID(ctx, rt.UserID, now)
+	})
+}
+
+// resetMailBody 生成重置密码邮件正文
+func (s *passwordService) resetMailBody(u *user.User, raw string) string {
+	link := raw
+	if s.resetURL != "" {
+		if parsed, err := url.Parse(s.resetURL); err == nil {
+			query := parsed.Query()
+			query.Set("token", raw)
+			parsed.RawQuery = query.Encode()
+			link = parsed.String()
+		}
+	}
+	return fmt.Sprintf("%s，您好：\n\n我们收到了重置您账户密码的请求，请在 %d 分钟内通过以下链接设置新密码：\n\n%s\n\n如果这不是您本人的操作，请忽略此邮件。\n",
+		u.Username, int(s.resetExpire.Minutes()), link)
+}
+
+// errResetTokenInvalid 重置令牌无效错误
+func errResetTokenInvalid() *common.AppError {
+	return common.ErrInvalidParam("重置链接无效或已过期")
+}
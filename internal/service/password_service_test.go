@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"power-supply-sys/internal/domain/user"
+	"power-supply-sys/internal/infra/db"
+	"power-supply-sys/internal/infra/repo"
+	"power-supply-sys/pkg/auth"
+	"power-supply-sys/pkg/common"
+	"power-supply-sys/pkg/mail"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// passwordTestEnv 密码服务测试环境
+type passwordTestEnv struct {
+	passwordSvc PasswordService
+	authSvc     AuthService
+	userSvc     UserService
+	outbox      *mail.OutboxMailer
+	user        *user.User
+}
+
+// setupPasswordService 创建密码服务、发件箱及测试用户
+func setupPasswordService(t *testing.T, gormDB *gorm.DB) *passwordTestEnv {
+	err := db.Migrate(gormDB)
+	require.NoError(t, err)
+
+	userRepo := repo.NewUserRepository(gormDB)
+	tokenRepo := repo.NewRefreshTokenRepository(gormDB)
+	transactor := common.NewTransactor(gormDB)
+	outbox := mail.NewOutboxMailer("", "noreply@example.com")
+
+	userSvc := NewUserService(userRepo, repo.NewRefreshTokenRepository(gormDB), repo.NewRBACRepository(gormDB), newTestLoginLimiter(), common.NewTransactor(gormDB), newTestPasswordHasher(), user.DefaultPasswordPolicy())
+	u, err := userSvc.Create(context.Background(), &user.UserCreateRequest{
+		Username: "resetuser",
+		Password: "password123",
+		Email:    "reset@example.com",
+	})
+	require.NoError(t, err)
+
+	return &passwordTestEnv{
+		passwordSvc: NewPasswordService(userRepo, repo.NewPasswordResetRepository(gormDB), tokenRepo, outbox, transactor, 30*time.Minute, "https://example.com/reset", newTestPasswordHasher(), user.DefaultPasswordPolicy()),
+		authSvc:     NewAuthService(tokenRepo, userRepo, auth.NewJWTManager("test-secret", 15*time.Minute), transactor, time.Hour),
+		userSvc:     userSvc,
+		outbox:      outbox,
+		user:        u,
+	}
+}
+
+// resetTokenFromMail 从重置邮件的链接中提取令牌
+func resetTokenFromMail(t *testing.T, msg *mail.Message) string {
+	require.NotNil(t, msg)
+	link := regexp.MustCompile(`https://\S+`).FindString(msg.Body)
+	parsed, err := url.Parse(link)
+	require.NoError(t, err)
+	raw := parsed.Query().Get("token")
+	require.NotEmpty(t, raw)
+	return raw
+}
+
+func TestPasswordService_ChangePassword(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	env := setupPasswordService(t, gormDB)
+	ctx := context.Background()
+
+	t.Run("原密码错误", func(t *testing.T) {
+		err := env.passwordSvc.ChangePassword(ctx, env.user.ID, &user.PasswordChangeRequest{
+			OldPassword: "wrongpassword",
+			NewPassword: "newpassword123",
+		})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+	})
+
+	t.Run("新旧密码相同", func(t *testing.T) {
+		err := env.passwordSvc.ChangePassword(ctx, env.user.ID, &user.PasswordChangeRequest{
+			OldPassword: "password123",
+			NewPassword: "password123",
+		})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+	})
+
+	t.Run("新密码不符合策略", func(t *testing.T) {
+		err := env.passwordSvc.ChangePassword(ctx, env.user.ID, &user.PasswordChangeRequest{
+			OldPassword: "password123",
+			NewPassword: "short",
+		})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+	})
+
+	t.Run("成功修改密码", func(t *testing.T) {
+		err := env.passwordSvc.ChangePassword(ctx, env.user.ID, &user.PasswordChangeRequest{
+			OldPassword: "password123",
+			NewPassword: "newpassword123",
+		})
+		require.NoError(t, err)
+
+		_, err = env.userSvc.Login(ctx, &user.LoginRequest{Username: "resetuser", Password: "newpassword123"})
+		assert.NoError(t, err)
+		_, err = env.userSvc.Login(ctx, &user.LoginRequest{Username: "resetuser", Password: "password123"})
+		assert.Error(t, err)
+	})
+}
+
+func TestPasswordService_ForgotPassword(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	env := setupPasswordService(t, gormDB)
+	ctx := context.Background()
+
+	t.Run("发送重置邮件", func(t *testing.T) {
+		err := env.passwordSvc.ForgotPassword(ctx, "reset@example.com")
+		require.NoError(t, err)
+
+		msg := env.outbox.Last()
+		require.NotNil(t, msg)
+		assert.Equal(t, []string{"reset@example.com"}, msg.To)
+		assert.Contains(t, msg.Body, "https://example.com/reset?token=")
+	})
+
+	t.Run("邮箱不存在时静默成功", func(t *testing.T) {
+		before := len(env.outbox.Messages())
+		err := env.passwordSvc.ForgotPassword(ctx, "missing@example.com")
+		assert.NoError(t, err)
+		assert.Len(t, env.outbox.Messages(), before)
+	})
+
+	t.Run("邮件发送失败时静默成功", func(t *testing.T) {
+		svc := NewPasswordService(repo.NewUserRepository(gormDB), repo.NewPasswordResetRepository(gormDB), repo.NewRefreshTokenRepository(gormDB),
+			failingMailer{}, common.NewTransactor(gormDB), 30*time.Minute, "https://example.com/reset", newTestPasswordHasher(), user.DefaultPasswordPolicy())
+		assert.NoError(t, svc.ForgotPassword(ctx, "reset@example.com"))
+	})
+}
+
+// failingMailer 总是发送失败的邮件发送器
+type failingMailer struct{}
+
+func (failingMailer) Send(ctx context.Context, msg *mail.Message) error {
+	return errors.New("smtp unavailable")
+}
+
+func TestPasswordService_ResetPassword(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	env := setupPasswordService(t, gormDB)
+	ctx := context.Background()
+
+	t.Run("无效令牌", func(t *testing.T) {
+		err := env.passwordSvc.ResetPassword(ctx, &user.PasswordResetRequest{Token: "invalid", NewPassword: "newpassword123"})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+	})
+
+	t.Run("重新申请后旧令牌失效", func(t *testing.T) {
+		require.NoError(t, env.passwordSvc.ForgotPassword(ctx, "reset@example.com"))
+		stale := resetTokenFromMail(t, env.outbox.Last())
+		require.NoError(t, env.passwordSvc.ForgotPassword(ctx, "reset@example.com"))
+
+		err := env.passwordSvc.ResetPassword(ctx, &user.PasswordResetRequest{Token: stale, NewPassword: "newpassword123"})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+	})
+
+	t.Run("重置成功后吊销所有会话且令牌不可重用", func(t *testing.T) {
+		first, err := env.authSvc.IssueTokens(ctx, env.user, nil)
+		require.NoError(t, err)
+		second, err := env.authSvc.IssueTokens(ctx, env.user, nil)
+		require.NoError(t, err)
+
+		require.NoError(t, env.passwordSvc.ForgotPassword(ctx, "reset@example.com"))
+		raw := resetTokenFromMail(t, env.outbox.Last())
+
+		err = env.passwordSvc.ResetPassword(ctx, &user.PasswordResetRequest{Token: raw, NewPassword: "resetpassword123"})
+		require.NoError(t, err)
+
+		for _, sessionID := range []string{first.SessionID, second.SessionID} {
+			active, err := env.authSvc.IsSessionActive(ctx, sessionID)
+			assert.NoError(t, err)
+			assert.False(t, active)
+		}
+
+		_, err = env.userSvc.Login(ctx, &user.LoginRequest{Username: "resetuser", Password: "resetpassword123"})
+		assert.NoError(t, err)
+
+		err = env.passwordSvc.ResetPassword(ctx, &user.PasswordResetRequest{Token: raw, NewPassword: "anotherpassword123"})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+	})
+}
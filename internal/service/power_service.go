@@ -2,8 +2,14 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"power-supply-sys/internal/domain/catalog"
+	"power-supply-sys/internal/domain/inventory"
 	"power-supply-sys/internal/domain/power"
 	"power-supply-sys/pkg/common"
+	"strings"
+	"time"
 )
 
 // PowerService 电源服务接口
@@ -13,51 +19,118 @@ type PowerService interface {
 	Update(ctx context.Context, id uint, req *power.PowerSupplyUpdateRequest) (*power.PowerSupply, error)
 	Delete(ctx context.Context, id uint) error
 	List(ctx context.Context, req *power.PowerSupplyQueryRequest) ([]*power.PowerSupply, int64, error)
+	// Compare 对比多个电源，返回按属性排列的对比矩阵
+	Compare(ctx context.Context, ids []uint) (*power.Comparison, error)
 }
 
 // powerService 电源服务实现
 type powerService struct {
-	repo power.Repository
+	repo       power.Repository
+	inventory  InventoryService
+	pricing    PricingService
+	catalog    CatalogService
+	transactor common.Transactor
 }
 
 var _ PowerService = &powerService{}
 
 // NewPowerService 创建电源服务（接收 Repository 接口而非 GORM）
-func NewPowerService(repo power.Repository) PowerService {
+// 库存数量只通过 inventory 登记库存流水变更，价格只通过 pricing 修改并记录价格历史
+func NewPowerService(repo power.Repository, inventory InventoryService, pricing PricingService, catalog CatalogService, transactor common.Transactor) PowerService {
 	return &powerService{
-		repo: repo,
+		repo:       repo,
+		inventory:  inventory,
+		pricing:    pricing,
+		catalog:    catalog,
+		transactor: transactor,
 	}
 }
 
-// Create 创建电源
+// Create 创建电源，初始价格记录到价格历史，初始库存登记为入库流水，不存在的标签自动创建
 func (s *powerService) Create(ctx context.Context, req *power.PowerSupplyCreateRequest) (*power.PowerSupply, error) {
+	if req.Stock < 0 {
+		return nil, common.ErrInvalidParam("库存不能为负数")
+	}
+	if err := validateSpec(&req.Spec); err != nil {
+		return nil, err
+	}
+	efficiency, err := parseEfficiency(req.Efficiency)
+	if err != nil {
+		return nil, err
+	}
+	categoryID, err := s.categoryID(ctx, req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
 	ps := &power.PowerSupply{
 		Name:        req.Name,
 		Brand:       req.Brand,
 		Model:       req.Model,
 		Power:       req.Power,
-		Efficiency:  req.Efficiency,
+		Efficiency:  efficiency,
 		Modular:     req.Modular,
+		Spec:        req.Spec,
 		Price:       req.Price,
-		Stock:       req.Stock,
 		Description: req.Description,
 		Status:      1,
+		CategoryID:  categoryID,
 	}
 
-	if err := s.repo.Create(ctx, ps); err != nil {
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, ps); err != nil {
+			return err
+		}
+		if len(req.Tags) > 0 {
+			if err := s.replaceTags(ctx, ps, req.Tags); err != nil {
+				return err
+			}
+		}
+		err := s.pricing.ChangePrice(ctx, &power.PriceChangeRequest{
+			PowerSupplyID: ps.ID,
+			Price:         ps.Price,
+			Reason:        "初始价格",
+			ActorID:       req.ActorID,
+		})
+		if err != nil {
+			return err
+		}
+		if req.Stock == 0 {
+			return nil
+		}
+
+		_, err = s.inventory.RecordMovement(ctx, &inventory.MovementRequest{
+			PowerSupplyID: ps.ID,
+			Type:          inventory.MovementReceipt,
+			Quantity:      req.Stock,
+			WarehouseID:   req.WarehouseID,
+			Reason:        "初始库存",
+			ActorID:       req.ActorID,
+		})
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return ps, nil
+	return s.repo.FindDetail(ctx, ps.ID)
 }
 
-// GetByID 根据ID获取电源
+// GetByID 根据ID获取电源（含各仓库库存）
 func (s *powerService) GetByID(ctx context.Context, id uint) (*power.PowerSupply, error) {
-	return s.repo.FindByID(ctx, id)
+	return s.repo.FindDetail(ctx, id)
 }
 
 // Update 更新电源
+// 设置 Stock 时不直接覆盖库存，而是将差额登记为盘点调整流水；价格变化时记录价格历史
+// 设置 Tags 时整体替换电源的标签
 func (s *powerService) Update(ctx context.Context, id uint, req *power.PowerSupplyUpdateRequest) (*power.PowerSupply, error) {
+	if req.Spec != nil {
+		if err := validateSpec(req.Spec); err != nil {
+			return nil, err
+		}
+	}
+
 	ps, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -77,16 +150,19 @@ func (s *powerService) Update(ctx context.Context, id uint, req *power.PowerSupp
 		updates["power"] = *req.Power
 	}
 	if req.Efficiency != "" {
-		updates["efficiency"] = req.Efficiency
+		efficiency, err := parseEfficiency(req.Efficiency)
+		if err != nil {
+			return nil, err
+		}
+		updates["efficiency"] = efficiency
 	}
 	if req.Modular != nil {
 		updates["modular"] = *req.Modular
 	}
-	if req.Price != nil {
-		updates["price"] = *req.Price
-	}
-	if req.Stock != nil {
-		updates["stock"] = *req.Stock
+	if req.Spec != nil {
+		for column, value := range req.Spec.Columns() {
+			updates[column] = value
+		}
 	}
 	if req.Description != "" {
 		updates["description"] = req.Description
@@ -94,17 +170,54 @@ func (s *powerService) Update(ctx context.Context, id uint, req *power.PowerSupp
 	if req.Status != nil {
 		updates["status"] = *req.Status
 	}
+	if req.CategoryID != nil {
+		categoryID, err := s.categoryID(ctx, req.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		updates["category_id"] = categoryID
+	}
+
+	priceChanged := req.Price != nil && *req.Price != ps.Price
 
-	if len(updates) == 0 {
+	if len(updates) == 0 && req.Stock == nil && !priceChanged && req.Tags == nil {
 		return ps, nil
 	}
 
-	if err := s.repo.Update(ctx, ps, updates); err != nil {
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if len(updates) > 0 {
+			if err := s.repo.Update(ctx, ps, updates); err != nil {
+				return err
+			}
+		}
+		if req.Tags != nil {
+			if err := s.replaceTags(ctx, ps, *req.Tags); err != nil {
+				return err
+			}
+		}
+		if priceChanged {
+			err := s.pricing.ChangePrice(ctx, &power.PriceChangeRequest{
+				PowerSupplyID: id,
+				Price:         *req.Price,
+				Reason:        "编辑电源",
+				ActorID:       req.ActorID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if req.Stock != nil {
+			_, err := s.inventory.SetStock(ctx, id, *req.Stock, req.ActorID, "编辑电源时调整库存")
+			return err
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	// 重新查询更新后的电源信息
-	return s.repo.FindByID(ctx, id)
+	return s.repo.FindDetail(ctx, id)
 }
 
 // Delete 删除电源
@@ -112,22 +225,62 @@ func (s *powerService) Delete(ctx context.Context, id uint) error {
 	return s.repo.Delete(ctx, id)
 }
 
-// List 获取电源列表
+// List 获取电源列表，指定 AsOf 时返回该时刻生效的价格
+// 价格区间按当前价格筛选，因此不能与 AsOf 同时使用
+// 按分类查询时可包含子孙分类；按标签查询时默认包含任一标签即可，TagMode 为 all 时要求包含全部标签
 func (s *powerService) List(ctx context.Context, req *power.PowerSupplyQueryRequest) ([]*power.PowerSupply, int64, error) {
+	if req.AsOf != nil && (req.MinPrice != nil || req.MaxPrice != nil) {
+		return nil, 0, common.ErrInvalidParam("as_of 不能与价格区间同时使用")
+	}
+
 	// 构建查询选项
 	page, pageSize := common.GetPageInfo(req.Page, req.PageSize)
 
+	efficiency, err := parseEfficiency(req.Efficiency)
+	if err != nil {
+		return nil, 0, err
+	}
+	minTier, err := parseEfficiencyTier(req.MinEfficiency)
+	if err != nil {
+		return nil, 0, err
+	}
+	maxTier, err := parseEfficiencyTier(req.MaxEfficiency)
+	if err != nil {
+		return nil, 0, err
+	}
+	if minTier != 0 && maxTier != 0 && minTier > maxTier {
+		return nil, 0, common.ErrInvalidParam("能效下限不能高于上限")
+	}
+	var categoryIDs []uint
+	if req.CategoryID != nil {
+		if categoryIDs, err = s.catalog.CategoryScope(ctx, *req.CategoryID, req.SubCategories); err != nil {
+			return nil, 0, err
+		}
+	}
+	tags, allTags, err := parseTagFilter(req.Tags, req.TagMode)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	queryOpts := &power.QueryOptions{
-		Name:       req.Name,
-		Brand:      req.Brand,
-		MinPower:   req.MinPower,
-		MaxPower:   req.MaxPower,
-		MinPrice:   req.MinPrice,
-		MaxPrice:   req.MaxPrice,
-		Efficiency: req.Efficiency,
-		Status:     req.Status,
-		Page:       page,
-		PageSize:   pageSize,
+		Name:        req.Name,
+		Brand:       req.Brand,
+		MinPower:    req.MinPower,
+		MaxPower:    req.MaxPower,
+		MinPrice:    req.MinPrice,
+		MaxPrice:    req.MaxPrice,
+		Efficiency:  efficiency,
+		MinTier:     minTier,
+		MaxTier:     maxTier,
+		Status:      req.Status,
+		WarehouseID: req.WarehouseID,
+		InStock:     req.InStock,
+		Spec:        req.Spec,
+		CategoryIDs: categoryIDs,
+		Tags:        tags,
+		AllTags:     allTags,
+		Page:        page,
+		PageSize:    pageSize,
 	}
 
 	// 获取总数
@@ -142,5 +295,284 @@ func (s *powerService) List(ctx context.Context, req *power.PowerSupplyQueryRequ
 		return nil, 0, err
 	}
 
+	if req.AsOf != nil {
+		if err := s.applyPricesAt(ctx, powerSupplies, *req.AsOf); err != nil {
+			return nil, 0, err
+		}
+	}
+
 	return powerSupplies, total, nil
 }
+
+// categoryID 校验电源所属分类，为空或为 0 时表示不属于任何分类
+func (s *powerService) categoryID(ctx context.Context, id *uint) (*uint, error) {
+	if id == nil || *id == 0 {
+		return nil, nil
+	}
+	if _, err := s.catalog.CategoryScope(ctx, *id, false); err != nil {
+		return nil, err
+	}
+	return id, nil
+}
+
+// replaceTags 按名称整体替换电源的标签，不存在的标签自动创建（需在事务中调用）
+func (s *powerService) replaceTags(ctx context.Context, ps *power.PowerSupply, names []string) error {
+	tags, err := s.catalog.ResolveTags(ctx, names)
+	if err != nil {
+		return err
+	}
+	return s.repo.ReplaceTags(ctx, ps, tags)
+}
+
+// parseTagFilter 解析标签查询条件，返回去重后的小写标签名称以及是否要求包含全部标签
+func parseTagFilter(names []string, mode string) ([]string, bool, error) {
+	switch mode {
+	case "", catalog.TagMatchAny, catalog.TagMatchAll:
+	default:
+		return nil, false, common.ErrInvalidParam("标签匹配方式无效")
+	}
+	names, err := normalizeTagNames(names)
+	if err != nil {
+		return nil, false, err
+	}
+	for i, name := range names {
+		names[i] = strings.ToLower(name)
+	}
+	return names, mode == catalog.TagMatchAll, nil
+}
+
+// Compare 对比多个电源（按传入顺序排列，重复的 ID 只保留一个），所有电源通过一次批量查询获取
+// 标出各电源取值不同的属性，以及能效、每瓦价格、接口数量等属性中最优的电源
+func (s *powerService) Compare(ctx context.Context, ids []uint) (*power.Comparison, error) {
+	unique := make([]uint, 0, len(ids))
+	seen := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) < 2 {
+		return nil, common.ErrInvalidParam("至少选择 2 个电源进行对比")
+	}
+	if len(unique) > power.MaxCompareItems {
+		return nil, common.ErrInvalidParam(fmt.Sprintf("最多对比 %d 个电源", power.MaxCompareItems))
+	}
+
+	list, err := s.repo.FindByIDs(ctx, unique)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*power.PowerSupply, len(list))
+	for _, ps := range list {
+		byID[ps.ID] = ps
+	}
+	items := make([]*power.PowerSupply, 0, len(unique))
+	for _, id := range unique {
+		ps, ok := byID[id]
+		if !ok {
+			return nil, common.ErrNotFound(fmt.Sprintf("ID 为 %d 的电源", id))
+		}
+		items = append(items, ps)
+	}
+
+	rows := make([]*power.ComparisonRow, 0, len(compareAttributes))
+	for _, attr := range compareAttributes {
+		rows = append(rows, attr.row(items))
+	}
+	return &power.Comparison{Items: items, Rows: rows}, nil
+}
+
+// applyPricesAt 将列表中的价格替换为 at 时刻生效的价格，无法确定时保留当前价格
+func (s *powerService) applyPricesAt(ctx context.Context, list []*power.PowerSupply, at time.Time) error {
+	ids := make([]uint, 0, len(list))
+	for _, ps := range list {
+		ids = append(ids, ps.ID)
+	}
+	prices, err := s.pricing.PricesAt(ctx, ids, at)
+	if err != nil {
+		return err
+	}
+	for _, ps := range list {
+		if price, ok := prices[ps.ID]; ok {
+			ps.Price = price
+		}
+	}
+	return nil
+}
+
+// parseEfficiency 规范化能效认证等级，为空时不做限制
+func parseEfficiency(s string) (power.Efficiency, error) {
+	if s == "" {
+		return "", nil
+	}
+	efficiency, ok := power.ParseEfficiency(s)
+	if !ok {
+		return "", common.ErrInvalidParam("能效等级无效")
+	}
+	return efficiency, nil
+}
+
+// parseEfficiencyTier 解析能效档位，为空时返回 0（不限制）
+func parseEfficiencyTier(s string) (power.EfficiencyTier, error) {
+	if s == "" {
+		return 0, nil
+	}
+	tier, ok := power.ParseEfficiencyTier(s)
+	if !ok {
+		return 0, common.ErrInvalidParam("能效等级无效")
+	}
+	return tier, nil
+}
+
+// validateSpec 校验技术规格：版型有效，接口数与尺寸等数值不能为负数
+func validateSpec(spec *power.Spec) error {
+	if spec.FormFactor != "" && !power.IsValidFormFactor(spec.FormFactor) {
+		return common.ErrInvalidParam("电源版型无效")
+	}
+	counts := []int{
+		spec.Connectors12VHPWR, spec.EPSConnectors, spec.PCIe8PinConnectors, spec.SATAConnectors, spec.MolexConnectors,
+		spec.FanSizeMM, spec.LengthMM, spec.WidthMM, spec.HeightMM,
+	}
+	for _, n := range counts {
+		if n < 0 {
+			return common.ErrInvalidParam("规格数值不能为负数")
+		}
+	}
+	if spec.Rail12VAmps < 0 || spec.NoiseDBA < 0 {
+		return common.ErrInvalidParam("规格数值不能为负数")
+	}
+	return nil
+}
+
+// compareAttribute 电源对比的一项属性
+// value 返回展示值、用于比较优劣的数值以及是否已填写；better 为 1 表示越大越好，-1 表示越小越好，0 表示无优劣之分
+type compareAttribute struct {
+	key      string
+	label    string
+	unit     string
+	computed bool
+	better   int
+	value    func(ps *power.PowerSupply) (any, float64, bool)
+}
+
+// compareAttributes 电源对比的属性（按展示顺序）
+var compareAttributes = []compareAttribute{
+	{key: "brand", label: "品牌", value: compareText(func(ps *power.PowerSupply) string { return ps.Brand })},
+	{key: "model", label: "型号", value: compareText(func(ps *power.PowerSupply) string { return ps.Model })},
+	{key: "power", label: "额定功率", unit: "W", value: compareInt(func(ps *power.PowerSupply) int { return ps.Power })},
+	{key: "efficiency", label: "能效认证", better: 1, value: func(ps *power.PowerSupply) (any, float64, bool) {
+		if !ps.Efficiency.IsValid() {
+			return nil, 0, false
+		}
+		return ps.Efficiency.Label(), float64(ps.Efficiency.Tier()), true
+	}},
+	{key: "modular", label: "模组化", value: compareBool(func(ps *power.PowerSupply) bool { return ps.Modular })},
+	{key: "form_factor", label: "版型", value: compareText(func(ps *power.PowerSupply) string { return ps.Spec.FormFactor })},
+	{key: "atx3", label: "ATX 3.x", value: compareBool(func(ps *power.PowerSupply) bool { return ps.Spec.ATX3 })},
+	{key: "connectors_12vhpwr", label: "12VHPWR 接口", unit: "个", better: 1, value: compareCount(func(ps *power.PowerSupply) int { return ps.Spec.Connectors12VHPWR })},
+	{key: "eps_connectors", label: "EPS 8pin 接口", unit: "个", better: 1, value: compareCount(func(ps *power.PowerSupply) int { return ps.Spec.EPSConnectors })},
+	{key: "pcie_8pin_connectors", label: "PCIe 8pin 接口", unit: "个", better: 1, value: compareCount(func(ps *power.PowerSupply) int { return ps.Spec.PCIe8PinConnectors })},
+	{key: "sata_connectors", label: "SATA 接口", unit: "个", better: 1, value: compareCount(func(ps *power.PowerSupply) int { return ps.Spec.SATAConnectors })},
+	{key: "molex_connectors", label: "Molex 接口", unit: "个", better: 1, value: compareCount(func(ps *power.PowerSupply) int { return ps.Spec.MolexConnectors })},
+	{key: "total_connectors", label: "接口总数", unit: "个", computed: true, better: 1, value: compareCount(func(ps *power.PowerSupply) int {
+		spec := ps.Spec
+		return spec.Connectors12VHPWR + spec.EPSConnectors + spec.PCIe8PinConnectors + spec.SATAConnectors + spec.MolexConnectors
+	})},
+	{key: "rail_12v_amps", label: "+12V 输出电流", unit: "A", better: 1, value: compareFloat(func(ps *power.PowerSupply) float64 { return ps.Spec.Rail12VAmps })},
+	{key: "fan_size_mm", label: "风扇尺寸", unit: "mm", value: compareInt(func(ps *power.PowerSupply) int { return ps.Spec.FanSizeMM })},
+	{key: "noise_dba", label: "噪音", unit: "dBA", better: -1, value: compareFloat(func(ps *power.PowerSupply) float64 { return ps.Spec.NoiseDBA })},
+	{key: "length_mm", label: "长度", unit: "mm", value: compareInt(func(ps *power.PowerSupply) int { return ps.Spec.LengthMM })},
+	{key: "width_mm", label: "宽度", unit: "mm", value: compareInt(func(ps *power.PowerSupply) int { return ps.Spec.WidthMM })},
+	{key: "height_mm", label: "高度", unit: "mm", value: compareInt(func(ps *power.PowerSupply) int { return ps.Spec.HeightMM })},
+	{key: "price", label: "价格", unit: "元", better: -1, value: compareFloat(func(ps *power.PowerSupply) float64 { return ps.Price })},
+	{key: "price_per_watt", label: "每瓦价格", unit: "元/W", computed: true, better: -1, value: func(ps *power.PowerSupply) (any, float64, bool) {
+		if ps.Price <= 0 || ps.Power <= 0 {
+			return nil, 0, false
+		}
+		v := math.Round(ps.Price/float64(ps.Power)*10000) / 10000
+		return v, v, true
+	}},
+	{key: "available", label: "可售库存", unit: "个", computed: true, value: compareCount(func(ps *power.PowerSupply) int { return ps.Available() })},
+}
+
+// row 计算各电源在该属性上的取值、是否存在差异以及最优的电源
+func (a *compareAttribute) row(items []*power.PowerSupply) *power.ComparisonRow {
+	row := &power.ComparisonRow{
+		Key:      a.key,
+		Label:    a.label,
+		Unit:     a.unit,
+		Computed: a.computed,
+		Values:   make([]any, len(items)),
+		Best:     []uint{},
+	}
+	scores := make([]float64, len(items))
+	known := make([]bool, len(items))
+	for i, ps := range items {
+		row.Values[i], scores[i], known[i] = a.value(ps)
+		if i > 0 && fmt.Sprint(row.Values[i]) != fmt.Sprint(row.Values[0]) {
+			row.Differs = true
+		}
+	}
+	if a.better == 0 || !row.Differs {
+		return row
+	}
+
+	best, found := 0.0, false
+	for i := range items {
+		if known[i] && (!found || float64(a.better)*(scores[i]-best) > 0) {
+			best, found = scores[i], true
+		}
+	}
+	for i, ps := range items {
+		if known[i] && scores[i] == best {
+			row.Best = append(row.Best, ps.ID)
+		}
+	}
+	return row
+}
+
+// compareText 文本属性，为空表示未填写
+func compareText(get func(ps *power.PowerSupply) string) func(ps *power.PowerSupply) (any, float64, bool) {
+	return func(ps *power.PowerSupply) (any, float64, bool) {
+		if v := get(ps); v != "" {
+			return v, 0, true
+		}
+		return nil, 0, false
+	}
+}
+
+// compareBool 布尔属性
+func compareBool(get func(ps *power.PowerSupply) bool) func(ps *power.PowerSupply) (any, float64, bool) {
+	return func(ps *power.PowerSupply) (any, float64, bool) {
+		return get(ps), 0, true
+	}
+}
+
+// compareCount 数量属性，0 也是有效值
+func compareCount(get func(ps *power.PowerSupply) int) func(ps *power.PowerSupply) (any, float64, bool) {
+	return func(ps *power.PowerSupply) (any, float64, bool) {
+		v := get(ps)
+		return v, float64(v), true
+	}
+}
+
+// compareInt 整数规格，为 0 表示未填写
+func compareInt(get func(ps *power.PowerSupply) int) func(ps *power.PowerSupply) (any, float64, bool) {
+	return func(ps *power.PowerSupply) (any, float64, bool) {
+		if v := get(ps); v > 0 {
+			return v, float64(v), true
+		}
+		return nil, 0, false
+	}
+}
+
+// compareFloat 小数规格，为 0 表示未填写
+func compareFloat(get func(ps *power.PowerSupply) float64) func(ps *power.PowerSupply) (any, float64, bool) {
+	return func(ps *power.PowerSupply) (any, float64, bool) {
+		if v := get(ps); v > 0 {
+			return v, v, true
+		}
+		return nil, 0, false
+	}
+}
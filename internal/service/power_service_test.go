@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"power-supply-sys/internal/domain/catalog"
+	"power-supply-sys/internal/domain/inventory"
 	"power-supply-sys/internal/domain/power"
 	"power-supply-sys/internal/infra/db"
 	"power-supply-sys/internal/infra/repo"
@@ -10,8 +12,16 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 )
 
+// newTestPowerService 创建电源服务（库存通过库存流水维护，价格记录到价格历史）
+func newTestPowerService(gormDB *gorm.DB) PowerService {
+	powerRepo := repo.NewPowerRepository(gormDB)
+	transactor := common.NewTransactor(gormDB)
+	return NewPowerService(powerRepo, newTestInventoryService(gormDB), newTestPricingService(gormDB), newTestCatalogService(gormDB), transactor)
+}
+
 func TestPowerService_Create(t *testing.T) {
 	gormDB := common.SetupTestDB(t)
 	defer common.TeardownTestDB(t, gormDB)
@@ -19,8 +29,7 @@ func TestPowerService_Create(t *testing.T) {
 	err := db.Migrate(gormDB)
 	require.NoError(t, err)
 
-	powerRepo := repo.NewPowerRepository(gormDB)
-	service := NewPowerService(powerRepo)
+	service := newTestPowerService(gormDB)
 	ctx := context.Background()
 
 	t.Run("成功创建电源", func(t *testing.T) {
@@ -43,7 +52,70 @@ func TestPowerService_Create(t *testing.T) {
 		assert.Equal(t, req.Brand, ps.Brand)
 		assert.Equal(t, req.Power, ps.Power)
 		assert.Equal(t, req.Price, ps.Price)
+		assert.Equal(t, 10, ps.Stock)
 		assert.Equal(t, 1, ps.Status) // 默认状态为1
+		assert.Equal(t, power.Efficiency80PlusGold, ps.Efficiency)
+
+		// 初始库存登记为入库流水
+		movements, err := repo.NewStockMovementRepository(gormDB).List(ctx, &inventory.QueryOptions{PowerSupplyID: ps.ID, Page: 1, PageSize: 10})
+		require.NoError(t, err)
+		require.Len(t, movements, 1)
+		assert.Equal(t, inventory.MovementReceipt, movements[0].Type)
+		assert.Equal(t, 10, movements[0].Quantity)
+	})
+}
+
+func TestPowerService_Efficiency(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	err := db.Migrate(gormDB)
+	require.NoError(t, err)
+
+	service := newTestPowerService(gormDB)
+	ctx := context.Background()
+
+	t.Run("规范化能效等级", func(t *testing.T) {
+		tests := map[string]power.Efficiency{
+			"80Plus Gold":            power.Efficiency80PlusGold,
+			"80+ gold":               power.Efficiency80PlusGold,
+			"Gold":                   power.Efficiency80PlusGold,
+			"80Plus金牌":               power.Efficiency80PlusGold,
+			"80 PLUS":                power.Efficiency80Plus,
+			"80 PLUS White":          power.Efficiency80Plus,
+			"80PLUS Titanium":        power.Efficiency80PlusTitanium,
+			"Cybenetics Platinum":    power.EfficiencyCyberneticsPlatinum,
+			"Cybenetics ETA Diamond": power.EfficiencyCyberneticsDiamond,
+			"cybenetics_bronze":      power.EfficiencyCyberneticsBronze,
+		}
+		for input, want := range tests {
+			ps, err := service.Create(ctx, &power.PowerSupplyCreateRequest{Name: input, Efficiency: input})
+			require.NoError(t, err, input)
+			assert.Equal(t, want, ps.Efficiency, input)
+		}
+
+		for _, input := range []string{"80 PLUS Diamond", "Cybenetics", "Super"} {
+			_, err := service.Create(ctx, &power.PowerSupplyCreateRequest{Name: input, Efficiency: input})
+			assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam), input)
+		}
+	})
+
+	t.Run("按能效档位筛选", func(t *testing.T) {
+		list, total, err := service.List(ctx, &power.PowerSupplyQueryRequest{MinEfficiency: "platinum", PageSize: 100})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		for _, ps := range list {
+			assert.GreaterOrEqual(t, ps.Efficiency.Tier(), power.TierPlatinum)
+		}
+
+		_, total, err = service.List(ctx, &power.PowerSupplyQueryRequest{Efficiency: "80+ Gold"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), total)
+
+		_, _, err = service.List(ctx, &power.PowerSupplyQueryRequest{MinEfficiency: "gold", MaxEfficiency: "bronze"})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+		_, _, err = service.List(ctx, &power.PowerSupplyQueryRequest{MinEfficiency: "shiny"})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
 	})
 }
 
@@ -54,8 +126,7 @@ func TestPowerService_GetByID(t *testing.T) {
 	err := db.Migrate(gormDB)
 	require.NoError(t, err)
 
-	powerRepo := repo.NewPowerRepository(gormDB)
-	service := NewPowerService(powerRepo)
+	service := newTestPowerService(gormDB)
 	ctx := context.Background()
 
 	// 创建测试电源
@@ -89,8 +160,7 @@ func TestPowerService_Update(t *testing.T) {
 	err := db.Migrate(gormDB)
 	require.NoError(t, err)
 
-	powerRepo := repo.NewPowerRepository(gormDB)
-	service := NewPowerService(powerRepo)
+	service := newTestPowerService(gormDB)
 	ctx := context.Background()
 
 	// 创建测试电源
@@ -133,6 +203,34 @@ func TestPowerService_Update(t *testing.T) {
 		assert.Equal(t, 20, updated.Stock)
 		// 其他字段应该保持不变
 		assert.Equal(t, "New Brand", updated.Brand)
+
+		// 库存修改登记为盘点调整流水
+		movements, err := repo.NewStockMovementRepository(gormDB).List(ctx, &inventory.QueryOptions{PowerSupplyID: created.ID, Page: 1, PageSize: 10})
+		require.NoError(t, err)
+		require.Len(t, movements, 2)
+		assert.Equal(t, inventory.MovementAdjustment, movements[0].Type)
+		assert.Equal(t, 15, movements[0].Quantity)
+		assert.Equal(t, 20, movements[0].BalanceAfter)
+	})
+
+	t.Run("整体替换技术规格", func(t *testing.T) {
+		updated, err := service.Update(ctx, created.ID, &power.PowerSupplyUpdateRequest{
+			Spec: &power.Spec{FormFactor: power.FormFactorSFXL, ATX3: true, PCIe8PinConnectors: 2, FanSizeMM: 120},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, power.FormFactorSFXL, updated.Spec.FormFactor)
+		assert.True(t, updated.Spec.ATX3)
+		assert.Equal(t, 120, updated.Spec.FanSizeMM)
+
+		// 不传规格时保持不变
+		updated, err = service.Update(ctx, created.ID, &power.PowerSupplyUpdateRequest{Name: "Renamed PSU"})
+		require.NoError(t, err)
+		assert.Equal(t, 2, updated.Spec.PCIe8PinConnectors)
+
+		_, err = service.Update(ctx, created.ID, &power.PowerSupplyUpdateRequest{Spec: &power.Spec{FormFactor: "mATX"}})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+		_, err = service.Update(ctx, created.ID, &power.PowerSupplyUpdateRequest{Spec: &power.Spec{SATAConnectors: -1}})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
 	})
 
 	t.Run("更新不存在的电源", func(t *testing.T) {
@@ -151,8 +249,7 @@ func TestPowerService_Delete(t *testing.T) {
 	err := db.Migrate(gormDB)
 	require.NoError(t, err)
 
-	powerRepo := repo.NewPowerRepository(gormDB)
-	service := NewPowerService(powerRepo)
+	service := newTestPowerService(gormDB)
 	ctx := context.Background()
 
 	// 创建测试电源
@@ -181,8 +278,7 @@ func TestPowerService_List(t *testing.T) {
 	err := db.Migrate(gormDB)
 	require.NoError(t, err)
 
-	powerRepo := repo.NewPowerRepository(gormDB)
-	service := NewPowerService(powerRepo)
+	service := newTestPowerService(gormDB)
 	ctx := context.Background()
 
 	// 创建多个测试电源
@@ -277,3 +373,186 @@ func TestPowerService_List(t *testing.T) {
 		}
 	})
 }
+
+func TestPowerService_Compare(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	require.NoError(t, db.Migrate(gormDB))
+	service := newTestPowerService(gormDB)
+	ctx := context.Background()
+
+	create := func(req *power.PowerSupplyCreateRequest) *power.PowerSupply {
+		ps, err := service.Create(ctx, req)
+		require.NoError(t, err)
+		return ps
+	}
+	gold := create(&power.PowerSupplyCreateRequest{
+		Name: "RM750x", Brand: "Corsair", Power: 750, Efficiency: "gold", Price: 750,
+		Spec: power.Spec{FormFactor: power.FormFactorATX, PCIe8PinConnectors: 4, SATAConnectors: 8},
+	})
+	platinum := create(&power.PowerSupplyCreateRequest{
+		Name: "Prime PX-1000", Brand: "Seasonic", Power: 1000, Efficiency: "platinum", Price: 1500,
+		Spec: power.Spec{FormFactor: power.FormFactorATX, Connectors12VHPWR: 1, PCIe8PinConnectors: 4, SATAConnectors: 10, NoiseDBA: 25},
+	})
+	budget := create(&power.PowerSupplyCreateRequest{
+		Name: "MWE 650", Brand: "Cooler Master", Power: 650, Efficiency: "bronze", Price: 325,
+		Spec: power.Spec{FormFactor: power.FormFactorATX, PCIe8PinConnectors: 2, SATAConnectors: 6},
+	})
+
+	rowOf := func(c *power.Comparison, key string) *power.ComparisonRow {
+		for _, row := range c.Rows {
+			if row.Key == key {
+				return row
+			}
+		}
+		t.Fatalf("缺少对比项 %s", key)
+		return nil
+	}
+
+	t.Run("对比矩阵与最优值", func(t *testing.T) {
+		// 重复的 ID 只保留一个，按传入顺序排列
+		c, err := service.Compare(ctx, []uint{platinum.ID, gold.ID, budget.ID, gold.ID})
+		require.NoError(t, err)
+		require.Len(t, c.Items, 3)
+		assert.Equal(t, []uint{platinum.ID, gold.ID, budget.ID}, []uint{c.Items[0].ID, c.Items[1].ID, c.Items[2].ID})
+
+		efficiency := rowOf(c, "efficiency")
+		assert.Equal(t, []any{"80 PLUS Platinum", "80 PLUS Gold", "80 PLUS Bronze"}, efficiency.Values)
+		assert.Equal(t, []uint{platinum.ID}, efficiency.Best)
+
+		perWatt := rowOf(c, "price_per_watt")
+		assert.True(t, perWatt.Computed)
+		assert.Equal(t, []any{1.5, 1.0, 0.5}, perWatt.Values)
+		assert.Equal(t, []uint{budget.ID}, perWatt.Best)
+
+		total := rowOf(c, "total_connectors")
+		assert.Equal(t, []any{15, 12, 8}, total.Values)
+		assert.Equal(t, []uint{platinum.ID}, total.Best)
+
+		// 取值相同的属性不标记差异与最优值
+		formFactor := rowOf(c, "form_factor")
+		assert.False(t, formFactor.Differs)
+		pcie := rowOf(c, "pcie_8pin_connectors")
+		assert.True(t, pcie.Differs)
+		assert.ElementsMatch(t, []uint{platinum.ID, gold.ID}, pcie.Best)
+
+		// 未填写的值为空，不参与最优值比较
+		noise := rowOf(c, "noise_dba")
+		assert.Equal(t, []any{25.0, nil, nil}, noise.Values)
+		assert.Equal(t, []uint{platinum.ID}, noise.Best)
+	})
+
+	t.Run("无效的对比", func(t *testing.T) {
+		_, err := service.Compare(ctx, []uint{gold.ID, gold.ID})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		_, err = service.Compare(ctx, []uint{1, 2, 3, 4, 5, 6})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		_, err = service.Compare(ctx, []uint{gold.ID, 99999})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+}
+
+func TestPowerService_Catalog(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	require.NoError(t, db.Migrate(gormDB))
+	service := newTestPowerService(gormDB)
+	catalogSvc := newTestCatalogService(gormDB)
+	ctx := context.Background()
+
+	consumer, err := catalogSvc.CreateCategory(ctx, &catalog.CategoryCreateRequest{Name: "Consumer"})
+	require.NoError(t, err)
+	atx, err := catalogSvc.CreateCategory(ctx, &catalog.CategoryCreateRequest{ParentID: &consumer.ID, Name: "ATX"})
+	require.NoError(t, err)
+	server, err := catalogSvc.CreateCategory(ctx, &catalog.CategoryCreateRequest{Name: "Server"})
+	require.NoError(t, err)
+
+	quiet, err := service.Create(ctx, &power.PowerSupplyCreateRequest{Name: "Quiet 750", Power: 750, Price: 699, CategoryID: &atx.ID, Tags: []string{"Quiet", "White"}})
+	require.NoError(t, err)
+	rgb, err := service.Create(ctx, &power.PowerSupplyCreateRequest{Name: "RGB 850", Power: 850, Price: 799, CategoryID: &consumer.ID, Tags: []string{"rgb", "white"}})
+	require.NoError(t, err)
+	redundant, err := service.Create(ctx, &power.PowerSupplyCreateRequest{Name: "CRPS 1600", Power: 1600, Price: 2999, CategoryID: &server.ID})
+	require.NoError(t, err)
+
+	t.Run("创建时关联分类与标签", func(t *testing.T) {
+		assert.Equal(t, atx.ID, *quiet.CategoryID)
+		require.Len(t, quiet.Tags, 2)
+		assert.Equal(t, "Quiet", quiet.Tags[0].Name)
+		// 已有标签按名称复用（不区分大小写）
+		require.Len(t, rgb.Tags, 2)
+		assert.Equal(t, quiet.Tags[1].ID, rgb.Tags[1].ID)
+
+		missing := uint(9999)
+		_, err := service.Create(ctx, &power.PowerSupplyCreateRequest{Name: "X", Power: 500, Price: 1, CategoryID: &missing})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+
+	ids := func(list []*power.PowerSupply) []uint {
+		result := make([]uint, len(list))
+		for i, ps := range list {
+			result[i] = ps.ID
+		}
+		return result
+	}
+
+	t.Run("按分类查询", func(t *testing.T) {
+		list, total, err := service.List(ctx, &power.PowerSupplyQueryRequest{CategoryID: &consumer.ID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, []uint{rgb.ID}, ids(list))
+
+		// 包含子分类
+		list, _, err = service.List(ctx, &power.PowerSupplyQueryRequest{CategoryID: &consumer.ID, SubCategories: true})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []uint{quiet.ID, rgb.ID}, ids(list))
+
+		missing := uint(9999)
+		_, _, err = service.List(ctx, &power.PowerSupplyQueryRequest{CategoryID: &missing})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+
+	t.Run("按标签查询", func(t *testing.T) {
+		list, total, err := service.List(ctx, &power.PowerSupplyQueryRequest{Tags: []string{"QUIET", "rgb"}})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		assert.ElementsMatch(t, []uint{quiet.ID, rgb.ID}, ids(list))
+
+		list, _, err = service.List(ctx, &power.PowerSupplyQueryRequest{Tags: []string{"white", "Quiet", "quiet"}, TagMode: catalog.TagMatchAll})
+		require.NoError(t, err)
+		assert.Equal(t, []uint{quiet.ID}, ids(list))
+
+		list, _, err = service.List(ctx, &power.PowerSupplyQueryRequest{Tags: []string{"quiet", "unknown"}, TagMode: catalog.TagMatchAll})
+		require.NoError(t, err)
+		assert.Empty(t, list)
+
+		_, _, err = service.List(ctx, &power.PowerSupplyQueryRequest{Tags: []string{"quiet"}, TagMode: "some"})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+	})
+
+	t.Run("更新分类与标签", func(t *testing.T) {
+		tags := []string{"Redundant", "quiet"}
+		ps, err := service.Update(ctx, redundant.ID, &power.PowerSupplyUpdateRequest{Tags: &tags})
+		require.NoError(t, err)
+		require.Len(t, ps.Tags, 2)
+		assert.Equal(t, "Quiet", ps.Tags[0].Name)
+		assert.Equal(t, server.ID, *ps.CategoryID)
+
+		// 为 0 时清除分类，空列表清除标签
+		none := uint(0)
+		empty := []string{}
+		ps, err = service.Update(ctx, redundant.ID, &power.PowerSupplyUpdateRequest{CategoryID: &none, Tags: &empty})
+		require.NoError(t, err)
+		assert.Nil(t, ps.CategoryID)
+		assert.Empty(t, ps.Tags)
+
+		// 未修改标签时保留原有标签
+		name := "Quiet 750 V2"
+		ps, err = service.Update(ctx, quiet.ID, &power.PowerSupplyUpdateRequest{Name: name})
+		require.NoError(t, err)
+		assert.Len(t, ps.Tags, 2)
+	})
+}
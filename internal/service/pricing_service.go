@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/power"
+	"power-supply-sys/pkg/common"
+	"time"
+)
+
+// priceScheduleBatchSize 每轮应用计划调价的最大数量
+const priceScheduleBatchSize = 100
+
+// PricingService 价格服务接口：记录价格历史并管理计划调价
+type PricingService interface {
+	// ChangePrice 修改电源价格并追加价格历史（可在外部事务中调用）
+	ChangePrice(ctx context.Context, req *power.PriceChangeRequest) error
+	History(ctx context.Context, req *power.PriceHistoryQueryRequest) ([]*power.PriceHistory, int64, error)
+	Schedule(ctx context.Context, req *power.PriceScheduleRequest) (*power.PriceSchedule, error)
+	ListSchedules(ctx context.Context, powerSupplyID uint, status string) ([]*power.PriceSchedule, error)
+	CancelSchedule(ctx context.Context, powerSupplyID, id uint) (*power.PriceSchedule, error)
+	// ApplyDue 应用已到生效时间的计划调价，返回应用的数量（供后台任务定期调用）
+	ApplyDue(ctx context.Context) (int, error)
+	// PricesAt 查询各电源在 at 时刻生效的价格，无法确定的电源不在结果中
+	PricesAt(ctx context.Context, ids []uint, at time.Time) (map[uint]float64, error)
+}
+
+// pricingService 价格服务实现
+type pricingService struct {
+	powerRepo    power.Repository
+	historyRepo  power.PriceHistoryRepository
+	scheduleRepo power.PriceScheduleRepository
+	transactor   common.Transactor
+}
+
+var _ PricingService = &pricingService{}
+
+// NewPricingService 创建价格服务
+// 电源价格只通过 ChangePrice 修改，每次修改关闭上一条价格记录并追加新记录
+func NewPricingService(powerRepo power.Repository, historyRepo power.PriceHistoryRepository, scheduleRepo power.PriceScheduleRepository, transactor common.Transactor) PricingService {
+	return &pricingService{
+		powerRepo:    powerRepo,
+		historyRepo:  historyRepo,
+		scheduleRepo: scheduleRepo,
+		transactor:   transactor,
+	}
+}
+
+// ChangePrice 修改电源价格，在同一事务中关闭当前价格记录并追加新的价格记录
+func (s *pricingService) ChangePrice(ctx context.Context, req *power.PriceChangeRequest) error {
+	if req.Price < 0 {
+		return common.ErrInvalidParam("价格不能为负数")
+	}
+	if _, err := s.findPower(ctx, req.PowerSupplyID); err != nil {
+		return err
+	}
+
+	price := roundAmount(req.Price)
+	now := time.Now()
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.powerRepo.UpdateByID(ctx, req.PowerSupplyID, map[string]any{"price": price}); err != nil {
+			return err
+		}
+		if err := s.historyRepo.CloseOpen(ctx, req.PowerSupplyID, now); err != nil {
+			return err
+		}
+		return s.historyRepo.Create(ctx, &power.PriceHistory{
+			PowerSupplyID: req.PowerSupplyID,
+			Price:         price,
+			EffectiveFrom: now,
+			ActorID:       req.ActorID,
+			Reason:        req.Reason,
+			ScheduleID:    req.ScheduleID,
+		})
+	})
+}
+
+// History 分页查询电源的价格历史（最新生效的在前）
+func (s *pricingService) History(ctx context.Context, req *power.PriceHistoryQueryRequest) ([]*power.PriceHistory, int64, error) {
+	if _, err := s.findPower(ctx, req.PowerSupplyID); err != nil {
+		return nil, 0, err
+	}
+
+	page, pageSize := common.GetPageInfo(req.Page, req.PageSize)
+	total, err := s.historyRepo.Count(ctx, req.PowerSupplyID)
+	if err != nil {
+		return nil, 0, err
+	}
+	list, err := s.historyRepo.List(ctx, req.PowerSupplyID, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+// Schedule 创建计划调价，生效时间必须晚于当前时间
+func (s *pricingService) Schedule(ctx context.Context, req *power.PriceScheduleRequest) (*power.PriceSchedule, error) {
+	if req.Price < 0 {
+		return nil, common.ErrInvalidParam("价格不能为负数")
+	}
+	if !req.EffectiveAt.After(time.Now()) {
+		return nil, common.ErrInvalidParam("生效时间必须晚于当前时间")
+	}
+	if _, err := s.findPower(ctx, req.PowerSupplyID); err != nil {
+		return nil, err
+	}
+
+	schedule := &power.PriceSchedule{
+		PowerSupplyID: req.PowerSupplyID,
+		Price:         roundAmount(req.Price),
+		EffectiveAt:   req.EffectiveAt,
+		Status:        power.ScheduleStatusPending,
+		ActorID:       req.ActorID,
+		Reason:        req.Reason,
+	}
+	if err := s.scheduleRepo.Create(ctx, schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// ListSchedules 查询电源的计划调价（按生效时间排序）
+func (s *pricingService) ListSchedules(ctx context.Context, powerSupplyID uint, status string) ([]*power.PriceSchedule, error) {
+	if status != "" && !power.IsValidScheduleStatus(status) {
+		return nil, common.ErrInvalidParam("计划调价状态无效")
+	}
+	if _, err := s.findPower(ctx, powerSupplyID); err != nil {
+		return nil, err
+	}
+	return s.scheduleRepo.List(ctx, powerSupplyID, status)
+}
+
+// CancelSchedule 取消待生效的计划调价
+func (s *pricingService) CancelSchedule(ctx context.Context, powerSupplyID, id uint) (*power.PriceSchedule, error) {
+	schedule, err := s.scheduleRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.PowerSupplyID != powerSupplyID {
+		return nil, common.ErrNotFound("计划调价")
+	}
+
+	ok, err := s.scheduleRepo.UpdateStatus(ctx, id, power.ScheduleStatusPending, power.ScheduleStatusCancelled, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, common.ErrInvalidParam("计划调价已生效或已取消")
+	}
+	return s.scheduleRepo.FindByID(ctx, id)
+}
+
+// ApplyDue 应用已到生效时间的计划调价，返回应用的数量
+// 电源已被删除的计划直接取消
+func (s *pricingService) ApplyDue(ctx context.Context) (int, error) {
+	applied := 0
+	for {
+		due, err := s.scheduleRepo.ListDue(ctx, time.Now(), priceScheduleBatchSize)
+		if err != nil {
+			return applied, err
+		}
+		for _, schedule := range due {
+			ok, err := s.apply(ctx, schedule)
+			if err != nil {
+				if !common.HasErrorCode(err, common.ErrCodeNotFound) {
+					return applied, err
+				}
+				if _, err := s.scheduleRepo.UpdateStatus(ctx, schedule.ID, power.ScheduleStatusPending, power.ScheduleStatusCancelled, time.Now()); err != nil {
+					return applied, err
+				}
+				continue
+			}
+			// 计划已被并发取消或应用时跳过
+			if ok {
+				applied++
+			}
+		}
+		if len(due) < priceScheduleBatchSize {
+			return applied, nil
+		}
+	}
+}
+
+// apply 在同一事务中将计划标记为已生效并修改电源价格，价格历史按实际应用时间记录
+// 计划生效时间之后价格已被手动修改时取消计划，不覆盖手动修改
+// 状态条件更新保证应用与取消并发时只有一个生效
+func (s *pricingService) apply(ctx context.Context, schedule *power.PriceSchedule) (bool, error) {
+	applied := false
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		latest, err := s.historyRepo.List(ctx, schedule.PowerSupplyID, 1, 1)
+		if err != nil {
+			return err
+		}
+		if len(latest) > 0 && latest[0].ScheduleID == nil && latest[0].EffectiveFrom.After(schedule.EffectiveAt) {
+			_, err := s.scheduleRepo.UpdateStatus(ctx, schedule.ID, power.ScheduleStatusPending, power.ScheduleStatusCancelled, time.Now())
+			return err
+		}
+
+		ok, err := s.scheduleRepo.UpdateStatus(ctx, schedule.ID, power.ScheduleStatusPending, power.ScheduleStatusApplied, time.Now())
+		if err != nil || !ok {
+			return err
+		}
+		reason := schedule.Reason
+		if reason == "" {
+			reason = "计划调价"
+		}
+		if err := s.ChangePrice(ctx, &power.PriceChangeRequest{
+			PowerSupplyID: schedule.PowerSupplyID,
+			Price:         schedule.Price,
+			Reason:        reason,
+			ActorID:       schedule.ActorID,
+			ScheduleID:    &schedule.ID,
+		}); err != nil {
+			return err
+		}
+		applied = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return applied, nil
+}
+
+// PricesAt 查询各电源在 at 时刻生效的价格
+// at 早于当前时间时按价格历史查询；晚于当前时间时按待生效的计划调价推算，没有计划的电源保持当前价格
+func (s *pricingService) PricesAt(ctx context.Context, ids []uint, at time.Time) (map[uint]float64, error) {
+	if at.After(time.Now()) {
+		return s.scheduleRepo.PendingPricesAt(ctx, ids, at)
+	}
+	return s.historyRepo.PricesAt(ctx, ids, at)
+}
+
+// findPower 查询电源，不存在时返回电源不存在错误
+func (s *pricingService) findPower(ctx context.Context, id uint) (*power.PowerSupply, error) {
+	ps, err := s.powerRepo.FindByID(ctx, id)
+	if err != nil {
+		if common.HasErrorCode(err, common.ErrCodeNotFound) {
+			return nil, common.ErrNotFound("电源")
+		}
+		return nil, err
+	}
+	return ps, nil
+}
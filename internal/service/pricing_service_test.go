@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/power"
+	"power-supply-sys/internal/infra/db"
+	"power-supply-sys/internal/infra/repo"
+	"power-supply-sys/pkg/common"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// newTestPricingService 创建价格服务
+func newTestPricingService(gormDB *gorm.DB) PricingService {
+	return NewPricingService(
+		repo.NewPowerRepository(gormDB),
+		repo.NewPriceHistoryRepository(gormDB),
+		repo.NewPriceScheduleRepository(gormDB),
+		common.NewTransactor(gormDB),
+	)
+}
+
+func TestPricingService_History(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	require.NoError(t, db.Migrate(gormDB))
+	powerSvc := newTestPowerService(gormDB)
+	svc := newTestPricingService(gormDB)
+	ctx := context.Background()
+
+	ps, err := powerSvc.Create(ctx, &power.PowerSupplyCreateRequest{Name: "RM850x", Power: 850, Price: 899, ActorID: 7})
+	require.NoError(t, err)
+
+	setPrice := func(price float64) {
+		time.Sleep(5 * time.Millisecond)
+		_, err := powerSvc.Update(ctx, ps.ID, &power.PowerSupplyUpdateRequest{Price: &price, ActorID: 8})
+		require.NoError(t, err)
+	}
+
+	t.Run("创建与改价记录价格历史", func(t *testing.T) {
+		setPrice(799)
+		setPrice(849)
+		// 价格未变化时不记录
+		setPrice(849)
+
+		history, total, err := svc.History(ctx, &power.PriceHistoryQueryRequest{PowerSupplyID: ps.ID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		require.Len(t, history, 3)
+
+		assert.Equal(t, 849.0, history[0].Price)
+		assert.Nil(t, history[0].EffectiveTo)
+		assert.Equal(t, uint(8), history[0].ActorID)
+
+		assert.Equal(t, 899.0, history[2].Price)
+		assert.Equal(t, "初始价格", history[2].Reason)
+		assert.Equal(t, uint(7), history[2].ActorID)
+		require.NotNil(t, history[2].EffectiveTo)
+		assert.True(t, history[2].EffectiveTo.Equal(history[1].EffectiveFrom))
+	})
+
+	t.Run("按时间查询生效价格", func(t *testing.T) {
+		history, _, err := svc.History(ctx, &power.PriceHistoryQueryRequest{PowerSupplyID: ps.ID})
+		require.NoError(t, err)
+		between := history[1].EffectiveFrom.Add(time.Millisecond)
+
+		prices, err := svc.PricesAt(ctx, []uint{ps.ID}, between)
+		require.NoError(t, err)
+		assert.Equal(t, 799.0, prices[ps.ID])
+
+		// 列表返回指定时刻的价格
+		list, _, err := powerSvc.List(ctx, &power.PowerSupplyQueryRequest{AsOf: &between})
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, 799.0, list[0].Price)
+
+		// 价格区间按当前价格筛选，不能与指定时刻同时使用
+		minPrice := 700.0
+		_, _, err = powerSvc.List(ctx, &power.PowerSupplyQueryRequest{AsOf: &between, MinPrice: &minPrice})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		// 早于首条记录时没有生效价格
+		prices, err = svc.PricesAt(ctx, []uint{ps.ID}, history[2].EffectiveFrom.Add(-time.Hour))
+		require.NoError(t, err)
+		assert.NotContains(t, prices, ps.ID)
+	})
+
+	t.Run("无效调价", func(t *testing.T) {
+		err := svc.ChangePrice(ctx, &power.PriceChangeRequest{PowerSupplyID: ps.ID, Price: -1})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		err = svc.ChangePrice(ctx, &power.PriceChangeRequest{PowerSupplyID: 9999, Price: 1})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+
+		_, _, err = svc.History(ctx, &power.PriceHistoryQueryRequest{PowerSupplyID: 9999})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+}
+
+func TestPricingService_Schedule(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	require.NoError(t, db.Migrate(gormDB))
+	powerSvc := newTestPowerService(gormDB)
+	svc := newTestPricingService(gormDB)
+	ctx := context.Background()
+
+	ps, err := powerSvc.Create(ctx, &power.PowerSupplyCreateRequest{Name: "Focus GX-650", Power: 650, Price: 549})
+	require.NoError(t, err)
+	// 初始价格早于计划的生效时间，否则计划会被视为已被手动调价覆盖
+	require.NoError(t, gormDB.Model(&power.PriceHistory{}).Where("power_supply_id = ?", ps.ID).
+		Update("effective_from", time.Now().Add(-2*time.Hour)).Error)
+
+	schedule := func(price float64, at time.Time) *power.PriceSchedule {
+		s, err := svc.Schedule(ctx, &power.PriceScheduleRequest{PowerSupplyID: ps.ID, Price: price, EffectiveAt: at, ActorID: 3})
+		require.NoError(t, err)
+		return s
+	}
+	// makeDue 将计划的生效时间改为已过去
+	makeDue := func(id uint) {
+		require.NoError(t, gormDB.Model(&power.PriceSchedule{}).Where("id = ?", id).
+			Update("effective_at", time.Now().Add(-time.Second)).Error)
+	}
+
+	t.Run("生效时间必须在未来", func(t *testing.T) {
+		_, err := svc.Schedule(ctx, &power.PriceScheduleRequest{PowerSupplyID: ps.ID, Price: 499, EffectiveAt: time.Now().Add(-time.Minute)})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		_, err = svc.Schedule(ctx, &power.PriceScheduleRequest{PowerSupplyID: 9999, Price: 499, EffectiveAt: time.Now().Add(time.Hour)})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+
+	t.Run("未来时刻按计划调价推算价格", func(t *testing.T) {
+		s1 := schedule(499, time.Now().Add(24*time.Hour))
+		s2 := schedule(529, time.Now().Add(48*time.Hour))
+
+		at := time.Now().Add(36 * time.Hour)
+		list, _, err := powerSvc.List(ctx, &power.PowerSupplyQueryRequest{AsOf: &at})
+		require.NoError(t, err)
+		assert.Equal(t, 499.0, list[0].Price)
+
+		at = time.Now().Add(72 * time.Hour)
+		prices, err := svc.PricesAt(ctx, []uint{ps.ID}, at)
+		require.NoError(t, err)
+		assert.Equal(t, 529.0, prices[ps.ID])
+
+		// 计划生效之前保持当前价格
+		at = time.Now().Add(time.Hour)
+		list, _, err = powerSvc.List(ctx, &power.PowerSupplyQueryRequest{AsOf: &at})
+		require.NoError(t, err)
+		assert.Equal(t, 549.0, list[0].Price)
+
+		for _, s := range []*power.PriceSchedule{s1, s2} {
+			_, err := svc.CancelSchedule(ctx, ps.ID, s.ID)
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("取消计划", func(t *testing.T) {
+		s := schedule(459, time.Now().Add(time.Hour))
+
+		_, err := svc.CancelSchedule(ctx, ps.ID+1, s.ID)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+
+		cancelled, err := svc.CancelSchedule(ctx, ps.ID, s.ID)
+		require.NoError(t, err)
+		assert.Equal(t, power.ScheduleStatusCancelled, cancelled.Status)
+
+		_, err = svc.CancelSchedule(ctx, ps.ID, s.ID)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		// 已取消的计划不会被应用
+		makeDue(s.ID)
+		applied, err := svc.ApplyDue(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, applied)
+	})
+
+	t.Run("后台应用到期的计划", func(t *testing.T) {
+		s := schedule(479, time.Now().Add(time.Hour))
+		makeDue(s.ID)
+
+		applied, err := svc.ApplyDue(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, applied)
+
+		found, err := powerSvc.GetByID(ctx, ps.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 479.0, found.Price)
+
+		history, _, err := svc.History(ctx, &power.PriceHistoryQueryRequest{PowerSupplyID: ps.ID})
+		require.NoError(t, err)
+		require.NotNil(t, history[0].ScheduleID)
+		assert.Equal(t, s.ID, *history[0].ScheduleID)
+		assert.Equal(t, uint(3), history[0].ActorID)
+
+		schedules, err := svc.ListSchedules(ctx, ps.ID, power.ScheduleStatusApplied)
+		require.NoError(t, err)
+		require.Len(t, schedules, 1)
+		assert.NotNil(t, schedules[0].AppliedAt)
+
+		// 重复执行不会再次应用
+		applied, err = svc.ApplyDue(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, applied)
+
+		_, err = svc.ListSchedules(ctx, ps.ID, "unknown")
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+	})
+
+	t.Run("延迟应用按实际应用时间记录历史", func(t *testing.T) {
+		delayed, err := powerSvc.Create(ctx, &power.PowerSupplyCreateRequest{Name: "Toughpower GF3", Power: 750, Price: 399})
+		require.NoError(t, err)
+		require.NoError(t, gormDB.Model(&power.PriceHistory{}).Where("power_supply_id = ?", delayed.ID).
+			Update("effective_from", time.Now().Add(-2*time.Hour)).Error)
+
+		s, err := svc.Schedule(ctx, &power.PriceScheduleRequest{PowerSupplyID: delayed.ID, Price: 379, EffectiveAt: time.Now().Add(time.Hour)})
+		require.NoError(t, err)
+		require.NoError(t, gormDB.Model(&power.PriceSchedule{}).Where("id = ?", s.ID).Update("effective_at", time.Now().Add(-time.Hour)).Error)
+
+		applied, err := svc.ApplyDue(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, applied)
+
+		history, _, err := svc.History(ctx, &power.PriceHistoryQueryRequest{PowerSupplyID: delayed.ID})
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		assert.WithinDuration(t, time.Now(), history[0].EffectiveFrom, 5*time.Second)
+
+		// 应用之前按原价格收费
+		prices, err := svc.PricesAt(ctx, []uint{delayed.ID}, time.Now().Add(-30*time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, 399.0, prices[delayed.ID])
+	})
+
+	t.Run("生效时间之后已手动调价时取消计划", func(t *testing.T) {
+		manual, err := powerSvc.Create(ctx, &power.PowerSupplyCreateRequest{Name: "Toughpower GF A3", Power: 850, Price: 499})
+		require.NoError(t, err)
+		require.NoError(t, gormDB.Model(&power.PriceHistory{}).Where("power_supply_id = ?", manual.ID).
+			Update("effective_from", time.Now().Add(-2*time.Hour)).Error)
+
+		s, err := svc.Schedule(ctx, &power.PriceScheduleRequest{PowerSupplyID: manual.ID, Price: 459, EffectiveAt: time.Now().Add(time.Hour)})
+		require.NoError(t, err)
+		require.NoError(t, gormDB.Model(&power.PriceSchedule{}).Where("id = ?", s.ID).Update("effective_at", time.Now().Add(-time.Hour)).Error)
+
+		require.NoError(t, svc.ChangePrice(ctx, &power.PriceChangeRequest{PowerSupplyID: manual.ID, Price: 469, Reason: "手动调价"}))
+
+		applied, err := svc.ApplyDue(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, applied)
+
+		got, err := powerSvc.GetByID(ctx, manual.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 469.0, got.Price)
+
+		schedules, err := svc.ListSchedules(ctx, manual.ID, power.ScheduleStatusCancelled)
+		require.NoError(t, err)
+		require.Len(t, schedules, 1)
+		assert.Equal(t, s.ID, schedules[0].ID)
+	})
+
+	t.Run("电源已删除时取消计划", func(t *testing.T) {
+		other, err := powerSvc.Create(ctx, &power.PowerSupplyCreateRequest{Name: "MWE 550", Power: 550, Price: 299})
+		require.NoError(t, err)
+		s, err := svc.Schedule(ctx, &power.PriceScheduleRequest{PowerSupplyID: other.ID, Price: 279, EffectiveAt: time.Now().Add(time.Hour)})
+		require.NoError(t, err)
+		makeDue(s.ID)
+		require.NoError(t, powerSvc.Delete(ctx, other.ID))
+
+		applied, err := svc.ApplyDue(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, applied)
+
+		var found power.PriceSchedule
+		require.NoError(t, gormDB.First(&found, s.ID).Error)
+		assert.Equal(t, power.ScheduleStatusCancelled, found.Status)
+	})
+}
@@ -0,0 +1,341 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"power-supply-sys/internal/domain/inventory"
+	"power-supply-sys/internal/domain/purchase"
+	"power-supply-sys/internal/domain/supplier"
+	"power-supply-sys/internal/domain/warehouse"
+	"power-supply-sys/pkg/common"
+	"time"
+)
+
+// maxPurchaseLines 单个采购单的最大明细数量
+const maxPurchaseLines = 100
+
+// PurchaseOrderService 采购单服务接口
+type PurchaseOrderService interface {
+	Create(ctx context.Context, req *purchase.CreateRequest) (*purchase.Order, error)
+	GetByID(ctx context.Context, id uint) (*purchase.Order, error)
+	Update(ctx context.Context, id uint, req *purchase.UpdateRequest) (*purchase.Order, error)
+	List(ctx context.Context, req *purchase.QueryRequest) ([]*purchase.Order, int64, error)
+	Send(ctx context.Context, id uint) (*purchase.Order, error)
+	Receive(ctx context.Context, id uint, req *purchase.ReceiveRequest) (*purchase.Order, error)
+}
+
+// purchaseOrderService 采购单服务实现
+type purchaseOrderService struct {
+	repo          purchase.Repository
+	supplierRepo  supplier.Repository
+	productRepo   supplier.ProductRepository
+	warehouseRepo warehouse.Repository
+	inventory     InventoryService
+	transactor    common.Transactor
+}
+
+var _ PurchaseOrderService = &purchaseOrderService{}
+
+// NewPurchaseOrderService 创建采购单服务
+// 收货时通过 inventory 登记入库流水，增加收货仓库的库存
+func NewPurchaseOrderService(repo purchase.Repository, supplierRepo supplier.Repository, productRepo supplier.ProductRepository, warehouseRepo warehouse.Repository, inventory InventoryService, transactor common.Transactor) PurchaseOrderService {
+	return &purchaseOrderService{
+		repo:          repo,
+		supplierRepo:  supplierRepo,
+		productRepo:   productRepo,
+		warehouseRepo: warehouseRepo,
+		inventory:     inventory,
+		transactor:    transactor,
+	}
+}
+
+// Create 创建草稿采购单，明细中的电源必须由该供应商供应，未指定单价时使用供应商的采购成本价
+func (s *purchaseOrderService) Create(ctx context.Context, req *purchase.CreateRequest) (*purchase.Order, error) {
+	if _, err := s.supplierRepo.FindByID(ctx, req.SupplierID); err != nil {
+		return nil, err
+	}
+	warehouseID, err := s.resolveWarehouse(ctx, req.WarehouseID)
+	if err != nil {
+		return nil, err
+	}
+	lines, total, err := s.buildLines(ctx, req.SupplierID, req.Lines)
+	if err != nil {
+		return nil, err
+	}
+
+	orderNo, err := generateSerialNo("PO")
+	if err != nil {
+		return nil, common.ErrInternal(err)
+	}
+
+	o := &purchase.Order{
+		OrderNo:     orderNo,
+		SupplierID:  req.SupplierID,
+		WarehouseID: warehouseID,
+		Status:      purchase.StatusDraft,
+		TotalAmount: total,
+		Remark:      req.Remark,
+		CreatedBy:   req.ActorID,
+		Lines:       lines,
+	}
+	if err := s.repo.Create(ctx, o); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, o.ID)
+}
+
+// GetByID 获取采购单（含采购明细）
+func (s *purchaseOrderService) GetByID(ctx context.Context, id uint) (*purchase.Order, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// Update 修改草稿采购单，传入明细时替换全部明细并重新计算总金额
+func (s *purchaseOrderService) Update(ctx context.Context, id uint, req *purchase.UpdateRequest) (*purchase.Order, error) {
+	o, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if o.Status != purchase.StatusDraft {
+		return nil, common.ErrInvalidParam("只能修改草稿状态的采购单")
+	}
+
+	updates := make(map[string]any)
+	if req.WarehouseID != nil {
+		warehouseID, err := s.resolveWarehouse(ctx, *req.WarehouseID)
+		if err != nil {
+			return nil, err
+		}
+		updates["warehouse_id"] = warehouseID
+	}
+	if req.Remark != nil {
+		updates["remark"] = *req.Remark
+	}
+
+	if len(req.Lines) == 0 {
+		if len(updates) > 0 {
+			if err := s.repo.UpdateByID(ctx, id, updates); err != nil {
+				return nil, err
+			}
+		}
+		return s.repo.FindByID(ctx, id)
+	}
+
+	lines, total, err := s.buildLines(ctx, o.SupplierID, req.Lines)
+	if err != nil {
+		return nil, err
+	}
+	updates["total_amount"] = total
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		return s.repo.ReplaceLines(ctx, id, lines, updates)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, id)
+}
+
+// List 查询采购单列表
+func (s *purchaseOrderService) List(ctx context.Context, req *purchase.QueryRequest) ([]*purchase.Order, int64, error) {
+	if req.Status != "" && !purchase.IsValidStatus(req.Status) {
+		return nil, 0, common.ErrInvalidParam("采购单状态无效")
+	}
+
+	page, pageSize := common.GetPageInfo(req.Page, req.PageSize)
+	query := &purchase.QueryOptions{
+		SupplierID: req.SupplierID,
+		Status:     req.Status,
+		Page:       page,
+		PageSize:   pageSize,
+	}
+	total, err := s.repo.Count(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	list, err := s.repo.List(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+// Send 将草稿采购单下发给供应商，下发后不能再修改
+func (s *purchaseOrderService) Send(ctx context.Context, id uint) (*purchase.Order, error) {
+	o, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !purchase.CanTransition(o.Status, purchase.StatusSent) {
+		return nil, errPurchaseStatus(o.Status)
+	}
+	if err := s.transition(ctx, o.ID, o.Status, purchase.StatusSent); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, id)
+}
+
+// Receive 采购收货：增加明细的已收货数量，并在同一事务中登记入库流水增加收货仓库的库存
+// 全部明细收货完成时采购单流转为已收货，否则为部分收货
+func (s *purchaseOrderService) Receive(ctx context.Context, id uint, req *purchase.ReceiveRequest) (*purchase.Order, error) {
+	if len(req.Lines) == 0 {
+		return nil, common.ErrInvalidParam("收货明细不能为空")
+	}
+	o, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !purchase.Receivable(o.Status) {
+		return nil, errPurchaseStatus(o.Status)
+	}
+
+	lines := make(map[uint]*purchase.Line, len(o.Lines))
+	for _, line := range o.Lines {
+		lines[line.ID] = line
+	}
+	seen := make(map[uint]bool, len(req.Lines))
+	for _, item := range req.Lines {
+		if _, ok := lines[item.LineID]; !ok {
+			return nil, common.ErrNotFound("采购明细")
+		}
+		if item.Quantity <= 0 {
+			return nil, common.ErrInvalidParam("数量必须大于0")
+		}
+		if seen[item.LineID] {
+			return nil, common.ErrInvalidParam("收货明细重复")
+		}
+		seen[item.LineID] = true
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, item := range req.Lines {
+			line := lines[item.LineID]
+			ok, err := s.repo.ReceiveLine(ctx, line.ID, item.Quantity)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return common.ErrInvalidParam(fmt.Sprintf("电源 %d 的收货数量超过未收货数量", line.PowerSupplyID))
+			}
+			_, err = s.inventory.RecordMovement(ctx, &inventory.MovementRequest{
+				PowerSupplyID: line.PowerSupplyID,
+				Type:          inventory.MovementReceipt,
+				Quantity:      item.Quantity,
+				WarehouseID:   o.WarehouseID,
+				Reason:        "采购收货",
+				Reference:     o.OrderNo,
+				ActorID:       req.ActorID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		current, err := s.repo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		to := purchase.StatusPartiallyReceived
+		if current.FullyReceived() {
+			to = purchase.StatusReceived
+		}
+		if to == o.Status {
+			return nil
+		}
+		return s.transition(ctx, o.ID, o.Status, to)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, id)
+}
+
+// transition 将采购单从 from 状态流转到 to 状态
+// 状态条件更新保证并发操作同一采购单时只有一个生效
+func (s *purchaseOrderService) transition(ctx context.Context, id uint, from, to string) error {
+	ok, err := s.repo.UpdateStatus(ctx, id, from, to, time.Now())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		current, err := s.repo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return errPurchaseStatus(current.Status)
+	}
+	return nil
+}
+
+// buildLines 校验采购明细并按供应商的采购成本价计算金额
+func (s *purchaseOrderService) buildLines(ctx context.Context, supplierID uint, items []purchase.LineRequest) ([]*purchase.Line, float64, error) {
+	if len(items) == 0 {
+		return nil, 0, common.ErrInvalidParam("采购明细不能为空")
+	}
+	if len(items) > maxPurchaseLines {
+		return nil, 0, common.ErrInvalidParam(fmt.Sprintf("采购明细不能超过%d项", maxPurchaseLines))
+	}
+
+	lines := make([]*purchase.Line, 0, len(items))
+	seen := make(map[uint]bool, len(items))
+	var total float64
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return nil, 0, common.ErrInvalidParam("数量必须大于0")
+		}
+		if seen[item.PowerSupplyID] {
+			return nil, 0, common.ErrInvalidParam("采购明细中电源重复")
+		}
+		seen[item.PowerSupplyID] = true
+
+		product, err := s.productRepo.FindBySupplier(ctx, supplierID, item.PowerSupplyID)
+		if err != nil {
+			if common.HasErrorCode(err, common.ErrCodeNotFound) {
+				return nil, 0, common.ErrInvalidParam(fmt.Sprintf("供应商未供应电源 %d", item.PowerSupplyID))
+			}
+			return nil, 0, err
+		}
+		unitCost := product.CostPrice
+		if item.UnitCost != nil {
+			if *item.UnitCost < 0 {
+				return nil, 0, common.ErrInvalidParam("采购单价不能为负数")
+			}
+			unitCost = roundAmount(*item.UnitCost)
+		}
+
+		subtotal := roundAmount(unitCost * float64(item.Quantity))
+		total += subtotal
+		lines = append(lines, &purchase.Line{
+			PowerSupplyID: item.PowerSupplyID,
+			Quantity:      item.Quantity,
+			UnitCost:      unitCost,
+			Subtotal:      subtotal,
+		})
+	}
+	return lines, roundAmount(total), nil
+}
+
+// resolveWarehouse 确定收货仓库，未指定时使用默认仓库
+func (s *purchaseOrderService) resolveWarehouse(ctx context.Context, warehouseID uint) (uint, error) {
+	if warehouseID == 0 {
+		w, err := s.warehouseRepo.FindDefault(ctx)
+		if err != nil {
+			return 0, err
+		}
+		return w.ID, nil
+	}
+	if _, err := s.warehouseRepo.FindByID(ctx, warehouseID); err != nil {
+		return 0, err
+	}
+	return warehouseID, nil
+}
+
+// errPurchaseStatus 采购单状态不允许操作的错误
+func errPurchaseStatus(status string) *common.AppError {
+	switch status {
+	case purchase.StatusDraft:
+		return common.ErrInvalidParam("采购单尚未下发")
+	case purchase.StatusReceived:
+		return common.ErrInvalidParam("采购单已收货完成")
+	default:
+		return common.ErrInvalidParam(fmt.Sprintf("采购单状态为%s，不允许此操作", status))
+	}
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/inventory"
+	"power-supply-sys/internal/domain/power"
+	"power-supply-sys/internal/domain/purchase"
+	"power-supply-sys/internal/domain/supplier"
+	"power-supply-sys/internal/domain/warehouse"
+	"power-supply-sys/internal/infra/db"
+	"power-supply-sys/internal/infra/repo"
+	"power-supply-sys/pkg/common"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurchaseOrderService(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	err := db.Migrate(gormDB)
+	require.NoError(t, err)
+
+	powerRepo := repo.NewPowerRepository(gormDB)
+	supplierRepo := repo.NewSupplierRepository(gormDB)
+	productRepo := repo.NewSupplierProductRepository(gormDB)
+	warehouseRepo := repo.NewWarehouseRepository(gormDB)
+	levelRepo := repo.NewStockLevelRepository(gormDB)
+	movementRepo := repo.NewStockMovementRepository(gormDB)
+	svc := NewPurchaseOrderService(
+		repo.NewPurchaseOrderRepository(gormDB),
+		supplierRepo,
+		productRepo,
+		warehouseRepo,
+		newTestInventoryService(gormDB),
+		common.NewTransactor(gormDB),
+	)
+	ctx := context.Background()
+
+	products := []*power.PowerSupply{
+		{Name: "RM850x", Brand: "Corsair", Power: 850, Price: 899.90, Status: 1},
+		{Name: "Focus GX-650", Brand: "Seasonic", Power: 650, Price: 549.50, Status: 1},
+		{Name: "Unlisted", Brand: "Other", Power: 500, Price: 199, Status: 1},
+	}
+	for _, ps := range products {
+		require.NoError(t, powerRepo.Create(ctx, ps))
+	}
+	vendor := &supplier.Supplier{Name: "Vendor"}
+	require.NoError(t, supplierRepo.Create(ctx, vendor))
+	require.NoError(t, productRepo.Save(ctx, &supplier.Product{SupplierID: vendor.ID, PowerSupplyID: products[0].ID, CostPrice: 650, LeadTimeDays: 7}))
+	require.NoError(t, productRepo.Save(ctx, &supplier.Product{SupplierID: vendor.ID, PowerSupplyID: products[1].ID, CostPrice: 400, LeadTimeDays: 3}))
+	branch := &warehouse.Warehouse{Code: "SZ", Name: "深圳仓"}
+	require.NoError(t, warehouseRepo.Create(ctx, branch))
+
+	var po *purchase.Order
+
+	t.Run("创建草稿采购单", func(t *testing.T) {
+		unitCost := 380.0
+		po, err = svc.Create(ctx, &purchase.CreateRequest{
+			SupplierID:  vendor.ID,
+			WarehouseID: branch.ID,
+			Lines: []purchase.LineRequest{
+				{PowerSupplyID: products[0].ID, Quantity: 10},
+				{PowerSupplyID: products[1].ID, Quantity: 5, UnitCost: &unitCost},
+			},
+			ActorID: 7,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, purchase.StatusDraft, po.Status)
+		assert.NotEmpty(t, po.OrderNo)
+		assert.Equal(t, branch.ID, po.WarehouseID)
+		require.Len(t, po.Lines, 2)
+		assert.Equal(t, 650.0, po.Lines[0].UnitCost)
+		assert.Equal(t, 380.0, po.Lines[1].UnitCost)
+		assert.Equal(t, 8400.0, po.TotalAmount)
+	})
+
+	t.Run("无效明细", func(t *testing.T) {
+		_, err := svc.Create(ctx, &purchase.CreateRequest{SupplierID: vendor.ID})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		// 供应商未供应的电源
+		_, err = svc.Create(ctx, &purchase.CreateRequest{SupplierID: vendor.ID, Lines: []purchase.LineRequest{{PowerSupplyID: products[2].ID, Quantity: 1}}})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		_, err = svc.Create(ctx, &purchase.CreateRequest{SupplierID: vendor.ID, Lines: []purchase.LineRequest{
+			{PowerSupplyID: products[0].ID, Quantity: 1},
+			{PowerSupplyID: products[0].ID, Quantity: 2},
+		}})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		_, err = svc.Create(ctx, &purchase.CreateRequest{SupplierID: 9999, Lines: []purchase.LineRequest{{PowerSupplyID: products[0].ID, Quantity: 1}}})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+
+	t.Run("修改草稿采购单", func(t *testing.T) {
+		remark := "加急"
+		updated, err := svc.Update(ctx, po.ID, &purchase.UpdateRequest{
+			Remark: &remark,
+			Lines: []purchase.LineRequest{
+				{PowerSupplyID: products[0].ID, Quantity: 6},
+				{PowerSupplyID: products[1].ID, Quantity: 4},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "加急", updated.Remark)
+		require.Len(t, updated.Lines, 2)
+		assert.Equal(t, 5500.0, updated.TotalAmount)
+		po = updated
+	})
+
+	t.Run("草稿不能收货", func(t *testing.T) {
+		_, err := svc.Receive(ctx, po.ID, &purchase.ReceiveRequest{Lines: []purchase.ReceiveLineRequest{{LineID: po.Lines[0].ID, Quantity: 1}}})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+	})
+
+	t.Run("下发后不能修改", func(t *testing.T) {
+		sent, err := svc.Send(ctx, po.ID)
+		require.NoError(t, err)
+		assert.Equal(t, purchase.StatusSent, sent.Status)
+		assert.NotNil(t, sent.SentAt)
+
+		remark := "修改"
+		_, err = svc.Update(ctx, po.ID, &purchase.UpdateRequest{Remark: &remark})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		_, err = svc.Send(ctx, po.ID)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+	})
+
+	t.Run("部分收货增加库存", func(t *testing.T) {
+		received, err := svc.Receive(ctx, po.ID, &purchase.ReceiveRequest{
+			Lines:   []purchase.ReceiveLineRequest{{LineID: po.Lines[0].ID, Quantity: 4}},
+			ActorID: 7,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, purchase.StatusPartiallyReceived, received.Status)
+		assert.Equal(t, 4, received.Lines[0].ReceivedQuantity)
+
+		ps, err := powerRepo.FindByID(ctx, products[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, 4, ps.Stock)
+
+		levels, err := levelRepo.ListByPowerSupply(ctx, products[0].ID)
+		require.NoError(t, err)
+		require.Len(t, levels, 1)
+		assert.Equal(t, branch.ID, levels[0].WarehouseID)
+
+		movements, err := movementRepo.List(ctx, &inventory.QueryOptions{PowerSupplyID: products[0].ID})
+		require.NoError(t, err)
+		require.Len(t, movements, 1)
+		assert.Equal(t, po.OrderNo, movements[0].Reference)
+		assert.Equal(t, uint(7), movements[0].ActorID)
+	})
+
+	t.Run("超量收货整体回滚", func(t *testing.T) {
+		_, err := svc.Receive(ctx, po.ID, &purchase.ReceiveRequest{Lines: []purchase.ReceiveLineRequest{
+			{LineID: po.Lines[1].ID, Quantity: 4},
+			{LineID: po.Lines[0].ID, Quantity: 3},
+		}})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		ps, err := powerRepo.FindByID(ctx, products[1].ID)
+		require.NoError(t, err)
+		assert.Equal(t, 0, ps.Stock)
+
+		_, err = svc.Receive(ctx, po.ID, &purchase.ReceiveRequest{Lines: []purchase.ReceiveLineRequest{{LineID: 9999, Quantity: 1}}})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+
+	t.Run("全部收货完成", func(t *testing.T) {
+		received, err := svc.Receive(ctx, po.ID, &purchase.ReceiveRequest{Lines: []purchase.ReceiveLineRequest{
+			{LineID: po.Lines[0].ID, Quantity: 2},
+			{LineID: po.Lines[1].ID, Quantity: 4},
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, purchase.StatusReceived, received.Status)
+		assert.NotNil(t, received.ReceivedAt)
+
+		_, err = svc.Receive(ctx, po.ID, &purchase.ReceiveRequest{Lines: []purchase.ReceiveLineRequest{{LineID: po.Lines[0].ID, Quantity: 1}}})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		list, total, err := svc.List(ctx, &purchase.QueryRequest{Status: purchase.StatusReceived})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Len(t, list, 1)
+
+		_, _, err = svc.List(ctx, &purchase.QueryRequest{Status: "unknown"})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+	})
+}
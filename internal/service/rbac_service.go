@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/rbac"
+	"power-supply-sys/internal/domain/user"
+	"power-supply-sys/pkg/common"
+)
+
+// RBACService 角色权限服务接口
+type RBACService interface {
+	rbac.PermissionChecker
+	ListRoles(ctx context.Context) ([]*rbac.Role, error)
+	ListPermissions(ctx context.Context) ([]*rbac.Permission, error)
+	CreateRole(ctx context.Context, req *rbac.RoleCreateRequest) (*rbac.Role, error)
+	SetRolePermissions(ctx context.Context, roleID uint, permissions []string) (*rbac.Role, error)
+	AssignUserRole(ctx context.Context, userID uint, role string) (*user.User, error)
+}
+
+// rbacService 角色权限服务实现
+type rbacService struct {
+	repo     rbac.Repository
+	userRepo user.Repository
+}
+
+var _ RBACService = &rbacService{}
+
+// NewRBACService 创建角色权限服务
+func NewRBACService(repo rbac.Repository, userRepo user.Repository) RBACService {
+	return &rbacService{
+		repo:     repo,
+		userRepo: userRepo,
+	}
+}
+
+// HasPermission 角色是否拥有指定权限
+func (s *rbacService) HasPermission(ctx context.Context, role, permission string) (bool, error) {
+	return s.repo.HasPermission(ctx, role, permission)
+}
+
+// ListRoles 获取角色列表
+func (s *rbacService) ListRoles(ctx context.Context) ([]*rbac.Role, error) {
+	return s.repo.ListRoles(ctx)
+}
+
+// ListPermissions 获取权限列表
+func (s *rbacService) ListPermissions(ctx context.Context) ([]*rbac.Permission, error) {
+	return s.repo.ListPermissions(ctx)
+}
+
+// CreateRole 创建角色
+func (s *rbacService) CreateRole(ctx context.Context, req *rbac.RoleCreateRequest) (*rbac.Role, error) {
+	exists, err := s.repo.RoleExists(ctx, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, common.ErrAlreadyExists("角色")
+	}
+
+	permissions, err := s.resolvePermissions(ctx, req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &rbac.Role{
+		Code:        req.Code,
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: permissions,
+	}
+	if err := s.repo.CreateRole(ctx, role); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindRoleByID(ctx, role.ID)
+}
+
+// SetRolePermissions 设置角色权限（管理员角色不可修改，避免失去管理能力）
+func (s *rbacService) SetRolePermissions(ctx context.Context, roleID uint, permissions []string) (*rbac.Role, error) {
+	role, err := s.repo.FindRoleByID(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+	if role.Code == rbac.RoleAdmin {
+		return nil, common.ErrForbidden("管理员角色的权限不可修改")
+	}
+
+	perms, err := s.resolvePermissions(ctx, permissions)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.ReplaceRolePermissions(ctx, role, perms); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindRoleByID(ctx, roleID)
+}
+
+// AssignUserRole 为用户分配角色
+func (s *rbacService) AssignUserRole(ctx context.Context, userID uint, role string) (*user.User, error) {
+	exists, err := s.repo.RoleExists(ctx, role)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, common.ErrNotFound("角色")
+	}
+
+	if err := s.userRepo.UpdateByID(ctx, userID, map[string]any{"role": role}); err != nil {
+		return nil, err
+	}
+
+	return s.userRepo.FindByID(ctx, userID)
+}
+
+// resolvePermissions 将权限编码解析为权限实体，存在未知编码时返回参数错误
+func (s *rbacService) resolvePermissions(ctx context.Context, codes []string) ([]*rbac.Permission, error) {
+	permissions, err := s.repo.FindPermissionsByCodes(ctx, codes)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]bool, len(permissions))
+	for _, p := range permissions {
+		found[p.Code] = true
+	}
+	for _, code := range codes {
+		if !found[code] {
+			return nil, common.ErrInvalidParam("未知的权限: " + code)
+		}
+	}
+
+	return permissions, nil
+}
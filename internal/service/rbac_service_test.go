@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/rbac"
+	"power-supply-sys/internal/domain/user"
+	"power-supply-sys/internal/infra/db"
+	"power-supply-sys/internal/infra/repo"
+	"power-supply-sys/pkg/common"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRBACService_CreateRole(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	err := db.Migrate(gormDB)
+	require.NoError(t, err)
+
+	service := NewRBACService(repo.NewRBACRepository(gormDB), repo.NewUserRepository(gormDB))
+	ctx := context.Background()
+
+	t.Run("成功创建角色", func(t *testing.T) {
+		role, err := service.CreateRole(ctx, &rbac.RoleCreateRequest{
+			Code:        "catalog_editor",
+			Name:        "目录编辑",
+			Permissions: []string{rbac.PermPowerRead, rbac.PermPowerWrite},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, role.Permissions, 2)
+
+		ok, err := service.HasPermission(ctx, "catalog_editor", rbac.PermPowerWrite)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("重复的角色编码", func(t *testing.T) {
+		_, err := service.CreateRole(ctx, &rbac.RoleCreateRequest{Code: rbac.RoleUser, Name: "重复"})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeAlreadyExists))
+	})
+
+	t.Run("未知的权限", func(t *testing.T) {
+		_, err := service.CreateRole(ctx, &rbac.RoleCreateRequest{
+			Code:        "bad_role",
+			Name:        "错误",
+			Permissions: []string{"power:fly"},
+		})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+	})
+}
+
+func TestRBACService_SetRolePermissions(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	err := db.Migrate(gormDB)
+	require.NoError(t, err)
+
+	rbacRepo := repo.NewRBACRepository(gormDB)
+	service := NewRBACService(rbacRepo, repo.NewUserRepository(gormDB))
+	ctx := context.Background()
+
+	t.Run("修改普通角色权限", func(t *testing.T) {
+		userRole, err := rbacRepo.FindRoleByCode(ctx, rbac.RoleUser)
+		require.NoError(t, err)
+
+		role, err := service.SetRolePermissions(ctx, userRole.ID, []string{rbac.PermPowerRead, rbac.PermUserRead})
+		assert.NoError(t, err)
+		assert.Len(t, role.Permissions, 2)
+	})
+
+	t.Run("管理员角色不可修改", func(t *testing.T) {
+		admin, err := rbacRepo.FindRoleByCode(ctx, rbac.RoleAdmin)
+		require.NoError(t, err)
+
+		_, err = service.SetRolePermissions(ctx, admin.ID, []string{rbac.PermPowerRead})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeForbidden))
+	})
+}
+
+func TestRBACService_AssignUserRole(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	err := db.Migrate(gormDB)
+	require.NoError(t, err)
+
+	userRepo := repo.NewUserRepository(gormDB)
+	service := NewRBACService(repo.NewRBACRepository(gormDB), userRepo)
+	ctx := context.Background()
+
+	created, err := NewUserService(userRepo, repo.NewRefreshTokenRepository(gormDB), repo.NewRBACRepository(gormDB), newTestLoginLimiter(), common.NewTransactor(gormDB), newTestPasswordHasher(), user.DefaultPasswordPolicy()).Create(ctx, &user.UserCreateRequest{
+		Username: "roleuser",
+		Password: "password123",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, rbac.RoleUser, created.Role)
+
+	t.Run("成功分配角色", func(t *testing.T) {
+		u, err := service.AssignUserRole(ctx, created.ID, rbac.RoleAdmin)
+		assert.NoError(t, err)
+		assert.Equal(t, rbac.RoleAdmin, u.Role)
+	})
+
+	t.Run("分配不存在的角色", func(t *testing.T) {
+		_, err := service.AssignUserRole(ctx, created.ID, "ghost")
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+}
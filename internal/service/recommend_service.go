@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"power-supply-sys/internal/domain/component"
+	"power-supply-sys/internal/domain/power"
+	"power-supply-sys/pkg/common"
+	"sort"
+	"strings"
+)
+
+// 推荐评分权重：功率匹配度、能效档位、价格
+const (
+	recommendFitWeight        = 0.5
+	recommendEfficiencyWeight = 0.3
+	recommendPriceWeight      = 0.2
+)
+
+// recommendWattsStep 推荐功率向上取整的步长(W)，与常见电源额定功率档位一致
+const recommendWattsStep = 50
+
+// RecommendService 电源推荐服务接口：按整机配置估算功耗并推荐在售电源
+type RecommendService interface {
+	Recommend(ctx context.Context, req *power.RecommendRequest) (*power.Recommendation, error)
+}
+
+// recommendService 电源推荐服务实现
+type recommendService struct {
+	powerRepo     power.Repository
+	componentRepo component.Repository
+	policy        power.RecommendPolicy
+}
+
+var _ RecommendService = &recommendService{}
+
+// NewRecommendService 创建电源推荐服务
+// CPU 与显卡功耗从组件功耗档案查询，估算参数由 policy 配置
+func NewRecommendService(powerRepo power.Repository, componentRepo component.Repository, policy power.RecommendPolicy) RecommendService {
+	return &recommendService{
+		powerRepo:     powerRepo,
+		componentRepo: componentRepo,
+		policy:        policy,
+	}
+}
+
+// Recommend 估算整机持续功耗与瞬时峰值，推荐额定功率满足要求的上架且有货的电源
+// 推荐功率取持续功耗加余量与瞬时峰值中的较大者；候选电源按功率匹配度、能效档位与价格综合评分排序
+func (s *recommendService) Recommend(ctx context.Context, req *power.RecommendRequest) (*power.Recommendation, error) {
+	if req.CPUTDP < 0 || req.GPUTGP < 0 || req.GPUCount < 0 || req.Drives < 0 || req.Fans < 0 || req.Limit < 0 {
+		return nil, common.ErrInvalidParam("功耗与数量不能为负数")
+	}
+	cpuWatts, cpuFactor, err := s.componentPower(ctx, component.TypeCPU, req.CPUModel, req.CPUTDP, s.policy.CPUTransientFactor)
+	if err != nil {
+		return nil, err
+	}
+	if cpuWatts == 0 {
+		return nil, common.ErrInvalidParam("请指定CPU型号或功耗")
+	}
+	gpuWatts, gpuFactor, err := s.componentPower(ctx, component.TypeGPU, req.GPUModel, req.GPUTGP, s.policy.GPUTransientFactor)
+	if err != nil {
+		return nil, err
+	}
+
+	estimate := s.estimate(req, cpuWatts, cpuFactor, gpuWatts, gpuFactor)
+	items, err := s.candidates(ctx, estimate)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = s.policy.Limit
+	}
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return &power.Recommendation{Estimate: estimate, Items: items}, nil
+}
+
+// componentPower 确定组件功耗与瞬时峰值倍数
+// 指定型号时从组件功耗档案查询，直接给出的功耗优先于档案中的功耗
+func (s *recommendService) componentPower(ctx context.Context, componentType, model string, watts int, factor float64) (int, float64, error) {
+	model = strings.TrimSpace(model)
+	if model == "" {
+		return watts, factor, nil
+	}
+
+	c, err := s.componentRepo.FindByName(ctx, componentType, model)
+	if err != nil {
+		if common.HasErrorCode(err, common.ErrCodeNotFound) {
+			label := "CPU"
+			if componentType == component.TypeGPU {
+				label = "显卡"
+			}
+			return 0, 0, common.ErrInvalidParam(fmt.Sprintf("未知的%s型号: %s", label, model))
+		}
+		return 0, 0, err
+	}
+	if c.TransientFactor > 0 {
+		factor = c.TransientFactor
+	}
+	if watts == 0 {
+		watts = c.Watts
+	}
+	return watts, factor, nil
+}
+
+// estimate 估算整机功耗：超频时 CPU 与显卡功耗按超频倍数放大，峰值按各自的瞬时峰值倍数计算
+func (s *recommendService) estimate(req *power.RecommendRequest, cpuWatts int, cpuFactor float64, gpuWatts int, gpuFactor float64) power.PowerEstimate {
+	gpuCount := req.GPUCount
+	if gpuWatts == 0 {
+		gpuCount = 0
+	} else if gpuCount == 0 {
+		gpuCount = 1
+	}
+
+	overclock := 1.0
+	if req.Overclocked {
+		overclock = s.policy.OverclockFactor
+	}
+	cpu := float64(cpuWatts) * overclock
+	gpu := float64(gpuWatts*gpuCount) * overclock
+	other := float64(s.policy.PlatformWatts + req.Drives*s.policy.DriveWatts + req.Fans*s.policy.FanWatts)
+
+	sustained := other + cpu + gpu
+	peak := other + cpu*cpuFactor + gpu*gpuFactor
+	recommended := math.Max(sustained*(1+s.policy.Headroom), peak)
+
+	return power.PowerEstimate{
+		CPUWatts:         int(math.Round(cpu)),
+		GPUWatts:         int(math.Round(gpu)),
+		OtherWatts:       int(other),
+		SustainedWatts:   int(math.Round(sustained)),
+		PeakWatts:        int(math.Round(peak)),
+		RecommendedWatts: int(math.Ceil(recommended/recommendWattsStep)) * recommendWattsStep,
+	}
+}
+
+// candidates 查询额定功率在 [推荐功率, 推荐功率 × MaxOversize] 之间、上架且有货的电源并评分排序
+func (s *recommendService) candidates(ctx context.Context, estimate power.PowerEstimate) ([]*power.RecommendedItem, error) {
+	minPower := estimate.RecommendedWatts
+	maxPower := int(float64(minPower) * s.policy.MaxOversize)
+	status := 1
+	inStock := true
+	list, err := s.powerRepo.List(ctx, &power.QueryOptions{
+		MinPower: &minPower,
+		MaxPower: &maxPower,
+		Status:   &status,
+		InStock:  &inStock,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	minPrice := 0.0
+	for _, ps := range list {
+		if ps.Price > 0 && (minPrice == 0 || ps.Price < minPrice) {
+			minPrice = ps.Price
+		}
+	}
+
+	items := make([]*power.RecommendedItem, 0, len(list))
+	for _, ps := range list {
+		fit := float64(estimate.RecommendedWatts) / float64(ps.Power)
+		efficiency := float64(ps.Efficiency.Tier()) / float64(power.TierDiamond)
+		price := 1.0
+		if ps.Price > 0 {
+			price = minPrice / ps.Price
+		}
+		score := recommendFitWeight*fit + recommendEfficiencyWeight*efficiency + recommendPriceWeight*price
+		items = append(items, &power.RecommendedItem{
+			PowerSupply: ps,
+			Score:       math.Round(score*1000) / 1000,
+			LoadPercent: math.Round(float64(estimate.SustainedWatts)/float64(ps.Power)*1000) / 10,
+		})
+	}
+
+	// 评分相同时价格低的在前
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Score != items[j].Score {
+			return items[i].Score > items[j].Score
+		}
+		return items[i].PowerSupply.Price < items[j].PowerSupply.Price
+	})
+	return items, nil
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/power"
+	"power-supply-sys/internal/infra/db"
+	"power-supply-sys/internal/infra/repo"
+	"power-supply-sys/pkg/common"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecommendService(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	require.NoError(t, db.Migrate(gormDB))
+	powerSvc := newTestPowerService(gormDB)
+	svc := NewRecommendService(repo.NewPowerRepository(gormDB), repo.NewComponentRepository(gormDB), power.DefaultRecommendPolicy())
+	ctx := context.Background()
+
+	create := func(name string, watts int, efficiency string, price float64, stock int) *power.PowerSupply {
+		ps, err := powerSvc.Create(ctx, &power.PowerSupplyCreateRequest{Name: name, Power: watts, Efficiency: efficiency, Price: price, Stock: stock})
+		require.NoError(t, err)
+		return ps
+	}
+	gold550 := create("Gold 550", 550, "gold", 400, 5)
+	platinum650 := create("Platinum 650", 650, "platinum", 600, 5)
+	create("Gold 500", 500, "gold", 350, 5)
+	create("Gold 1200", 1200, "gold", 1500, 5)
+	create("Gold 750 缺货", 750, "gold", 500, 0)
+	offShelf := create("Gold 850 下架", 850, "gold", 550, 5)
+	status := 0
+	_, err := powerSvc.Update(ctx, offShelf.ID, &power.PowerSupplyUpdateRequest{Status: &status})
+	require.NoError(t, err)
+
+	t.Run("估算功耗并按评分排序", func(t *testing.T) {
+		// 平台 50 + 硬盘 2×8 + 风扇 3×3 = 75W；持续 75+125+200 = 400W；峰值 75+125×1.2+200×1.6 = 545W
+		result, err := svc.Recommend(ctx, &power.RecommendRequest{CPUTDP: 125, GPUModel: "GeForce RTX 4070", Drives: 2, Fans: 3})
+		require.NoError(t, err)
+		assert.Equal(t, power.PowerEstimate{
+			CPUWatts:         125,
+			GPUWatts:         200,
+			OtherWatts:       75,
+			SustainedWatts:   400,
+			PeakWatts:        545,
+			RecommendedWatts: 550,
+		}, result.Estimate)
+
+		// 低于推荐功率、超过推荐功率 2 倍、缺货与下架的电源不推荐
+		require.Len(t, result.Items, 2)
+		assert.Equal(t, gold550.ID, result.Items[0].PowerSupply.ID)
+		assert.Equal(t, platinum650.ID, result.Items[1].PowerSupply.ID)
+		assert.Greater(t, result.Items[0].Score, result.Items[1].Score)
+		assert.Equal(t, 72.7, result.Items[0].LoadPercent)
+	})
+
+	t.Run("超频与组件瞬时峰值倍数", func(t *testing.T) {
+		result, err := svc.Recommend(ctx, &power.RecommendRequest{CPUTDP: 125, GPUModel: "GeForce RTX 4070", Drives: 2, Fans: 3, Overclocked: true})
+		require.NoError(t, err)
+		assert.Equal(t, 465, result.Estimate.SustainedWatts)
+		assert.Equal(t, 650, result.Estimate.RecommendedWatts)
+		require.Len(t, result.Items, 2)
+		assert.Equal(t, platinum650.ID, result.Items[0].PowerSupply.ID)
+		assert.Equal(t, 1200, result.Items[1].PowerSupply.Power)
+
+		// 直接给出的功耗优先，瞬时峰值倍数仍使用档案中的值（RTX 3090 为 2.0）
+		result, err = svc.Recommend(ctx, &power.RecommendRequest{CPUModel: "ryzen 5 7600", GPUModel: "GeForce RTX 3090", GPUTGP: 300})
+		require.NoError(t, err)
+		assert.Equal(t, 88, result.Estimate.CPUWatts)
+		assert.Equal(t, 300, result.Estimate.GPUWatts)
+		// 峰值 50 + 88×1.2 + 300×2.0 = 755.6W
+		assert.Equal(t, 756, result.Estimate.PeakWatts)
+	})
+
+	t.Run("多显卡与返回数量", func(t *testing.T) {
+		result, err := svc.Recommend(ctx, &power.RecommendRequest{CPUTDP: 65, GPUTGP: 115, GPUCount: 2, Limit: 1})
+		require.NoError(t, err)
+		assert.Equal(t, 230, result.Estimate.GPUWatts)
+		assert.Len(t, result.Items, 1)
+	})
+
+	t.Run("无效配置", func(t *testing.T) {
+		for _, req := range []*power.RecommendRequest{
+			{GPUTGP: 200},
+			{CPUModel: "Unknown CPU"},
+			{CPUTDP: 65, GPUModel: "Unknown GPU"},
+			{CPUTDP: 65, Drives: -1},
+		} {
+			_, err := svc.Recommend(ctx, req)
+			assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam), req)
+		}
+	})
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"power-supply-sys/internal/domain/inventory"
+	"power-supply-sys/internal/domain/power"
+	"power-supply-sys/pkg/common"
+	"time"
+)
+
+// releaseBatchSize 每轮释放过期预留的最大数量
+const releaseBatchSize = 100
+
+// ReservationService 库存预留服务接口
+type ReservationService interface {
+	Reserve(ctx context.Context, req *inventory.ReserveRequest) (*inventory.Reservation, error)
+	GetByID(ctx context.Context, id uint) (*inventory.Reservation, error)
+	Confirm(ctx context.Context, id uint, actorID uint) (*inventory.Reservation, error)
+	Cancel(ctx context.Context, id uint) (*inventory.Reservation, error)
+	ReleaseExpired(ctx context.Context) (int, error)
+}
+
+// reservationService 库存预留服务实现
+type reservationService struct {
+	reservationRepo inventory.ReservationRepository
+	powerRepo       power.Repository
+	inventory       InventoryService
+	transactor      common.Transactor
+	ttl             time.Duration
+}
+
+var _ ReservationService = &reservationService{}
+
+// NewReservationService 创建库存预留服务
+// ttl 为预留有效期，过期未确认的预留由 ReleaseExpired 释放
+func NewReservationService(reservationRepo inventory.ReservationRepository, powerRepo power.Repository, inventory InventoryService, transactor common.Transactor, ttl time.Duration) ReservationService {
+	return &reservationService{
+		reservationRepo: reservationRepo,
+		powerRepo:       powerRepo,
+		inventory:       inventory,
+		transactor:      transactor,
+		ttl:             ttl,
+	}
+}
+
+// Reserve 预留库存，可售库存（库存减去已预留数量）不足时返回库存不足错误
+func (s *reservationService) Reserve(ctx context.Context, req *inventory.ReserveRequest) (*inventory.Reservation, error) {
+	if req.Quantity <= 0 {
+		return nil, common.ErrInvalidParam("数量必须大于0")
+	}
+
+	ps, err := s.powerRepo.FindByID(ctx, req.PowerSupplyID)
+	if err != nil {
+		return nil, err
+	}
+	if ps.Status != 1 {
+		return nil, common.ErrInvalidParam("电源已下架")
+	}
+
+	reservation := &inventory.Reservation{
+		PowerSupplyID: req.PowerSupplyID,
+		Quantity:      req.Quantity,
+		Status:        inventory.ReservationPending,
+		Reference:     req.Reference,
+		ActorID:       req.ActorID,
+		ExpiresAt:     time.Now().Add(s.ttl),
+	}
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		ok, err := s.powerRepo.Reserve(ctx, req.PowerSupplyID, req.Quantity)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return common.ErrStockShortage()
+		}
+		return s.reservationRepo.Create(ctx, reservation)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// GetByID 获取预留
+func (s *reservationService) GetByID(ctx context.Context, id uint) (*inventory.Reservation, error) {
+	return s.reservationRepo.FindByID(ctx, id)
+}
+
+// Confirm 确认预留：释放预留数量并登记销售出库流水
+// 已过期的预留不能确认，此时立即释放并返回错误
+func (s *reservationService) Confirm(ctx context.Context, id uint, actorID uint) (*inventory.Reservation, error) {
+	r, err := s.reservationRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if r.Status == inventory.ReservationPending && r.IsExpired(time.Now()) {
+		if err := s.transition(ctx, r, inventory.ReservationExpired); err != nil {
+			return nil, err
+		}
+		return nil, errReservationStatus(inventory.ReservationExpired)
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.transition(ctx, r, inventory.ReservationConfirmed); err != nil {
+			return err
+		}
+		movement, err := s.inventory.RecordMovement(ctx, &inventory.MovementRequest{
+			PowerSupplyID: r.PowerSupplyID,
+			Type:          inventory.MovementSale,
+			Quantity:      r.Quantity,
+			Reason:        "确认库存预留",
+			Reference:     r.Reference,
+			ActorID:       actorID,
+		})
+		if err != nil {
+			return err
+		}
+		return s.reservationRepo.UpdateByID(ctx, r.ID, map[string]any{"movement_id": movement.ID})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.reservationRepo.FindByID(ctx, id)
+}
+
+// Cancel 取消预留并释放预留数量
+func (s *reservationService) Cancel(ctx context.Context, id uint) (*inventory.Reservation, error) {
+	r, err := s.reservationRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.transition(ctx, r, inventory.ReservationCancelled); err != nil {
+		return nil, err
+	}
+	return s.reservationRepo.FindByID(ctx, id)
+}
+
+// ReleaseExpired 释放已过期的预留，返回释放的数量（供后台任务定期调用）
+func (s *reservationService) ReleaseExpired(ctx context.Context) (int, error) {
+	released := 0
+	for {
+		expired, err := s.reservationRepo.ListExpired(ctx, time.Now(), releaseBatchSize)
+		if err != nil {
+			return released, err
+		}
+		for _, r := range expired {
+			err := s.transition(ctx, r, inventory.ReservationExpired)
+			if err != nil && !common.HasErrorCode(err, common.ErrCodeInvalidParam) {
+				return released, err
+			}
+			// 状态已被并发确认或取消时跳过
+			if err == nil {
+				released++
+			}
+		}
+		if len(expired) < releaseBatchSize {
+			return released, nil
+		}
+	}
+}
+
+// transition 将预留从预留中流转到 to 状态，并在同一事务中释放预留数量
+// 状态条件更新保证确认、取消与过期释放并发时只有一个生效
+func (s *reservationService) transition(ctx context.Context, r *inventory.Reservation, to string) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		ok, err := s.reservationRepo.UpdateStatus(ctx, r.ID, inventory.ReservationPending, to)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			current, err := s.reservationRepo.FindByID(ctx, r.ID)
+			if err != nil {
+				return err
+			}
+			return errReservationStatus(current.Status)
+		}
+
+		released, err := s.powerRepo.Release(ctx, r.PowerSupplyID, r.Quantity)
+		if err != nil {
+			return err
+		}
+		if !released {
+			return common.ErrInternal(fmt.Errorf("reserved quantity of power supply %d is less than reservation %d", r.PowerSupplyID, r.ID))
+		}
+		return nil
+	})
+}
+
+// errReservationStatus 预留状态不允许操作的错误
+func errReservationStatus(status string) *common.AppError {
+	switch status {
+	case inventory.ReservationConfirmed:
+		return common.ErrInvalidParam("预留已确认")
+	case inventory.ReservationCancelled:
+		return common.ErrInvalidParam("预留已取消")
+	case inventory.ReservationExpired:
+		return common.ErrInvalidParam("预留已过期")
+	default:
+		return common.ErrInvalidParam("预留状态不允许此操作")
+	}
+}
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/inventory"
+	"power-supply-sys/internal/domain/power"
+	"power-supply-sys/internal/domain/warehouse"
+	"power-supply-sys/internal/infra/db"
+	"power-supply-sys/internal/infra/repo"
+	"power-supply-sys/pkg/common"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// setupReservationTest 创建库存预留服务及库存为 stock 的测试电源
+func setupReservationTest(t *testing.T, gormDB *gorm.DB, ttl time.Duration, stock int) (ReservationService, power.Repository, *power.PowerSupply) {
+	err := db.Migrate(gormDB)
+	require.NoError(t, err)
+
+	powerRepo := repo.NewPowerRepository(gormDB)
+	transactor := common.NewTransactor(gormDB)
+	inventorySvc := newTestInventoryService(gormDB)
+	svc := NewReservationService(repo.NewReservationRepository(gormDB), powerRepo, inventorySvc, transactor, ttl)
+
+	ps := &power.PowerSupply{Name: "Reserve PSU", Power: 850, Price: 199.99, Status: 1}
+	require.NoError(t, powerRepo.Create(context.Background(), ps))
+	_, err = inventorySvc.RecordMovement(context.Background(), &inventory.MovementRequest{
+		PowerSupplyID: ps.ID, Type: inventory.MovementReceipt, Quantity: stock,
+	})
+	require.NoError(t, err)
+	return svc, powerRepo, ps
+}
+
+func TestReservationService_Lifecycle(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	svc, powerRepo, ps := setupReservationTest(t, gormDB, 15*time.Minute, 10)
+	ctx := context.Background()
+
+	stockOf := func() *power.PowerSupply {
+		found, err := powerRepo.FindByID(ctx, ps.ID)
+		require.NoError(t, err)
+		return found
+	}
+
+	t.Run("预留不改变库存数量", func(t *testing.T) {
+		r, err := svc.Reserve(ctx, &inventory.ReserveRequest{PowerSupplyID: ps.ID, Quantity: 4, Reference: "CART-1"})
+		require.NoError(t, err)
+		assert.Equal(t, inventory.ReservationPending, r.Status)
+		assert.WithinDuration(t, time.Now().Add(15*time.Minute), r.ExpiresAt, time.Minute)
+
+		found := stockOf()
+		assert.Equal(t, 10, found.Stock)
+		assert.Equal(t, 4, found.Reserved)
+	})
+
+	t.Run("可售库存不足", func(t *testing.T) {
+		_, err := svc.Reserve(ctx, &inventory.ReserveRequest{PowerSupplyID: ps.ID, Quantity: 7})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeStockShortage))
+
+		_, err = svc.Reserve(ctx, &inventory.ReserveRequest{PowerSupplyID: ps.ID, Quantity: 0})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+	})
+
+	t.Run("确认后转为销售出库", func(t *testing.T) {
+		r, err := svc.Reserve(ctx, &inventory.ReserveRequest{PowerSupplyID: ps.ID, Quantity: 2, Reference: "SO-1"})
+		require.NoError(t, err)
+
+		confirmed, err := svc.Confirm(ctx, r.ID, 9)
+		require.NoError(t, err)
+		assert.Equal(t, inventory.ReservationConfirmed, confirmed.Status)
+		require.NotNil(t, confirmed.MovementID)
+
+		found := stockOf()
+		assert.Equal(t, 8, found.Stock)
+		assert.Equal(t, 4, found.Reserved)
+
+		_, err = svc.Confirm(ctx, r.ID, 9)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+		_, err = svc.Cancel(ctx, r.ID)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+	})
+
+	t.Run("取消后释放预留", func(t *testing.T) {
+		r, err := svc.Reserve(ctx, &inventory.ReserveRequest{PowerSupplyID: ps.ID, Quantity: 3})
+		require.NoError(t, err)
+
+		cancelled, err := svc.Cancel(ctx, r.ID)
+		require.NoError(t, err)
+		assert.Equal(t, inventory.ReservationCancelled, cancelled.Status)
+		assert.Equal(t, 4, stockOf().Reserved)
+	})
+
+	t.Run("下架的电源不能预留", func(t *testing.T) {
+		require.NoError(t, powerRepo.UpdateByID(ctx, ps.ID, map[string]any{"status": 0}))
+		defer powerRepo.UpdateByID(ctx, ps.ID, map[string]any{"status": 1})
+
+		_, err := svc.Reserve(ctx, &inventory.ReserveRequest{PowerSupplyID: ps.ID, Quantity: 1})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+	})
+}
+
+func TestReservationService_ConfirmAcrossWarehouses(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	svc, powerRepo, ps := setupReservationTest(t, gormDB, 15*time.Minute, 5)
+	ctx := context.Background()
+
+	// 两个仓库各有 5 件库存
+	warehouseRepo := repo.NewWarehouseRepository(gormDB)
+	branch := &warehouse.Warehouse{Code: "WH", Name: "武汉仓"}
+	require.NoError(t, warehouseRepo.Create(ctx, branch))
+	_, err := newTestInventoryService(gormDB).RecordMovement(ctx, &inventory.MovementRequest{
+		PowerSupplyID: ps.ID, Type: inventory.MovementReceipt, Quantity: 5, WarehouseID: branch.ID,
+	})
+	require.NoError(t, err)
+
+	r, err := svc.Reserve(ctx, &inventory.ReserveRequest{PowerSupplyID: ps.ID, Quantity: 8, Reference: "SO-8"})
+	require.NoError(t, err)
+
+	confirmed, err := svc.Confirm(ctx, r.ID, 0)
+	require.NoError(t, err)
+	assert.Equal(t, inventory.ReservationConfirmed, confirmed.Status)
+
+	found, err := powerRepo.FindByID(ctx, ps.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, found.Stock)
+	assert.Equal(t, 0, found.Reserved)
+
+	levels, err := repo.NewStockLevelRepository(gormDB).ListByPowerSupply(ctx, ps.ID)
+	require.NoError(t, err)
+	sum := 0
+	for _, l := range levels {
+		sum += l.Quantity
+	}
+	assert.Equal(t, 2, sum)
+}
+
+func TestReservationService_ReleaseExpired(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	// 有效期为负数，预留创建后立即过期
+	svc, powerRepo, ps := setupReservationTest(t, gormDB, -time.Second, 5)
+	ctx := context.Background()
+
+	first, err := svc.Reserve(ctx, &inventory.ReserveRequest{PowerSupplyID: ps.ID, Quantity: 2})
+	require.NoError(t, err)
+	_, err = svc.Reserve(ctx, &inventory.ReserveRequest{PowerSupplyID: ps.ID, Quantity: 3})
+	require.NoError(t, err)
+
+	t.Run("过期的预留不能确认", func(t *testing.T) {
+		_, err := svc.Confirm(ctx, first.ID, 0)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		found, err := svc.GetByID(ctx, first.ID)
+		require.NoError(t, err)
+		assert.Equal(t, inventory.ReservationExpired, found.Status)
+	})
+
+	t.Run("后台释放过期预留", func(t *testing.T) {
+		released, err := svc.ReleaseExpired(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, released)
+
+		found, err := powerRepo.FindByID(ctx, ps.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 5, found.Stock)
+		assert.Equal(t, 0, found.Reserved)
+
+		released, err = svc.ReleaseExpired(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, released)
+	})
+}
+
+func TestReservationService_ConcurrentReserve(t *testing.T) {
+	gormDB := common.SetupConcurrentTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	svc, powerRepo, ps := setupReservationTest(t, gormDB, 15*time.Minute, 10)
+	ctx := context.Background()
+
+	const workers = 50
+	var succeeded, shortage int64
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			_, err := svc.Reserve(ctx, &inventory.ReserveRequest{PowerSupplyID: ps.ID, Quantity: 1})
+			switch {
+			case err == nil:
+				atomic.AddInt64(&succeeded, 1)
+			case common.HasErrorCode(err, common.ErrCodeStockShortage):
+				atomic.AddInt64(&shortage, 1)
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	assert.Equal(t, int64(10), succeeded)
+	assert.Equal(t, int64(workers-10), shortage)
+
+	found, err := powerRepo.FindByID(ctx, ps.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 10, found.Stock)
+	assert.Equal(t, 10, found.Reserved)
+	assert.Zero(t, found.Available())
+}
+
+func TestReservationService_ConcurrentConfirmAndCancel(t *testing.T) {
+	gormDB := common.SetupConcurrentTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	svc, powerRepo, ps := setupReservationTest(t, gormDB, 15*time.Minute, 10)
+	ctx := context.Background()
+
+	r, err := svc.Reserve(ctx, &inventory.ReserveRequest{PowerSupplyID: ps.ID, Quantity: 4})
+	require.NoError(t, err)
+
+	var succeeded int64
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(confirm bool) {
+			defer wg.Done()
+			<-start
+			var err error
+			if confirm {
+				_, err = svc.Confirm(ctx, r.ID, 0)
+			} else {
+				_, err = svc.Cancel(ctx, r.ID)
+			}
+			if err == nil {
+				atomic.AddInt64(&succeeded, 1)
+			} else {
+				assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam), "unexpected error: %v", err)
+			}
+		}(i%2 == 0)
+	}
+	close(start)
+	wg.Wait()
+
+	// 只有一次确认或取消生效，预留数量只释放一次
+	assert.Equal(t, int64(1), succeeded)
+	found, err := powerRepo.FindByID(ctx, ps.ID)
+	require.NoError(t, err)
+	assert.Zero(t, found.Reserved)
+	assert.Contains(t, []int{6, 10}, found.Stock)
+}
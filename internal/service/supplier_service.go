@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/power"
+	"power-supply-sys/internal/domain/purchase"
+	"power-supply-sys/internal/domain/supplier"
+	"power-supply-sys/pkg/common"
+	"strings"
+)
+
+// SupplierService 供应商服务接口
+type SupplierService interface {
+	Create(ctx context.Context, req *supplier.CreateRequest) (*supplier.Supplier, error)
+	GetByID(ctx context.Context, id uint) (*supplier.Supplier, error)
+	Update(ctx context.Context, id uint, req *supplier.UpdateRequest) (*supplier.Supplier, error)
+	Delete(ctx context.Context, id uint) error
+	List(ctx context.Context, req *supplier.QueryRequest) ([]*supplier.Supplier, int64, error)
+	SetProduct(ctx context.Context, supplierID uint, req *supplier.ProductRequest) (*supplier.Product, error)
+	RemoveProduct(ctx context.Context, supplierID, powerSupplyID uint) error
+}
+
+// supplierService 供应商服务实现
+type supplierService struct {
+	repo         supplier.Repository
+	productRepo  supplier.ProductRepository
+	powerRepo    power.Repository
+	purchaseRepo purchase.Repository
+	transactor   common.Transactor
+}
+
+var _ SupplierService = &supplierService{}
+
+// NewSupplierService 创建供应商服务
+func NewSupplierService(repo supplier.Repository, productRepo supplier.ProductRepository, powerRepo power.Repository, purchaseRepo purchase.Repository, transactor common.Transactor) SupplierService {
+	return &supplierService{
+		repo:         repo,
+		productRepo:  productRepo,
+		powerRepo:    powerRepo,
+		purchaseRepo: purchaseRepo,
+		transactor:   transactor,
+	}
+}
+
+// Create 创建供应商，名称不能重复
+func (s *supplierService) Create(ctx context.Context, req *supplier.CreateRequest) (*supplier.Supplier, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, common.ErrInvalidParam("供应商名称不能为空")
+	}
+	if err := s.ensureNameAvailable(ctx, name); err != nil {
+		return nil, err
+	}
+
+	sup := &supplier.Supplier{
+		Name:        name,
+		ContactName: req.ContactName,
+		Phone:       req.Phone,
+		Email:       req.Email,
+		Address:     req.Address,
+		Remark:      req.Remark,
+	}
+	if err := s.repo.Create(ctx, sup); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, sup.ID)
+}
+
+// GetByID 获取供应商（含供应的电源）
+func (s *supplierService) GetByID(ctx context.Context, id uint) (*supplier.Supplier, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// Update 更新供应商
+func (s *supplierService) Update(ctx context.Context, id uint, req *supplier.UpdateRequest) (*supplier.Supplier, error) {
+	sup, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]any)
+	if name := strings.TrimSpace(req.Name); name != "" && name != sup.Name {
+		if err := s.ensureNameAvailable(ctx, name); err != nil {
+			return nil, err
+		}
+		updates["name"] = name
+	}
+	if req.ContactName != nil {
+		updates["contact_name"] = *req.ContactName
+	}
+	if req.Phone != nil {
+		updates["phone"] = *req.Phone
+	}
+	if req.Email != nil {
+		updates["email"] = *req.Email
+	}
+	if req.Address != nil {
+		updates["address"] = *req.Address
+	}
+	if req.Remark != nil {
+		updates["remark"] = *req.Remark
+	}
+
+	if len(updates) > 0 {
+		if err := s.repo.UpdateByID(ctx, id, updates); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.FindByID(ctx, id)
+}
+
+// Delete 删除供应商及其供应的电源，已有采购单的供应商不能删除
+func (s *supplierService) Delete(ctx context.Context, id uint) error {
+	if _, err := s.repo.FindByID(ctx, id); err != nil {
+		return err
+	}
+	exists, err := s.purchaseRepo.ExistsBySupplier(ctx, id)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return common.ErrInvalidParam("供应商已有采购单，不能删除")
+	}
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		return s.repo.Delete(ctx, id)
+	})
+}
+
+// List 查询供应商列表
+func (s *supplierService) List(ctx context.Context, req *supplier.QueryRequest) ([]*supplier.Supplier, int64, error) {
+	page, pageSize := common.GetPageInfo(req.Page, req.PageSize)
+	query := &supplier.QueryOptions{
+		Name:          req.Name,
+		PowerSupplyID: req.PowerSupplyID,
+		Page:          page,
+		PageSize:      pageSize,
+	}
+	total, err := s.repo.Count(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	list, err := s.repo.List(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+// SetProduct 设置供应商供应的电源及采购成本价与交货周期，已存在时覆盖
+func (s *supplierService) SetProduct(ctx context.Context, supplierID uint, req *supplier.ProductRequest) (*supplier.Product, error) {
+	if req.CostPrice < 0 {
+		return nil, common.ErrInvalidParam("采购成本价不能为负数")
+	}
+	if req.LeadTimeDays < 0 {
+		return nil, common.ErrInvalidParam("交货周期不能为负数")
+	}
+	if _, err := s.repo.FindByID(ctx, supplierID); err != nil {
+		return nil, err
+	}
+	if _, err := s.powerRepo.FindByID(ctx, req.PowerSupplyID); err != nil {
+		if common.HasErrorCode(err, common.ErrCodeNotFound) {
+			return nil, common.ErrNotFound("电源")
+		}
+		return nil, err
+	}
+
+	err := s.productRepo.Save(ctx, &supplier.Product{
+		SupplierID:    supplierID,
+		PowerSupplyID: req.PowerSupplyID,
+		SupplierSKU:   req.SupplierSKU,
+		CostPrice:     roundAmount(req.CostPrice),
+		LeadTimeDays:  req.LeadTimeDays,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.productRepo.FindBySupplier(ctx, supplierID, req.PowerSupplyID)
+}
+
+// RemoveProduct 删除供应商供应的电源
+func (s *supplierService) RemoveProduct(ctx context.Context, supplierID, powerSupplyID uint) error {
+	removed, err := s.productRepo.Remove(ctx, supplierID, powerSupplyID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return common.ErrNotFound("供应商电源")
+	}
+	return nil
+}
+
+// ensureNameAvailable 校验供应商名称未被使用
+func (s *supplierService) ensureNameAvailable(ctx context.Context, name string) error {
+	exists, err := s.repo.ExistsByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return common.ErrAlreadyExists("供应商名称")
+	}
+	return nil
+}
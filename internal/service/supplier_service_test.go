@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/power"
+	"power-supply-sys/internal/domain/purchase"
+	"power-supply-sys/internal/domain/supplier"
+	"power-supply-sys/internal/infra/db"
+	"power-supply-sys/internal/infra/repo"
+	"power-supply-sys/pkg/common"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSupplierService(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	err := db.Migrate(gormDB)
+	require.NoError(t, err)
+
+	powerRepo := repo.NewPowerRepository(gormDB)
+	purchaseRepo := repo.NewPurchaseOrderRepository(gormDB)
+	svc := NewSupplierService(
+		repo.NewSupplierRepository(gormDB),
+		repo.NewSupplierProductRepository(gormDB),
+		powerRepo,
+		purchaseRepo,
+		common.NewTransactor(gormDB),
+	)
+	ctx := context.Background()
+
+	ps := &power.PowerSupply{Name: "RM850x", Brand: "Corsair", Power: 850, Price: 899.90, Status: 1}
+	require.NoError(t, powerRepo.Create(ctx, ps))
+
+	var created *supplier.Supplier
+
+	t.Run("创建供应商", func(t *testing.T) {
+		created, err = svc.Create(ctx, &supplier.CreateRequest{Name: " 海盗船代理 ", ContactName: "李四", Phone: "13800000000"})
+		require.NoError(t, err)
+		assert.Equal(t, "海盗船代理", created.Name)
+
+		_, err = svc.Create(ctx, &supplier.CreateRequest{Name: "海盗船代理"})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeAlreadyExists))
+	})
+
+	t.Run("更新供应商", func(t *testing.T) {
+		email := "sales@example.com"
+		updated, err := svc.Update(ctx, created.ID, &supplier.UpdateRequest{Email: &email})
+		require.NoError(t, err)
+		assert.Equal(t, email, updated.Email)
+		assert.Equal(t, "李四", updated.ContactName)
+	})
+
+	t.Run("设置供应的电源", func(t *testing.T) {
+		p, err := svc.SetProduct(ctx, created.ID, &supplier.ProductRequest{PowerSupplyID: ps.ID, CostPrice: 650.456, LeadTimeDays: 10})
+		require.NoError(t, err)
+		assert.Equal(t, 650.46, p.CostPrice)
+
+		p, err = svc.SetProduct(ctx, created.ID, &supplier.ProductRequest{PowerSupplyID: ps.ID, CostPrice: 640, LeadTimeDays: 7})
+		require.NoError(t, err)
+		assert.Equal(t, 7, p.LeadTimeDays)
+
+		found, err := svc.GetByID(ctx, created.ID)
+		require.NoError(t, err)
+		require.Len(t, found.Products, 1)
+		assert.Equal(t, 640.0, found.Products[0].CostPrice)
+
+		list, total, err := svc.List(ctx, &supplier.QueryRequest{PowerSupplyID: ps.ID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Len(t, list, 1)
+	})
+
+	t.Run("设置供应的电源参数校验", func(t *testing.T) {
+		_, err := svc.SetProduct(ctx, created.ID, &supplier.ProductRequest{PowerSupplyID: 9999})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+
+		_, err = svc.SetProduct(ctx, created.ID, &supplier.ProductRequest{PowerSupplyID: ps.ID, CostPrice: -1})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		_, err = svc.SetProduct(ctx, 9999, &supplier.ProductRequest{PowerSupplyID: ps.ID})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+
+	t.Run("删除供应的电源", func(t *testing.T) {
+		require.NoError(t, svc.RemoveProduct(ctx, created.ID, ps.ID))
+		err := svc.RemoveProduct(ctx, created.ID, ps.ID)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+
+	t.Run("有采购单的供应商不能删除", func(t *testing.T) {
+		require.NoError(t, purchaseRepo.Create(ctx, &purchase.Order{OrderNo: "PO-1", SupplierID: created.ID, WarehouseID: 1, Status: purchase.StatusDraft}))
+		err := svc.Delete(ctx, created.ID)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		other, err := svc.Create(ctx, &supplier.CreateRequest{Name: "其他供应商"})
+		require.NoError(t, err)
+		require.NoError(t, svc.Delete(ctx, other.ID))
+		_, err = svc.GetByID(ctx, other.ID)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+}
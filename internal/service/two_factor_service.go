@@ -0,0 +1,444 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"power-supply-sys/internal/domain/lockout"
+	"power-supply-sys/internal/domain/mfa"
+	"power-supply-sys/internal/domain/user"
+	"power-supply-sys/pkg/auth"
+	"power-supply-sys/pkg/common"
+	"strings"
+	"time"
+)
+
+const (
+	recoveryCodeCount     = 10 // 每次生成的恢复码数量
+	maxChallengeAttempts  = 5  // 单个登录挑战允许的验证失败次数
+	recoveryCodeRandBytes = 7  // 恢复码随机字节数（Base32 编码后取 10 位）
+)
+
+// TwoFactorService 两步验证服务接口（TOTP 登记、恢复码与两步登录）
+type TwoFactorService interface {
+	Status(ctx context.Context, userID uint) (*mfa.Status, error)
+	Enroll(ctx context.Context, userID uint) (*mfa.Enrollment, error)
+	Confirm(ctx context.Context, userID uint, code string) ([]string, error)
+	Disable(ctx context.Context, userID uint, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID uint, code string) ([]string, error)
+	IsEnabled(ctx context.Context, userID uint) (bool, error)
+	CreateChallenge(ctx context.Context, userID uint) (*mfa.ChallengeTicket, error)
+	VerifyChallenge(ctx context.Context, challengeToken, code string) (*user.User, error)
+}
+
+// twoFactorService 两步验证服务实现
+type twoFactorService struct {
+	repo            mfa.Repository
+	userRepo        user.Repository
+	attempts        lockout.Store
+	policy          lockout.Policy
+	transactor      common.Transactor
+	issuer          string
+	challengeExpire time.Duration
+}
+
+var _ TwoFactorService = &twoFactorService{}
+
+// NewTwoFactorService 创建两步验证服务
+// 验证码失败次数复用登录保护的计数存储与策略，避免通过反复登录绕过挑战的尝试次数限制
+func NewTwoFactorService(repo mfa.Repository, userRepo user.Repository, attempts lockout.Store, policy lockout.Policy, transactor common.Transactor, issuer string, challengeExpire time.Duration) TwoFactorService {
+	return &twoFactorService{
+		repo:            repo,
+		userRepo:        userRepo,
+		attempts:        attempts,
+		policy:          policy,
+		transactor:      transactor,
+		issuer:          issuer,
+		challengeExpire: challengeExpire,
+	}
+}
+
+// Status 查询两步验证状态
+func (s *twoFactorService) Status(ctx context.Context, userID uint) (*mfa.Status, error) {
+	enabled, err := s.IsEnabled(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	status := &mfa.Status{Enabled: enabled}
+	if enabled {
+		status.RecoveryCodesRemaining, err = s.repo.CountUnusedRecoveryCodes(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+// Enroll 登记两步验证：生成新密钥，验证首个验证码之前不生效
+func (s *twoFactorService) Enroll(ctx context.Context, userID uint) (*mfa.Enrollment, error) {
+	u, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled, err := s.IsEnabled(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		return nil, errTwoFactorAlreadyEnabled()
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return nil, common.ErrInternal(err)
+	}
+	if err := s.repo.Save(ctx, &mfa.TwoFactor{UserID: userID, Secret: secret}); err != nil {
+		return nil, err
+	}
+
+	return &mfa.Enrollment{
+		Secret:          secret,
+		ProvisioningURI: auth.TOTPProvisioningURI(secret, s.issuer, u.Username),
+	}, nil
+}
+
+// Confirm 验证首个验证码并启用两步验证，返回一次性恢复码（仅此一次可见）
+func (s *twoFactorService) Confirm(ctx context.Context, userID uint, code string) ([]string, error) {
+	tf, err := s.repo.FindByUserID(ctx, userID)
+	if err != nil {
+		if common.HasErrorCode(err, common.ErrCodeNotFound) {
+			return nil, common.ErrInvalidParam("请先登记两步验证")
+		}
+		return nil, err
+	}
+	if tf.Enabled {
+		return nil, errTwoFactorAlreadyEnabled()
+	}
+
+	now := time.Now()
+	key := lockout.TwoFactorKey(userID)
+	if err := s.checkCodeLock(ctx, key, now); err != nil {
+		return nil, err
+	}
+
+	var codes []string
+	codeInvalid := false
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		ok, err := s.verifyTOTP(ctx, tf, code, now)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			codeInvalid = true
+			return errTwoFactorCodeInvalid()
+		}
+		if err := s.repo.Enable(ctx, userID, now); err != nil {
+			return err
+		}
+		codes, err = s.replaceRecoveryCodes(ctx, userID)
+		return err
+	})
+	if codeInvalid {
+		return nil, s.codeFailed(ctx, key, now, errTwoFactorCodeInvalid())
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attempts.Reset(ctx, key); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Disable 关闭两步验证（需要验证码或恢复码）
+func (s *twoFactorService) Disable(ctx context.Context, userID uint, code string) error {
+	tf, err := s.enabledFactor(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	key := lockout.TwoFactorKey(userID)
+	if err := s.checkCodeLock(ctx, key, now); err != nil {
+		return err
+	}
+
+	codeInvalid := false
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		ok, err := s.verifyCode(ctx, tf, code, now)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			codeInvalid = true
+			return errTwoFactorCodeInvalid()
+		}
+		return s.repo.Delete(ctx, userID)
+	})
+	if codeInvalid {
+		return s.codeFailed(ctx, key, now, errTwoFactorCodeInvalid())
+	}
+	if err != nil {
+		return err
+	}
+	return s.attempts.Reset(ctx, key)
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码（需要验证码），旧恢复码全部作废
+func (s *twoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID uint, code string) ([]string, error) {
+	tf, err := s.enabledFactor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	key := lockout.TwoFactorKey(userID)
+	if err := s.checkCodeLock(ctx, key, now); err != nil {
+		return nil, err
+	}
+
+	var codes []string
+	codeInvalid := false
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		ok, err := s.verifyTOTP(ctx, tf, code, now)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			codeInvalid = true
+			return errTwoFactorCodeInvalid()
+		}
+		codes, err = s.replaceRecoveryCodes(ctx, userID)
+		return err
+	})
+	if codeInvalid {
+		return nil, s.codeFailed(ctx, key, now, errTwoFactorCodeInvalid())
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attempts.Reset(ctx, key); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// IsEnabled 用户是否已启用两步验证
+func (s *twoFactorService) IsEnabled(ctx context.Context, userID uint) (bool, error) {
+	tf, err := s.repo.FindByUserID(ctx, userID)
+	if err != nil {
+		if common.HasErrorCode(err, common.ErrCodeNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return tf.Enabled, nil
+}
+
+// CreateChallenge 密码验证通过后签发两步登录挑战
+func (s *twoFactorService) CreateChallenge(ctx context.Context, userID uint) (*mfa.ChallengeTicket, error) {
+	raw, hash, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return nil, common.ErrInternal(err)
+	}
+
+	challenge := &mfa.Challenge{
+		UserID:    userID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(s.challengeExpire),
+	}
+	if err := s.repo.CreateChallenge(ctx, challenge); err != nil {
+		return nil, err
+	}
+
+	return &mfa.ChallengeTicket{
+		Token:     raw,
+		ExpiresIn: int64(s.challengeExpire.Seconds()),
+	}, nil
+}
+
+// VerifyChallenge 校验挑战令牌与验证码（或恢复码），成功后返回登录用户
+func (s *twoFactorService) VerifyChallenge(ctx context.Context, challengeToken, code string) (*user.User, error) {
+	now := time.Now()
+
+	challenge, err := s.repo.FindChallengeByHash(ctx, auth.HashOpaqueToken(challengeToken))
+	if err != nil {
+		if common.HasErrorCode(err, common.ErrCodeNotFound) {
+			return nil, errChallengeInvalid()
+		}
+		return nil, err
+	}
+	if challenge.UsedAt != nil || challenge.IsExpired(now) || challenge.Attempts >= maxChallengeAttempts {
+		return nil, errChallengeInvalid()
+	}
+
+	key := lockout.TwoFactorKey(challenge.UserID)
+	if err := s.checkCodeLock(ctx, key, now); err != nil {
+		return nil, err
+	}
+
+	tf, err := s.enabledFactor(ctx, challenge.UserID)
+	if err != nil {
+		return nil, errChallengeInvalid()
+	}
+
+	// 先标记挑战已使用再校验验证码，两者在同一事务中完成：
+	// 挑战已被并发使用时不会消耗恢复码，验证码错误时回滚挑战的使用标记
+	codeInvalid := false
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		used, err := s.repo.MarkChallengeUsed(ctx, challenge.ID, now)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return errChallengeInvalid()
+		}
+		ok, err := s.verifyCode(ctx, tf, code, now)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			codeInvalid = true
+			return errTwoFactorCodeInvalid()
+		}
+		return nil
+	})
+	if codeInvalid {
+		return nil, s.challengeFailed(ctx, challenge, key, now)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attempts.Reset(ctx, key); err != nil {
+		return nil, err
+	}
+
+	u, err := s.userRepo.FindByID(ctx, challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.StatusError(); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// challengeFailed 记录验证码错误，达到阈值时锁定两步验证
+func (s *twoFactorService) challengeFailed(ctx context.Context, challenge *mfa.Challenge, key string, now time.Time) error {
+	if err := s.repo.IncrementChallengeAttempts(ctx, challenge.ID); err != nil {
+		return err
+	}
+	return s.codeFailed(ctx, key, now, common.ErrUnauthorized("验证码错误"))
+}
+
+// checkCodeLock 验证码错误次数过多被锁定时返回锁定错误
+func (s *twoFactorService) checkCodeLock(ctx context.Context, key string, now time.Time) error {
+	attempt, err := s.attempts.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	if attempt != nil && attempt.IsLocked(now) {
+		return errAccountLocked(attempt.LockedUntil.Sub(now))
+	}
+	return nil
+}
+
+// codeFailed 记录一次验证码错误，达到阈值时锁定并返回锁定错误，否则返回 invalid
+// 登录挑战与管理两步验证（启用、停用、重新生成恢复码）共用同一计数
+func (s *twoFactorService) codeFailed(ctx context.Context, key string, now time.Time, invalid error) error {
+	attempt, err := s.attempts.Increment(ctx, key, now, s.policy.Window)
+	if err != nil {
+		return err
+	}
+	if attempt.Failures >= s.policy.MaxFailures {
+		if err := s.attempts.Lock(ctx, key, now.Add(s.policy.LockDuration)); err != nil {
+			return err
+		}
+		return errAccountLocked(s.policy.LockDuration)
+	}
+	return invalid
+}
+
+// enabledFactor 查询已启用的两步验证配置
+func (s *twoFactorService) enabledFactor(ctx context.Context, userID uint) (*mfa.TwoFactor, error) {
+	tf, err := s.repo.FindByUserID(ctx, userID)
+	if err != nil {
+		if common.HasErrorCode(err, common.ErrCodeNotFound) {
+			return nil, common.ErrInvalidParam("未启用两步验证")
+		}
+		return nil, err
+	}
+	if !tf.Enabled {
+		return nil, common.ErrInvalidParam("未启用两步验证")
+	}
+	return tf, nil
+}
+
+// verifyCode 校验 TOTP 验证码，不匹配时尝试作为恢复码使用
+func (s *twoFactorService) verifyCode(ctx context.Context, tf *mfa.TwoFactor, code string, now time.Time) (bool, error) {
+	ok, err := s.verifyTOTP(ctx, tf, code, now)
+	if err != nil || ok {
+		return ok, err
+	}
+	return s.repo.UseRecoveryCode(ctx, tf.UserID, hashRecoveryCode(code), now)
+}
+
+// verifyTOTP 校验 TOTP 验证码，同一时间步的验证码只能使用一次
+func (s *twoFactorService) verifyTOTP(ctx context.Context, tf *mfa.TwoFactor, code string, now time.Time) (bool, error) {
+	step, ok := auth.VerifyTOTP(tf.Secret, code, now)
+	if !ok {
+		return false, nil
+	}
+	return s.repo.UseStep(ctx, tf.UserID, step)
+}
+
+// replaceRecoveryCodes 生成新的恢复码并保存摘要，返回恢复码原文
+func (s *twoFactorService) replaceRecoveryCodes(ctx context.Context, userID uint) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, common.ErrInternal(err)
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+
+	if err := s.repo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// generateRecoveryCode 生成形如 abcde-fghij 的恢复码
+func generateRecoveryCode() (string, error) {
+	buf := make([]byte, recoveryCodeRandBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	raw := strings.ToLower(base32.StdEncoding.EncodeToString(buf))[:10]
+	return raw[:5] + "-" + raw[5:], nil
+}
+
+// hashRecoveryCode 计算恢复码摘要（忽略大小写、空格与连字符）
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(code)))
+	return auth.HashOpaqueToken(normalized)
+}
+
+// errTwoFactorAlreadyEnabled 两步验证已启用错误
+func errTwoFactorAlreadyEnabled() *common.AppError {
+	return common.NewError(common.ErrCodeAlreadyExists, "两步验证已启用")
+}
+
+// errTwoFactorCodeInvalid 验证码错误
+func errTwoFactorCodeInvalid() *common.AppError {
+	return common.ErrInvalidParam("验证码错误")
+}
+
+// errChallengeInvalid 登录挑战无效错误
+func errChallengeInvalid() *common.AppError {
+	return common.NewError(common.ErrCodeInvalidToken, "两步验证已失效，请重新登录")
+}
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/lockout"
+	"power-supply-sys/internal/domain/mfa"
+	"power-supply-sys/internal/domain/user"
+	"power-supply-sys/internal/infra/db"
+	"power-supply-sys/internal/infra/repo"
+	"power-supply-sys/pkg/auth"
+	"power-supply-sys/pkg/common"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// setupTwoFactorService 创建两步验证服务及测试用户
+func setupTwoFactorService(t *testing.T, gormDB *gorm.DB) (TwoFactorService, *user.User) {
+	err := db.Migrate(gormDB)
+	require.NoError(t, err)
+
+	userRepo := repo.NewUserRepository(gormDB)
+	policy := lockout.Policy{MaxFailures: 3, IPMaxFailures: 100, Window: time.Minute, LockDuration: time.Minute}
+	svc := NewTwoFactorService(repo.NewMFARepository(gormDB), userRepo, repo.NewMemoryLoginAttemptStore(), policy,
+		common.NewTransactor(gormDB), "Power Supply", 5*time.Minute)
+
+	u, err := NewUserService(userRepo, repo.NewRefreshTokenRepository(gormDB), repo.NewRBACRepository(gormDB), newTestLoginLimiter(), common.NewTransactor(gormDB), newTestPasswordHasher(), user.DefaultPasswordPolicy()).Create(context.Background(), &user.UserCreateRequest{
+		Username: "totpuser",
+		Password: "password123",
+	})
+	require.NoError(t, err)
+
+	return svc, u
+}
+
+// totpCodeAt 计算指定时间偏移处的验证码（测试中用不同时间步避免触发重放保护）
+func totpCodeAt(t *testing.T, secret string, offset int64) string {
+	code, err := auth.TOTPCode(secret, auth.TOTPStep(time.Now())+offset)
+	require.NoError(t, err)
+	return code
+}
+
+// enableTwoFactor 登记并启用两步验证，返回密钥与恢复码
+func enableTwoFactor(t *testing.T, svc TwoFactorService, userID uint) (string, []string) {
+	ctx := context.Background()
+	enrollment, err := svc.Enroll(ctx, userID)
+	require.NoError(t, err)
+	codes, err := svc.Confirm(ctx, userID, totpCodeAt(t, enrollment.Secret, -1))
+	require.NoError(t, err)
+	return enrollment.Secret, codes
+}
+
+func TestTwoFactorService_Enroll(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	svc, u := setupTwoFactorService(t, gormDB)
+	ctx := context.Background()
+
+	t.Run("登记后未确认前不生效", func(t *testing.T) {
+		enrollment, err := svc.Enroll(ctx, u.ID)
+		require.NoError(t, err)
+		assert.NotEmpty(t, enrollment.Secret)
+		assert.Contains(t, enrollment.ProvisioningURI, "otpauth://totp/Power%20Supply:totpuser?")
+
+		enabled, err := svc.IsEnabled(ctx, u.ID)
+		assert.NoError(t, err)
+		assert.False(t, enabled)
+
+		_, err = svc.Confirm(ctx, u.ID, "000000")
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+	})
+
+	t.Run("确认后启用并返回恢复码", func(t *testing.T) {
+		_, codes := enableTwoFactor(t, svc, u.ID)
+		assert.Len(t, codes, recoveryCodeCount)
+
+		status, err := svc.Status(ctx, u.ID)
+		require.NoError(t, err)
+		assert.True(t, status.Enabled)
+		assert.Equal(t, int64(recoveryCodeCount), status.RecoveryCodesRemaining)
+
+		_, err = svc.Enroll(ctx, u.ID)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeAlreadyExists))
+	})
+}
+
+func TestTwoFactorService_VerifyChallenge(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	svc, u := setupTwoFactorService(t, gormDB)
+	ctx := context.Background()
+	secret, recoveryCodes := enableTwoFactor(t, svc, u.ID)
+
+	t.Run("验证码通过且不可重放", func(t *testing.T) {
+		ticket, err := svc.CreateChallenge(ctx, u.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(300), ticket.ExpiresIn)
+
+		code := totpCodeAt(t, secret, 0)
+		verified, err := svc.VerifyChallenge(ctx, ticket.Token, code)
+		require.NoError(t, err)
+		assert.Equal(t, u.ID, verified.ID)
+
+		// 挑战令牌只能使用一次
+		_, err = svc.VerifyChallenge(ctx, ticket.Token, code)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidToken))
+
+		// 同一验证码不能在新的挑战中重放
+		ticket, err = svc.CreateChallenge(ctx, u.ID)
+		require.NoError(t, err)
+		_, err = svc.VerifyChallenge(ctx, ticket.Token, code)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeUnauthorized))
+	})
+
+	t.Run("恢复码只能使用一次", func(t *testing.T) {
+		ticket, err := svc.CreateChallenge(ctx, u.ID)
+		require.NoError(t, err)
+		verified, err := svc.VerifyChallenge(ctx, ticket.Token, " "+recoveryCodes[0]+" ")
+		require.NoError(t, err)
+		assert.Equal(t, u.ID, verified.ID)
+
+		ticket, err = svc.CreateChallenge(ctx, u.ID)
+		require.NoError(t, err)
+		_, err = svc.VerifyChallenge(ctx, ticket.Token, recoveryCodes[0])
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeUnauthorized))
+
+		status, err := svc.Status(ctx, u.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(recoveryCodeCount-1), status.RecoveryCodesRemaining)
+	})
+
+	t.Run("无效挑战令牌", func(t *testing.T) {
+		_, err := svc.VerifyChallenge(ctx, "invalid", "000000")
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidToken))
+	})
+}
+
+// racedMFARepository 模拟登录挑战已被并发请求使用
+type racedMFARepository struct {
+	mfa.Repository
+}
+
+func (r *racedMFARepository) MarkChallengeUsed(ctx context.Context, id uint, usedAt time.Time) (bool, error) {
+	return false, nil
+}
+
+func TestTwoFactorService_VerifyChallengeRace(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	svc, u := setupTwoFactorService(t, gormDB)
+	ctx := context.Background()
+	_, recoveryCodes := enableTwoFactor(t, svc, u.ID)
+
+	raced := NewTwoFactorService(&racedMFARepository{Repository: repo.NewMFARepository(gormDB)}, repo.NewUserRepository(gormDB),
+		repo.NewMemoryLoginAttemptStore(), lockout.Policy{MaxFailures: 3, Window: time.Minute, LockDuration: time.Minute},
+		common.NewTransactor(gormDB), "Power Supply", 5*time.Minute)
+
+	ticket, err := svc.CreateChallenge(ctx, u.ID)
+	require.NoError(t, err)
+	_, err = raced.VerifyChallenge(ctx, ticket.Token, recoveryCodes[0])
+	assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidToken))
+
+	// 挑战未能标记为已使用时恢复码不被消耗
+	status, err := svc.Status(ctx, u.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(recoveryCodeCount), status.RecoveryCodesRemaining)
+
+	verified, err := svc.VerifyChallenge(ctx, ticket.Token, recoveryCodes[0])
+	require.NoError(t, err)
+	assert.Equal(t, u.ID, verified.ID)
+}
+
+func TestTwoFactorService_ChallengeLockout(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	svc, u := setupTwoFactorService(t, gormDB)
+	ctx := context.Background()
+	secret, _ := enableTwoFactor(t, svc, u.ID)
+
+	// 重新登录获取新挑战也无法绕过失败次数限制
+	for i := 0; i < 2; i++ {
+		ticket, err := svc.CreateChallenge(ctx, u.ID)
+		require.NoError(t, err)
+		_, err = svc.VerifyChallenge(ctx, ticket.Token, "000000")
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeUnauthorized))
+	}
+
+	ticket, err := svc.CreateChallenge(ctx, u.ID)
+	require.NoError(t, err)
+	_, err = svc.VerifyChallenge(ctx, ticket.Token, "000000")
+	assert.True(t, common.HasErrorCode(err, common.ErrCodeAccountLocked))
+
+	// 锁定期间正确的验证码也被拒绝
+	_, err = svc.VerifyChallenge(ctx, ticket.Token, totpCodeAt(t, secret, 0))
+	assert.True(t, common.HasErrorCode(err, common.ErrCodeAccountLocked))
+}
+
+func TestTwoFactorService_ManagementLockout(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	svc, u := setupTwoFactorService(t, gormDB)
+	ctx := context.Background()
+	secret, _ := enableTwoFactor(t, svc, u.ID)
+
+	// 关闭与重新生成恢复码的错误验证码累计计数
+	err := svc.Disable(ctx, u.ID, "000000")
+	assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+	_, err = svc.RegenerateRecoveryCodes(ctx, u.ID, "000000")
+	assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+	err = svc.Disable(ctx, u.ID, "000000")
+	assert.True(t, common.HasErrorCode(err, common.ErrCodeAccountLocked))
+
+	// 锁定期间正确的验证码也被拒绝，登录挑战同样锁定
+	err = svc.Disable(ctx, u.ID, totpCodeAt(t, secret, 0))
+	assert.True(t, common.HasErrorCode(err, common.ErrCodeAccountLocked))
+	ticket, err := svc.CreateChallenge(ctx, u.ID)
+	require.NoError(t, err)
+	_, err = svc.VerifyChallenge(ctx, ticket.Token, totpCodeAt(t, secret, 0))
+	assert.True(t, common.HasErrorCode(err, common.ErrCodeAccountLocked))
+
+	enabled, err := svc.IsEnabled(ctx, u.ID)
+	require.NoError(t, err)
+	assert.True(t, enabled)
+}
+
+func TestTwoFactorService_Disable(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	svc, u := setupTwoFactorService(t, gormDB)
+	ctx := context.Background()
+	secret, _ := enableTwoFactor(t, svc, u.ID)
+
+	t.Run("重新生成恢复码", func(t *testing.T) {
+		codes, err := svc.RegenerateRecoveryCodes(ctx, u.ID, totpCodeAt(t, secret, 0))
+		require.NoError(t, err)
+		assert.Len(t, codes, recoveryCodeCount)
+	})
+
+	t.Run("验证码错误无法关闭", func(t *testing.T) {
+		err := svc.Disable(ctx, u.ID, "000000")
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+	})
+
+	t.Run("成功关闭", func(t *testing.T) {
+		err := svc.Disable(ctx, u.ID, totpCodeAt(t, secret, 1))
+		require.NoError(t, err)
+
+		enabled, err := svc.IsEnabled(ctx, u.ID)
+		assert.NoError(t, err)
+		assert.False(t, enabled)
+
+		err = svc.Disable(ctx, u.ID, totpCodeAt(t, secret, 1))
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+	})
+}
@@ -2,40 +2,110 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"power-supply-sys/internal/domain/rbac"
+	"power-supply-sys/internal/domain/token"
 	"power-supply-sys/internal/domain/user"
+	"power-supply-sys/pkg/auth"
 	"power-supply-sys/pkg/common"
-
-	"golang.org/x/crypto/bcrypt"
+	"time"
 )
 
 // UserService 用户服务接口
 type UserService interface {
 	Create(ctx context.Context, req *user.UserCreateRequest) (*user.User, error)
+	Register(ctx context.Context, req *user.UserCreateRequest) (*user.User, error)
+	Activate(ctx context.Context, id uint) (*user.User, error)
+	ConfirmEmail(ctx context.Context, id uint, email string) (*user.User, error)
 	GetByID(ctx context.Context, id uint) (*user.User, error)
 	GetByUsername(ctx context.Context, username string) (*user.User, error)
-	Update(ctx context.Context, id uint, req *user.UserUpdateRequest) (*user.User, error)
-	Delete(ctx context.Context, id uint) error
+	Update(ctx context.Context, actor *user.Actor, id uint, req *user.UserUpdateRequest) (*user.User, error)
+	Delete(ctx context.Context, actor *user.Actor, id uint) error
 	List(ctx context.Context, req *user.UserQueryRequest) ([]*user.User, int64, error)
 	Login(ctx context.Context, req *user.LoginRequest) (*user.User, error)
+	Unlock(ctx context.Context, id uint) error
 	VerifyPassword(hashedPassword, password string) error
 }
 
 // userService 用户服务实现
 type userService struct {
-	repo user.Repository
+	repo        user.Repository
+	tokenRepo   token.Repository
+	permissions rbac.PermissionChecker
+	limiter     LoginLimiter
+	transactor  common.Transactor
+	hasher      auth.PasswordHasher
+	policy      user.PasswordPolicy
 }
 
 var _ UserService = &userService{}
 
 // NewUserService 创建用户服务（接收 Repository 接口而非 GORM）
-func NewUserService(repo user.Repository) UserService {
+func NewUserService(repo user.Repository, tokenRepo token.Repository, permissions rbac.PermissionChecker, limiter LoginLimiter, transactor common.Transactor, hasher auth.PasswordHasher, policy user.PasswordPolicy) UserService {
 	return &userService{
-		repo: repo,
+		repo:        repo,
+		tokenRepo:   tokenRepo,
+		permissions: permissions,
+		limiter:     limiter,
+		transactor:  transactor,
+		hasher:      hasher,
+		policy:      policy,
 	}
 }
 
-// Create 创建用户
+// Create 创建用户（状态为正常）
 func (s *userService) Create(ctx context.Context, req *user.UserCreateRequest) (*user.User, error) {
+	return s.create(ctx, req, user.StatusActive)
+}
+
+// Register 自助注册用户，验证邮箱前处于待验证状态
+func (s *userService) Register(ctx context.Context, req *user.UserCreateRequest) (*user.User, error) {
+	if req.Email == "" {
+		return nil, common.ErrInvalidParam("注册需要填写邮箱")
+	}
+	if err := s.checkEmailAvailable(ctx, req.Email, 0); err != nil {
+		return nil, err
+	}
+	return s.create(ctx, req, user.StatusPending)
+}
+
+// Activate 激活待验证的用户（邮箱验证通过后调用）
+func (s *userService) Activate(ctx context.Context, id uint) (*user.User, error) {
+	u, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if u.Status != user.StatusPending {
+		return nil, common.ErrInvalidParam("账户不处于待验证状态")
+	}
+	if err := s.changeStatus(ctx, u, user.StatusActive); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// ConfirmEmail 将待验证的新邮箱替换为当前邮箱（新邮箱验证通过后调用）
+func (s *userService) ConfirmEmail(ctx context.Context, id uint, email string) (*user.User, error) {
+	u, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if u.PendingEmail == "" || u.PendingEmail != email {
+		return nil, common.ErrInvalidParam("没有待验证的新邮箱")
+	}
+	// 新邮箱可能在等待验证期间被其他用户占用
+	if err := s.checkEmailAvailable(ctx, email, u.ID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateByID(ctx, u.ID, map[string]any{"email": email, "pending_email": ""}); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, id)
+}
+
+// create 创建指定初始状态的用户
+func (s *userService) create(ctx context.Context, req *user.UserCreateRequest, status int) (*user.User, error) {
 	// 检查用户名是否已存在
 	existUser, err := s.repo.FindByUsername(ctx, req.Username)
 	if err != nil && !common.IsAppError(err) {
@@ -45,20 +115,21 @@ func (s *userService) Create(ctx context.Context, req *user.UserCreateRequest) (
 		return nil, common.ErrAlreadyExists("用户名")
 	}
 
-	// 加密密码
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	// 校验密码策略并计算摘要
+	hashedPassword, err := hashNewPassword(s.hasher, s.policy, req.Password)
 	if err != nil {
-		return nil, common.ErrInternal(err)
+		return nil, err
 	}
 
 	u := &user.User{
 		Username: req.Username,
-		Password: string(hashedPassword),
+		Password: hashedPassword,
 		Email:    req.Email,
 		Phone:    req.Phone,
 		Nickname: req.Nickname,
 		Avatar:   req.Avatar,
-		Status:   1,
+		Role:     rbac.RoleUser,
+		Status:   status,
 	}
 
 	if err := s.repo.Create(ctx, u); err != nil {
@@ -68,26 +139,70 @@ func (s *userService) Create(ctx context.Context, req *user.UserCreateRequest) (
 	return u, nil
 }
 
-// GetByID 根据ID获取用户
+// GetByID 根据ID获取用户（已注销的用户视为不存在）
 func (s *userService) GetByID(ctx context.Context, id uint) (*user.User, error) {
-	return s.repo.FindByID(ctx, id)
+	return visibleUser(s.repo.FindByID(ctx, id))
 }
 
-// GetByUsername 根据用户名获取用户
+// GetByUsername 根据用户名获取用户（已注销的用户视为不存在）
 func (s *userService) GetByUsername(ctx context.Context, username string) (*user.User, error) {
-	return s.repo.FindByUsername(ctx, username)
+	return visibleUser(s.repo.FindByUsername(ctx, username))
+}
+
+// visibleUser 过滤已注销的用户
+func visibleUser(u *user.User, err error) (*user.User, error) {
+	if err != nil {
+		return nil, err
+	}
+	if u.Status == user.StatusDeleted {
+		return nil, common.ErrNotFound("用户")
+	}
+	return u, nil
 }
 
 // Update 更新用户
-func (s *userService) Update(ctx context.Context, id uint, req *user.UserUpdateRequest) (*user.User, error) {
-	u, err := s.repo.FindByID(ctx, id)
+// 本人只能修改自己的资料，修改他人需要 user:write 权限，修改状态需要 user:manage 权限
+// 修改邮箱时，待验证的用户直接替换注册邮箱；其他用户的新邮箱记为待验证，验证通过后才生效，
+// 提交当前邮箱时取消待验证的修改
+func (s *userService) Update(ctx context.Context, actor *user.Actor, id uint, req *user.UserUpdateRequest) (*user.User, error) {
+	if err := s.authorize(ctx, actor, id, rbac.PermUserWrite); err != nil {
+		return nil, err
+	}
+	if req.Status != nil {
+		if err := s.requirePermission(ctx, actor, rbac.PermUserManage); err != nil {
+			return nil, err
+		}
+	}
+
+	u, err := s.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	status := u.Status
+	if req.Status != nil {
+		if *req.Status != u.Status && !user.CanTransition(u.Status, *req.Status) {
+			return nil, errStatusTransition(u.Status, *req.Status)
+		}
+		status = *req.Status
+	}
 
+	// 先完成所有校验，再在同一事务中修改状态与资料
 	updates := make(map[string]any)
-	if req.Email != "" {
-		updates["email"] = req.Email
+	switch {
+	case req.Email == "":
+	case req.Email == u.Email:
+		if u.PendingEmail != "" {
+			updates["pending_email"] = ""
+		}
+	default:
+		if err := s.checkEmailAvailable(ctx, req.Email, u.ID); err != nil {
+			return nil, err
+		}
+		if status == user.StatusPending {
+			updates["email"] = req.Email
+		} else {
+			updates["pending_email"] = req.Email
+		}
 	}
 	if req.Phone != "" {
 		updates["phone"] = req.Phone
@@ -98,15 +213,21 @@ func (s *userService) Update(ctx context.Context, id uint, req *user.UserUpdateR
 	if req.Avatar != "" {
 		updates["avatar"] = req.Avatar
 	}
-	if req.Status != nil {
-		updates["status"] = *req.Status
-	}
 
-	if len(updates) == 0 {
+	if status == u.Status && len(updates) == 0 {
 		return u, nil
 	}
 
-	if err := s.repo.Update(ctx, u, updates); err != nil {
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.changeStatus(ctx, u, status); err != nil {
+			return err
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+		return s.repo.Update(ctx, u, updates)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -114,9 +235,17 @@ func (s *userService) Update(ctx context.Context, id uint, req *user.UserUpdateR
 	return s.repo.FindByID(ctx, id)
 }
 
-// Delete 删除用户
-func (s *userService) Delete(ctx context.Context, id uint) error {
-	return s.repo.Delete(ctx, id)
+// Delete 注销用户（本人或拥有 user:delete 权限）
+// 用户记录保留并流转为已注销状态，用户名与邮箱不会被重新占用
+func (s *userService) Delete(ctx context.Context, actor *user.Actor, id uint) error {
+	if err := s.authorize(ctx, actor, id, rbac.PermUserDelete); err != nil {
+		return err
+	}
+	u, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.changeStatus(ctx, u, user.StatusDeleted)
 }
 
 // List 获取用户列表
@@ -148,34 +277,175 @@ func (s *userService) List(ctx context.Context, req *user.UserQueryRequest) ([]*
 }
 
 // Login 用户登录
+// 失败次数按用户名与客户端 IP 统计，超过阈值后暂时锁定
 func (s *userService) Login(ctx context.Context, req *user.LoginRequest) (*user.User, error) {
+	if err := s.limiter.Check(ctx, req.Username, req.ClientIP); err != nil {
+		return nil, err
+	}
+
 	// 根据用户名查询用户
 	u, err := s.repo.FindByUsername(ctx, req.Username)
 	if err != nil {
 		if common.IsAppError(err) {
-			return nil, common.ErrUnauthorized("用户名或密码错误")
+			return nil, s.loginFailed(ctx, req)
 		}
 		return nil, err
 	}
 
 	// 验证密码
 	if err := s.VerifyPassword(u.Password, req.Password); err != nil {
-		return nil, common.ErrUnauthorized("用户名或密码错误")
+		return nil, s.loginFailed(ctx, req)
+	}
+
+	// 检查用户状态，每种不可登录的状态返回各自的错误码
+	if err := u.StatusError(); err != nil {
+		return nil, err
 	}
 
-	// 检查用户状态
-	if u.Status != 1 {
-		return nil, common.ErrForbidden("用户已被禁用")
+	if err := s.limiter.RecordSuccess(ctx, req.Username); err != nil {
+		return nil, err
 	}
 
+	s.rehashIfNeeded(ctx, u, req.Password)
 	return u, nil
 }
 
+// rehashIfNeeded 摘要的算法或参数已过时时，使用本次登录的明文密码重新摘要
+// 重新摘要失败不影响本次登录，下次登录时会再次尝试
+func (s *userService) rehashIfNeeded(ctx context.Context, u *user.User, password string) {
+	if !s.hasher.NeedsRehash(u.Password) {
+		return
+	}
+	hashed, err := s.hasher.Hash(password)
+	if err != nil {
+		return
+	}
+	if err := s.repo.UpdateByID(ctx, u.ID, map[string]any{"password": hashed}); err != nil {
+		return
+	}
+	u.Password = hashed
+}
+
+// Unlock 解除用户的登录锁定，处于锁定状态的用户同时恢复为正常状态
+func (s *userService) Unlock(ctx context.Context, id uint) error {
+	u, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if u.Status == user.StatusLocked {
+		if err := s.changeStatus(ctx, u, user.StatusActive); err != nil {
+			return err
+		}
+	}
+	return s.limiter.Unlock(ctx, u.Username)
+}
+
+// checkEmailAvailable 校验邮箱未被其他用户占用（包括已注销的用户），excludeID 为当前用户
+func (s *userService) checkEmailAvailable(ctx context.Context, email string, excludeID uint) error {
+	taken, err := s.repo.Exists(ctx,
+		common.Where("email", email),
+		common.WhereNot("id", excludeID),
+	)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return common.ErrAlreadyExists("邮箱")
+	}
+	return nil
+}
+
+// changeStatus 按状态机流转用户状态，状态未变化时直接返回
+// 流转为禁用、锁定或注销状态时同时吊销用户的所有会话与 refresh token
+func (s *userService) changeStatus(ctx context.Context, u *user.User, to int) error {
+	if u.Status == to {
+		return nil
+	}
+	if !user.CanTransition(u.Status, to) {
+		return errStatusTransition(u.Status, to)
+	}
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		ok, err := s.repo.UpdateStatus(ctx, u.ID, u.Status, to)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return common.ErrInvalidParam("用户状态已被修改，请刷新后重试")
+		}
+		if to == user.StatusDisabled || to == user.StatusLocked || to == user.StatusDeleted {
+			return s.tokenRepo.RevokeByUserID(ctx, u.ID, time.Now())
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	u.Status = to
+	return nil
+}
+
+// loginFailed 记录登录失败，达到阈值时返回锁定错误，否则返回统一的认证失败错误
+func (s *userService) loginFailed(ctx context.Context, req *user.LoginRequest) error {
+	if err := s.limiter.RecordFailure(ctx, req.Username, req.ClientIP); err != nil {
+		return err
+	}
+	return common.ErrUnauthorized("用户名或密码错误")
+}
+
 // VerifyPassword 验证密码
 func (s *userService) VerifyPassword(hashedPassword, password string) error {
-	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+	return s.hasher.Verify(hashedPassword, password)
+}
+
+// hashNewPassword 校验密码策略后计算摘要，用于设置新密码
+func hashNewPassword(hasher auth.PasswordHasher, policy user.PasswordPolicy, password string) (string, error) {
+	if err := policy.Validate(password); err != nil {
+		return "", err
+	}
+	hashed, err := hasher.Hash(password)
+	if err != nil {
+		if errors.Is(err, auth.ErrPasswordTooLong) {
+			return "", common.ErrInvalidParam("密码过长")
+		}
+		return "", common.ErrInternal(err)
+	}
+	return hashed, nil
+}
+
+// authorize 资源级授权：操作自己的账户总是允许，操作他人需要指定权限
+func (s *userService) authorize(ctx context.Context, actor *user.Actor, targetID uint, permission string) error {
+	if actor == nil {
+		return common.ErrUnauthorized("")
+	}
+	if actor.ID == targetID {
+		return nil
+	}
+	allowed, err := s.permissions.HasPermission(ctx, actor.Role, permission)
 	if err != nil {
 		return err
 	}
+	if !allowed {
+		return common.ErrForbidden("无权操作其他用户")
+	}
 	return nil
 }
+
+// requirePermission 校验操作者拥有指定权限
+func (s *userService) requirePermission(ctx context.Context, actor *user.Actor, permission string) error {
+	if actor == nil {
+		return common.ErrUnauthorized("")
+	}
+	allowed, err := s.permissions.HasPermission(ctx, actor.Role, permission)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return common.ErrForbidden("")
+	}
+	return nil
+}
+
+// errStatusTransition 用户状态不能流转错误
+func errStatusTransition(from, to int) *common.AppError {
+	return common.ErrInvalidParam(fmt.Sprintf("用户状态不能从 %s 变更为 %s", user.StatusName(from), user.StatusName(to)))
+}
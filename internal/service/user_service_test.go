@@ -2,17 +2,36 @@ package service
 
 import (
 	"context"
+	"power-supply-sys/internal/domain/lockout"
+	"power-supply-sys/internal/domain/rbac"
 	"power-supply-sys/internal/domain/user"
 	"power-supply-sys/internal/infra/db"
 	"power-supply-sys/internal/infra/repo"
+	"power-supply-sys/pkg/auth"
 	"power-supply-sys/pkg/common"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// testAdmin 测试用管理员操作者
+var testAdmin = &user.Actor{ID: 0, Role: rbac.RoleAdmin}
+
+// newTestLoginLimiter 创建使用内存存储与默认策略的登录保护
+func newTestLoginLimiter() LoginLimiter {
+	return NewLoginLimiter(repo.NewMemoryLoginAttemptStore(), lockout.DefaultPolicy())
+}
+
+// newTestPasswordHasher 创建使用低开销参数的 argon2id 摘要器
+func newTestPasswordHasher() auth.PasswordHasher {
+	return auth.NewArgon2idHasher(auth.Argon2idParams{Memory: 1024, Iterations: 1, Parallelism: 1})
+}
+
 func TestUserService_Create(t *testing.T) {
 	gormDB := common.SetupTestDB(t)
 	defer common.TeardownTestDB(t, gormDB)
@@ -21,7 +40,7 @@ func TestUserService_Create(t *testing.T) {
 	require.NoError(t, err)
 
 	userRepo := repo.NewUserRepository(gormDB)
-	service := NewUserService(userRepo)
+	service := NewUserService(userRepo, repo.NewRefreshTokenRepository(gormDB), repo.NewRBACRepository(gormDB), newTestLoginLimiter(), common.NewTransactor(gormDB), newTestPasswordHasher(), user.DefaultPasswordPolicy())
 	ctx := context.Background()
 
 	t.Run("成功创建用户", func(t *testing.T) {
@@ -40,9 +59,9 @@ func TestUserService_Create(t *testing.T) {
 		assert.NotEqual(t, req.Password, u.Password) // 密码应该被加密
 		assert.Equal(t, 1, u.Status)
 
-		// 验证密码已加密
-		err = bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(req.Password))
-		assert.NoError(t, err)
+		// 验证密码已使用 argon2id 摘要
+		assert.True(t, strings.HasPrefix(u.Password, "$argon2id$"))
+		assert.NoError(t, newTestPasswordHasher().Verify(u.Password, req.Password))
 	})
 
 	t.Run("创建重复用户名失败", func(t *testing.T) {
@@ -71,7 +90,7 @@ func TestUserService_GetByID(t *testing.T) {
 	require.NoError(t, err)
 
 	userRepo := repo.NewUserRepository(gormDB)
-	service := NewUserService(userRepo)
+	service := NewUserService(userRepo, repo.NewRefreshTokenRepository(gormDB), repo.NewRBACRepository(gormDB), newTestLoginLimiter(), common.NewTransactor(gormDB), newTestPasswordHasher(), user.DefaultPasswordPolicy())
 	ctx := context.Background()
 
 	// 创建测试用户
@@ -106,7 +125,7 @@ func TestUserService_GetByUsername(t *testing.T) {
 	require.NoError(t, err)
 
 	userRepo := repo.NewUserRepository(gormDB)
-	service := NewUserService(userRepo)
+	service := NewUserService(userRepo, repo.NewRefreshTokenRepository(gormDB), repo.NewRBACRepository(gormDB), newTestLoginLimiter(), common.NewTransactor(gormDB), newTestPasswordHasher(), user.DefaultPasswordPolicy())
 	ctx := context.Background()
 
 	// 创建测试用户
@@ -140,7 +159,7 @@ func TestUserService_Update(t *testing.T) {
 	require.NoError(t, err)
 
 	userRepo := repo.NewUserRepository(gormDB)
-	service := NewUserService(userRepo)
+	service := NewUserService(userRepo, repo.NewRefreshTokenRepository(gormDB), repo.NewRBACRepository(gormDB), newTestLoginLimiter(), common.NewTransactor(gormDB), newTestPasswordHasher(), user.DefaultPasswordPolicy())
 	ctx := context.Background()
 
 	// 创建测试用户
@@ -159,18 +178,44 @@ func TestUserService_Update(t *testing.T) {
 			Nickname: "NewNick",
 		}
 
-		updated, err := service.Update(ctx, created.ID, updateReq)
+		updated, err := service.Update(ctx, testAdmin, created.ID, updateReq)
 		assert.NoError(t, err)
 		assert.NotNil(t, updated)
-		assert.Equal(t, "new@example.com", updated.Email)
 		assert.Equal(t, "NewNick", updated.Nickname)
+		// 新邮箱验证通过前不生效
+		assert.Equal(t, "old@example.com", updated.Email)
+		assert.Equal(t, "new@example.com", updated.PendingEmail)
+	})
+
+	t.Run("邮箱已被其他用户占用", func(t *testing.T) {
+		_, err := service.Create(ctx, &user.UserCreateRequest{Username: "otheruser", Password: "password123", Email: "taken@example.com"})
+		require.NoError(t, err)
+
+		_, err = service.Update(ctx, testAdmin, created.ID, &user.UserUpdateRequest{Email: "taken@example.com"})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeAlreadyExists))
+
+		// 提交本人当前的邮箱不视为冲突，并取消待验证的修改
+		updated, err := service.Update(ctx, testAdmin, created.ID, &user.UserUpdateRequest{Email: "old@example.com"})
+		require.NoError(t, err)
+		assert.Equal(t, "old@example.com", updated.Email)
+		assert.Empty(t, updated.PendingEmail)
+	})
+
+	t.Run("校验失败时不修改状态", func(t *testing.T) {
+		disabled := user.StatusDisabled
+		_, err := service.Update(ctx, testAdmin, created.ID, &user.UserUpdateRequest{Email: "taken@example.com", Status: &disabled})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeAlreadyExists))
+
+		found, err := service.GetByID(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, user.StatusActive, found.Status)
 	})
 
 	t.Run("更新不存在的用户", func(t *testing.T) {
 		updateReq := &user.UserUpdateRequest{
 			Email: "test@example.com",
 		}
-		_, err := service.Update(ctx, 99999, updateReq)
+		_, err := service.Update(ctx, testAdmin, 99999, updateReq)
 		assert.Error(t, err)
 	})
 }
@@ -183,7 +228,7 @@ func TestUserService_Delete(t *testing.T) {
 	require.NoError(t, err)
 
 	userRepo := repo.NewUserRepository(gormDB)
-	service := NewUserService(userRepo)
+	service := NewUserService(userRepo, repo.NewRefreshTokenRepository(gormDB), repo.NewRBACRepository(gormDB), newTestLoginLimiter(), common.NewTransactor(gormDB), newTestPasswordHasher(), user.DefaultPasswordPolicy())
 	ctx := context.Background()
 
 	// 创建测试用户
@@ -195,7 +240,7 @@ func TestUserService_Delete(t *testing.T) {
 	require.NoError(t, err)
 
 	t.Run("成功删除用户", func(t *testing.T) {
-		err := service.Delete(ctx, created.ID)
+		err := service.Delete(ctx, testAdmin, created.ID)
 		assert.NoError(t, err)
 
 		// 验证已删除
@@ -204,6 +249,47 @@ func TestUserService_Delete(t *testing.T) {
 	})
 }
 
+func TestUserService_StatusChangeRevokesSessions(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	err := db.Migrate(gormDB)
+	require.NoError(t, err)
+
+	userRepo := repo.NewUserRepository(gormDB)
+	tokenRepo := repo.NewRefreshTokenRepository(gormDB)
+	transactor := common.NewTransactor(gormDB)
+	service := NewUserService(userRepo, tokenRepo, repo.NewRBACRepository(gormDB), newTestLoginLimiter(), transactor, newTestPasswordHasher(), user.DefaultPasswordPolicy())
+	authSvc := NewAuthService(tokenRepo, userRepo, auth.NewJWTManager("test-secret", 15*time.Minute), transactor, time.Hour)
+	ctx := context.Background()
+
+	for _, status := range []int{user.StatusDisabled, user.StatusLocked, user.StatusDeleted} {
+		t.Run(user.StatusName(status), func(t *testing.T) {
+			u, err := service.Create(ctx, &user.UserCreateRequest{
+				Username: "revoke" + strconv.Itoa(status),
+				Password: "password123",
+				Email:    "revoke" + strconv.Itoa(status) + "@example.com",
+			})
+			require.NoError(t, err)
+			pair, err := authSvc.IssueTokens(ctx, u, nil)
+			require.NoError(t, err)
+
+			if status == user.StatusDeleted {
+				err = service.Delete(ctx, testAdmin, u.ID)
+			} else {
+				_, err = service.Update(ctx, testAdmin, u.ID, &user.UserUpdateRequest{Status: &status})
+			}
+			require.NoError(t, err)
+
+			active, err := authSvc.IsSessionActive(ctx, pair.SessionID)
+			require.NoError(t, err)
+			assert.False(t, active)
+			_, err = authSvc.Refresh(ctx, pair.RefreshToken)
+			assert.Error(t, err)
+		})
+	}
+}
+
 func TestUserService_List(t *testing.T) {
 	gormDB := common.SetupTestDB(t)
 	defer common.TeardownTestDB(t, gormDB)
@@ -212,14 +298,14 @@ func TestUserService_List(t *testing.T) {
 	require.NoError(t, err)
 
 	userRepo := repo.NewUserRepository(gormDB)
-	service := NewUserService(userRepo)
+	service := NewUserService(userRepo, repo.NewRefreshTokenRepository(gormDB), repo.NewRBACRepository(gormDB), newTestLoginLimiter(), common.NewTransactor(gormDB), newTestPasswordHasher(), user.DefaultPasswordPolicy())
 	ctx := context.Background()
 
 	// 创建多个测试用户
 	users := []*user.UserCreateRequest{
-		{Username: "listuser1", Password: "pwd", Email: "list1@test.com"},
-		{Username: "listuser2", Password: "pwd", Email: "list2@test.com"},
-		{Username: "listuser3", Password: "pwd", Email: "list3@test.com"},
+		{Username: "listuser1", Password: "password123", Email: "list1@test.com"},
+		{Username: "listuser2", Password: "password123", Email: "list2@test.com"},
+		{Username: "listuser3", Password: "password123", Email: "list3@test.com"},
 	}
 
 	for _, req := range users {
@@ -267,7 +353,7 @@ func TestUserService_Login(t *testing.T) {
 	require.NoError(t, err)
 
 	userRepo := repo.NewUserRepository(gormDB)
-	service := NewUserService(userRepo)
+	service := NewUserService(userRepo, repo.NewRefreshTokenRepository(gormDB), repo.NewRBACRepository(gormDB), newTestLoginLimiter(), common.NewTransactor(gormDB), newTestPasswordHasher(), user.DefaultPasswordPolicy())
 	ctx := context.Background()
 
 	// 创建测试用户
@@ -329,7 +415,7 @@ func TestUserService_Login(t *testing.T) {
 		updateReq := &user.UserUpdateRequest{
 			Status: &status,
 		}
-		_, err = service.Update(ctx, created.ID, updateReq)
+		_, err = service.Update(ctx, testAdmin, created.ID, updateReq)
 		require.NoError(t, err)
 
 		// 尝试登录
@@ -345,8 +431,71 @@ func TestUserService_Login(t *testing.T) {
 	})
 }
 
+func TestUserService_LoginLockout(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	err := db.Migrate(gormDB)
+	require.NoError(t, err)
+
+	userRepo := repo.NewUserRepository(gormDB)
+	policy := lockout.Policy{MaxFailures: 3, IPMaxFailures: 100, Window: time.Minute, LockDuration: time.Minute}
+	service := NewUserService(userRepo, repo.NewRefreshTokenRepository(gormDB), repo.NewRBACRepository(gormDB), NewLoginLimiter(repo.NewMemoryLoginAttemptStore(), policy), common.NewTransactor(gormDB), newTestPasswordHasher(), user.DefaultPasswordPolicy())
+	ctx := context.Background()
+
+	created, err := service.Create(ctx, &user.UserCreateRequest{Username: "lockuser", Password: "correctpassword"})
+	require.NoError(t, err)
+
+	wrong := &user.LoginRequest{Username: "lockuser", Password: "wrongpassword", ClientIP: "10.0.0.1"}
+	correct := &user.LoginRequest{Username: "lockuser", Password: "correctpassword", ClientIP: "10.0.0.1"}
+
+	t.Run("成功登录清除失败计数", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			_, err := service.Login(ctx, wrong)
+			assert.True(t, common.HasErrorCode(err, common.ErrCodeUnauthorized))
+		}
+		_, err := service.Login(ctx, correct)
+		require.NoError(t, err)
+
+		// 计数已清除，再失败两次也不会锁定
+		for i := 0; i < 2; i++ {
+			_, err := service.Login(ctx, wrong)
+			assert.True(t, common.HasErrorCode(err, common.ErrCodeUnauthorized))
+		}
+		_, err = service.Login(ctx, correct)
+		require.NoError(t, err)
+	})
+
+	t.Run("连续失败后锁定账户", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			_, err := service.Login(ctx, wrong)
+			assert.True(t, common.HasErrorCode(err, common.ErrCodeUnauthorized))
+		}
+		_, err := service.Login(ctx, wrong)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeAccountLocked))
+
+		// 锁定期间正确密码也无法登录
+		_, err = service.Login(ctx, correct)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeAccountLocked))
+	})
+
+	t.Run("管理员解锁后可以登录", func(t *testing.T) {
+		err := service.Unlock(ctx, created.ID)
+		require.NoError(t, err)
+
+		u, err := service.Login(ctx, correct)
+		require.NoError(t, err)
+		assert.Equal(t, created.ID, u.ID)
+	})
+
+	t.Run("解锁不存在的用户", func(t *testing.T) {
+		err := service.Unlock(ctx, 99999)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+}
+
 func TestUserService_VerifyPassword(t *testing.T) {
-	service := &userService{}
+	service := &userService{hasher: newTestPasswordHasher()}
 
 	t.Run("验证正确密码", func(t *testing.T) {
 		password := "testpassword"
@@ -366,3 +515,196 @@ func TestUserService_VerifyPassword(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestUserService_StatusTransitions(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	err := db.Migrate(gormDB)
+	require.NoError(t, err)
+
+	userRepo := repo.NewUserRepository(gormDB)
+	service := NewUserService(userRepo, repo.NewRefreshTokenRepository(gormDB), repo.NewRBACRepository(gormDB), newTestLoginLimiter(), common.NewTransactor(gormDB), newTestPasswordHasher(), user.DefaultPasswordPolicy())
+	ctx := context.Background()
+
+	u, err := service.Create(ctx, &user.UserCreateRequest{Username: "stateuser", Password: "password123", Email: "state@example.com"})
+	require.NoError(t, err)
+	login := &user.LoginRequest{Username: "stateuser", Password: "password123"}
+	setStatus := func(status int) error {
+		_, err := service.Update(ctx, testAdmin, u.ID, &user.UserUpdateRequest{Status: &status})
+		return err
+	}
+
+	t.Run("锁定后返回账户锁定错误码，解锁后恢复", func(t *testing.T) {
+		require.NoError(t, setStatus(user.StatusLocked))
+		_, err := service.Login(ctx, login)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeAccountLocked))
+
+		require.NoError(t, service.Unlock(ctx, u.ID))
+		_, err = service.Login(ctx, login)
+		assert.NoError(t, err)
+	})
+
+	t.Run("禁用后返回账户禁用错误码", func(t *testing.T) {
+		require.NoError(t, setStatus(user.StatusDisabled))
+		_, err := service.Login(ctx, login)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeAccountDisabled))
+	})
+
+	t.Run("不允许的状态流转", func(t *testing.T) {
+		// 禁用的用户不能直接锁定，也不能回到待验证
+		err := setStatus(user.StatusLocked)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+		err = setStatus(user.StatusPending)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+
+		_, err = service.Activate(ctx, u.ID)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+	})
+
+	t.Run("注销后记录保留但不可见且不能恢复", func(t *testing.T) {
+		require.NoError(t, service.Delete(ctx, testAdmin, u.ID))
+
+		_, err := service.GetByID(ctx, u.ID)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+		stored, err := userRepo.FindByID(ctx, u.ID)
+		require.NoError(t, err)
+		assert.Equal(t, user.StatusDeleted, stored.Status)
+
+		_, err = service.Login(ctx, login)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeAccountDeleted))
+
+		err = setStatus(user.StatusActive)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+
+		users, _, err := service.List(ctx, &user.UserQueryRequest{})
+		require.NoError(t, err)
+		assert.Empty(t, users)
+	})
+}
+
+func TestUserService_PasswordPolicy(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	err := db.Migrate(gormDB)
+	require.NoError(t, err)
+
+	policy := user.DefaultPasswordPolicy()
+	policy.RequireUpper = true
+	policy.RequireDigit = true
+	policy.Breached = map[string]struct{}{"password1a": {}}
+	service := NewUserService(repo.NewUserRepository(gormDB), repo.NewRefreshTokenRepository(gormDB), repo.NewRBACRepository(gormDB), newTestLoginLimiter(), common.NewTransactor(gormDB), newTestPasswordHasher(), policy)
+	ctx := context.Background()
+
+	rejected := map[string]string{
+		"长度不足":   "Ab1",
+		"缺少大写字母": "password123",
+		"缺少数字":   "PasswordABC",
+		"已泄露密码":  "Password1A",
+	}
+	for name, password := range rejected {
+		t.Run(name, func(t *testing.T) {
+			_, err := service.Create(ctx, &user.UserCreateRequest{Username: "policyuser", Password: password, Email: "policy@example.com"})
+			assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+		})
+	}
+
+	t.Run("符合策略", func(t *testing.T) {
+		_, err := service.Create(ctx, &user.UserCreateRequest{Username: "policyuser", Password: "Password123", Email: "policy@example.com"})
+		assert.NoError(t, err)
+	})
+}
+
+func TestUserService_LoginRehash(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	err := db.Migrate(gormDB)
+	require.NoError(t, err)
+
+	userRepo := repo.NewUserRepository(gormDB)
+	service := NewUserService(userRepo, repo.NewRefreshTokenRepository(gormDB), repo.NewRBACRepository(gormDB), newTestLoginLimiter(), common.NewTransactor(gormDB), newTestPasswordHasher(), user.DefaultPasswordPolicy())
+	ctx := context.Background()
+
+	// 历史用户使用 bcrypt 摘要
+	legacy, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	require.NoError(t, err)
+	u := &user.User{Username: "legacyuser", Password: string(legacy), Email: "legacy@example.com", Role: rbac.RoleUser, Status: 1}
+	require.NoError(t, userRepo.Create(ctx, u))
+
+	t.Run("密码错误时不重新摘要", func(t *testing.T) {
+		_, err := service.Login(ctx, &user.LoginRequest{Username: "legacyuser", Password: "wrongpassword"})
+		assert.Error(t, err)
+
+		stored, err := userRepo.FindByID(ctx, u.ID)
+		require.NoError(t, err)
+		assert.Equal(t, string(legacy), stored.Password)
+	})
+
+	t.Run("登录成功后升级为 argon2id", func(t *testing.T) {
+		_, err := service.Login(ctx, &user.LoginRequest{Username: "legacyuser", Password: "password123"})
+		require.NoError(t, err)
+
+		stored, err := userRepo.FindByID(ctx, u.ID)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(stored.Password, "$argon2id$"))
+		assert.False(t, newTestPasswordHasher().NeedsRehash(stored.Password))
+
+		_, err = service.Login(ctx, &user.LoginRequest{Username: "legacyuser", Password: "password123"})
+		assert.NoError(t, err)
+	})
+}
+
+func TestUserService_UpdateAuthorization(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	err := db.Migrate(gormDB)
+	require.NoError(t, err)
+
+	userRepo := repo.NewUserRepository(gormDB)
+	service := NewUserService(userRepo, repo.NewRefreshTokenRepository(gormDB), repo.NewRBACRepository(gormDB), newTestLoginLimiter(), common.NewTransactor(gormDB), newTestPasswordHasher(), user.DefaultPasswordPolicy())
+	ctx := context.Background()
+
+	alice, err := service.Create(ctx, &user.UserCreateRequest{Username: "alice", Password: "password123", Email: "alice@example.com"})
+	require.NoError(t, err)
+	bob, err := service.Create(ctx, &user.UserCreateRequest{Username: "bob", Password: "password123", Email: "bob@example.com"})
+	require.NoError(t, err)
+
+	aliceActor := &user.Actor{ID: alice.ID, Role: rbac.RoleUser}
+
+	t.Run("本人修改自己的资料", func(t *testing.T) {
+		updated, err := service.Update(ctx, aliceActor, alice.ID, &user.UserUpdateRequest{Nickname: "Alice"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Alice", updated.Nickname)
+	})
+
+	t.Run("普通用户不能修改他人资料", func(t *testing.T) {
+		_, err := service.Update(ctx, aliceActor, bob.ID, &user.UserUpdateRequest{Nickname: "Hacked"})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeForbidden))
+	})
+
+	t.Run("普通用户不能修改自己的状态", func(t *testing.T) {
+		status := 0
+		_, err := service.Update(ctx, aliceActor, alice.ID, &user.UserUpdateRequest{Status: &status})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeForbidden))
+	})
+
+	t.Run("管理员可以修改他人状态", func(t *testing.T) {
+		status := 0
+		updated, err := service.Update(ctx, testAdmin, bob.ID, &user.UserUpdateRequest{Status: &status})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, updated.Status)
+	})
+
+	t.Run("未认证的操作者", func(t *testing.T) {
+		_, err := service.Update(ctx, nil, alice.ID, &user.UserUpdateRequest{Nickname: "Nobody"})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeUnauthorized))
+	})
+
+	t.Run("普通用户不能删除他人", func(t *testing.T) {
+		err := service.Delete(ctx, aliceActor, bob.ID)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeForbidden))
+	})
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/warehouse"
+	"power-supply-sys/pkg/common"
+	"strings"
+)
+
+// WarehouseService 仓库服务接口
+type WarehouseService interface {
+	Create(ctx context.Context, req *warehouse.CreateRequest) (*warehouse.Warehouse, error)
+	GetByID(ctx context.Context, id uint) (*warehouse.Warehouse, error)
+	Update(ctx context.Context, id uint, req *warehouse.UpdateRequest) (*warehouse.Warehouse, error)
+	List(ctx context.Context) ([]*warehouse.Warehouse, error)
+}
+
+// warehouseService 仓库服务实现
+type warehouseService struct {
+	repo       warehouse.Repository
+	transactor common.Transactor
+}
+
+var _ WarehouseService = &warehouseService{}
+
+// NewWarehouseService 创建仓库服务
+func NewWarehouseService(repo warehouse.Repository, transactor common.Transactor) WarehouseService {
+	return &warehouseService{
+		repo:       repo,
+		transactor: transactor,
+	}
+}
+
+// Create 创建仓库，编码统一转为大写且不能重复
+func (s *warehouseService) Create(ctx context.Context, req *warehouse.CreateRequest) (*warehouse.Warehouse, error) {
+	code := strings.ToUpper(strings.TrimSpace(req.Code))
+	if code == "" {
+		return nil, common.ErrInvalidParam("仓库编码不能为空")
+	}
+	exists, err := s.repo.ExistsByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, common.ErrAlreadyExists("仓库编码")
+	}
+
+	w := &warehouse.Warehouse{
+		Code:    code,
+		Name:    req.Name,
+		Address: req.Address,
+	}
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, w); err != nil {
+			return err
+		}
+		if req.IsDefault {
+			return s.repo.SetDefault(ctx, w.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, w.ID)
+}
+
+// GetByID 获取仓库
+func (s *warehouseService) GetByID(ctx context.Context, id uint) (*warehouse.Warehouse, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// Update 更新仓库
+// 只能将仓库设为默认仓库（同时取消原默认仓库），不能直接取消默认仓库
+func (s *warehouseService) Update(ctx context.Context, id uint, req *warehouse.UpdateRequest) (*warehouse.Warehouse, error) {
+	w, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.IsDefault != nil && !*req.IsDefault && w.IsDefault {
+		return nil, common.ErrInvalidParam("请将其他仓库设为默认仓库")
+	}
+
+	updates := make(map[string]any)
+	if req.Name != "" {
+		updates["name"] = req.Name
+	}
+	if req.Address != nil {
+		updates["address"] = *req.Address
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if len(updates) > 0 {
+			if err := s.repo.UpdateByID(ctx, id, updates); err != nil {
+				return err
+			}
+		}
+		if req.IsDefault != nil && *req.IsDefault && !w.IsDefault {
+			return s.repo.SetDefault(ctx, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, id)
+}
+
+// List 获取全部仓库
+func (s *warehouseService) List(ctx context.Context) ([]*warehouse.Warehouse, error) {
+	return s.repo.List(ctx)
+}
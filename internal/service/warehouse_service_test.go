@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/warehouse"
+	"power-supply-sys/internal/infra/db"
+	"power-supply-sys/internal/infra/repo"
+	"power-supply-sys/pkg/common"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarehouseService(t *testing.T) {
+	gormDB := common.SetupTestDB(t)
+	defer common.TeardownTestDB(t, gormDB)
+
+	err := db.Migrate(gormDB)
+	require.NoError(t, err)
+
+	svc := NewWarehouseService(repo.NewWarehouseRepository(gormDB), common.NewTransactor(gormDB))
+	ctx := context.Background()
+
+	var created *warehouse.Warehouse
+
+	t.Run("创建仓库", func(t *testing.T) {
+		created, err = svc.Create(ctx, &warehouse.CreateRequest{Code: "bj", Name: "北京仓", Address: "北京市"})
+		require.NoError(t, err)
+		assert.Equal(t, "BJ", created.Code)
+		assert.False(t, created.IsDefault)
+
+		_, err = svc.Create(ctx, &warehouse.CreateRequest{Code: "BJ", Name: "北京二仓"})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeAlreadyExists))
+	})
+
+	t.Run("设为默认仓库", func(t *testing.T) {
+		isDefault := true
+		updated, err := svc.Update(ctx, created.ID, &warehouse.UpdateRequest{Name: "北京总仓", IsDefault: &isDefault})
+		require.NoError(t, err)
+		assert.Equal(t, "北京总仓", updated.Name)
+		assert.True(t, updated.IsDefault)
+
+		list, err := svc.List(ctx)
+		require.NoError(t, err)
+		defaults := 0
+		for _, w := range list {
+			if w.IsDefault {
+				defaults++
+			}
+		}
+		assert.Equal(t, 1, defaults)
+	})
+
+	t.Run("不能直接取消默认仓库", func(t *testing.T) {
+		isDefault := false
+		_, err := svc.Update(ctx, created.ID, &warehouse.UpdateRequest{IsDefault: &isDefault})
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeInvalidParam))
+	})
+
+	t.Run("仓库不存在", func(t *testing.T) {
+		_, err := svc.GetByID(ctx, 9999)
+		assert.True(t, common.HasErrorCode(err, common.ErrCodeNotFound))
+	})
+}
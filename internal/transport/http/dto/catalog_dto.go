@@ -0,0 +1,27 @@
+package dto
+
+// CategoryCreateRequest 创建分类请求
+type CategoryCreateRequest struct {
+	ParentID  *uint  `json:"parent_id" binding:"omitempty"` // 为空或为 0 时创建顶级分类
+	Name      string `json:"name" binding:"required,max=50"`
+	SortOrder int    `json:"sort_order"`
+}
+
+// CategoryUpdateRequest 更新分类请求
+type CategoryUpdateRequest struct {
+	Name      string `json:"name" binding:"omitempty,max=50"`
+	ParentID  *uint  `json:"parent_id" binding:"omitempty"` // 不为空时移动分类，为 0 时移动为顶级分类
+	SortOrder *int   `json:"sort_order"`
+}
+
+// TagRequest 创建或重命名标签请求
+type TagRequest struct {
+	Name string `json:"name" binding:"required,max=50"`
+}
+
+// TagQueryRequest 查询标签请求
+type TagQueryRequest struct {
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Name     string `form:"name" binding:"omitempty"`
+}
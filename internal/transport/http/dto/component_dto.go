@@ -0,0 +1,32 @@
+package dto
+
+// ComponentCreateRequest 创建组件功耗档案请求
+type ComponentCreateRequest struct {
+	Type            string  `json:"type" binding:"required,oneof=cpu gpu"`
+	Name            string  `json:"name" binding:"required,max=100"`
+	Brand           string  `json:"brand" binding:"omitempty,max=50"`
+	Watts           int     `json:"watts" binding:"required,min=1,max=2000"`
+	TransientFactor float64 `json:"transient_factor" binding:"omitempty,min=1,max=3"`
+}
+
+// ComponentUpdateRequest 更新组件功耗档案请求
+type ComponentUpdateRequest struct {
+	Name            string   `json:"name" binding:"omitempty,max=100"`
+	Brand           *string  `json:"brand" binding:"omitempty,max=50"`
+	Watts           *int     `json:"watts" binding:"omitempty,min=1,max=2000"`
+	TransientFactor *float64 `json:"transient_factor" binding:"omitempty,min=0,max=3"`
+}
+
+// ComponentImportRequest 批量导入组件功耗档案请求
+type ComponentImportRequest struct {
+	Components []*ComponentCreateRequest `json:"components" binding:"required,min=1,max=500,dive"`
+}
+
+// ComponentQueryRequest 查询组件功耗档案请求
+type ComponentQueryRequest struct {
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Type     string `form:"type" binding:"omitempty,oneof=cpu gpu"`
+	Name     string `form:"name" binding:"omitempty"`
+	Brand    string `form:"brand" binding:"omitempty"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+// EmailVerifyRequest 验证邮箱请求
+type EmailVerifyRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// EmailVerificationResendRequest 重新发送验证邮件请求
+type EmailVerificationResendRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
@@ -0,0 +1,17 @@
+package dto
+
+import "power-supply-sys/internal/domain/user"
+
+// ImpersonateResponse 模拟登录响应（不签发刷新令牌）
+type ImpersonateResponse struct {
+	Token        string        `json:"token"`
+	ExpiresIn    int64         `json:"expires_in"`
+	User         *user.User    `json:"user"`
+	Impersonator *Impersonator `json:"impersonator"`
+}
+
+// Impersonator 发起模拟的管理员
+type Impersonator struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+}
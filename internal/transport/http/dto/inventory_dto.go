@@ -0,0 +1,28 @@
+package dto
+
+// StockMovementCreateRequest 登记库存流水请求
+// 入库、销售、退货的 quantity 为正数；盘点调整的 quantity 为带符号的变动数量
+type StockMovementCreateRequest struct {
+	Type        string `json:"type" binding:"required,oneof=receipt sale adjustment return"`
+	Quantity    int    `json:"quantity" binding:"required"`
+	WarehouseID uint   `json:"warehouse_id" binding:"omitempty"`
+	Reason      string `json:"reason" binding:"omitempty,max=255"`
+	Reference   string `json:"reference" binding:"omitempty,max=100"`
+}
+
+// StockMovementQueryRequest 查询库存流水请求
+type StockMovementQueryRequest struct {
+	Page        int    `form:"page" binding:"omitempty,min=1"`
+	PageSize    int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Type        string `form:"type" binding:"omitempty,oneof=receipt sale adjustment return transfer"`
+	WarehouseID uint   `form:"warehouse_id" binding:"omitempty"`
+}
+
+// StockTransferRequest 仓库调拨请求
+type StockTransferRequest struct {
+	FromWarehouseID uint   `json:"from_warehouse_id" binding:"required"`
+	ToWarehouseID   uint   `json:"to_warehouse_id" binding:"required"`
+	Quantity        int    `json:"quantity" binding:"required,min=1"`
+	Reason          string `json:"reason" binding:"omitempty,max=255"`
+	Reference       string `json:"reference" binding:"omitempty,max=100"`
+}
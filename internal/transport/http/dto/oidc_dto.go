@@ -0,0 +1,20 @@
+package dto
+
+// OIDCLoginRequest 发起单点登录请求
+type OIDCLoginRequest struct {
+	Provider string `form:"provider" binding:"required"`
+}
+
+// OIDCCallbackRequest 身份提供方回调参数
+type OIDCCallbackRequest struct {
+	Code             string `form:"code"`
+	State            string `form:"state"`
+	Error            string `form:"error"`
+	ErrorDescription string `form:"error_description"`
+}
+
+// OIDCLoginResponse 发起单点登录响应
+type OIDCLoginResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+	ExpiresIn        int64  `json:"expires_in"`
+}
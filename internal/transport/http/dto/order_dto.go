@@ -0,0 +1,21 @@
+package dto
+
+// OrderItemRequest 订单明细请求
+type OrderItemRequest struct {
+	PowerSupplyID uint `json:"power_supply_id" binding:"required"`
+	Quantity      int  `json:"quantity" binding:"required,min=1"`
+}
+
+// OrderCreateRequest 创建订单请求
+type OrderCreateRequest struct {
+	Items  []OrderItemRequest `json:"items" binding:"required,min=1,max=50,dive"`
+	Remark string             `json:"remark" binding:"omitempty,max=255"`
+}
+
+// OrderQueryRequest 查询订单请求
+type OrderQueryRequest struct {
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+	UserID   *uint  `form:"user_id" binding:"omitempty"`
+	Status   string `form:"status" binding:"omitempty,oneof=created paid shipped completed cancelled refunded"`
+}
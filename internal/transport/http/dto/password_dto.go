@@ -0,0 +1,18 @@
+package dto
+
+// PasswordChangeRequest 修改密码请求
+type PasswordChangeRequest struct {
+	OldPassword string `json:"old_password" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+// PasswordForgotRequest 找回密码请求
+type PasswordForgotRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// PasswordResetRequest 重置密码请求
+type PasswordResetRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
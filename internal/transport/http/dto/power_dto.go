@@ -1,43 +1,95 @@
 package dto
 
+import "time"
+
 // PowerSupplyCreateRequest 创建电源请求（DTO 移至传输层）
 type PowerSupplyCreateRequest struct {
-	Name        string  `json:"name" binding:"required,min=1,max=100"`
-	Brand       string  `json:"brand" binding:"omitempty,max=50"`
-	Model       string  `json:"model" binding:"omitempty,max=50"`
-	Power       int     `json:"power" binding:"required,min=0"`
-	Efficiency  string  `json:"efficiency" binding:"omitempty,max=20"`
-	Modular     bool    `json:"modular"`
-	Price       float64 `json:"price" binding:"required,min=0"`
-	Stock       int     `json:"stock" binding:"omitempty,min=0"`
-	Description string  `json:"description" binding:"omitempty"`
+	Name        string                  `json:"name" binding:"required,min=1,max=100"`
+	Brand       string                  `json:"brand" binding:"omitempty,max=50"`
+	Model       string                  `json:"model" binding:"omitempty,max=50"`
+	Power       int                     `json:"power" binding:"required,min=0"`
+	Efficiency  string                  `json:"efficiency" binding:"omitempty,max=30"`
+	Modular     bool                    `json:"modular"`
+	Spec        *PowerSupplySpecRequest `json:"spec"`
+	Price       float64                 `json:"price" binding:"required,min=0"`
+	Stock       int                     `json:"stock" binding:"omitempty,min=0"`
+	WarehouseID uint                    `json:"warehouse_id" binding:"omitempty"`
+	Description string                  `json:"description" binding:"omitempty"`
+	CategoryID  *uint                   `json:"category_id" binding:"omitempty"`
+	Tags        []string                `json:"tags" binding:"omitempty,max=20,dive,max=50"` // 标签名称，不存在的标签自动创建
 }
 
 // PowerSupplyUpdateRequest 更新电源请求
 type PowerSupplyUpdateRequest struct {
-	Name        string   `json:"name" binding:"omitempty,min=1,max=100"`
-	Brand       string   `json:"brand" binding:"omitempty,max=50"`
-	Model       string   `json:"model" binding:"omitempty,max=50"`
-	Power       *int     `json:"power" binding:"omitempty,min=0"`
-	Efficiency  string   `json:"efficiency" binding:"omitempty,max=20"`
-	Modular     *bool    `json:"modular"`
-	Price       *float64 `json:"price" binding:"omitempty,min=0"`
-	Stock       *int     `json:"stock" binding:"omitempty,min=0"`
-	Description string   `json:"description" binding:"omitempty"`
-	Status      *int     `json:"status" binding:"omitempty,oneof=0 1"`
+	Name        string                  `json:"name" binding:"omitempty,min=1,max=100"`
+	Brand       string                  `json:"brand" binding:"omitempty,max=50"`
+	Model       string                  `json:"model" binding:"omitempty,max=50"`
+	Power       *int                    `json:"power" binding:"omitempty,min=0"`
+	Efficiency  string                  `json:"efficiency" binding:"omitempty,max=30"`
+	Modular     *bool                   `json:"modular"`
+	Spec        *PowerSupplySpecRequest `json:"spec"` // 不为空时整体替换技术规格
+	Price       *float64                `json:"price" binding:"omitempty,min=0"`
+	Stock       *int                    `json:"stock" binding:"omitempty,min=0"`
+	Description string                  `json:"description" binding:"omitempty"`
+	Status      *int                    `json:"status" binding:"omitempty,oneof=0 1"`
+	CategoryID  *uint                   `json:"category_id" binding:"omitempty"`             // 为 0 时清除分类
+	Tags        *[]string               `json:"tags" binding:"omitempty,max=20,dive,max=50"` // 不为空时整体替换标签
+}
+
+// PowerSupplySpecRequest 电源技术规格，数值为 0 表示未填写
+type PowerSupplySpecRequest struct {
+	FormFactor         string  `json:"form_factor" binding:"omitempty,oneof=ATX SFX SFX-L TFX"`
+	ATX3               bool    `json:"atx3"`
+	Connectors12VHPWR  int     `json:"connectors_12vhpwr" binding:"min=0,max=4"`
+	EPSConnectors      int     `json:"eps_connectors" binding:"min=0,max=8"`
+	PCIe8PinConnectors int     `json:"pcie_8pin_connectors" binding:"min=0,max=16"`
+	SATAConnectors     int     `json:"sata_connectors" binding:"min=0,max=32"`
+	MolexConnectors    int     `json:"molex_connectors" binding:"min=0,max=16"`
+	Rail12VAmps        float64 `json:"rail_12v_amps" binding:"min=0,max=1000"`
+	FanSizeMM          int     `json:"fan_size_mm" binding:"min=0,max=200"`
+	NoiseDBA           float64 `json:"noise_dba" binding:"min=0,max=100"`
+	LengthMM           int     `json:"length_mm" binding:"min=0,max=500"`
+	WidthMM            int     `json:"width_mm" binding:"min=0,max=300"`
+	HeightMM           int     `json:"height_mm" binding:"min=0,max=300"`
 }
 
 // PowerSupplyQueryRequest 查询电源请求
 type PowerSupplyQueryRequest struct {
-	Page       int      `form:"page" binding:"omitempty,min=1"`
-	PageSize   int      `form:"page_size" binding:"omitempty,min=1,max=100"`
-	Name       string   `form:"name" binding:"omitempty"`
-	Brand      string   `form:"brand" binding:"omitempty"`
-	MinPower   *int     `form:"min_power" binding:"omitempty,min=0"`
-	MaxPower   *int     `form:"max_power" binding:"omitempty,min=0"`
-	MinPrice   *float64 `form:"min_price" binding:"omitempty,min=0"`
-	MaxPrice   *float64 `form:"max_price" binding:"omitempty,min=0"`
-	Efficiency string   `form:"efficiency" binding:"omitempty"`
-	Status     *int     `form:"status" binding:"omitempty,oneof=0 1"`
+	Page          int        `form:"page" binding:"omitempty,min=1"`
+	PageSize      int        `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Name          string     `form:"name" binding:"omitempty"`
+	Brand         string     `form:"brand" binding:"omitempty"`
+	MinPower      *int       `form:"min_power" binding:"omitempty,min=0"`
+	MaxPower      *int       `form:"max_power" binding:"omitempty,min=0"`
+	MinPrice      *float64   `form:"min_price" binding:"omitempty,min=0"`
+	MaxPrice      *float64   `form:"max_price" binding:"omitempty,min=0"`
+	Efficiency    string     `form:"efficiency" binding:"omitempty"`
+	MinEfficiency string     `form:"min_efficiency" binding:"omitempty"`
+	MaxEfficiency string     `form:"max_efficiency" binding:"omitempty"`
+	Status        *int       `form:"status" binding:"omitempty,oneof=0 1"`
+	WarehouseID   *uint      `form:"warehouse_id" binding:"omitempty"`
+	InStock       *bool      `form:"in_stock" binding:"omitempty"`
+	FormFactor    string     `form:"form_factor" binding:"omitempty,oneof=ATX SFX SFX-L TFX"`
+	ATX3          *bool      `form:"atx3" binding:"omitempty"`
+	Min12VHPWR    *int       `form:"min_12vhpwr" binding:"omitempty,min=0"`
+	MinEPS        *int       `form:"min_eps" binding:"omitempty,min=0"`
+	MinPCIe8Pin   *int       `form:"min_pcie_8pin" binding:"omitempty,min=0"`
+	MinSATA       *int       `form:"min_sata" binding:"omitempty,min=0"`
+	MinMolex      *int       `form:"min_molex" binding:"omitempty,min=0"`
+	Min12VAmps    *float64   `form:"min_12v_amps" binding:"omitempty,min=0"`
+	FanSize       *int       `form:"fan_size" binding:"omitempty,min=0"`
+	MaxNoise      *float64   `form:"max_noise" binding:"omitempty,min=0"`
+	MaxLength     *int       `form:"max_length" binding:"omitempty,min=0"`
+	MaxWidth      *int       `form:"max_width" binding:"omitempty,min=0"`
+	MaxHeight     *int       `form:"max_height" binding:"omitempty,min=0"`
+	CategoryID    *uint      `form:"category_id" binding:"omitempty"`
+	SubCategories bool       `form:"include_subcategories"`                                             // 同时查询子孙分类下的电源
+	Tags          string     `form:"tags" binding:"omitempty"`                                          // 逗号分隔的标签名称，如 "quiet,white"
+	TagMode       string     `form:"tag_mode" binding:"omitempty,oneof=any all"`                        // any（默认）包含任一标签，all 包含全部标签
+	AsOf          *time.Time `form:"as_of" time_format:"2006-01-02T15:04:05Z07:00" binding:"omitempty"` // 返回该时刻（RFC3339）生效的价格
 }
 
+// PowerSupplyCompareRequest 电源对比请求
+type PowerSupplyCompareRequest struct {
+	IDs string `form:"ids" binding:"required"` // 逗号分隔的电源 ID，如 "1,2,3"
+}
@@ -0,0 +1,21 @@
+package dto
+
+import "time"
+
+// PriceHistoryQueryRequest 查询价格历史请求
+type PriceHistoryQueryRequest struct {
+	Page     int `form:"page" binding:"omitempty,min=1"`
+	PageSize int `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// PriceScheduleCreateRequest 创建计划调价请求
+type PriceScheduleCreateRequest struct {
+	Price       float64   `json:"price" binding:"required,min=0"`
+	EffectiveAt time.Time `json:"effective_at" binding:"required"`
+	Reason      string    `json:"reason" binding:"omitempty,max=255"`
+}
+
+// PriceScheduleQueryRequest 查询计划调价请求
+type PriceScheduleQueryRequest struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending applied cancelled"`
+}
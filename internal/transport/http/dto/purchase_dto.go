@@ -0,0 +1,42 @@
+package dto
+
+// PurchaseLineRequest 采购明细请求
+type PurchaseLineRequest struct {
+	PowerSupplyID uint     `json:"power_supply_id" binding:"required"`
+	Quantity      int      `json:"quantity" binding:"required,min=1"`
+	UnitCost      *float64 `json:"unit_cost" binding:"omitempty,min=0"`
+}
+
+// PurchaseOrderCreateRequest 创建采购单请求
+type PurchaseOrderCreateRequest struct {
+	SupplierID  uint                  `json:"supplier_id" binding:"required"`
+	WarehouseID uint                  `json:"warehouse_id"`
+	Lines       []PurchaseLineRequest `json:"lines" binding:"required,min=1,max=100,dive"`
+	Remark      string                `json:"remark" binding:"omitempty,max=255"`
+}
+
+// PurchaseOrderUpdateRequest 修改采购单请求（仅草稿）
+type PurchaseOrderUpdateRequest struct {
+	WarehouseID *uint                 `json:"warehouse_id"`
+	Lines       []PurchaseLineRequest `json:"lines" binding:"omitempty,max=100,dive"`
+	Remark      *string               `json:"remark" binding:"omitempty,max=255"`
+}
+
+// PurchaseReceiveLineRequest 明细收货请求
+type PurchaseReceiveLineRequest struct {
+	LineID   uint `json:"line_id" binding:"required"`
+	Quantity int  `json:"quantity" binding:"required,min=1"`
+}
+
+// PurchaseReceiveRequest 采购收货请求
+type PurchaseReceiveRequest struct {
+	Lines []PurchaseReceiveLineRequest `json:"lines" binding:"required,min=1,max=100,dive"`
+}
+
+// PurchaseOrderQueryRequest 查询采购单请求
+type PurchaseOrderQueryRequest struct {
+	Page       int    `form:"page" binding:"omitempty,min=1"`
+	PageSize   int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+	SupplierID *uint  `form:"supplier_id" binding:"omitempty"`
+	Status     string `form:"status" binding:"omitempty,oneof=draft sent partially_received received"`
+}
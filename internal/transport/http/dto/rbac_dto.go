@@ -0,0 +1,19 @@
+package dto
+
+// RoleCreateRequest 创建角色请求
+type RoleCreateRequest struct {
+	Code        string   `json:"code" binding:"required,min=2,max=50,excludesall= "`
+	Name        string   `json:"name" binding:"required,max=50"`
+	Description string   `json:"description" binding:"omitempty,max=255"`
+	Permissions []string `json:"permissions" binding:"omitempty,dive,required"`
+}
+
+// RolePermissionsRequest 设置角色权限请求
+type RolePermissionsRequest struct {
+	Permissions []string `json:"permissions" binding:"required,dive,required"`
+}
+
+// UserRoleRequest 分配用户角色请求
+type UserRoleRequest struct {
+	Role string `json:"role" binding:"required,max=50"`
+}
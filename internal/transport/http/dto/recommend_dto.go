@@ -0,0 +1,15 @@
+package dto
+
+// PowerRecommendRequest 电源推荐请求（整机配置）
+// CPU 需指定型号或功耗，显卡可选；同时给出型号与功耗时以功耗为准
+type PowerRecommendRequest struct {
+	CPUModel    string `json:"cpu_model" binding:"omitempty,max=100"`
+	CPUTDP      int    `json:"cpu_tdp" binding:"omitempty,min=1,max=1000"`
+	GPUModel    string `json:"gpu_model" binding:"omitempty,max=100"`
+	GPUTGP      int    `json:"gpu_tgp" binding:"omitempty,min=1,max=1500"`
+	GPUCount    int    `json:"gpu_count" binding:"omitempty,min=0,max=4"`
+	Drives      int    `json:"drives" binding:"omitempty,min=0,max=32"`
+	Fans        int    `json:"fans" binding:"omitempty,min=0,max=20"`
+	Overclocked bool   `json:"overclocked"`
+	Limit       int    `json:"limit" binding:"omitempty,min=1,max=50"`
+}
@@ -0,0 +1,8 @@
+package dto
+
+// ReservationCreateRequest 预留库存请求
+type ReservationCreateRequest struct {
+	PowerSupplyID uint   `json:"power_supply_id" binding:"required"`
+	Quantity      int    `json:"quantity" binding:"required,min=1"`
+	Reference     string `json:"reference" binding:"omitempty,max=100"`
+}
@@ -0,0 +1,36 @@
+package dto
+
+// SupplierCreateRequest 创建供应商请求
+type SupplierCreateRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	ContactName string `json:"contact_name" binding:"omitempty,max=50"`
+	Phone       string `json:"phone" binding:"omitempty,max=20"`
+	Email       string `json:"email" binding:"omitempty,email,max=100"`
+	Address     string `json:"address" binding:"omitempty,max=255"`
+	Remark      string `json:"remark" binding:"omitempty,max=255"`
+}
+
+// SupplierUpdateRequest 更新供应商请求
+type SupplierUpdateRequest struct {
+	Name        string  `json:"name" binding:"omitempty,max=100"`
+	ContactName *string `json:"contact_name" binding:"omitempty,max=50"`
+	Phone       *string `json:"phone" binding:"omitempty,max=20"`
+	Email       *string `json:"email" binding:"omitempty,email,max=100"`
+	Address     *string `json:"address" binding:"omitempty,max=255"`
+	Remark      *string `json:"remark" binding:"omitempty,max=255"`
+}
+
+// SupplierProductRequest 设置供应商电源请求
+type SupplierProductRequest struct {
+	SupplierSKU  string  `json:"supplier_sku" binding:"omitempty,max=64"`
+	CostPrice    float64 `json:"cost_price" binding:"min=0"`
+	LeadTimeDays int     `json:"lead_time_days" binding:"min=0"`
+}
+
+// SupplierQueryRequest 查询供应商请求
+type SupplierQueryRequest struct {
+	Page          int    `form:"page" binding:"omitempty,min=1"`
+	PageSize      int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Name          string `form:"name" binding:"omitempty"`
+	PowerSupplyID uint   `form:"power_supply_id" binding:"omitempty"`
+}
@@ -5,8 +5,8 @@ import "power-supply-sys/internal/domain/user"
 // UserCreateRequest 创建用户请求（DTO 移至传输层）
 type UserCreateRequest struct {
 	Username string `json:"username" binding:"required,min=3,max=50"`
-	Password string `json:"password" binding:"required,min=6"`
-	Email    string `json:"email" binding:"omitempty,email"`
+	Password string `json:"password" binding:"required"`
+	Email    string `json:"email" binding:"required,email"`
 	Phone    string `json:"phone" binding:"omitempty"`
 	Nickname string `json:"nickname" binding:"omitempty,max=50"`
 	Avatar   string `json:"avatar" binding:"omitempty"`
@@ -18,7 +18,15 @@ type UserUpdateRequest struct {
 	Phone    string `json:"phone" binding:"omitempty"`
 	Nickname string `json:"nickname" binding:"omitempty,max=50"`
 	Avatar   string `json:"avatar" binding:"omitempty"`
-	Status   *int   `json:"status" binding:"omitempty,oneof=0 1"`
+	Status   *int   `json:"status" binding:"omitempty,oneof=0 1 3"` // 0-禁用 1-正常 3-锁定
+}
+
+// UserProfileUpdateRequest 更新个人资料请求（不含特权字段）
+type UserProfileUpdateRequest struct {
+	Email    string `json:"email" binding:"omitempty,email"`
+	Phone    string `json:"phone" binding:"omitempty"`
+	Nickname string `json:"nickname" binding:"omitempty,max=50"`
+	Avatar   string `json:"avatar" binding:"omitempty"`
 }
 
 // UserQueryRequest 查询用户请求
@@ -27,18 +35,20 @@ type UserQueryRequest struct {
 	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`
 	Username string `form:"username" binding:"omitempty"`
 	Email    string `form:"email" binding:"omitempty"`
-	Status   *int   `form:"status" binding:"omitempty,oneof=0 1"`
+	Status   *int   `form:"status" binding:"omitempty,oneof=0 1 2 3 4"`
 }
 
 // LoginRequest 登录请求
 type LoginRequest struct {
 	Username string `json:"username" binding:"required,min=3"`
-	Password string `json:"password" binding:"required,min=6"`
+	Password string `json:"password" binding:"required"`
 }
 
 // LoginResponse 登录响应
 type LoginResponse struct {
-	Token string      `json:"token"`
-	User  *user.User  `json:"user"`
+	Token        string     `json:"token"`
+	RefreshToken string     `json:"refresh_token"`
+	ExpiresIn    int64      `json:"expires_in"`
+	User         *user.User `json:"user"`
 }
 
@@ -0,0 +1,16 @@
+package dto
+
+// WarehouseCreateRequest 创建仓库请求
+type WarehouseCreateRequest struct {
+	Code      string `json:"code" binding:"required,alphanum,max=32"`
+	Name      string `json:"name" binding:"required,max=100"`
+	Address   string `json:"address" binding:"omitempty,max=255"`
+	IsDefault bool   `json:"is_default"`
+}
+
+// WarehouseUpdateRequest 更新仓库请求
+type WarehouseUpdateRequest struct {
+	Name      string  `json:"name" binding:"omitempty,max=100"`
+	Address   *string `json:"address" binding:"omitempty,max=255"`
+	IsDefault *bool   `json:"is_default"`
+}
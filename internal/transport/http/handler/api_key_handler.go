@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"power-supply-sys/internal/domain/apikey"
+	"power-supply-sys/internal/service"
+	httputil "power-supply-sys/internal/transport/http"
+	"power-supply-sys/internal/transport/http/dto"
+	"power-supply-sys/pkg/common"
+	"power-supply-sys/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// APIKeyHandler API Key 处理器
+type APIKeyHandler struct {
+	service service.APIKeyService
+}
+
+// NewAPIKeyHandler 创建 API Key 处理器
+func NewAPIKeyHandler(apiKeyService service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		service: apiKeyService,
+	}
+}
+
+// Create 为当前用户创建 API Key
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	actor, err := currentActor(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.APIKeyCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	// 转换 DTO 为 Service 层需要的格式
+	serviceReq := &apikey.CreateRequest{
+		Name:          req.Name,
+		Scopes:        req.Scopes,
+		ExpiresInDays: req.ExpiresInDays,
+	}
+	created, err := h.service.Create(ctx, actor, serviceReq)
+	if err != nil {
+		logger.Warn("Failed to create API key", zap.Uint("user_id", actor.ID), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("API key created", zap.Uint("user_id", actor.ID), zap.Uint("api_key_id", created.Key.ID))
+	httputil.HandleSuccess(c, dto.APIKeyCreateResponse{
+		APIKeyResponse: toAPIKeyResponse(created.Key),
+		Key:            created.RawKey,
+	})
+}
+
+// List 获取当前用户的 API Key 列表
+func (h *APIKeyHandler) List(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	actor, err := currentActor(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	keys, err := h.service.List(ctx, actor.ID)
+	if err != nil {
+		logger.Error("Failed to list API keys", zap.Uint("user_id", actor.ID), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	resp := make([]dto.APIKeyResponse, 0, len(keys))
+	for _, k := range keys {
+		resp = append(resp, toAPIKeyResponse(k))
+	}
+	httputil.HandleSuccess(c, resp)
+}
+
+// Revoke 吊销当前用户的 API Key
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	actor, err := currentActor(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.service.Revoke(ctx, actor.ID, id); err != nil {
+		logger.Warn("Failed to revoke API key", zap.Uint("user_id", actor.ID), zap.Uint("api_key_id", id), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("API key revoked", zap.Uint("user_id", actor.ID), zap.Uint("api_key_id", id))
+	httputil.HandleSuccess(c, gin.H{"message": "吊销成功"})
+}
+
+// toAPIKeyResponse 转换 API Key 为响应格式
+func toAPIKeyResponse(k *apikey.APIKey) dto.APIKeyResponse {
+	return dto.APIKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.ScopeList(),
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
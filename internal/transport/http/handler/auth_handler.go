@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"power-supply-sys/internal/service"
+	httputil "power-supply-sys/internal/transport/http"
+	"power-supply-sys/internal/transport/http/dto"
+	"power-supply-sys/pkg/common"
+	"power-supply-sys/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// AuthHandler 认证处理器（令牌刷新与注销）
+type AuthHandler struct {
+	service service.AuthService
+}
+
+// NewAuthHandler 创建认证处理器
+func NewAuthHandler(authService service.AuthService) *AuthHandler {
+	return &AuthHandler{
+		service: authService,
+	}
+}
+
+// Refresh 刷新令牌（轮换 refresh token）
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req dto.RefreshTokenRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid refresh request", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	pair, err := h.service.Refresh(ctx, req.RefreshToken)
+	if err != nil {
+		logger.Warn("Failed to refresh token", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Token refreshed successfully", zap.String("session_id", pair.SessionID))
+	httputil.HandleSuccess(c, dto.TokenResponse{
+		Token:        pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		ExpiresIn:    pair.ExpiresIn,
+	})
+}
+
+// Logout 注销（吊销当前会话的全部令牌）
+func (h *AuthHandler) Logout(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req dto.LogoutRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid logout request", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	if err := h.service.Logout(ctx, req.RefreshToken); err != nil {
+		logger.Warn("Failed to logout", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("User logged out successfully")
+	httputil.HandleSuccess(c, gin.H{"message": "注销成功"})
+}
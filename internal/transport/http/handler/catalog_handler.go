@@ -0,0 +1,233 @@
+package handler
+
+import (
+	"power-supply-sys/internal/domain/catalog"
+	"power-supply-sys/internal/service"
+	httputil "power-supply-sys/internal/transport/http"
+	"power-supply-sys/internal/transport/http/dto"
+	"power-supply-sys/pkg/common"
+	"power-supply-sys/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// CatalogHandler 商品目录处理器（电源分类与标签）
+type CatalogHandler struct {
+	service service.CatalogService
+}
+
+// NewCatalogHandler 创建商品目录处理器
+func NewCatalogHandler(catalogService service.CatalogService) *CatalogHandler {
+	return &CatalogHandler{
+		service: catalogService,
+	}
+}
+
+// CategoryTree 获取分类树
+func (h *CatalogHandler) CategoryTree(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	tree, err := h.service.CategoryTree(ctx)
+	if err != nil {
+		logger.Error("Failed to get category tree", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	httputil.HandleSuccess(c, tree)
+}
+
+// GetCategory 获取分类详情（含子分类树）
+func (h *CatalogHandler) GetCategory(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	category, err := h.service.GetCategory(ctx, id)
+	if err != nil {
+		logger.Warn("Category not found", zap.Uint("category_id", id), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	httputil.HandleSuccess(c, category)
+}
+
+// CreateCategory 创建分类
+func (h *CatalogHandler) CreateCategory(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req dto.CategoryCreateRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	category, err := h.service.CreateCategory(ctx, &catalog.CategoryCreateRequest{
+		ParentID:  req.ParentID,
+		Name:      req.Name,
+		SortOrder: req.SortOrder,
+	})
+	if err != nil {
+		logger.Warn("Failed to create category", zap.String("name", req.Name), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Category created", zap.Uint("category_id", category.ID), zap.String("name", category.Name))
+	httputil.HandleSuccess(c, category)
+}
+
+// UpdateCategory 更新分类（重命名、调整排序或移动到其他分类下）
+func (h *CatalogHandler) UpdateCategory(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.CategoryUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	category, err := h.service.UpdateCategory(ctx, id, &catalog.CategoryUpdateRequest{
+		Name:      req.Name,
+		ParentID:  req.ParentID,
+		SortOrder: req.SortOrder,
+	})
+	if err != nil {
+		logger.Warn("Failed to update category", zap.Uint("category_id", id), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Category updated", zap.Uint("category_id", id))
+	httputil.HandleSuccess(c, category)
+}
+
+// DeleteCategory 删除分类
+func (h *CatalogHandler) DeleteCategory(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.service.DeleteCategory(ctx, id); err != nil {
+		logger.Warn("Failed to delete category", zap.Uint("category_id", id), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Category deleted", zap.Uint("category_id", id))
+	httputil.HandleSuccess(c, gin.H{"message": "删除成功"})
+}
+
+// ListTags 查询标签列表
+func (h *CatalogHandler) ListTags(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req dto.TagQueryRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Warn("Invalid query parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("查询参数错误"))
+		return
+	}
+
+	tags, total, err := h.service.ListTags(ctx, &catalog.TagQueryRequest{
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		Name:     req.Name,
+	})
+	if err != nil {
+		logger.Error("Failed to list tags", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	page, pageSize := common.GetPageInfo(req.Page, req.PageSize)
+	httputil.HandlePageSuccess(c, tags, total, page, pageSize)
+}
+
+// CreateTag 创建标签
+func (h *CatalogHandler) CreateTag(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req dto.TagRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	tag, err := h.service.CreateTag(ctx, req.Name)
+	if err != nil {
+		logger.Warn("Failed to create tag", zap.String("name", req.Name), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Tag created", zap.Uint("tag_id", tag.ID), zap.String("name", tag.Name))
+	httputil.HandleSuccess(c, tag)
+}
+
+// UpdateTag 重命名标签
+func (h *CatalogHandler) UpdateTag(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	tag, err := h.service.UpdateTag(ctx, id, req.Name)
+	if err != nil {
+		logger.Warn("Failed to update tag", zap.Uint("tag_id", id), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Tag updated", zap.Uint("tag_id", id), zap.String("name", tag.Name))
+	httputil.HandleSuccess(c, tag)
+}
+
+// DeleteTag 删除标签（同时移除电源上的该标签）
+func (h *CatalogHandler) DeleteTag(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.service.DeleteTag(ctx, id); err != nil {
+		logger.Warn("Failed to delete tag", zap.Uint("tag_id", id), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Tag deleted", zap.Uint("tag_id", id))
+	httputil.HandleSuccess(c, gin.H{"message": "删除成功"})
+}
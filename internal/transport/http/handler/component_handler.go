@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"power-supply-sys/internal/domain/component"
+	"power-supply-sys/internal/service"
+	httputil "power-supply-sys/internal/transport/http"
+	"power-supply-sys/internal/transport/http/dto"
+	"power-supply-sys/pkg/common"
+	"power-supply-sys/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ComponentHandler 组件功耗档案处理器
+type ComponentHandler struct {
+	service service.ComponentService
+}
+
+// NewComponentHandler 创建组件功耗档案处理器
+func NewComponentHandler(componentService service.ComponentService) *ComponentHandler {
+	return &ComponentHandler{
+		service: componentService,
+	}
+}
+
+// Create 创建组件
+func (h *ComponentHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req dto.ComponentCreateRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	comp, err := h.service.Create(ctx, toComponentCreateRequest(&req))
+	if err != nil {
+		logger.Warn("Failed to create component", zap.String("name", req.Name), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Component created", zap.Uint("component_id", comp.ID), zap.String("type", comp.Type), zap.String("name", comp.Name))
+	httputil.HandleSuccess(c, comp)
+}
+
+// Get 获取组件详情
+func (h *ComponentHandler) Get(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	comp, err := h.service.GetByID(ctx, id)
+	if err != nil {
+		logger.Warn("Component not found", zap.Uint("component_id", id), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	httputil.HandleSuccess(c, comp)
+}
+
+// Update 更新组件
+func (h *ComponentHandler) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.ComponentUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	comp, err := h.service.Update(ctx, id, &component.UpdateRequest{
+		Name:            req.Name,
+		Brand:           req.Brand,
+		Watts:           req.Watts,
+		TransientFactor: req.TransientFactor,
+	})
+	if err != nil {
+		logger.Warn("Failed to update component", zap.Uint("component_id", id), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Component updated", zap.Uint("component_id", id))
+	httputil.HandleSuccess(c, comp)
+}
+
+// Delete 删除组件
+func (h *ComponentHandler) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.service.Delete(ctx, id); err != nil {
+		logger.Warn("Failed to delete component", zap.Uint("component_id", id), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Component deleted", zap.Uint("component_id", id))
+	httputil.HandleSuccess(c, gin.H{"message": "删除成功"})
+}
+
+// List 查询组件列表
+func (h *ComponentHandler) List(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req dto.ComponentQueryRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Warn("Invalid query parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("查询参数错误"))
+		return
+	}
+
+	components, total, err := h.service.List(ctx, &component.QueryRequest{
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		Type:     req.Type,
+		Name:     req.Name,
+		Brand:    req.Brand,
+	})
+	if err != nil {
+		logger.Error("Failed to list components", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	page, pageSize := common.GetPageInfo(req.Page, req.PageSize)
+	httputil.HandlePageSuccess(c, components, total, page, pageSize)
+}
+
+// Import 批量导入组件（按类型与型号覆盖已有档案）
+func (h *ComponentHandler) Import(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req dto.ComponentImportRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	items := make([]*component.CreateRequest, 0, len(req.Components))
+	for _, item := range req.Components {
+		items = append(items, toComponentCreateRequest(item))
+	}
+	imported, err := h.service.Import(ctx, items)
+	if err != nil {
+		logger.Warn("Failed to import components", zap.Int("count", len(items)), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Components imported", zap.Int("count", imported))
+	httputil.HandleSuccess(c, gin.H{"imported": imported})
+}
+
+// toComponentCreateRequest 将创建组件 DTO 转换为 Service 层请求
+func toComponentCreateRequest(req *dto.ComponentCreateRequest) *component.CreateRequest {
+	return &component.CreateRequest{
+		Type:            req.Type,
+		Name:            req.Name,
+		Brand:           req.Brand,
+		Watts:           req.Watts,
+		TransientFactor: req.TransientFactor,
+	}
+}
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"power-supply-sys/internal/domain/token"
+	"power-supply-sys/internal/domain/user"
+	"power-supply-sys/internal/transport/http/middleware"
+	"power-supply-sys/pkg/common"
+
+	"github.com/gin-gonic/gin"
+)
+
+// currentActor 从认证信息中获取当前操作者
+func currentActor(c *gin.Context) (*user.Actor, error) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return nil, common.ErrUnauthorized("")
+	}
+	role, _ := middleware.GetRole(c)
+	return &user.Actor{ID: userID, Role: role}, nil
+}
+
+// clientInfo 获取请求的客户端信息（用于记录登录会话）
+func clientInfo(c *gin.Context) *token.ClientInfo {
+	return &token.ClientInfo{
+		UserAgent: c.Request.UserAgent(),
+		IP:        c.ClientIP(),
+	}
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"power-supply-sys/internal/service"
+	httputil "power-supply-sys/internal/transport/http"
+	"power-supply-sys/internal/transport/http/dto"
+	"power-supply-sys/pkg/common"
+	"power-supply-sys/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// EmailVerificationHandler 邮箱验证处理器
+type EmailVerificationHandler struct {
+	service service.EmailVerificationService
+}
+
+// NewEmailVerificationHandler 创建邮箱验证处理器
+func NewEmailVerificationHandler(verificationService service.EmailVerificationService) *EmailVerificationHandler {
+	return &EmailVerificationHandler{
+		service: verificationService,
+	}
+}
+
+// Verify 使用验证令牌激活账户
+func (h *EmailVerificationHandler) Verify(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req dto.EmailVerifyRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	u, err := h.service.Verify(ctx, req.Token)
+	if err != nil {
+		logger.Warn("Failed to verify email", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Email verified successfully", zap.Uint("user_id", u.ID))
+	httputil.HandleSuccess(c, u)
+}
+
+// Resend 重新发送验证邮件（无论邮箱是否存在都返回成功）
+func (h *EmailVerificationHandler) Resend(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req dto.EmailVerificationResendRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	if err := h.service.Resend(ctx, req.Email); err != nil {
+		logger.Error("Failed to resend verification mail", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Verification mail resend requested")
+	httputil.HandleSuccess(c, gin.H{"message": "如果该邮箱待验证，验证邮件已发送"})
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"power-supply-sys/internal/domain/audit"
+	"power-supply-sys/internal/service"
+	httputil "power-supply-sys/internal/transport/http"
+	"power-supply-sys/internal/transport/http/dto"
+	"power-supply-sys/internal/transport/http/middleware"
+	"power-supply-sys/pkg/common"
+	"power-supply-sys/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ImpersonationHandler 模拟登录处理器
+type ImpersonationHandler struct {
+	service service.ImpersonationService
+}
+
+// NewImpersonationHandler 创建模拟登录处理器
+func NewImpersonationHandler(impersonationService service.ImpersonationService) *ImpersonationHandler {
+	return &ImpersonationHandler{
+		service: impersonationService,
+	}
+}
+
+// Impersonate 以指定用户的身份签发模拟令牌
+func (h *ImpersonationHandler) Impersonate(c *gin.Context) {
+	ctx := c.Request.Context()
+	actor, err := currentActor(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	sessionID, _ := middleware.GetSessionID(c)
+
+	targetID, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	result, err := h.service.Impersonate(ctx, &audit.ImpersonateRequest{
+		ActorID:   actor.ID,
+		SessionID: sessionID,
+		TargetID:  targetID,
+		ClientIP:  c.ClientIP(),
+	})
+	if err != nil {
+		logger.Warn("Failed to impersonate user",
+			zap.Uint("actor_id", actor.ID),
+			zap.Uint("target_id", targetID),
+			zap.Error(err),
+		)
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Impersonation started",
+		zap.Uint("actor_id", result.Actor.ID),
+		zap.Uint("user_id", result.User.ID),
+	)
+	httputil.HandleSuccess(c, &dto.ImpersonateResponse{
+		Token:     result.AccessToken,
+		ExpiresIn: result.ExpiresIn,
+		User:      result.User,
+		Impersonator: &dto.Impersonator{
+			ID:       result.Actor.ID,
+			Username: result.Actor.Username,
+		},
+	})
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"power-supply-sys/internal/domain/inventory"
+	"power-supply-sys/internal/service"
+	httputil "power-supply-sys/internal/transport/http"
+	"power-supply-sys/internal/transport/http/dto"
+	"power-supply-sys/internal/transport/http/middleware"
+	"power-supply-sys/pkg/common"
+	"power-supply-sys/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// InventoryHandler 库存流水处理器
+type InventoryHandler struct {
+	service service.InventoryService
+}
+
+// NewInventoryHandler 创建库存流水处理器
+func NewInventoryHandler(inventoryService service.InventoryService) *InventoryHandler {
+	return &InventoryHandler{
+		service: inventoryService,
+	}
+}
+
+// RecordMovement 登记电源的库存流水
+func (h *InventoryHandler) RecordMovement(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.StockMovementCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	// 模拟登录时记录实际操作的管理员
+	actorID, _ := middleware.GetActorID(c)
+	movement, err := h.service.RecordMovement(ctx, &inventory.MovementRequest{
+		PowerSupplyID: id,
+		Type:          req.Type,
+		Quantity:      req.Quantity,
+		WarehouseID:   req.WarehouseID,
+		Reason:        req.Reason,
+		Reference:     req.Reference,
+		ActorID:       actorID,
+	})
+	if err != nil {
+		logger.Warn("Failed to record stock movement", zap.Uint("power_supply_id", id), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Stock movement recorded",
+		zap.Uint("power_supply_id", id),
+		zap.Uint("warehouse_id", movement.WarehouseID),
+		zap.String("type", movement.Type),
+		zap.Int("quantity", movement.Quantity),
+		zap.Int("balance_after", movement.BalanceAfter),
+	)
+	httputil.HandleSuccess(c, movement)
+}
+
+// Transfer 在仓库之间调拨电源库存
+func (h *InventoryHandler) Transfer(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.StockTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	actorID, _ := middleware.GetActorID(c)
+	movements, err := h.service.Transfer(ctx, &inventory.TransferRequest{
+		PowerSupplyID:   id,
+		FromWarehouseID: req.FromWarehouseID,
+		ToWarehouseID:   req.ToWarehouseID,
+		Quantity:        req.Quantity,
+		Reason:          req.Reason,
+		Reference:       req.Reference,
+		ActorID:         actorID,
+	})
+	if err != nil {
+		logger.Warn("Failed to transfer stock", zap.Uint("power_supply_id", id), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Stock transferred",
+		zap.Uint("power_supply_id", id),
+		zap.Uint("from_warehouse_id", req.FromWarehouseID),
+		zap.Uint("to_warehouse_id", req.ToWarehouseID),
+		zap.Int("quantity", req.Quantity),
+	)
+	httputil.HandleSuccess(c, movements)
+}
+
+// ListMovements 获取电源的库存流水
+func (h *InventoryHandler) ListMovements(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.StockMovementQueryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Warn("Invalid query parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("查询参数错误"))
+		return
+	}
+
+	movements, total, err := h.service.ListMovements(ctx, &inventory.MovementQueryRequest{
+		PowerSupplyID: id,
+		WarehouseID:   req.WarehouseID,
+		Type:          req.Type,
+		Page:          req.Page,
+		PageSize:      req.PageSize,
+	})
+	if err != nil {
+		logger.Warn("Failed to list stock movements", zap.Uint("power_supply_id", id), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	page, pageSize := common.GetPageInfo(req.Page, req.PageSize)
+	httputil.HandlePageSuccess(c, movements, total, page, pageSize)
+}
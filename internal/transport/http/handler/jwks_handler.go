@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"net/http"
+	"power-supply-sys/pkg/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JWKSHandler JWT 公钥集合处理器
+type JWKSHandler struct {
+	jwtManager *auth.JWTManager
+}
+
+// NewJWKSHandler 创建 JWT 公钥集合处理器
+func NewJWKSHandler(jwtManager *auth.JWTManager) *JWKSHandler {
+	return &JWKSHandler{
+		jwtManager: jwtManager,
+	}
+}
+
+// Get 返回当前可用于验签的公钥集合
+// 按 RFC 7517 格式直接输出，不使用统一响应包装，便于标准 JWT 库直接加载
+func (h *JWKSHandler) Get(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.jwtManager.JWKS())
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"power-supply-sys/internal/domain/user"
+	"power-supply-sys/internal/service"
+	httputil "power-supply-sys/internal/transport/http"
+	"power-supply-sys/internal/transport/http/dto"
+	"power-supply-sys/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// respondLogin 身份校验通过后完成登录：已启用两步验证的用户返回登录挑战，否则签发令牌
+// method 为登录方式，仅用于日志
+func respondLogin(c *gin.Context, u *user.User, method string, authService service.AuthService, twoFactorService service.TwoFactorService) {
+	ctx := c.Request.Context()
+
+	enabled, err := twoFactorService.IsEnabled(ctx, u.ID)
+	if err != nil {
+		logger.Error("Failed to check two-factor status", zap.Uint("user_id", u.ID), zap.Error(err))
+		c.Error(err)
+		return
+	}
+	if enabled {
+		ticket, err := twoFactorService.CreateChallenge(ctx, u.ID)
+		if err != nil {
+			logger.Error("Failed to create two-factor challenge", zap.Uint("user_id", u.ID), zap.Error(err))
+			c.Error(err)
+			return
+		}
+
+		logger.Info("Two-factor challenge issued", zap.Uint("user_id", u.ID), zap.String("method", method))
+		httputil.HandleSuccess(c, dto.TwoFactorChallengeResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    ticket.Token,
+			ExpiresIn:         ticket.ExpiresIn,
+		})
+		return
+	}
+
+	respondTokens(c, u, method, authService)
+}
+
+// respondTokens 签发 access token 与 refresh token 并返回登录响应
+func respondTokens(c *gin.Context, u *user.User, method string, authService service.AuthService) {
+	pair, err := authService.IssueTokens(c.Request.Context(), u, clientInfo(c))
+	if err != nil {
+		logger.Error("Failed to issue tokens", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("User logged in successfully",
+		zap.Uint("user_id", u.ID),
+		zap.String("username", u.Username),
+		zap.String("method", method),
+	)
+	httputil.HandleSuccess(c, dto.LoginResponse{
+		Token:        pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		ExpiresIn:    pair.ExpiresIn,
+		User:         u,
+	})
+}
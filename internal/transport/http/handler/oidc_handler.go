@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"net/http"
+	"path"
+	"power-supply-sys/internal/domain/identity"
+	"power-supply-sys/internal/service"
+	httputil "power-supply-sys/internal/transport/http"
+	"power-supply-sys/internal/transport/http/dto"
+	"power-supply-sys/pkg/common"
+	"power-supply-sys/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// oidcStateCookie 保存单点登录 state 的 Cookie，回调时必须与查询参数中的 state 一致
+const oidcStateCookie = "oidc_state"
+
+// OIDCHandler 单点登录处理器
+type OIDCHandler struct {
+	service          service.OIDCService
+	authService      service.AuthService
+	twoFactorService service.TwoFactorService
+}
+
+// NewOIDCHandler 创建单点登录处理器
+func NewOIDCHandler(oidcService service.OIDCService, authService service.AuthService, twoFactorService service.TwoFactorService) *OIDCHandler {
+	return &OIDCHandler{
+		service:          oidcService,
+		authService:      authService,
+		twoFactorService: twoFactorService,
+	}
+}
+
+// Login 发起单点登录，返回跳转到身份提供方的授权地址
+func (h *OIDCHandler) Login(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req dto.OIDCLoginRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	redirect, err := h.service.BeginLogin(ctx, req.Provider)
+	if err != nil {
+		logger.Warn("Failed to begin OIDC login", zap.String("provider", req.Provider), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	// state 绑定到发起登录的浏览器，Cookie 作用于登录与回调所在的路径
+	setOIDCStateCookie(c, redirect.State, int(redirect.ExpiresIn))
+
+	logger.Info("OIDC login started", zap.String("provider", req.Provider))
+	httputil.HandleSuccess(c, dto.OIDCLoginResponse{
+		AuthorizationURL: redirect.AuthorizationURL,
+		ExpiresIn:        redirect.ExpiresIn,
+	})
+}
+
+// Callback 处理身份提供方回调，校验通过后按本地登录流程签发令牌
+func (h *OIDCHandler) Callback(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req dto.OIDCCallbackRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	// state 只能使用一次，无论回调结果如何都清除 Cookie
+	browserState, _ := c.Cookie(oidcStateCookie)
+	setOIDCStateCookie(c, "", -1)
+
+	// 转换 DTO 为 Service 层需要的格式
+	serviceReq := &identity.CallbackRequest{
+		Code:             req.Code,
+		State:            req.State,
+		BrowserState:     browserState,
+		Error:            req.Error,
+		ErrorDescription: req.ErrorDescription,
+	}
+	u, err := h.service.CompleteLogin(ctx, serviceReq)
+	if err != nil {
+		logger.Warn("OIDC login failed",
+			zap.String("provider_error", req.Error),
+			zap.String("provider_error_description", req.ErrorDescription),
+			zap.Error(err),
+		)
+		c.Error(err)
+		return
+	}
+
+	respondLogin(c, u, "oidc", h.authService, h.twoFactorService)
+}
+
+// setOIDCStateCookie 设置或清除（maxAge < 0）保存 state 的 Cookie
+func setOIDCStateCookie(c *gin.Context, state string, maxAge int) {
+	secure := c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state,
+		Path:     path.Dir(c.Request.URL.Path),
+		MaxAge:   maxAge,
+		Secure:   secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"context"
+	"power-supply-sys/internal/domain/order"
+	"power-supply-sys/internal/domain/user"
+	"power-supply-sys/internal/service"
+	httputil "power-supply-sys/internal/transport/http"
+	"power-supply-sys/internal/transport/http/dto"
+	"power-supply-sys/pkg/common"
+	"power-supply-sys/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// OrderHandler 订单处理器
+type OrderHandler struct {
+	service service.OrderService
+}
+
+// NewOrderHandler 创建订单处理器
+func NewOrderHandler(orderService service.OrderService) *OrderHandler {
+	return &OrderHandler{
+		service: orderService,
+	}
+}
+
+// Create 创建订单
+func (h *OrderHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req dto.OrderCreateRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	actor, err := currentActor(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// 转换 DTO 为 Service 层需要的格式
+	serviceReq := &order.CreateRequest{
+		Items:  make([]order.ItemRequest, 0, len(req.Items)),
+		Remark: req.Remark,
+	}
+	for _, item := range req.Items {
+		serviceReq.Items = append(serviceReq.Items, order.ItemRequest{
+			PowerSupplyID: item.PowerSupplyID,
+			Quantity:      item.Quantity,
+		})
+	}
+
+	o, err := h.service.Create(ctx, actor, serviceReq)
+	if err != nil {
+		logger.Warn("Failed to create order", zap.Uint("user_id", actor.ID), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Order created",
+		zap.Uint("order_id", o.ID),
+		zap.String("order_no", o.OrderNo),
+		zap.Uint("user_id", o.UserID),
+	)
+	httputil.HandleSuccess(c, o)
+}
+
+// Get 获取订单详情
+func (h *OrderHandler) Get(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	actor, err := currentActor(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	o, err := h.service.GetByID(ctx, actor, id)
+	if err != nil {
+		logger.Warn("Failed to get order", zap.Uint("order_id", id), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	httputil.HandleSuccess(c, o)
+}
+
+// List 查询订单列表
+func (h *OrderHandler) List(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req dto.OrderQueryRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Warn("Invalid query parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("查询参数错误"))
+		return
+	}
+
+	actor, err := currentActor(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	orders, total, err := h.service.List(ctx, actor, &order.QueryRequest{
+		Page:     req.Page,
+		PageSize: req.PageSize,
+		UserID:   req.UserID,
+		Status:   req.Status,
+	})
+	if err != nil {
+		logger.Error("Failed to list orders", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	page, pageSize := common.GetPageInfo(req.Page, req.PageSize)
+	httputil.HandlePageSuccess(c, orders, total, page, pageSize)
+}
+
+// Pay 支付订单
+func (h *OrderHandler) Pay(c *gin.Context) {
+	h.transition(c, order.StatusPaid, h.service.Pay)
+}
+
+// Cancel 取消订单
+func (h *OrderHandler) Cancel(c *gin.Context) {
+	h.transition(c, order.StatusCancelled, h.service.Cancel)
+}
+
+// Ship 订单发货
+func (h *OrderHandler) Ship(c *gin.Context) {
+	h.transition(c, order.StatusShipped, h.service.Ship)
+}
+
+// Complete 完成订单
+func (h *OrderHandler) Complete(c *gin.Context) {
+	h.transition(c, order.StatusCompleted, h.service.Complete)
+}
+
+// Refund 订单退款
+func (h *OrderHandler) Refund(c *gin.Context) {
+	h.transition(c, order.StatusRefunded, h.service.Refund)
+}
+
+// transition 执行订单状态流转操作
+func (h *OrderHandler) transition(c *gin.Context, to string, fn func(ctx context.Context, actor *user.Actor, id uint) (*order.Order, error)) {
+	ctx := c.Request.Context()
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	actor, err := currentActor(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	o, err := fn(ctx, actor, id)
+	if err != nil {
+		logger.Warn("Failed to update order status",
+			zap.Uint("order_id", id),
+			zap.String("to", to),
+			zap.Error(err),
+		)
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Order status updated",
+		zap.Uint("order_id", id),
+		zap.String("status", o.Status),
+		zap.Uint("operator_id", actor.ID),
+	)
+	httputil.HandleSuccess(c, o)
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"power-supply-sys/internal/domain/user"
+	"power-supply-sys/internal/service"
+	httputil "power-supply-sys/internal/transport/http"
+	"power-supply-sys/internal/transport/http/dto"
+	"power-supply-sys/pkg/common"
+	"power-supply-sys/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// PasswordHandler 密码处理器（修改密码与找回密码）
+type PasswordHandler struct {
+	service service.PasswordService
+}
+
+// NewPasswordHandler 创建密码处理器
+func NewPasswordHandler(passwordService service.PasswordService) *PasswordHandler {
+	return &PasswordHandler{
+		service: passwordService,
+	}
+}
+
+// Change 修改当前用户密码
+func (h *PasswordHandler) Change(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	actor, err := currentActor(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.PasswordChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	serviceReq := &user.PasswordChangeRequest{
+		OldPassword: req.OldPassword,
+		NewPassword: req.NewPassword,
+	}
+	if err := h.service.ChangePassword(ctx, actor.ID, serviceReq); err != nil {
+		logger.Warn("Failed to change password", zap.Uint("user_id", actor.ID), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Password changed successfully", zap.Uint("user_id", actor.ID))
+	httputil.HandleSuccess(c, gin.H{"message": "密码修改成功"})
+}
+
+// Forgot 发送密码重置邮件（无论邮箱是否存在都返回成功）
+func (h *PasswordHandler) Forgot(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req dto.PasswordForgotRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	if err := h.service.ForgotPassword(ctx, req.Email); err != nil {
+		logger.Error("Failed to send password reset mail", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Password reset requested")
+	httputil.HandleSuccess(c, gin.H{"message": "如果该邮箱已注册，重置邮件已发送"})
+}
+
+// Reset 使用重置令牌设置新密码
+func (h *PasswordHandler) Reset(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req dto.PasswordResetRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	serviceReq := &user.PasswordResetRequest{
+		Token:       req.Token,
+		NewPassword: req.NewPassword,
+	}
+	if err := h.service.ResetPassword(ctx, serviceReq); err != nil {
+		logger.Warn("Failed to reset password", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Password reset successfully")
+	httputil.HandleSuccess(c, gin.H{"message": "密码重置成功，请重新登录"})
+}
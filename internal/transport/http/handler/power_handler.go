@@ -3,10 +3,11 @@ package handler
 import (
 	"power-supply-sys/internal/domain/power"
 	"power-supply-sys/internal/service"
+	httputil "power-supply-sys/internal/transport/http"
 	"power-supply-sys/internal/transport/http/dto"
+	"power-supply-sys/internal/transport/http/middleware"
 	"power-supply-sys/pkg/common"
 	"power-supply-sys/pkg/logger"
-	httputil "power-supply-sys/internal/transport/http"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -36,6 +37,7 @@ func (h *PowerHandler) Create(c *gin.Context) {
 	}
 
 	// 转换 DTO 为 Service 层需要的格式
+	actorID, _ := middleware.GetActorID(c)
 	serviceReq := &power.PowerSupplyCreateRequest{
 		Name:        req.Name,
 		Brand:       req.Brand,
@@ -45,7 +47,14 @@ func (h *PowerHandler) Create(c *gin.Context) {
 		Modular:     req.Modular,
 		Price:       req.Price,
 		Stock:       req.Stock,
+		WarehouseID: req.WarehouseID,
 		Description: req.Description,
+		CategoryID:  req.CategoryID,
+		Tags:        req.Tags,
+		ActorID:     actorID,
+	}
+	if req.Spec != nil {
+		serviceReq.Spec = toPowerSpec(req.Spec)
 	}
 	ps, err := h.service.Create(ctx, serviceReq)
 	if err != nil {
@@ -78,6 +87,33 @@ func (h *PowerHandler) Get(c *gin.Context) {
 	httputil.HandleSuccess(c, ps)
 }
 
+// Compare 对比多个电源
+func (h *PowerHandler) Compare(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req dto.PowerSupplyCompareRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Warn("Invalid query parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("查询参数错误"))
+		return
+	}
+
+	ids, err := common.ParseUintList(req.IDs)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	comparison, err := h.service.Compare(ctx, ids)
+	if err != nil {
+		logger.Warn("Failed to compare power supplies", zap.String("ids", req.IDs), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	httputil.HandleSuccess(c, comparison)
+}
+
 // Update 更新电源
 func (h *PowerHandler) Update(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -96,6 +132,7 @@ func (h *PowerHandler) Update(c *gin.Context) {
 	}
 
 	// 转换 DTO 为 Service 层需要的格式
+	actorID, _ := middleware.GetActorID(c)
 	serviceReq := &power.PowerSupplyUpdateRequest{
 		Name:        req.Name,
 		Brand:       req.Brand,
@@ -107,6 +144,13 @@ func (h *PowerHandler) Update(c *gin.Context) {
 		Stock:       req.Stock,
 		Description: req.Description,
 		Status:      req.Status,
+		CategoryID:  req.CategoryID,
+		Tags:        req.Tags,
+		ActorID:     actorID,
+	}
+	if req.Spec != nil {
+		spec := toPowerSpec(req.Spec)
+		serviceReq.Spec = &spec
 	}
 	ps, err := h.service.Update(ctx, id, serviceReq)
 	if err != nil {
@@ -152,16 +196,40 @@ func (h *PowerHandler) List(c *gin.Context) {
 
 	// 转换 DTO 为 Service 层需要的格式
 	serviceReq := &power.PowerSupplyQueryRequest{
-		Page:       req.Page,
-		PageSize:   req.PageSize,
-		Name:       req.Name,
-		Brand:      req.Brand,
-		MinPower:   req.MinPower,
-		MaxPower:   req.MaxPower,
-		MinPrice:   req.MinPrice,
-		MaxPrice:   req.MaxPrice,
-		Efficiency: req.Efficiency,
-		Status:     req.Status,
+		Page:          req.Page,
+		PageSize:      req.PageSize,
+		Name:          req.Name,
+		Brand:         req.Brand,
+		MinPower:      req.MinPower,
+		MaxPower:      req.MaxPower,
+		MinPrice:      req.MinPrice,
+		MaxPrice:      req.MaxPrice,
+		Efficiency:    req.Efficiency,
+		MinEfficiency: req.MinEfficiency,
+		MaxEfficiency: req.MaxEfficiency,
+		Status:        req.Status,
+		WarehouseID:   req.WarehouseID,
+		InStock:       req.InStock,
+		CategoryID:    req.CategoryID,
+		SubCategories: req.SubCategories,
+		Tags:          common.SplitList(req.Tags),
+		TagMode:       req.TagMode,
+		AsOf:          req.AsOf,
+		Spec: power.SpecFilter{
+			FormFactor:  req.FormFactor,
+			ATX3:        req.ATX3,
+			Min12VHPWR:  req.Min12VHPWR,
+			MinEPS:      req.MinEPS,
+			MinPCIe8Pin: req.MinPCIe8Pin,
+			MinSATA:     req.MinSATA,
+			MinMolex:    req.MinMolex,
+			Min12VAmps:  req.Min12VAmps,
+			FanSizeMM:   req.FanSize,
+			MaxNoiseDBA: req.MaxNoise,
+			MaxLengthMM: req.MaxLength,
+			MaxWidthMM:  req.MaxWidth,
+			MaxHeightMM: req.MaxHeight,
+		},
 	}
 	powerSupplies, total, err := h.service.List(ctx, serviceReq)
 	if err != nil {
@@ -174,3 +242,21 @@ func (h *PowerHandler) List(c *gin.Context) {
 	httputil.HandlePageSuccess(c, powerSupplies, total, page, pageSize)
 }
 
+// toPowerSpec 转换技术规格 DTO 为领域模型
+func toPowerSpec(req *dto.PowerSupplySpecRequest) power.Spec {
+	return power.Spec{
+		FormFactor:         req.FormFactor,
+		ATX3:               req.ATX3,
+		Connectors12VHPWR:  req.Connectors12VHPWR,
+		EPSConnectors:      req.EPSConnectors,
+		PCIe8PinConnectors: req.PCIe8PinConnectors,
+		SATAConnectors:     req.SATAConnectors,
+		MolexConnectors:    req.MolexConnectors,
+		Rail12VAmps:        req.Rail12VAmps,
+		FanSizeMM:          req.FanSizeMM,
+		NoiseDBA:           req.NoiseDBA,
+		LengthMM:           req.LengthMM,
+		WidthMM:            req.WidthMM,
+		HeightMM:           req.HeightMM,
+	}
+}
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"power-supply-sys/internal/domain/power"
+	"power-supply-sys/internal/service"
+	httputil "power-supply-sys/internal/transport/http"
+	"power-supply-sys/internal/transport/http/dto"
+	"power-supply-sys/internal/transport/http/middleware"
+	"power-supply-sys/pkg/common"
+	"power-supply-sys/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// PricingHandler 价格历史与计划调价处理器
+type PricingHandler struct {
+	service service.PricingService
+}
+
+// NewPricingHandler 创建价格处理器
+func NewPricingHandler(pricingService service.PricingService) *PricingHandler {
+	return &PricingHandler{
+		service: pricingService,
+	}
+}
+
+// History 获取电源的价格历史
+func (h *PricingHandler) History(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.PriceHistoryQueryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Warn("Invalid query parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("查询参数错误"))
+		return
+	}
+
+	history, total, err := h.service.History(ctx, &power.PriceHistoryQueryRequest{
+		PowerSupplyID: id,
+		Page:          req.Page,
+		PageSize:      req.PageSize,
+	})
+	if err != nil {
+		logger.Warn("Failed to list price history", zap.Uint("power_supply_id", id), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	page, pageSize := common.GetPageInfo(req.Page, req.PageSize)
+	httputil.HandlePageSuccess(c, history, total, page, pageSize)
+}
+
+// CreateSchedule 为电源创建计划调价
+func (h *PricingHandler) CreateSchedule(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.PriceScheduleCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	actorID, _ := middleware.GetActorID(c)
+	schedule, err := h.service.Schedule(ctx, &power.PriceScheduleRequest{
+		PowerSupplyID: id,
+		Price:         req.Price,
+		EffectiveAt:   req.EffectiveAt,
+		Reason:        req.Reason,
+		ActorID:       actorID,
+	})
+	if err != nil {
+		logger.Warn("Failed to schedule price change", zap.Uint("power_supply_id", id), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Price change scheduled",
+		zap.Uint("power_supply_id", id),
+		zap.Uint("schedule_id", schedule.ID),
+		zap.Float64("price", schedule.Price),
+		zap.Time("effective_at", schedule.EffectiveAt),
+	)
+	httputil.HandleSuccess(c, schedule)
+}
+
+// ListSchedules 获取电源的计划调价
+func (h *PricingHandler) ListSchedules(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.PriceScheduleQueryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Warn("Invalid query parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("查询参数错误"))
+		return
+	}
+
+	schedules, err := h.service.ListSchedules(ctx, id, req.Status)
+	if err != nil {
+		logger.Warn("Failed to list price schedules", zap.Uint("power_supply_id", id), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	httputil.HandleSuccess(c, schedules)
+}
+
+// CancelSchedule 取消待生效的计划调价
+func (h *PricingHandler) CancelSchedule(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	scheduleID, err := common.ParseUintParam(c, "schedule_id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	schedule, err := h.service.CancelSchedule(ctx, id, scheduleID)
+	if err != nil {
+		logger.Warn("Failed to cancel price schedule",
+			zap.Uint("power_supply_id", id),
+			zap.Uint("schedule_id", scheduleID),
+			zap.Error(err),
+		)
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Price schedule cancelled",
+		zap.Uint("power_supply_id", id),
+		zap.Uint("schedule_id", scheduleID),
+	)
+	httputil.HandleSuccess(c, schedule)
+}
@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"power-supply-sys/internal/domain/purchase"
+	"power-supply-sys/internal/service"
+	httputil "power-supply-sys/internal/transport/http"
+	"power-supply-sys/internal/transport/http/dto"
+	"power-supply-sys/internal/transport/http/middleware"
+	"power-supply-sys/pkg/common"
+	"power-supply-sys/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// PurchaseOrderHandler 采购单处理器
+type PurchaseOrderHandler struct {
+	service service.PurchaseOrderService
+}
+
+// NewPurchaseOrderHandler 创建采购单处理器
+func NewPurchaseOrderHandler(purchaseOrderService service.PurchaseOrderService) *PurchaseOrderHandler {
+	return &PurchaseOrderHandler{
+		service: purchaseOrderService,
+	}
+}
+
+// Create 创建采购单
+func (h *PurchaseOrderHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req dto.PurchaseOrderCreateRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	actorID, _ := middleware.GetActorID(c)
+	o, err := h.service.Create(ctx, &purchase.CreateRequest{
+		SupplierID:  req.SupplierID,
+		WarehouseID: req.WarehouseID,
+		Lines:       toPurchaseLines(req.Lines),
+		Remark:      req.Remark,
+		ActorID:     actorID,
+	})
+	if err != nil {
+		logger.Warn("Failed to create purchase order", zap.Uint("supplier_id", req.SupplierID), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Purchase order created",
+		zap.Uint("purchase_order_id", o.ID),
+		zap.String("order_no", o.OrderNo),
+		zap.Uint("supplier_id", o.SupplierID),
+	)
+	httputil.HandleSuccess(c, o)
+}
+
+// Get 获取采购单详情
+func (h *PurchaseOrderHandler) Get(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	o, err := h.service.GetByID(ctx, id)
+	if err != nil {
+		logger.Warn("Failed to get purchase order", zap.Uint("purchase_order_id", id), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	httputil.HandleSuccess(c, o)
+}
+
+// Update 修改草稿采购单
+func (h *PurchaseOrderHandler) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.PurchaseOrderUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	o, err := h.service.Update(ctx, id, &purchase.UpdateRequest{
+		WarehouseID: req.WarehouseID,
+		Lines:       toPurchaseLines(req.Lines),
+		Remark:      req.Remark,
+	})
+	if err != nil {
+		logger.Warn("Failed to update purchase order", zap.Uint("purchase_order_id", id), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Purchase order updated", zap.Uint("purchase_order_id", id))
+	httputil.HandleSuccess(c, o)
+}
+
+// List 查询采购单列表
+func (h *PurchaseOrderHandler) List(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req dto.PurchaseOrderQueryRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Warn("Invalid query parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("查询参数错误"))
+		return
+	}
+
+	orders, total, err := h.service.List(ctx, &purchase.QueryRequest{
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		SupplierID: req.SupplierID,
+		Status:     req.Status,
+	})
+	if err != nil {
+		logger.Error("Failed to list purchase orders", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	page, pageSize := common.GetPageInfo(req.Page, req.PageSize)
+	httputil.HandlePageSuccess(c, orders, total, page, pageSize)
+}
+
+// Send 下发采购单
+func (h *PurchaseOrderHandler) Send(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	o, err := h.service.Send(ctx, id)
+	if err != nil {
+		logger.Warn("Failed to send purchase order", zap.Uint("purchase_order_id", id), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Purchase order sent", zap.Uint("purchase_order_id", id), zap.String("order_no", o.OrderNo))
+	httputil.HandleSuccess(c, o)
+}
+
+// Receive 采购收货
+func (h *PurchaseOrderHandler) Receive(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.PurchaseReceiveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	actorID, _ := middleware.GetActorID(c)
+	serviceReq := &purchase.ReceiveRequest{
+		Lines:   make([]purchase.ReceiveLineRequest, 0, len(req.Lines)),
+		ActorID: actorID,
+	}
+	for _, line := range req.Lines {
+		serviceReq.Lines = append(serviceReq.Lines, purchase.ReceiveLineRequest{
+			LineID:   line.LineID,
+			Quantity: line.Quantity,
+		})
+	}
+
+	o, err := h.service.Receive(ctx, id, serviceReq)
+	if err != nil {
+		logger.Warn("Failed to receive purchase order", zap.Uint("purchase_order_id", id), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Purchase order received",
+		zap.Uint("purchase_order_id", id),
+		zap.String("status", o.Status),
+		zap.Uint("operator_id", actorID),
+	)
+	httputil.HandleSuccess(c, o)
+}
+
+// toPurchaseLines 转换采购明细 DTO 为 Service 层需要的格式
+func toPurchaseLines(lines []dto.PurchaseLineRequest) []purchase.LineRequest {
+	if len(lines) == 0 {
+		return nil
+	}
+	result := make([]purchase.LineRequest, 0, len(lines))
+	for _, line := range lines {
+		result = append(result, purchase.LineRequest{
+			PowerSupplyID: line.PowerSupplyID,
+			Quantity:      line.Quantity,
+			UnitCost:      line.UnitCost,
+		})
+	}
+	return result
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"power-supply-sys/internal/domain/rbac"
+	"power-supply-sys/internal/service"
+	httputil "power-supply-sys/internal/transport/http"
+	"power-supply-sys/internal/transport/http/dto"
+	"power-supply-sys/pkg/common"
+	"power-supply-sys/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RBACHandler 角色权限处理器
+type RBACHandler struct {
+	service service.RBACService
+}
+
+// NewRBACHandler 创建角色权限处理器
+func NewRBACHandler(rbacService service.RBACService) *RBACHandler {
+	return &RBACHandler{
+		service: rbacService,
+	}
+}
+
+// ListRoles 获取角色列表
+func (h *RBACHandler) ListRoles(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	roles, err := h.service.ListRoles(ctx)
+	if err != nil {
+		logger.Error("Failed to list roles", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	httputil.HandleSuccess(c, roles)
+}
+
+// ListPermissions 获取权限列表
+func (h *RBACHandler) ListPermissions(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	permissions, err := h.service.ListPermissions(ctx)
+	if err != nil {
+		logger.Error("Failed to list permissions", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	httputil.HandleSuccess(c, permissions)
+}
+
+// CreateRole 创建角色
+func (h *RBACHandler) CreateRole(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req dto.RoleCreateRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	// 转换 DTO 为 Service 层需要的格式
+	serviceReq := &rbac.RoleCreateRequest{
+		Code:        req.Code,
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: req.Permissions,
+	}
+	role, err := h.service.CreateRole(ctx, serviceReq)
+	if err != nil {
+		logger.Error("Failed to create role", zap.Error(err), zap.String("code", req.Code))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Role created successfully", zap.Uint("role_id", role.ID), zap.String("code", role.Code))
+	httputil.HandleSuccess(c, role)
+}
+
+// SetRolePermissions 设置角色权限
+func (h *RBACHandler) SetRolePermissions(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.RolePermissionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	role, err := h.service.SetRolePermissions(ctx, id, req.Permissions)
+	if err != nil {
+		logger.Error("Failed to set role permissions", zap.Uint("role_id", id), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Role permissions updated successfully", zap.Uint("role_id", id), zap.Strings("permissions", req.Permissions))
+	httputil.HandleSuccess(c, role)
+}
+
+// AssignUserRole 为用户分配角色
+func (h *RBACHandler) AssignUserRole(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.UserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	u, err := h.service.AssignUserRole(ctx, id, req.Role)
+	if err != nil {
+		logger.Error("Failed to assign user role", zap.Uint("user_id", id), zap.String("role", req.Role), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("User role assigned successfully", zap.Uint("user_id", id), zap.String("role", req.Role))
+	httputil.HandleSuccess(c, u)
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"power-supply-sys/internal/domain/power"
+	"power-supply-sys/internal/service"
+	httputil "power-supply-sys/internal/transport/http"
+	"power-supply-sys/internal/transport/http/dto"
+	"power-supply-sys/pkg/common"
+	"power-supply-sys/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RecommendHandler 电源推荐处理器
+type RecommendHandler struct {
+	service service.RecommendService
+}
+
+// NewRecommendHandler 创建电源推荐处理器
+func NewRecommendHandler(recommendService service.RecommendService) *RecommendHandler {
+	return &RecommendHandler{
+		service: recommendService,
+	}
+}
+
+// Recommend 按整机配置估算功耗并推荐电源
+func (h *RecommendHandler) Recommend(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req dto.PowerRecommendRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	result, err := h.service.Recommend(ctx, &power.RecommendRequest{
+		CPUModel:    req.CPUModel,
+		CPUTDP:      req.CPUTDP,
+		GPUModel:    req.GPUModel,
+		GPUTGP:      req.GPUTGP,
+		GPUCount:    req.GPUCount,
+		Drives:      req.Drives,
+		Fans:        req.Fans,
+		Overclocked: req.Overclocked,
+		Limit:       req.Limit,
+	})
+	if err != nil {
+		logger.Warn("Failed to recommend power supplies", zap.String("cpu_model", req.CPUModel), zap.String("gpu_model", req.GPUModel), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	httputil.HandleSuccess(c, result)
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"power-supply-sys/internal/domain/inventory"
+	"power-supply-sys/internal/service"
+	httputil "power-supply-sys/internal/transport/http"
+	"power-supply-sys/internal/transport/http/dto"
+	"power-supply-sys/internal/transport/http/middleware"
+	"power-supply-sys/pkg/common"
+	"power-supply-sys/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ReservationHandler 库存预留处理器
+type ReservationHandler struct {
+	service service.ReservationService
+}
+
+// NewReservationHandler 创建库存预留处理器
+func NewReservationHandler(reservationService service.ReservationService) *ReservationHandler {
+	return &ReservationHandler{
+		service: reservationService,
+	}
+}
+
+// Create 预留库存
+func (h *ReservationHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req dto.ReservationCreateRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	actorID, _ := middleware.GetActorID(c)
+	r, err := h.service.Reserve(ctx, &inventory.ReserveRequest{
+		PowerSupplyID: req.PowerSupplyID,
+		Quantity:      req.Quantity,
+		Reference:     req.Reference,
+		ActorID:       actorID,
+	})
+	if err != nil {
+		logger.Warn("Failed to reserve stock",
+			zap.Uint("power_supply_id", req.PowerSupplyID),
+			zap.Int("quantity", req.Quantity),
+			zap.Error(err),
+		)
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Stock reserved",
+		zap.Uint("reservation_id", r.ID),
+		zap.Uint("power_supply_id", r.PowerSupplyID),
+		zap.Int("quantity", r.Quantity),
+	)
+	httputil.HandleSuccess(c, r)
+}
+
+// Get 获取预留详情
+func (h *ReservationHandler) Get(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	r, err := h.service.GetByID(ctx, id)
+	if err != nil {
+		logger.Warn("Reservation not found", zap.Uint("reservation_id", id), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	httputil.HandleSuccess(c, r)
+}
+
+// Confirm 确认预留（转为销售出库）
+func (h *ReservationHandler) Confirm(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	actorID, _ := middleware.GetActorID(c)
+	r, err := h.service.Confirm(ctx, id, actorID)
+	if err != nil {
+		logger.Warn("Failed to confirm reservation", zap.Uint("reservation_id", id), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Reservation confirmed", zap.Uint("reservation_id", id))
+	httputil.HandleSuccess(c, r)
+}
+
+// Cancel 取消预留
+func (h *ReservationHandler) Cancel(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	r, err := h.service.Cancel(ctx, id)
+	if err != nil {
+		logger.Warn("Failed to cancel reservation", zap.Uint("reservation_id", id), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Reservation cancelled", zap.Uint("reservation_id", id))
+	httputil.HandleSuccess(c, r)
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"power-supply-sys/internal/domain/token"
+	"power-supply-sys/internal/service"
+	httputil "power-supply-sys/internal/transport/http"
+	"power-supply-sys/internal/transport/http/dto"
+	"power-supply-sys/internal/transport/http/middleware"
+	"power-supply-sys/pkg/common"
+	"power-supply-sys/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SessionHandler 登录会话处理器
+type SessionHandler struct {
+	service service.AuthService
+}
+
+// NewSessionHandler 创建登录会话处理器
+func NewSessionHandler(authService service.AuthService) *SessionHandler {
+	return &SessionHandler{
+		service: authService,
+	}
+}
+
+// List 获取当前用户的登录会话列表
+func (h *SessionHandler) List(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	actor, err := currentActor(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	sessions, err := h.service.ListSessions(ctx, actor.ID)
+	if err != nil {
+		logger.Error("Failed to list sessions", zap.Uint("user_id", actor.ID), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	currentSessionID, _ := middleware.GetSessionID(c)
+	resp := make([]dto.SessionResponse, 0, len(sessions))
+	for _, s := range sessions {
+		resp = append(resp, toSessionResponse(s, currentSessionID))
+	}
+	httputil.HandleSuccess(c, resp)
+}
+
+// Terminate 终止当前用户的指定登录会话（远程退出该设备）
+func (h *SessionHandler) Terminate(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	actor, err := currentActor(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.service.TerminateSession(ctx, actor.ID, id); err != nil {
+		logger.Warn("Failed to terminate session", zap.Uint("user_id", actor.ID), zap.Uint("session_id", id), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Session terminated", zap.Uint("user_id", actor.ID), zap.Uint("session_id", id))
+	httputil.HandleSuccess(c, gin.H{"message": "会话已终止"})
+}
+
+// toSessionResponse 转换登录会话为响应格式
+func toSessionResponse(s *token.Session, currentSessionID string) dto.SessionResponse {
+	return dto.SessionResponse{
+		ID:         s.ID,
+		UserAgent:  s.UserAgent,
+		IP:         s.IP,
+		CreatedAt:  s.CreatedAt,
+		LastSeenAt: s.LastSeenAt,
+		ExpiresAt:  s.ExpiresAt,
+		Current:    s.SessionID == currentSessionID,
+	}
+}
@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"power-supply-sys/internal/domain/supplier"
+	"power-supply-sys/internal/service"
+	httputil "power-supply-sys/internal/transport/http"
+	"power-supply-sys/internal/transport/http/dto"
+	"power-supply-sys/pkg/common"
+	"power-supply-sys/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SupplierHandler 供应商处理器
+type SupplierHandler struct {
+	service service.SupplierService
+}
+
+// NewSupplierHandler 创建供应商处理器
+func NewSupplierHandler(supplierService service.SupplierService) *SupplierHandler {
+	return &SupplierHandler{
+		service: supplierService,
+	}
+}
+
+// Create 创建供应商
+func (h *SupplierHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req dto.SupplierCreateRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	s, err := h.service.Create(ctx, &supplier.CreateRequest{
+		Name:        req.Name,
+		ContactName: req.ContactName,
+		Phone:       req.Phone,
+		Email:       req.Email,
+		Address:     req.Address,
+		Remark:      req.Remark,
+	})
+	if err != nil {
+		logger.Warn("Failed to create supplier", zap.String("name", req.Name), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Supplier created", zap.Uint("supplier_id", s.ID), zap.String("name", s.Name))
+	httputil.HandleSuccess(c, s)
+}
+
+// Get 获取供应商详情
+func (h *SupplierHandler) Get(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	s, err := h.service.GetByID(ctx, id)
+	if err != nil {
+		logger.Warn("Supplier not found", zap.Uint("supplier_id", id), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	httputil.HandleSuccess(c, s)
+}
+
+// Update 更新供应商
+func (h *SupplierHandler) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.SupplierUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	s, err := h.service.Update(ctx, id, &supplier.UpdateRequest{
+		Name:        req.Name,
+		ContactName: req.ContactName,
+		Phone:       req.Phone,
+		Email:       req.Email,
+		Address:     req.Address,
+		Remark:      req.Remark,
+	})
+	if err != nil {
+		logger.Warn("Failed to update supplier", zap.Uint("supplier_id", id), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Supplier updated", zap.Uint("supplier_id", id))
+	httputil.HandleSuccess(c, s)
+}
+
+// Delete 删除供应商
+func (h *SupplierHandler) Delete(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.service.Delete(ctx, id); err != nil {
+		logger.Warn("Failed to delete supplier", zap.Uint("supplier_id", id), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Supplier deleted", zap.Uint("supplier_id", id))
+	httputil.HandleSuccess(c, gin.H{"message": "删除成功"})
+}
+
+// List 查询供应商列表
+func (h *SupplierHandler) List(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req dto.SupplierQueryRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Warn("Invalid query parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("查询参数错误"))
+		return
+	}
+
+	suppliers, total, err := h.service.List(ctx, &supplier.QueryRequest{
+		Page:          req.Page,
+		PageSize:      req.PageSize,
+		Name:          req.Name,
+		PowerSupplyID: req.PowerSupplyID,
+	})
+	if err != nil {
+		logger.Error("Failed to list suppliers", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	page, pageSize := common.GetPageInfo(req.Page, req.PageSize)
+	httputil.HandlePageSuccess(c, suppliers, total, page, pageSize)
+}
+
+// SetProduct 设置供应商供应的电源及采购成本价与交货周期
+func (h *SupplierHandler) SetProduct(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	powerSupplyID, err := common.ParseUintParam(c, "power_supply_id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.SupplierProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	p, err := h.service.SetProduct(ctx, id, &supplier.ProductRequest{
+		PowerSupplyID: powerSupplyID,
+		SupplierSKU:   req.SupplierSKU,
+		CostPrice:     req.CostPrice,
+		LeadTimeDays:  req.LeadTimeDays,
+	})
+	if err != nil {
+		logger.Warn("Failed to set supplier product",
+			zap.Uint("supplier_id", id),
+			zap.Uint("power_supply_id", powerSupplyID),
+			zap.Error(err),
+		)
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Supplier product set",
+		zap.Uint("supplier_id", id),
+		zap.Uint("power_supply_id", powerSupplyID),
+		zap.Float64("cost_price", p.CostPrice),
+	)
+	httputil.HandleSuccess(c, p)
+}
+
+// RemoveProduct 删除供应商供应的电源
+func (h *SupplierHandler) RemoveProduct(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	powerSupplyID, err := common.ParseUintParam(c, "power_supply_id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.service.RemoveProduct(ctx, id, powerSupplyID); err != nil {
+		logger.Warn("Failed to remove supplier product",
+			zap.Uint("supplier_id", id),
+			zap.Uint("power_supply_id", powerSupplyID),
+			zap.Error(err),
+		)
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Supplier product removed", zap.Uint("supplier_id", id), zap.Uint("power_supply_id", powerSupplyID))
+	httputil.HandleSuccess(c, gin.H{"message": "删除成功"})
+}
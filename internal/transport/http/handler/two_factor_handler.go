@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"power-supply-sys/internal/service"
+	httputil "power-supply-sys/internal/transport/http"
+	"power-supply-sys/internal/transport/http/dto"
+	"power-supply-sys/pkg/common"
+	"power-supply-sys/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// TwoFactorHandler 两步验证处理器
+type TwoFactorHandler struct {
+	service     service.TwoFactorService
+	authService service.AuthService
+}
+
+// NewTwoFactorHandler 创建两步验证处理器
+func NewTwoFactorHandler(twoFactorService service.TwoFactorService, authService service.AuthService) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		service:     twoFactorService,
+		authService: authService,
+	}
+}
+
+// Status 查询当前用户的两步验证状态
+func (h *TwoFactorHandler) Status(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	actor, err := currentActor(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	status, err := h.service.Status(ctx, actor.ID)
+	if err != nil {
+		logger.Error("Failed to get two-factor status", zap.Uint("user_id", actor.ID), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	httputil.HandleSuccess(c, dto.TwoFactorStatusResponse{
+		Enabled:                status.Enabled,
+		RecoveryCodesRemaining: status.RecoveryCodesRemaining,
+	})
+}
+
+// Enroll 登记两步验证，返回密钥与 otpauth 链接
+func (h *TwoFactorHandler) Enroll(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	actor, err := currentActor(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	enrollment, err := h.service.Enroll(ctx, actor.ID)
+	if err != nil {
+		logger.Warn("Failed to enroll two-factor", zap.Uint("user_id", actor.ID), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Two-factor enrollment started", zap.Uint("user_id", actor.ID))
+	httputil.HandleSuccess(c, dto.TwoFactorEnrollResponse{
+		Secret:          enrollment.Secret,
+		ProvisioningURI: enrollment.ProvisioningURI,
+	})
+}
+
+// Confirm 验证首个验证码并启用两步验证
+func (h *TwoFactorHandler) Confirm(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	actor, err := currentActor(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	codes, err := h.service.Confirm(ctx, actor.ID, req.Code)
+	if err != nil {
+		logger.Warn("Failed to confirm two-factor", zap.Uint("user_id", actor.ID), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Two-factor enabled", zap.Uint("user_id", actor.ID))
+	httputil.HandleSuccess(c, dto.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// Disable 关闭两步验证
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	actor, err := currentActor(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	if err := h.service.Disable(ctx, actor.ID, req.Code); err != nil {
+		logger.Warn("Failed to disable two-factor", zap.Uint("user_id", actor.ID), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Two-factor disabled", zap.Uint("user_id", actor.ID))
+	httputil.HandleSuccess(c, gin.H{"message": "两步验证已关闭"})
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	actor, err := currentActor(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	codes, err := h.service.RegenerateRecoveryCodes(ctx, actor.ID, req.Code)
+	if err != nil {
+		logger.Warn("Failed to regenerate recovery codes", zap.Uint("user_id", actor.ID), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Recovery codes regenerated", zap.Uint("user_id", actor.ID))
+	httputil.HandleSuccess(c, dto.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// Verify 两步登录：校验挑战令牌与验证码后签发令牌
+func (h *TwoFactorHandler) Verify(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req dto.TwoFactorVerifyRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	u, err := h.service.VerifyChallenge(ctx, req.ChallengeToken, req.Code)
+	if err != nil {
+		logger.Warn("Two-factor verification failed", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	respondTokens(c, u, "two_factor", h.authService)
+}
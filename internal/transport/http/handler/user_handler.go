@@ -1,11 +1,12 @@
 package handler
 
 import (
+	"context"
 	"power-supply-sys/internal/domain/user"
 	"power-supply-sys/internal/service"
 	httputil "power-supply-sys/internal/transport/http"
 	"power-supply-sys/internal/transport/http/dto"
-	"power-supply-sys/pkg/auth"
+	"power-supply-sys/internal/transport/http/middleware"
 	"power-supply-sys/pkg/common"
 	"power-supply-sys/pkg/logger"
 
@@ -15,20 +16,25 @@ import (
 
 // UserHandler 用户处理器
 type UserHandler struct {
-	service    service.UserService
-	jwtManager *auth.JWTManager
+	service             service.UserService
+	authService         service.AuthService
+	twoFactorService    service.TwoFactorService
+	verificationService service.EmailVerificationService
 }
 
 // NewUserHandler 创建用户处理器
-func NewUserHandler(userService service.UserService, jwtManager *auth.JWTManager) *UserHandler {
+func NewUserHandler(userService service.UserService, authService service.AuthService, twoFactorService service.TwoFactorService, verificationService service.EmailVerificationService) *UserHandler {
 	return &UserHandler{
-		service:    userService,
-		jwtManager: jwtManager,
+		service:             userService,
+		authService:         authService,
+		twoFactorService:    twoFactorService,
+		verificationService: verificationService,
 	}
 }
 
-// Create 创建用户
-func (h *UserHandler) Create(c *gin.Context) {
+// Register 注册用户，注册后处于待验证状态并发送验证邮件
+// 邮件发送失败时注册仍然成功，用户可以重新发送验证邮件
+func (h *UserHandler) Register(c *gin.Context) {
 	ctx := c.Request.Context()
 	var req dto.UserCreateRequest
 
@@ -47,14 +53,18 @@ func (h *UserHandler) Create(c *gin.Context) {
 		Nickname: req.Nickname,
 		Avatar:   req.Avatar,
 	}
-	u, err := h.service.Create(ctx, serviceReq)
+	u, err := h.service.Register(ctx, serviceReq)
 	if err != nil {
-		logger.Error("Failed to create user", zap.Error(err), zap.String("username", req.Username))
+		logger.Error("Failed to register user", zap.Error(err), zap.String("username", req.Username))
 		c.Error(err)
 		return
 	}
 
-	logger.Info("User created successfully", zap.Uint("user_id", u.ID), zap.String("username", u.Username))
+	if err := h.verificationService.Send(ctx, u); err != nil {
+		logger.Error("Failed to send verification mail", zap.Uint("user_id", u.ID), zap.Error(err))
+	}
+
+	logger.Info("User registered successfully", zap.Uint("user_id", u.ID), zap.String("username", u.Username))
 	httputil.HandleSuccess(c, u)
 }
 
@@ -95,6 +105,16 @@ func (h *UserHandler) Update(c *gin.Context) {
 		return
 	}
 
+	actor, err := currentActor(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if err := h.checkImpersonatedEmail(c, id, req.Email); err != nil {
+		c.Error(err)
+		return
+	}
+
 	// 转换 DTO 为 Service 层需要的格式
 	serviceReq := &user.UserUpdateRequest{
 		Email:    req.Email,
@@ -103,17 +123,107 @@ func (h *UserHandler) Update(c *gin.Context) {
 		Avatar:   req.Avatar,
 		Status:   req.Status,
 	}
-	u, err := h.service.Update(ctx, id, serviceReq)
+	u, err := h.service.Update(ctx, actor, id, serviceReq)
 	if err != nil {
 		logger.Error("Failed to update user", zap.Uint("user_id", id), zap.Error(err))
 		c.Error(err)
 		return
 	}
 
+	h.sendEmailVerification(ctx, u, req.Email)
+
 	logger.Info("User updated successfully", zap.Uint("user_id", id))
 	httputil.HandleSuccess(c, u)
 }
 
+// GetMe 获取当前用户资料
+func (h *UserHandler) GetMe(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	actor, err := currentActor(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	u, err := h.service.GetByID(ctx, actor.ID)
+	if err != nil {
+		logger.Warn("Current user not found", zap.Uint("user_id", actor.ID), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	httputil.HandleSuccess(c, u)
+}
+
+// UpdateMe 更新当前用户资料（只允许修改非特权字段）
+func (h *UserHandler) UpdateMe(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	actor, err := currentActor(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.UserProfileUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+	if err := h.checkImpersonatedEmail(c, actor.ID, req.Email); err != nil {
+		c.Error(err)
+		return
+	}
+
+	// 转换 DTO 为 Service 层需要的格式
+	serviceReq := &user.UserUpdateRequest{
+		Email:    req.Email,
+		Phone:    req.Phone,
+		Nickname: req.Nickname,
+		Avatar:   req.Avatar,
+	}
+	u, err := h.service.Update(ctx, actor, actor.ID, serviceReq)
+	if err != nil {
+		logger.Error("Failed to update profile", zap.Uint("user_id", actor.ID), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	h.sendEmailVerification(ctx, u, req.Email)
+
+	logger.Info("Profile updated successfully", zap.Uint("user_id", actor.ID))
+	httputil.HandleSuccess(c, u)
+}
+
+// checkImpersonatedEmail 模拟登录期间不能修改邮箱
+// 否则可以先把邮箱改为自己控制的地址，再通过邮箱验证与找回密码接管被模拟的账户
+func (h *UserHandler) checkImpersonatedEmail(c *gin.Context, id uint, email string) error {
+	if email == "" || !middleware.IsImpersonating(c) {
+		return nil
+	}
+	u, err := h.service.GetByID(c.Request.Context(), id)
+	if err != nil {
+		return err
+	}
+	if email != u.Email {
+		logger.Warn("Email change rejected during impersonation", zap.Uint("user_id", id))
+		return common.ErrForbidden("模拟登录期间不能修改邮箱")
+	}
+	return nil
+}
+
+// sendEmailVerification 修改邮箱后向待验证的邮箱发送验证邮件，发送失败只记录日志
+func (h *UserHandler) sendEmailVerification(ctx context.Context, u *user.User, email string) {
+	if email == "" || (email != u.PendingEmail && u.Status != user.StatusPending) {
+		return
+	}
+	if err := h.verificationService.Send(ctx, u); err != nil {
+		logger.Error("Failed to send verification mail", zap.Uint("user_id", u.ID), zap.Error(err))
+	}
+}
+
 // Delete 删除用户
 func (h *UserHandler) Delete(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -124,7 +234,13 @@ func (h *UserHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.Delete(ctx, id); err != nil {
+	actor, err := currentActor(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.service.Delete(ctx, actor, id); err != nil {
 		logger.Error("Failed to delete user", zap.Uint("user_id", id), zap.Error(err))
 		c.Error(err)
 		return
@@ -134,6 +250,26 @@ func (h *UserHandler) Delete(c *gin.Context) {
 	httputil.HandleSuccess(c, gin.H{"message": "删除成功"})
 }
 
+// Unlock 解除用户登录锁定（管理员）
+func (h *UserHandler) Unlock(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.service.Unlock(ctx, id); err != nil {
+		logger.Error("Failed to unlock user", zap.Uint("user_id", id), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("User unlocked successfully", zap.Uint("user_id", id))
+	httputil.HandleSuccess(c, gin.H{"message": "解锁成功"})
+}
+
 // List 获取用户列表
 func (h *UserHandler) List(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -179,6 +315,7 @@ func (h *UserHandler) Login(c *gin.Context) {
 	serviceReq := &user.LoginRequest{
 		Username: req.Username,
 		Password: req.Password,
+		ClientIP: c.ClientIP(),
 	}
 	// 验证用户名和密码
 	u, err := h.service.Login(ctx, serviceReq)
@@ -188,24 +325,6 @@ func (h *UserHandler) Login(c *gin.Context) {
 		return
 	}
 
-	// 生成 JWT token
-	if h.jwtManager == nil {
-		logger.Error("JWT manager not initialized")
-		c.Error(common.ErrInternal(nil))
-		return
-	}
-
-	token, err := h.jwtManager.GenerateToken(u.ID, u.Username)
-	if err != nil {
-		logger.Error("Failed to generate token", zap.Error(err))
-		c.Error(common.ErrInternal(err))
-		return
-	}
-
-	logger.Info("User logged in successfully", zap.Uint("user_id", u.ID), zap.String("username", u.Username))
-
-	httputil.HandleSuccess(c, dto.LoginResponse{
-		Token: token,
-		User:  u,
-	})
+	// 已启用两步验证的用户先签发登录挑战，验证通过后再签发令牌
+	respondLogin(c, u, "password", h.authService, h.twoFactorService)
 }
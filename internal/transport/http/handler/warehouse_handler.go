@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"power-supply-sys/internal/domain/warehouse"
+	"power-supply-sys/internal/service"
+	httputil "power-supply-sys/internal/transport/http"
+	"power-supply-sys/internal/transport/http/dto"
+	"power-supply-sys/pkg/common"
+	"power-supply-sys/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// WarehouseHandler 仓库处理器
+type WarehouseHandler struct {
+	service service.WarehouseService
+}
+
+// NewWarehouseHandler 创建仓库处理器
+func NewWarehouseHandler(warehouseService service.WarehouseService) *WarehouseHandler {
+	return &WarehouseHandler{
+		service: warehouseService,
+	}
+}
+
+// Create 创建仓库
+func (h *WarehouseHandler) Create(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req dto.WarehouseCreateRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	w, err := h.service.Create(ctx, &warehouse.CreateRequest{
+		Code:      req.Code,
+		Name:      req.Name,
+		Address:   req.Address,
+		IsDefault: req.IsDefault,
+	})
+	if err != nil {
+		logger.Warn("Failed to create warehouse", zap.String("code", req.Code), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Warehouse created", zap.Uint("warehouse_id", w.ID), zap.String("code", w.Code))
+	httputil.HandleSuccess(c, w)
+}
+
+// Get 获取仓库详情
+func (h *WarehouseHandler) Get(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	w, err := h.service.GetByID(ctx, id)
+	if err != nil {
+		logger.Warn("Warehouse not found", zap.Uint("warehouse_id", id), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	httputil.HandleSuccess(c, w)
+}
+
+// Update 更新仓库
+func (h *WarehouseHandler) Update(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := common.ParseUintParam(c, "id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.WarehouseUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request parameters", zap.Error(err))
+		c.Error(common.ErrInvalidParam("参数格式错误"))
+		return
+	}
+
+	w, err := h.service.Update(ctx, id, &warehouse.UpdateRequest{
+		Name:      req.Name,
+		Address:   req.Address,
+		IsDefault: req.IsDefault,
+	})
+	if err != nil {
+		logger.Warn("Failed to update warehouse", zap.Uint("warehouse_id", id), zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	logger.Info("Warehouse updated", zap.Uint("warehouse_id", id))
+	httputil.HandleSuccess(c, w)
+}
+
+// List 获取全部仓库
+func (h *WarehouseHandler) List(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	warehouses, err := h.service.List(ctx)
+	if err != nil {
+		logger.Error("Failed to list warehouses", zap.Error(err))
+		c.Error(err)
+		return
+	}
+
+	httputil.HandleSuccess(c, warehouses)
+}
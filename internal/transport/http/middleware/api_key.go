@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"context"
+
+	"power-supply-sys/internal/domain/apikey"
+	"power-supply-sys/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	// HeaderAPIKey 携带 API Key 的请求头
+	HeaderAPIKey = "X-API-Key"
+	// ContextKeyAPIKeyID context 中存储 API Key ID 的 key
+	ContextKeyAPIKeyID = "api_key_id"
+	// ContextKeyScopes context 中存储 API Key 权限范围的 key
+	ContextKeyScopes = "scopes"
+)
+
+// APIKeyAuthenticator API Key 校验接口（由服务层实现）
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, rawKey string) (*apikey.Principal, error)
+}
+
+// APIKeyAuth API Key 认证中间件
+// 请求携带 X-API-Key 时按 API Key 认证，否则交给 fallback（通常为 JWTAuth）处理
+// API Key 认证的请求在 RequirePermission 中还会受 Key 的权限范围限制
+func APIKeyAuth(apiKeys APIKeyAuthenticator, fallback gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rawKey := c.GetHeader(HeaderAPIKey)
+		if rawKey == "" {
+			fallback(c)
+			return
+		}
+
+		principal, err := apiKeys.Authenticate(c.Request.Context(), rawKey)
+		if err != nil {
+			logger.Warn("API key authentication failed", zap.String("path", c.Request.URL.Path), zap.Error(err))
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		c.Set(ContextKeyUserID, principal.UserID)
+		c.Set(ContextKeyUsername, principal.Username)
+		c.Set(ContextKeyRole, principal.Role)
+		c.Set(ContextKeyAPIKeyID, principal.KeyID)
+		c.Set(ContextKeyScopes, principal.Scopes)
+
+		logger.Debug("API key authenticated",
+			zap.Uint("user_id", principal.UserID),
+			zap.Uint("api_key_id", principal.KeyID),
+			zap.String("path", c.Request.URL.Path),
+		)
+
+		c.Next()
+	}
+}
+
+// GetScopes 从 context 中获取 API Key 的权限范围，非 API Key 认证的请求返回 false
+func GetScopes(c *gin.Context) ([]string, bool) {
+	scopes, exists := c.Get(ContextKeyScopes)
+	if !exists {
+		return nil, false
+	}
+	list, ok := scopes.([]string)
+	return list, ok
+}
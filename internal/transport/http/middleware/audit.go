@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"power-supply-sys/internal/domain/audit"
+	"power-supply-sys/pkg/common"
+	"power-supply-sys/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// AuditRecorder 审计日志记录接口（由服务层实现）
+type AuditRecorder interface {
+	RecordWrite(ctx context.Context, entry *audit.Log) error
+}
+
+// ImpersonationAudit 模拟登录审计中间件
+// 使用模拟令牌发起的写请求（GET/HEAD/OPTIONS 以外）在处理完成后记录审计日志，
+// 同时记录实际操作的管理员与被模拟的用户；需放在 JWTAuth 之后
+func ImpersonationAudit(recorder AuditRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if !IsImpersonating(c) || !isWriteMethod(c.Request.Method) {
+			return
+		}
+
+		actorID, _ := GetActorID(c)
+		actorName, _ := GetActorUsername(c)
+		userID, _ := GetUserID(c)
+		username, _ := GetUsername(c)
+		entry := &audit.Log{
+			ActorID:       actorID,
+			ActorUsername: actorName,
+			UserID:        userID,
+			Username:      username,
+			Method:        c.Request.Method,
+			Path:          c.Request.URL.Path,
+			StatusCode:    responseStatus(c),
+			IP:            c.ClientIP(),
+		}
+
+		logger.Info("Impersonated write",
+			zap.Uint("actor_id", entry.ActorID),
+			zap.Uint("user_id", entry.UserID),
+			zap.String("method", entry.Method),
+			zap.String("path", entry.Path),
+			zap.Int("status", entry.StatusCode),
+		)
+
+		// 请求已处理完成，审计写入失败只记录日志，不影响响应
+		if err := recorder.RecordWrite(c.Request.Context(), entry); err != nil {
+			logger.Error("Failed to record audit log", zap.Uint("actor_id", entry.ActorID), zap.Error(err))
+		}
+	}
+}
+
+// isWriteMethod 判断是否为写请求
+func isWriteMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
+
+// responseStatus 获取请求最终的响应状态码
+// 错误响应由外层的 ErrorHandler 写入，此处按错误码推算
+func responseStatus(c *gin.Context) int {
+	if c.Writer.Written() || len(c.Errors) == 0 {
+		return c.Writer.Status()
+	}
+	if appErr, ok := c.Errors.Last().Err.(*common.AppError); ok {
+		return appErr.Code.GetHTTPStatus()
+	}
+	return http.StatusInternalServerError
+}
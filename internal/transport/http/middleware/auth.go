@@ -1,13 +1,17 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"strings"
+	"time"
 
 	"power-supply-sys/pkg/auth"
 	"power-supply-sys/pkg/common"
 	"power-supply-sys/pkg/logger"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 	"go.uber.org/zap"
 )
 
@@ -16,10 +20,48 @@ const (
 	ContextKeyUserID = "user_id"
 	// ContextKeyUsername context 中存储用户名的 key
 	ContextKeyUsername = "username"
+	// ContextKeySessionID context 中存储会话 ID 的 key
+	ContextKeySessionID = "session_id"
+	// ContextKeyRole context 中存储角色编码的 key
+	ContextKeyRole = "role"
+	// ContextKeyActorID context 中存储模拟登录实际操作者 ID 的 key
+	ContextKeyActorID = "actor_id"
+	// ContextKeyActorUsername context 中存储模拟登录实际操作者用户名的 key
+	ContextKeyActorUsername = "actor_username"
+
+	// HeaderRenewedToken 滑动会话续期时返回新 access token 的响应头
+	HeaderRenewedToken = "X-Renewed-Token"
 )
 
+// SessionValidator 会话校验接口（由服务层实现）
+type SessionValidator interface {
+	IsSessionActive(ctx context.Context, sessionID string) (bool, error)
+}
+
+// JWTAuthOption JWT 认证中间件的可选参数 - 函数式选项模式
+type JWTAuthOption func(*jwtAuthOptions)
+
+// jwtAuthOptions JWT 认证中间件的可选配置
+type jwtAuthOptions struct {
+	renewAfter float64 // 滑动会话续期阈值（有效期已过比例），0 表示不启用
+}
+
+// WithSlidingSession 启用滑动会话：token 有效期已过 renewAfter 比例（0-1）时，
+// 在响应头 X-Renewed-Token 中返回续期后的新 token
+func WithSlidingSession(renewAfter float64) JWTAuthOption {
+	return func(o *jwtAuthOptions) {
+		o.renewAfter = renewAfter
+	}
+}
+
 // JWTAuth JWT 认证中间件
-func JWTAuth(jwtManager *auth.JWTManager) gin.HandlerFunc {
+// sessions 不为空时，要求 token 绑定的会话仍然有效（注销、远程退出或吊销后立即失效）
+func JWTAuth(jwtManager *auth.JWTManager, sessions SessionValidator, opts ...JWTAuthOption) gin.HandlerFunc {
+	options := &jwtAuthOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	return func(c *gin.Context) {
 		// 从 Header 中获取 token
 		authHeader := c.GetHeader("Authorization")
@@ -45,14 +87,57 @@ func JWTAuth(jwtManager *auth.JWTManager) gin.HandlerFunc {
 		claims, err := jwtManager.ParseToken(tokenString)
 		if err != nil {
 			logger.Warn("Failed to parse token", zap.Error(err))
-			c.Error(common.ErrInvalidToken())
+			c.Error(tokenError(err))
 			c.Abort()
 			return
 		}
 
+		// 校验会话是否已被吊销
+		if sessions != nil {
+			if claims.SessionID == "" {
+				logger.Warn("Token without session", zap.Uint("user_id", claims.UserID))
+				c.Error(common.ErrInvalidToken())
+				c.Abort()
+				return
+			}
+			active, err := sessions.IsSessionActive(c.Request.Context(), claims.SessionID)
+			if err != nil {
+				c.Error(err)
+				c.Abort()
+				return
+			}
+			if !active {
+				logger.Warn("Session revoked",
+					zap.Uint("user_id", claims.UserID),
+					zap.String("session_id", claims.SessionID),
+					zap.String("jti", claims.ID),
+				)
+				c.Error(common.NewError(common.ErrCodeInvalidToken, "会话已失效，请重新登录"))
+				c.Abort()
+				return
+			}
+		}
+
+		// 滑动会话：临近过期时下发续期后的 token（会话已校验有效，续期不延长会话本身）
+		// 模拟令牌不续期，过期后需要重新发起模拟
+		if options.renewAfter > 0 && !claims.IsImpersonated() && claims.ElapsedFraction(time.Now()) >= options.renewAfter {
+			renewed, err := jwtManager.Renew(claims)
+			if err != nil {
+				logger.Error("Failed to renew token", zap.Uint("user_id", claims.UserID), zap.Error(err))
+			} else {
+				c.Header(HeaderRenewedToken, renewed)
+			}
+		}
+
 		// 将用户信息存入 context
 		c.Set(ContextKeyUserID, claims.UserID)
 		c.Set(ContextKeyUsername, claims.Username)
+		c.Set(ContextKeySessionID, claims.SessionID)
+		c.Set(ContextKeyRole, claims.Role)
+		if claims.IsImpersonated() {
+			c.Set(ContextKeyActorID, claims.Actor.UserID)
+			c.Set(ContextKeyActorUsername, claims.Actor.Username)
+		}
 
 		logger.Debug("User authenticated",
 			zap.Uint("user_id", claims.UserID),
@@ -64,6 +149,22 @@ func JWTAuth(jwtManager *auth.JWTManager) gin.HandlerFunc {
 	}
 }
 
+// tokenError 将 token 解析错误归类为对应的错误码，便于客户端区分处理（如过期时静默刷新）
+func tokenError(err error) *common.AppError {
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return common.ErrTokenExpired()
+	case errors.Is(err, jwt.ErrTokenNotValidYet):
+		return common.ErrTokenNotYetValid()
+	case errors.Is(err, jwt.ErrTokenSignatureInvalid), errors.Is(err, jwt.ErrTokenUnverifiable):
+		return common.ErrTokenSignature()
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		return common.ErrTokenMalformed()
+	default:
+		return common.ErrInvalidToken()
+	}
+}
+
 // GetUserID 从 context 中获取用户 ID
 func GetUserID(c *gin.Context) (uint, bool) {
 	userID, exists := c.Get(ContextKeyUserID)
@@ -84,6 +185,52 @@ func GetUsername(c *gin.Context) (string, bool) {
 	return name, ok
 }
 
+// GetSessionID 从 context 中获取会话 ID
+func GetSessionID(c *gin.Context) (string, bool) {
+	sessionID, exists := c.Get(ContextKeySessionID)
+	if !exists {
+		return "", false
+	}
+	sid, ok := sessionID.(string)
+	return sid, ok
+}
+
+// GetRole 从 context 中获取角色编码
+func GetRole(c *gin.Context) (string, bool) {
+	role, exists := c.Get(ContextKeyRole)
+	if !exists {
+		return "", false
+	}
+	code, ok := role.(string)
+	return code, ok
+}
+
+// GetActorID 从 context 中获取实际操作者 ID
+// 模拟登录时返回发起模拟的管理员，否则与 GetUserID 相同
+func GetActorID(c *gin.Context) (uint, bool) {
+	if actorID, exists := c.Get(ContextKeyActorID); exists {
+		id, ok := actorID.(uint)
+		return id, ok
+	}
+	return GetUserID(c)
+}
+
+// GetActorUsername 从 context 中获取实际操作者用户名
+// 模拟登录时返回发起模拟的管理员，否则与 GetUsername 相同
+func GetActorUsername(c *gin.Context) (string, bool) {
+	if actor, exists := c.Get(ContextKeyActorUsername); exists {
+		name, ok := actor.(string)
+		return name, ok
+	}
+	return GetUsername(c)
+}
+
+// IsImpersonating 判断当前请求是否使用模拟令牌
+func IsImpersonating(c *gin.Context) bool {
+	_, exists := c.Get(ContextKeyActorID)
+	return exists
+}
+
 // MustGetUserID 从 context 中获取用户 ID，如果不存在则 panic
 func MustGetUserID(c *gin.Context) uint {
 	userID, ok := GetUserID(c)
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"power-supply-sys/pkg/common"
+	"power-supply-sys/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// DenyImpersonation 拒绝使用模拟令牌访问的中间件（需在 JWTAuth 之后使用）
+// 用于密码、两步验证、登录会话与 API Key 等凭证管理路由：
+// 模拟令牌不能签发刷新令牌，也不能借此为被模拟用户创建在模拟结束后仍有效的凭证
+func DenyImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !IsImpersonating(c) {
+			c.Next()
+			return
+		}
+
+		actorID, _ := GetActorID(c)
+		userID, _ := GetUserID(c)
+		logger.Warn("Impersonation denied",
+			zap.Uint("actor_id", actorID),
+			zap.Uint("user_id", userID),
+			zap.String("path", c.Request.URL.Path),
+		)
+		c.Error(common.ErrForbidden("模拟登录期间不能管理凭证与会话"))
+		c.Abort()
+	}
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"power-supply-sys/pkg/common"
+	"power-supply-sys/pkg/logger"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestDenyImpersonation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger.Logger = zap.NewNop()
+
+	// serve 模拟 JWTAuth 写入身份信息后访问受保护的路由
+	serve := func(impersonating bool) (*httptest.ResponseRecorder, *gin.Context, bool) {
+		var handled bool
+		var ctx *gin.Context
+		r := gin.New()
+		r.POST("/api-keys",
+			func(c *gin.Context) {
+				ctx = c
+				c.Set(ContextKeyUserID, uint(2))
+				if impersonating {
+					c.Set(ContextKeyActorID, uint(1))
+				}
+			},
+			DenyImpersonation(),
+			func(c *gin.Context) {
+				handled = true
+				c.Status(http.StatusCreated)
+			},
+		)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api-keys", nil))
+		return w, ctx, handled
+	}
+
+	t.Run("普通令牌放行", func(t *testing.T) {
+		w, _, handled := serve(false)
+		assert.True(t, handled)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("模拟令牌拒绝", func(t *testing.T) {
+		_, c, handled := serve(true)
+		assert.False(t, handled)
+		require.NotEmpty(t, c.Errors)
+		assert.True(t, common.HasErrorCode(c.Errors.Last().Err, common.ErrCodeForbidden))
+		assert.Equal(t, http.StatusForbidden, c.Errors.Last().Err.(*common.AppError).Code.GetHTTPStatus())
+	})
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"power-supply-sys/internal/domain/rbac"
+	"power-supply-sys/pkg/common"
+	"power-supply-sys/pkg/logger"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RequirePermission 权限校验中间件（需在 JWTAuth 或 APIKeyAuth 之后使用）
+// API Key 认证的请求还要求权限在 Key 的权限范围内
+func RequirePermission(checker rbac.PermissionChecker, permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, _ := GetRole(c)
+
+		if scopes, ok := GetScopes(c); ok && !slices.Contains(scopes, permission) {
+			userID, _ := GetUserID(c)
+			logger.Warn("API key scope denied",
+				zap.Uint("user_id", userID),
+				zap.String("permission", permission),
+				zap.String("path", c.Request.URL.Path),
+			)
+			c.Error(common.ErrForbidden("API Key 无此权限范围"))
+			c.Abort()
+			return
+		}
+
+		allowed, err := checker.HasPermission(c.Request.Context(), role, permission)
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		if !allowed {
+			userID, _ := GetUserID(c)
+			logger.Warn("Permission denied",
+				zap.Uint("user_id", userID),
+				zap.String("role", role),
+				zap.String("permission", permission),
+				zap.String("path", c.Request.URL.Path),
+			)
+			c.Error(common.ErrForbidden(""))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailTokenSigner(t *testing.T) {
+	signer := NewEmailTokenSigner("test-secret", time.Hour)
+
+	t.Run("签发并解析", func(t *testing.T) {
+		raw, err := signer.Generate(42, "user@example.com")
+		require.NoError(t, err)
+
+		claims, err := signer.Parse(raw)
+		require.NoError(t, err)
+		assert.Equal(t, "user@example.com", claims.Email)
+		id, err := claims.UserID()
+		require.NoError(t, err)
+		assert.Equal(t, uint(42), id)
+	})
+
+	t.Run("不同密钥签发的令牌无效", func(t *testing.T) {
+		raw, err := NewEmailTokenSigner("other-secret", time.Hour).Generate(42, "user@example.com")
+		require.NoError(t, err)
+
+		_, err = signer.Parse(raw)
+		assert.ErrorIs(t, err, jwt.ErrTokenSignatureInvalid)
+	})
+
+	t.Run("过期令牌无效", func(t *testing.T) {
+		raw, err := NewEmailTokenSigner("test-secret", -time.Minute).Generate(42, "user@example.com")
+		require.NoError(t, err)
+
+		_, err = signer.Parse(raw)
+		assert.ErrorIs(t, err, jwt.ErrTokenExpired)
+	})
+
+	t.Run("使用相同密钥的 access token 不能冒用", func(t *testing.T) {
+		access, err := NewJWTManager("test-secret", time.Hour).GenerateToken(42, "user")
+		require.NoError(t, err)
+
+		_, err = signer.Parse(access)
+		assert.Error(t, err)
+	})
+}
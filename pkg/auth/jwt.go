@@ -2,49 +2,173 @@ package auth
 
 import (
 	"errors"
+	"fmt"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
 // Claims JWT 自定义声明
+// 每个 token 带有唯一的 jti（RegisteredClaims.ID），并通过 sid 关联到所属登录会话
+// 模拟登录时 UserID 为被模拟的用户，Actor 为实际操作的管理员
 type Claims struct {
+	UserID    uint         `json:"user_id"`
+	Username  string       `json:"username"`
+	SessionID string       `json:"sid,omitempty"`
+	Role      string       `json:"role,omitempty"`
+	Actor     *ActorClaims `json:"act,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// ActorClaims 模拟登录的实际操作者（对应 RFC 8693 的 act 声明）
+type ActorClaims struct {
 	UserID   uint   `json:"user_id"`
 	Username string `json:"username"`
-	jwt.RegisteredClaims
+}
+
+// IsImpersonated 是否为模拟登录签发的 token
+func (c *Claims) IsImpersonated() bool {
+	return c.Actor != nil
+}
+
+// ElapsedFraction 返回 token 有效期已经过的比例，缺少签发或过期时间时返回 0
+func (c *Claims) ElapsedFraction(now time.Time) float64 {
+	if c.IssuedAt == nil || c.ExpiresAt == nil {
+		return 0
+	}
+	lifetime := c.ExpiresAt.Sub(c.IssuedAt.Time)
+	if lifetime <= 0 {
+		return 1
+	}
+	return float64(now.Sub(c.IssuedAt.Time)) / float64(lifetime)
+}
+
+// TokenOption 生成 token 的可选参数 - 函数式选项模式
+type TokenOption func(*Claims)
+
+// WithSessionID 设置 token 所属的会话（刷新令牌族）
+func WithSessionID(sessionID string) TokenOption {
+	return func(c *Claims) {
+		c.SessionID = sessionID
+	}
+}
+
+// WithActor 设置模拟登录的实际操作者
+func WithActor(userID uint, username string) TokenOption {
+	return func(c *Claims) {
+		c.Actor = &ActorClaims{UserID: userID, Username: username}
+	}
+}
+
+// WithRole 设置 token 携带的角色
+func WithRole(role string) TokenOption {
+	return func(c *Claims) {
+		c.Role = role
+	}
 }
 
 // JWTManager JWT 管理器
+// 使用当前密钥签发 token，并按 kid 从密钥环中选择验签密钥，轮换后的旧密钥在宽限期内仍可验签
 type JWTManager struct {
-	secret      string
-	expireHours int
+	mu      sync.RWMutex
+	current *SigningKey
+	keys    map[string]*SigningKey
+	expire  time.Duration
 }
 
-// NewJWTManager 创建 JWT 管理器
-func NewJWTManager(secret string, expireHours int) *JWTManager {
-	return &JWTManager{
-		secret:      secret,
-		expireHours: expireHours,
+// NewJWTManager 创建使用 HS256 共享密钥的 JWT 管理器，expire 为 access token 有效期
+func NewJWTManager(secret string, expire time.Duration) *JWTManager {
+	return NewJWTManagerWithKeys(NewHMACKey("", secret), expire)
+}
+
+// NewJWTManagerWithKeys 创建 JWT 管理器，current 为签名密钥，previous 为仍需验签的旧密钥
+func NewJWTManagerWithKeys(current *SigningKey, expire time.Duration, previous ...*SigningKey) *JWTManager {
+	m := &JWTManager{
+		current: current,
+		keys:    make(map[string]*SigningKey),
+		expire:  expire,
+	}
+	for _, k := range previous {
+		m.keys[k.ID] = k
 	}
+	m.keys[current.ID] = current
+	return m
+}
+
+// Expire 获取 access token 有效期
+func (m *JWTManager) Expire() time.Duration {
+	return m.expire
+}
+
+// Rotate 切换到新的签名密钥，旧密钥在 grace 时间内仍可用于验签
+func (m *JWTManager) Rotate(next *SigningKey, grace time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	retired := *m.current
+	retired.ExpiresAt = time.Now().Add(grace)
+	m.keys[retired.ID] = &retired
+	m.keys[next.ID] = next
+	m.current = next
+}
+
+// JWKS 返回当前可用于验签的公钥集合（共享密钥不会公开）
+func (m *JWTManager) JWKS() JWKS {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := time.Now()
+	set := JWKS{Keys: []JWK{}}
+	for _, k := range m.keys {
+		if k.IsExpired(now) {
+			continue
+		}
+		if jwk, ok := k.JWK(); ok {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+	return set
 }
 
 // GenerateToken 生成 JWT token
-func (m *JWTManager) GenerateToken(userID uint, username string) (string, error) {
+func (m *JWTManager) GenerateToken(userID uint, username string, opts ...TokenOption) (string, error) {
 	now := time.Now()
-	expiresAt := now.Add(time.Duration(m.expireHours) * time.Hour)
+	expiresAt := now.Add(m.expire)
+
+	jti, err := NewRandomID()
+	if err != nil {
+		return "", err
+	}
 
 	claims := &Claims{
 		UserID:   userID,
 		Username: username,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
+	for _, opt := range opts {
+		opt(claims)
+	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(m.secret))
+	m.mu.RLock()
+	key := m.current
+	m.mu.RUnlock()
+	if !key.CanSign() {
+		return "", errors.New("signing key has no private key")
+	}
+
+	token := jwt.NewWithClaims(key.Method, claims)
+	if key.ID != "" {
+		token.Header["kid"] = key.ID
+	}
+	tokenString, err := token.SignedString(key.signKey)
 	if err != nil {
 		return "", err
 	}
@@ -55,11 +179,15 @@ func (m *JWTManager) GenerateToken(userID uint, username string) (string, error)
 // ParseToken 解析 JWT token
 func (m *JWTManager) ParseToken(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (any, error) {
-		// 验证签名算法
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		key, err := m.verificationKey(token)
+		if err != nil {
+			return nil, err
+		}
+		// 验证签名算法与密钥类型一致，防止算法混淆攻击
+		if !key.accepts(token.Method) {
 			return nil, errors.New("unexpected signing method")
 		}
-		return []byte(m.secret), nil
+		return key.verifyKey, nil
 	})
 
 	if err != nil {
@@ -73,6 +201,32 @@ func (m *JWTManager) ParseToken(tokenString string) (*Claims, error) {
 	return nil, errors.New("invalid token")
 }
 
+// verificationKey 按 kid 选择验签密钥，不带 kid 的 token 使用当前密钥
+func (m *JWTManager) verificationKey(token *jwt.Token) (*SigningKey, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return m.current, nil
+	}
+
+	key, ok := m.keys[kid]
+	if !ok || key.IsExpired(time.Now()) {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	return key, nil
+}
+
+// Renew 基于已验证的声明签发新 token（保留会话、角色与模拟登录的操作者，重新计算有效期）
+func (m *JWTManager) Renew(claims *Claims) (string, error) {
+	opts := []TokenOption{WithSessionID(claims.SessionID), WithRole(claims.Role)}
+	if claims.Actor != nil {
+		opts = append(opts, WithActor(claims.Actor.UserID, claims.Actor.Username))
+	}
+	return m.GenerateToken(claims.UserID, claims.Username, opts...)
+}
+
 // RefreshToken 刷新 token
 func (m *JWTManager) RefreshToken(tokenString string) (string, error) {
 	claims, err := m.ParseToken(tokenString)
@@ -81,5 +235,5 @@ func (m *JWTManager) RefreshToken(tokenString string) (string, error) {
 	}
 
 	// 生成新的 token
-	return m.GenerateToken(claims.UserID, claims.Username)
+	return m.Renew(claims)
 }
@@ -11,17 +11,18 @@ import (
 
 func TestNewJWTManager(t *testing.T) {
 	secret := "test-secret"
-	expireHours := 24
+	expire := 24 * time.Hour
 
-	manager := NewJWTManager(secret, expireHours)
+	manager := NewJWTManager(secret, expire)
 
 	assert.NotNil(t, manager)
-	assert.Equal(t, secret, manager.secret)
-	assert.Equal(t, expireHours, manager.expireHours)
+	assert.Equal(t, []byte(secret), manager.current.signKey)
+	assert.Equal(t, expire, manager.expire)
+	assert.Equal(t, expire, manager.Expire())
 }
 
 func TestGenerateToken(t *testing.T) {
-	manager := NewJWTManager("test-secret", 24)
+	manager := NewJWTManager("test-secret", 24*time.Hour)
 
 	tests := []struct {
 		name     string
@@ -71,7 +72,7 @@ func TestGenerateToken(t *testing.T) {
 }
 
 func TestParseToken(t *testing.T) {
-	manager := NewJWTManager("test-secret", 24)
+	manager := NewJWTManager("test-secret", 24*time.Hour)
 
 	tests := []struct {
 		name      string
@@ -109,7 +110,7 @@ func TestParseToken(t *testing.T) {
 			name: "错误的签名",
 			setupFunc: func() string {
 				// 使用不同的secret生成token
-				wrongManager := NewJWTManager("wrong-secret", 24)
+				wrongManager := NewJWTManager("wrong-secret", 24*time.Hour)
 				token, _ := wrongManager.GenerateToken(1, "testuser")
 				return token
 			},
@@ -130,7 +131,7 @@ func TestParseToken(t *testing.T) {
 					},
 				}
 				token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-				tokenString, _ := token.SignedString([]byte(manager.secret))
+				tokenString, _ := token.SignedString([]byte("test-secret"))
 				return tokenString
 			},
 			wantErr: true,
@@ -157,7 +158,7 @@ func TestParseToken(t *testing.T) {
 }
 
 func TestRefreshToken(t *testing.T) {
-	manager := NewJWTManager("test-secret", 24)
+	manager := NewJWTManager("test-secret", 24*time.Hour)
 
 	tests := []struct {
 		name      string
@@ -192,7 +193,7 @@ func TestRefreshToken(t *testing.T) {
 					},
 				}
 				token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-				tokenString, _ := token.SignedString([]byte(manager.secret))
+				tokenString, _ := token.SignedString([]byte("test-secret"))
 				return tokenString
 			},
 			wantErr: true,
@@ -238,7 +239,7 @@ func TestTokenExpiry(t *testing.T) {
 		},
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(manager.secret))
+	tokenString, err := token.SignedString([]byte("test-secret"))
 	require.NoError(t, err)
 
 	// 等待token过期
@@ -251,7 +252,7 @@ func TestTokenExpiry(t *testing.T) {
 }
 
 func TestTokenWithDifferentSigningMethods(t *testing.T) {
-	manager := NewJWTManager("test-secret", 24)
+	manager := NewJWTManager("test-secret", 24*time.Hour)
 
 	// 创建使用不同签名方法的token
 	now := time.Now()
@@ -266,7 +267,7 @@ func TestTokenWithDifferentSigningMethods(t *testing.T) {
 
 	// 使用 HS512 而不是 HS256
 	token := jwt.NewWithClaims(jwt.SigningMethodHS512, claims)
-	tokenString, err := token.SignedString([]byte(manager.secret))
+	tokenString, err := token.SignedString([]byte("test-secret"))
 	require.NoError(t, err)
 
 	// HS512也是HMAC方法，应该可以解析（虽然实际应用中应该验证具体算法）
@@ -274,3 +275,67 @@ func TestTokenWithDifferentSigningMethods(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, parsedClaims)
 }
+
+func TestGenerateTokenWithOptions(t *testing.T) {
+	manager := NewJWTManager("test-secret", time.Hour)
+
+	token, err := manager.GenerateToken(1, "testuser", WithSessionID("session-1"), WithRole("admin"))
+	require.NoError(t, err)
+
+	claims, err := manager.ParseToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "session-1", claims.SessionID)
+	assert.Equal(t, "admin", claims.Role)
+	assert.Len(t, claims.ID, 32)
+	jti := claims.ID
+
+	// 刷新后的 token 保留会话与角色，jti 重新生成
+	refreshed, err := manager.RefreshToken(token)
+	require.NoError(t, err)
+	claims, err = manager.ParseToken(refreshed)
+	require.NoError(t, err)
+	assert.Equal(t, "session-1", claims.SessionID)
+	assert.Equal(t, "admin", claims.Role)
+	assert.NotEqual(t, jti, claims.ID)
+}
+
+func TestGenerateTokenWithActor(t *testing.T) {
+	manager := NewJWTManager("test-secret", time.Hour)
+
+	plain, err := manager.GenerateToken(2, "alice")
+	require.NoError(t, err)
+	claims, err := manager.ParseToken(plain)
+	require.NoError(t, err)
+	assert.False(t, claims.IsImpersonated())
+
+	token, err := manager.GenerateToken(2, "alice", WithSessionID("session-1"), WithActor(1, "admin"))
+	require.NoError(t, err)
+	claims, err = manager.ParseToken(token)
+	require.NoError(t, err)
+	require.True(t, claims.IsImpersonated())
+	assert.Equal(t, uint(2), claims.UserID)
+	assert.Equal(t, uint(1), claims.Actor.UserID)
+	assert.Equal(t, "admin", claims.Actor.Username)
+
+	// 续期后保留实际操作者
+	renewed, err := manager.Renew(claims)
+	require.NoError(t, err)
+	claims, err = manager.ParseToken(renewed)
+	require.NoError(t, err)
+	require.True(t, claims.IsImpersonated())
+	assert.Equal(t, uint(1), claims.Actor.UserID)
+}
+
+func TestClaimsElapsedFraction(t *testing.T) {
+	now := time.Now()
+	claims := &Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(now.Add(-30 * time.Minute)),
+			ExpiresAt: jwt.NewNumericDate(now.Add(30 * time.Minute)),
+		},
+	}
+	assert.InDelta(t, 0.5, claims.ElapsedFraction(now), 0.01)
+
+	// 缺少签发时间时不参与续期
+	assert.Equal(t, float64(0), (&Claims{}).ElapsedFraction(now))
+}
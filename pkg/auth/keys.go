@@ -0,0 +1,240 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minRSAKeyBits RSA 密钥的最小长度
+const minRSAKeyBits = 2048
+
+// SigningKey JWT 签名密钥
+// 仅持有公钥的密钥只能用于验签（如轮换后保留的旧密钥）
+type SigningKey struct {
+	ID        string            // kid，为空时签发的 token 不带 kid 头
+	Method    jwt.SigningMethod // 签名算法
+	ExpiresAt time.Time         // 验签截止时间，零值表示长期有效
+
+	signKey   any
+	verifyKey any
+}
+
+// NewHMACKey 创建 HS256 共享密钥
+func NewHMACKey(id, secret string) *SigningKey {
+	return &SigningKey{
+		ID:        id,
+		Method:    jwt.SigningMethodHS256,
+		signKey:   []byte(secret),
+		verifyKey: []byte(secret),
+	}
+}
+
+// NewPrivateKey 根据私钥创建签名密钥（RSA 使用 RS256，Ed25519 使用 EdDSA）
+// id 为空时使用公钥的 RFC 7638 指纹作为 kid
+func NewPrivateKey(id string, key crypto.Signer) (*SigningKey, error) {
+	k, err := NewPublicKey(id, key.Public())
+	if err != nil {
+		return nil, err
+	}
+	k.signKey = key
+	return k, nil
+}
+
+// NewPublicKey 根据公钥创建仅用于验签的密钥
+// id 为空时使用公钥的 RFC 7638 指纹作为 kid
+func NewPublicKey(id string, key crypto.PublicKey) (*SigningKey, error) {
+	k := &SigningKey{ID: id, verifyKey: key}
+	switch pub := key.(type) {
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("rsa key too short: %d bits, need at least %d", pub.N.BitLen(), minRSAKeyBits)
+		}
+		k.Method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		k.Method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", key)
+	}
+
+	if k.ID == "" {
+		thumbprint, err := k.Thumbprint()
+		if err != nil {
+			return nil, err
+		}
+		k.ID = thumbprint
+	}
+	return k, nil
+}
+
+// LoadPrivateKeyFile 从 PEM 文件加载私钥（PKCS#8 或 PKCS#1）
+func LoadPrivateKeyFile(id, path string) (*SigningKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := ParsePrivateKeyPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return NewPrivateKey(id, key)
+}
+
+// LoadPublicKeyFile 从 PEM 文件加载验签公钥（也接受私钥文件，只取其公钥）
+func LoadPublicKeyFile(id, path string) (*SigningKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := ParsePublicKeyPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return NewPublicKey(id, key)
+}
+
+// ParsePrivateKeyPEM 解析 PEM 格式私钥
+func ParsePrivateKeyPEM(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no pem block found")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key type %T", key)
+		}
+		return signer, nil
+	default:
+		return nil, fmt.Errorf("unsupported pem block type %q", block.Type)
+	}
+}
+
+// ParsePublicKeyPEM 解析 PEM 格式公钥
+func ParsePublicKeyPEM(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no pem block found")
+	}
+
+	switch block.Type {
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	case "RSA PRIVATE KEY", "PRIVATE KEY":
+		signer, err := ParsePrivateKeyPEM(data)
+		if err != nil {
+			return nil, err
+		}
+		return signer.Public(), nil
+	default:
+		return nil, fmt.Errorf("unsupported pem block type %q", block.Type)
+	}
+}
+
+// CanSign 是否持有私钥（可用于签发 token）
+func (k *SigningKey) CanSign() bool {
+	return k.signKey != nil
+}
+
+// accepts 签名算法是否与密钥匹配：共享密钥接受任意 HMAC 算法，非对称密钥要求算法完全一致
+func (k *SigningKey) accepts(method jwt.SigningMethod) bool {
+	if _, ok := k.Method.(*jwt.SigningMethodHMAC); ok {
+		_, ok = method.(*jwt.SigningMethodHMAC)
+		return ok
+	}
+	return method.Alg() == k.Method.Alg()
+}
+
+// IsExpired 验签截止时间是否已过
+func (k *SigningKey) IsExpired(now time.Time) bool {
+	return !k.ExpiresAt.IsZero() && now.After(k.ExpiresAt)
+}
+
+// JWK 返回公钥的 JWK 表示，共享密钥不可公开，返回 false
+func (k *SigningKey) JWK() (JWK, bool) {
+	jwk := JWK{Kid: k.ID, Use: "sig", Alg: k.Method.Alg()}
+	switch pub := k.verifyKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	default:
+		return JWK{}, false
+	}
+	return jwk, true
+}
+
+// Thumbprint 计算公钥的 RFC 7638 指纹（base64url 编码的 SHA-256）
+func (k *SigningKey) Thumbprint() (string, error) {
+	jwk, ok := k.JWK()
+	if !ok {
+		return "", errors.New("thumbprint requires a public key")
+	}
+
+	// RFC 7638 要求只包含必需成员，且按字典序排列
+	var members any
+	switch jwk.Kty {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+	default:
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Crv, jwk.Kty, jwk.X}
+	}
+
+	data, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+// JWK JSON Web Key（RFC 7517）
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
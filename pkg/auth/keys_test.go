@@ -0,0 +1,208 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writePEM 将密钥写入临时 PEM 文件
+func writePEM(t *testing.T, blockType string, der []byte) string {
+	path := filepath.Join(t.TempDir(), "key.pem")
+	err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600)
+	require.NoError(t, err)
+	return path
+}
+
+// newEd25519Key 生成 Ed25519 签名密钥
+func newEd25519Key(t *testing.T, id string) *SigningKey {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	key, err := NewPrivateKey(id, priv)
+	require.NoError(t, err)
+	return key
+}
+
+func TestLoadKeyFiles(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	t.Run("PKCS#1 RSA 私钥", func(t *testing.T) {
+		path := writePEM(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+		key, err := LoadPrivateKeyFile("rsa-1", path)
+		require.NoError(t, err)
+		assert.Equal(t, "rsa-1", key.ID)
+		assert.Equal(t, "RS256", key.Method.Alg())
+		assert.True(t, key.CanSign())
+	})
+
+	t.Run("PKCS#8 Ed25519 私钥，kid 默认为指纹", func(t *testing.T) {
+		der, err := x509.MarshalPKCS8PrivateKey(edKey)
+		require.NoError(t, err)
+		key, err := LoadPrivateKeyFile("", writePEM(t, "PRIVATE KEY", der))
+		require.NoError(t, err)
+		assert.Equal(t, "EdDSA", key.Method.Alg())
+
+		thumbprint, err := key.Thumbprint()
+		require.NoError(t, err)
+		assert.Equal(t, thumbprint, key.ID)
+	})
+
+	t.Run("公钥只能验签", func(t *testing.T) {
+		der, err := x509.MarshalPKIXPublicKey(rsaKey.Public())
+		require.NoError(t, err)
+		key, err := LoadPublicKeyFile("rsa-1", writePEM(t, "PUBLIC KEY", der))
+		require.NoError(t, err)
+		assert.False(t, key.CanSign())
+	})
+
+	t.Run("拒绝过短的 RSA 密钥", func(t *testing.T) {
+		weak, err := rsa.GenerateKey(rand.Reader, 1024)
+		require.NoError(t, err)
+		_, err = LoadPrivateKeyFile("", writePEM(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(weak)))
+		assert.Error(t, err)
+	})
+
+	t.Run("无效文件", func(t *testing.T) {
+		_, err := LoadPrivateKeyFile("", writePEM(t, "CERTIFICATE", []byte("x")))
+		assert.Error(t, err)
+		_, err = LoadPrivateKeyFile("", filepath.Join(t.TempDir(), "missing.pem"))
+		assert.Error(t, err)
+	})
+}
+
+func TestThumbprint(t *testing.T) {
+	// RFC 8037 附录 A.3 的 Ed25519 指纹示例
+	pub := ed25519.PublicKey(mustDecodeBase64URL(t, "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"))
+	key, err := NewPublicKey("", crypto.PublicKey(pub))
+	require.NoError(t, err)
+	assert.Equal(t, "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k", key.ID)
+}
+
+func TestJWTManager_AsymmetricKeys(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	rsaSigning, err := NewPrivateKey("rsa-1", rsaKey)
+	require.NoError(t, err)
+
+	for _, key := range []*SigningKey{rsaSigning, newEd25519Key(t, "ed-1")} {
+		t.Run(key.Method.Alg(), func(t *testing.T) {
+			manager := NewJWTManagerWithKeys(key, time.Hour)
+			tokenString, err := manager.GenerateToken(1, "testuser")
+			require.NoError(t, err)
+
+			token, _, err := jwt.NewParser().ParseUnverified(tokenString, &Claims{})
+			require.NoError(t, err)
+			assert.Equal(t, key.ID, token.Header["kid"])
+			assert.Equal(t, key.Method.Alg(), token.Header["alg"])
+
+			claims, err := manager.ParseToken(tokenString)
+			require.NoError(t, err)
+			assert.Equal(t, uint(1), claims.UserID)
+
+			// 只持有公钥的一方可以验签
+			verifier, err := NewPublicKey(key.ID, key.verifyKey)
+			require.NoError(t, err)
+			_, err = NewJWTManagerWithKeys(verifier, time.Hour).ParseToken(tokenString)
+			assert.NoError(t, err)
+			_, err = NewJWTManagerWithKeys(verifier, time.Hour).GenerateToken(1, "testuser")
+			assert.Error(t, err)
+		})
+	}
+
+	t.Run("拒绝未知 kid", func(t *testing.T) {
+		other := NewJWTManagerWithKeys(newEd25519Key(t, "ed-2"), time.Hour)
+		tokenString, err := other.GenerateToken(1, "testuser")
+		require.NoError(t, err)
+
+		_, err = NewJWTManagerWithKeys(rsaSigning, time.Hour).ParseToken(tokenString)
+		assert.Error(t, err)
+	})
+
+	t.Run("拒绝算法混淆", func(t *testing.T) {
+		// 使用 RSA 公钥作为 HMAC 密钥伪造 token
+		pubDER, err := x509.MarshalPKIXPublicKey(rsaKey.Public())
+		require.NoError(t, err)
+		forged := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{UserID: 1})
+		forged.Header["kid"] = "rsa-1"
+		tokenString, err := forged.SignedString(pubDER)
+		require.NoError(t, err)
+
+		_, err = NewJWTManagerWithKeys(rsaSigning, time.Hour).ParseToken(tokenString)
+		assert.Error(t, err)
+	})
+}
+
+func TestJWTManager_Rotate(t *testing.T) {
+	oldKey := newEd25519Key(t, "old")
+	manager := NewJWTManagerWithKeys(oldKey, time.Hour)
+	oldToken, err := manager.GenerateToken(1, "testuser")
+	require.NoError(t, err)
+
+	manager.Rotate(newEd25519Key(t, "new"), time.Hour)
+
+	t.Run("新 token 使用新密钥", func(t *testing.T) {
+		tokenString, err := manager.GenerateToken(1, "testuser")
+		require.NoError(t, err)
+		token, _, err := jwt.NewParser().ParseUnverified(tokenString, &Claims{})
+		require.NoError(t, err)
+		assert.Equal(t, "new", token.Header["kid"])
+	})
+
+	t.Run("宽限期内旧 token 仍有效", func(t *testing.T) {
+		_, err := manager.ParseToken(oldToken)
+		assert.NoError(t, err)
+
+		kids := []string{}
+		for _, k := range manager.JWKS().Keys {
+			kids = append(kids, k.Kid)
+		}
+		assert.Equal(t, []string{"new", "old"}, kids)
+	})
+
+	t.Run("宽限期结束后旧密钥失效", func(t *testing.T) {
+		newToken, err := manager.GenerateToken(1, "testuser")
+		require.NoError(t, err)
+
+		manager.Rotate(newEd25519Key(t, "newer"), -time.Second)
+
+		_, err = manager.ParseToken(newToken)
+		assert.Error(t, err)
+		for _, k := range manager.JWKS().Keys {
+			assert.NotEqual(t, "new", k.Kid)
+		}
+	})
+}
+
+func TestJWTManager_JWKSExcludesSharedSecret(t *testing.T) {
+	manager := NewJWTManager("test-secret", time.Hour)
+	assert.Empty(t, manager.JWKS().Keys)
+
+	manager = NewJWTManagerWithKeys(NewHMACKey("hs-1", "test-secret"), time.Hour, newEd25519Key(t, "ed-1"))
+	jwks := manager.JWKS()
+	require.Len(t, jwks.Keys, 1)
+	assert.Equal(t, "OKP", jwks.Keys[0].Kty)
+	assert.Equal(t, "Ed25519", jwks.Keys[0].Crv)
+	assert.Equal(t, "sig", jwks.Keys[0].Use)
+}
+
+// mustDecodeBase64URL 解码 base64url 字符串
+func mustDecodeBase64URL(t *testing.T, s string) []byte {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	require.NoError(t, err)
+	return data
+}
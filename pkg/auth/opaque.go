@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// opaqueTokenBytes 不透明 token 的随机字节数
+const opaqueTokenBytes = 32
+
+// GenerateOpaqueToken 生成随机不透明 token，返回原文及其摘要
+// 原文只下发给客户端，数据库中只保存摘要
+func GenerateOpaqueToken() (string, string, error) {
+	buf := make([]byte, opaqueTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	raw := base64.RawURLEncoding.EncodeToString(buf)
+	return raw, HashOpaqueToken(raw), nil
+}
+
+// HashOpaqueToken 计算不透明 token 的 SHA-256 摘要（十六进制）
+func HashOpaqueToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewRandomID 生成 32 位十六进制随机 ID
+func NewRandomID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateOpaqueToken(t *testing.T) {
+	raw, hash, err := GenerateOpaqueToken()
+	require.NoError(t, err)
+	assert.NotEmpty(t, raw)
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, HashOpaqueToken(raw))
+
+	other, _, err := GenerateOpaqueToken()
+	require.NoError(t, err)
+	assert.NotEqual(t, raw, other)
+}
+
+func TestNewRandomID(t *testing.T) {
+	id1, err := NewRandomID()
+	require.NoError(t, err)
+	id2, err := NewRandomID()
+	require.NoError(t, err)
+
+	assert.Len(t, id1, 32)
+	assert.NotEqual(t, id1, id2)
+}
@@ -0,0 +1,197 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 密码摘要算法
+const (
+	HashArgon2id = "argon2id"
+	HashBcrypt   = "bcrypt"
+)
+
+var (
+	// ErrPasswordMismatch 密码与摘要不匹配
+	ErrPasswordMismatch = errors.New("password does not match")
+	// ErrUnsupportedHash 无法识别的摘要格式
+	ErrUnsupportedHash = errors.New("unsupported password hash format")
+	// ErrPasswordTooLong 密码超过算法支持的最大长度（bcrypt 只支持 72 字节）
+	ErrPasswordTooLong = errors.New("password too long")
+)
+
+// bcryptMaxPasswordBytes bcrypt 支持的最大密码字节数
+const bcryptMaxPasswordBytes = 72
+
+// PasswordHasher 密码摘要器
+// 摘要采用 PHC 字符串格式，自身记录算法与参数，因此任一摘要器都能校验其他算法生成的摘要
+type PasswordHasher interface {
+	// Hash 使用当前算法与参数计算密码摘要
+	Hash(password string) (string, error)
+	// Verify 校验密码，不匹配时返回 ErrPasswordMismatch
+	Verify(encoded, password string) error
+	// NeedsRehash 摘要的算法或参数与当前配置不一致时返回 true
+	NeedsRehash(encoded string) bool
+}
+
+// Argon2idParams argon2id 参数
+type Argon2idParams struct {
+	Memory      uint32 // 内存开销（KiB）
+	Iterations  uint32 // 迭代次数
+	Parallelism uint8  // 并行度
+	SaltLength  uint32 // 盐长度（字节）
+	KeyLength   uint32 // 摘要长度（字节）
+}
+
+// DefaultArgon2idParams 默认 argon2id 参数：64 MiB 内存、3 次迭代、并行度 2
+func DefaultArgon2idParams() Argon2idParams {
+	return Argon2idParams{
+		Memory:      64 * 1024,
+		Iterations:  3,
+		Parallelism: 2,
+		SaltLength:  16,
+		KeyLength:   32,
+	}
+}
+
+// passwordHasher 密码摘要器实现，algorithm 为新摘要使用的算法
+type passwordHasher struct {
+	algorithm  string
+	argon2     Argon2idParams
+	bcryptCost int
+}
+
+var _ PasswordHasher = &passwordHasher{}
+
+// NewArgon2idHasher 创建使用 argon2id 的摘要器，已有的 bcrypt 摘要仍可校验并会被标记为需要重新摘要
+func NewArgon2idHasher(params Argon2idParams) PasswordHasher {
+	defaults := DefaultArgon2idParams()
+	if params.SaltLength == 0 {
+		params.SaltLength = defaults.SaltLength
+	}
+	if params.KeyLength == 0 {
+		params.KeyLength = defaults.KeyLength
+	}
+	return &passwordHasher{algorithm: HashArgon2id, argon2: params, bcryptCost: bcrypt.DefaultCost}
+}
+
+// NewBcryptHasher 创建使用 bcrypt 的摘要器，cost 超出范围时使用 bcrypt.DefaultCost
+func NewBcryptHasher(cost int) PasswordHasher {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = bcrypt.DefaultCost
+	}
+	return &passwordHasher{algorithm: HashBcrypt, argon2: DefaultArgon2idParams(), bcryptCost: cost}
+}
+
+// Hash 计算密码摘要
+func (h *passwordHasher) Hash(password string) (string, error) {
+	if h.algorithm == HashBcrypt {
+		if len(password) > bcryptMaxPasswordBytes {
+			return "", ErrPasswordTooLong
+		}
+		hashed, err := bcrypt.GenerateFromPassword([]byte(password), h.bcryptCost)
+		if err != nil {
+			return "", err
+		}
+		return string(hashed), nil
+	}
+
+	salt := make([]byte, h.argon2.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	p := h.argon2
+	key := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		HashArgon2id, argon2.Version, p.Memory, p.Iterations, p.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify 按摘要中记录的算法与参数校验密码
+func (h *passwordHasher) Verify(encoded, password string) error {
+	if isBcryptHash(encoded) {
+		err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return ErrPasswordMismatch
+		}
+		return err
+	}
+
+	p, salt, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return err
+	}
+	actual := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+	if subtle.ConstantTimeCompare(actual, key) != 1 {
+		return ErrPasswordMismatch
+	}
+	return nil
+}
+
+// NeedsRehash 摘要算法与当前算法不同，或参数低于/不同于当前配置时需要重新摘要
+func (h *passwordHasher) NeedsRehash(encoded string) bool {
+	if h.algorithm == HashBcrypt {
+		if !isBcryptHash(encoded) {
+			return true
+		}
+		cost, err := bcrypt.Cost([]byte(encoded))
+		return err != nil || cost != h.bcryptCost
+	}
+
+	p, _, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+	return p.Memory != h.argon2.Memory ||
+		p.Iterations != h.argon2.Iterations ||
+		p.Parallelism != h.argon2.Parallelism ||
+		uint32(len(key)) != h.argon2.KeyLength
+}
+
+// isBcryptHash 判断是否为 bcrypt 摘要（$2a$、$2b$、$2y$ 前缀）
+func isBcryptHash(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") ||
+		strings.HasPrefix(encoded, "$2b$") ||
+		strings.HasPrefix(encoded, "$2y$")
+}
+
+// decodeArgon2id 解析 argon2id 的 PHC 字符串：$argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>
+func decodeArgon2id(encoded string) (Argon2idParams, []byte, []byte, error) {
+	var p Argon2idParams
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != HashArgon2id {
+		return p, nil, nil, ErrUnsupportedHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, ErrUnsupportedHash
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Iterations, &p.Parallelism); err != nil {
+		return p, nil, nil, ErrUnsupportedHash
+	}
+	if p.Memory == 0 || p.Iterations == 0 || p.Parallelism == 0 {
+		return p, nil, nil, ErrUnsupportedHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return p, nil, nil, ErrUnsupportedHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return p, nil, nil, ErrUnsupportedHash
+	}
+	p.SaltLength = uint32(len(salt))
+	p.KeyLength = uint32(len(key))
+	return p, salt, key, nil
+}
@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testArgon2idParams 测试用的低开销参数
+var testArgon2idParams = Argon2idParams{Memory: 1024, Iterations: 1, Parallelism: 1}
+
+func TestArgon2idHasher(t *testing.T) {
+	hasher := NewArgon2idHasher(testArgon2idParams)
+
+	encoded, err := hasher.Hash("correct horse")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encoded, "$argon2id$v=19$m=1024,t=1,p=1$"))
+
+	assert.NoError(t, hasher.Verify(encoded, "correct horse"))
+	assert.ErrorIs(t, hasher.Verify(encoded, "wrong horse"), ErrPasswordMismatch)
+	assert.False(t, hasher.NeedsRehash(encoded))
+
+	// 相同密码每次使用不同的盐
+	other, err := hasher.Hash("correct horse")
+	require.NoError(t, err)
+	assert.NotEqual(t, encoded, other)
+}
+
+func TestPasswordHasher_CrossAlgorithm(t *testing.T) {
+	argonHasher := NewArgon2idHasher(testArgon2idParams)
+	bcryptHasher := NewBcryptHasher(bcrypt.MinCost)
+
+	legacy, err := bcrypt.GenerateFromPassword([]byte("secret123"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	t.Run("argon2id 摘要器校验历史 bcrypt 摘要", func(t *testing.T) {
+		assert.NoError(t, argonHasher.Verify(string(legacy), "secret123"))
+		assert.ErrorIs(t, argonHasher.Verify(string(legacy), "secret124"), ErrPasswordMismatch)
+		assert.True(t, argonHasher.NeedsRehash(string(legacy)))
+	})
+
+	t.Run("bcrypt 摘要器校验 argon2id 摘要", func(t *testing.T) {
+		encoded, err := argonHasher.Hash("secret123")
+		require.NoError(t, err)
+		assert.NoError(t, bcryptHasher.Verify(encoded, "secret123"))
+		assert.True(t, bcryptHasher.NeedsRehash(encoded))
+		assert.False(t, bcryptHasher.NeedsRehash(string(legacy)))
+	})
+
+	t.Run("无法识别的摘要", func(t *testing.T) {
+		assert.ErrorIs(t, argonHasher.Verify("plaintext", "plaintext"), ErrUnsupportedHash)
+		assert.ErrorIs(t, argonHasher.Verify("$argon2id$v=19$m=0,t=1,p=1$c2FsdA$a2V5", "x"), ErrUnsupportedHash)
+		assert.True(t, argonHasher.NeedsRehash("plaintext"))
+	})
+}
+
+func TestPasswordHasher_NeedsRehash(t *testing.T) {
+	old := NewArgon2idHasher(testArgon2idParams)
+	encoded, err := old.Hash("secret123")
+	require.NoError(t, err)
+
+	stronger := testArgon2idParams
+	stronger.Iterations = 2
+	assert.True(t, NewArgon2idHasher(stronger).NeedsRehash(encoded))
+
+	legacy, err := NewBcryptHasher(bcrypt.MinCost).Hash("secret123")
+	require.NoError(t, err)
+	assert.True(t, NewBcryptHasher(bcrypt.MinCost+1).NeedsRehash(legacy))
+}
+
+func TestBcryptHasher_TooLong(t *testing.T) {
+	_, err := NewBcryptHasher(bcrypt.MinCost).Hash(strings.Repeat("a", 73))
+	assert.ErrorIs(t, err, ErrPasswordTooLong)
+}
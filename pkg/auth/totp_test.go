@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"encoding/base32"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret RFC 6238 附录 B 中 SHA1 测试向量使用的密钥
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestTOTPCode(t *testing.T) {
+	// RFC 6238 测试向量为 8 位，6 位验证码取其后 6 位
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, tt := range tests {
+		code, err := TOTPCode(rfcSecret, TOTPStep(time.Unix(tt.unix, 0)))
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, code, tt.unix)
+	}
+
+	_, err := TOTPCode("not base32!", 1)
+	assert.Error(t, err)
+}
+
+func TestVerifyTOTP(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	require.NoError(t, err)
+	now := time.Now()
+
+	t.Run("当前时间步", func(t *testing.T) {
+		code, err := TOTPCode(secret, TOTPStep(now))
+		require.NoError(t, err)
+		step, ok := VerifyTOTP(secret, code, now)
+		assert.True(t, ok)
+		assert.Equal(t, TOTPStep(now), step)
+	})
+
+	t.Run("允许一个时间步的偏差", func(t *testing.T) {
+		code, err := TOTPCode(secret, TOTPStep(now)-1)
+		require.NoError(t, err)
+		_, ok := VerifyTOTP(secret, code, now)
+		assert.True(t, ok)
+	})
+
+	t.Run("超出偏差范围", func(t *testing.T) {
+		code, err := TOTPCode(secret, TOTPStep(now)-3)
+		require.NoError(t, err)
+		_, ok := VerifyTOTP(secret, code, now)
+		assert.False(t, ok)
+	})
+
+	t.Run("格式错误", func(t *testing.T) {
+		_, ok := VerifyTOTP(secret, "12345", now)
+		assert.False(t, ok)
+	})
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	uri := TOTPProvisioningURI("JBSWY3DPEHPK3PXP", "Power Supply", "alice")
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/Power%20Supply:alice?"))
+
+	parsed, err := url.Parse(uri)
+	require.NoError(t, err)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", parsed.Query().Get("secret"))
+	assert.Equal(t, "Power Supply", parsed.Query().Get("issuer"))
+	assert.Equal(t, "6", parsed.Query().Get("digits"))
+}
@@ -17,9 +17,14 @@ func NewBaseRepository[T any](db *gorm.DB) *BaseRepository[T] {
 	return &BaseRepository[T]{db: db}
 }
 
+// conn 获取数据库连接（自动加入上下文中的事务）
+func (r *BaseRepository[T]) conn(ctx context.Context) *gorm.DB {
+	return DBFromContext(ctx, r.db)
+}
+
 // Create 创建记录
 func (r *BaseRepository[T]) Create(ctx context.Context, entity *T) error {
-	if err := r.db.WithContext(ctx).Create(entity).Error; err != nil {
+	if err := r.conn(ctx).Create(entity).Error; err != nil {
 		return ErrDatabase(err)
 	}
 	return nil
@@ -28,7 +33,7 @@ func (r *BaseRepository[T]) Create(ctx context.Context, entity *T) error {
 // FindByID 根据ID查询记录
 func (r *BaseRepository[T]) FindByID(ctx context.Context, id uint) (*T, error) {
 	var entity T
-	err := r.db.WithContext(ctx).First(&entity, id).Error
+	err := r.conn(ctx).First(&entity, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrNotFound("记录")
@@ -41,7 +46,7 @@ func (r *BaseRepository[T]) FindByID(ctx context.Context, id uint) (*T, error) {
 // FindOne 根据条件查询单条记录
 func (r *BaseRepository[T]) FindOne(ctx context.Context, opts ...QueryOption) (*T, error) {
 	var entity T
-	db := r.db.WithContext(ctx).Model(new(T))
+	db := r.conn(ctx).Model(new(T))
 	db = ApplyQuery(db, opts...)
 
 	err := db.First(&entity).Error
@@ -56,7 +61,7 @@ func (r *BaseRepository[T]) FindOne(ctx context.Context, opts ...QueryOption) (*
 
 // Update 更新记录
 func (r *BaseRepository[T]) Update(ctx context.Context, entity *T, updates map[string]any) error {
-	if err := r.db.WithContext(ctx).Model(entity).Updates(updates).Error; err != nil {
+	if err := r.conn(ctx).Model(entity).Updates(updates).Error; err != nil {
 		return ErrDatabase(err)
 	}
 	return nil
@@ -64,7 +69,7 @@ func (r *BaseRepository[T]) Update(ctx context.Context, entity *T, updates map[s
 
 // UpdateByID 根据ID更新记录
 func (r *BaseRepository[T]) UpdateByID(ctx context.Context, id uint, updates map[string]any) error {
-	result := r.db.WithContext(ctx).Model(new(T)).Where("id = ?", id).Updates(updates)
+	result := r.conn(ctx).Model(new(T)).Where("id = ?", id).Updates(updates)
 	if result.Error != nil {
 		return ErrDatabase(result.Error)
 	}
@@ -76,7 +81,7 @@ func (r *BaseRepository[T]) UpdateByID(ctx context.Context, id uint, updates map
 
 // Delete 删除记录
 func (r *BaseRepository[T]) Delete(ctx context.Context, id uint) error {
-	result := r.db.WithContext(ctx).Delete(new(T), id)
+	result := r.conn(ctx).Delete(new(T), id)
 	if result.Error != nil {
 		return ErrDatabase(result.Error)
 	}
@@ -88,7 +93,7 @@ func (r *BaseRepository[T]) Delete(ctx context.Context, id uint) error {
 
 // DeleteByCondition 根据条件删除记录
 func (r *BaseRepository[T]) DeleteByCondition(ctx context.Context, opts ...QueryOption) error {
-	db := r.db.WithContext(ctx).Model(new(T))
+	db := r.conn(ctx).Model(new(T))
 	db = ApplyQuery(db, opts...)
 
 	result := db.Delete(new(T))
@@ -104,7 +109,7 @@ func (r *BaseRepository[T]) DeleteByCondition(ctx context.Context, opts ...Query
 // List 查询记录列表
 func (r *BaseRepository[T]) List(ctx context.Context, opts ...QueryOption) ([]*T, error) {
 	var entities []*T
-	db := r.db.WithContext(ctx).Model(new(T))
+	db := r.conn(ctx).Model(new(T))
 	db = ApplyQuery(db, opts...)
 
 	if err := db.Find(&entities).Error; err != nil {
@@ -116,7 +121,7 @@ func (r *BaseRepository[T]) List(ctx context.Context, opts ...QueryOption) ([]*T
 // Count 统计记录数量
 func (r *BaseRepository[T]) Count(ctx context.Context, opts ...QueryOption) (int64, error) {
 	var count int64
-	db := r.db.WithContext(ctx).Model(new(T))
+	db := r.conn(ctx).Model(new(T))
 	db = ApplyQuery(db, opts...)
 
 	if err := db.Count(&count).Error; err != nil {
@@ -137,7 +142,7 @@ func (r *BaseRepository[T]) Exists(ctx context.Context, opts ...QueryOption) (bo
 // First 查询第一条记录
 func (r *BaseRepository[T]) First(ctx context.Context, opts ...QueryOption) (*T, error) {
 	var entity T
-	db := r.db.WithContext(ctx).Model(new(T))
+	db := r.conn(ctx).Model(new(T))
 	db = ApplyQuery(db, opts...)
 
 	err := db.First(&entity).Error
@@ -155,7 +160,7 @@ func (r *BaseRepository[T]) BatchCreate(ctx context.Context, entities []*T) erro
 	if len(entities) == 0 {
 		return nil
 	}
-	if err := r.db.WithContext(ctx).Create(&entities).Error; err != nil {
+	if err := r.conn(ctx).Create(&entities).Error; err != nil {
 		return ErrDatabase(err)
 	}
 	return nil
@@ -163,7 +168,7 @@ func (r *BaseRepository[T]) BatchCreate(ctx context.Context, entities []*T) erro
 
 // BatchUpdate 批量更新记录
 func (r *BaseRepository[T]) BatchUpdate(ctx context.Context, updates map[string]any, opts ...QueryOption) (int64, error) {
-	db := r.db.WithContext(ctx).Model(new(T))
+	db := r.conn(ctx).Model(new(T))
 	db = ApplyQuery(db, opts...)
 
 	result := db.Updates(updates)
@@ -175,10 +180,10 @@ func (r *BaseRepository[T]) BatchUpdate(ctx context.Context, updates map[string]
 
 // Transaction 执行事务
 func (r *BaseRepository[T]) Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
-	return r.db.WithContext(ctx).Transaction(fn)
+	return r.conn(ctx).Transaction(fn)
 }
 
 // GetDB 获取数据库连接(用于复杂查询)
 func (r *BaseRepository[T]) GetDB(ctx context.Context) *gorm.DB {
-	return r.db.WithContext(ctx)
+	return r.conn(ctx)
 }
@@ -13,14 +13,23 @@ const (
 	ErrCodeSuccess ErrorCode = 0
 
 	// 客户端错误 1xxx
-	ErrCodeInvalidParam   ErrorCode = 1001 // 参数错误
-	ErrCodeUnauthorized   ErrorCode = 1002 // 未授权
-	ErrCodeForbidden      ErrorCode = 1003 // 禁止访问
-	ErrCodeNotFound       ErrorCode = 1004 // 资源不存在
-	ErrCodeAlreadyExists  ErrorCode = 1005 // 资源已存在
-	ErrCodeInvalidToken   ErrorCode = 1006 // Token无效
-	ErrCodeTokenExpired   ErrorCode = 1007 // Token过期
-	ErrCodeInvalidRequest ErrorCode = 1008 // 请求格式错误
+	ErrCodeInvalidParam     ErrorCode = 1001 // 参数错误
+	ErrCodeUnauthorized     ErrorCode = 1002 // 未授权
+	ErrCodeForbidden        ErrorCode = 1003 // 禁止访问
+	ErrCodeNotFound         ErrorCode = 1004 // 资源不存在
+	ErrCodeAlreadyExists    ErrorCode = 1005 // 资源已存在
+	ErrCodeInvalidToken     ErrorCode = 1006 // Token无效
+	ErrCodeTokenExpired     ErrorCode = 1007 // Token过期
+	ErrCodeInvalidRequest   ErrorCode = 1008 // 请求格式错误
+	ErrCodeAccountLocked    ErrorCode = 1009 // 账户已锁定
+	ErrCodeTooManyRequests  ErrorCode = 1010 // 请求过于频繁
+	ErrCodeTokenNotYetValid ErrorCode = 1011 // Token尚未生效
+	ErrCodeTokenSignature   ErrorCode = 1012 // Token签名无效
+	ErrCodeTokenMalformed   ErrorCode = 1013 // Token格式错误
+	ErrCodeAccountPending   ErrorCode = 1014 // 账户未激活（邮箱未验证）
+	ErrCodeAccountDisabled  ErrorCode = 1015 // 账户已禁用
+	ErrCodeAccountDeleted   ErrorCode = 1016 // 账户已注销
+	ErrCodeStockShortage    ErrorCode = 1017 // 库存不足
 
 	// 服务端错误 5xxx
 	ErrCodeInternalError ErrorCode = 5000 // 内部错误
@@ -31,19 +40,28 @@ const (
 
 // errorMessages 错误码对应的默认消息
 var errorMessages = map[ErrorCode]string{
-	ErrCodeSuccess:        "成功",
-	ErrCodeInvalidParam:   "参数错误",
-	ErrCodeUnauthorized:   "未授权，请先登录",
-	ErrCodeForbidden:      "禁止访问",
-	ErrCodeNotFound:       "资源不存在",
-	ErrCodeAlreadyExists:  "资源已存在",
-	ErrCodeInvalidToken:   "Token无效",
-	ErrCodeTokenExpired:   "Token已过期",
-	ErrCodeInvalidRequest: "请求格式错误",
-	ErrCodeInternalError:  "服务器内部错误",
-	ErrCodeDatabaseError:  "数据库操作失败",
-	ErrCodeCacheError:     "缓存操作失败",
-	ErrCodeServiceError:   "服务调用失败",
+	ErrCodeSuccess:          "成功",
+	ErrCodeInvalidParam:     "参数错误",
+	ErrCodeUnauthorized:     "未授权，请先登录",
+	ErrCodeForbidden:        "禁止访问",
+	ErrCodeNotFound:         "资源不存在",
+	ErrCodeAlreadyExists:    "资源已存在",
+	ErrCodeInvalidToken:     "Token无效",
+	ErrCodeTokenExpired:     "Token已过期",
+	ErrCodeInvalidRequest:   "请求格式错误",
+	ErrCodeAccountLocked:    "账户已锁定",
+	ErrCodeTooManyRequests:  "请求过于频繁",
+	ErrCodeTokenNotYetValid: "Token尚未生效",
+	ErrCodeTokenSignature:   "Token签名无效",
+	ErrCodeTokenMalformed:   "Token格式错误",
+	ErrCodeAccountPending:   "账户未激活，请先验证邮箱",
+	ErrCodeAccountDisabled:  "账户已被禁用",
+	ErrCodeAccountDeleted:   "账户已注销",
+	ErrCodeStockShortage:    "库存不足",
+	ErrCodeInternalError:    "服务器内部错误",
+	ErrCodeDatabaseError:    "数据库操作失败",
+	ErrCodeCacheError:       "缓存操作失败",
+	ErrCodeServiceError:     "服务调用失败",
 }
 
 // GetMessage 获取错误码对应的消息
@@ -59,14 +77,19 @@ func (e ErrorCode) GetHTTPStatus() int {
 	if e >= 1000 && e < 2000 {
 		// 客户端错误
 		switch e {
-		case ErrCodeUnauthorized, ErrCodeInvalidToken, ErrCodeTokenExpired:
+		case ErrCodeUnauthorized, ErrCodeInvalidToken, ErrCodeTokenExpired,
+			ErrCodeTokenNotYetValid, ErrCodeTokenSignature, ErrCodeTokenMalformed:
 			return http.StatusUnauthorized
-		case ErrCodeForbidden:
+		case ErrCodeForbidden, ErrCodeAccountPending, ErrCodeAccountDisabled, ErrCodeAccountDeleted:
 			return http.StatusForbidden
 		case ErrCodeNotFound:
 			return http.StatusNotFound
-		case ErrCodeAlreadyExists:
+		case ErrCodeAlreadyExists, ErrCodeStockShortage:
 			return http.StatusConflict
+		case ErrCodeAccountLocked:
+			return http.StatusLocked
+		case ErrCodeTooManyRequests:
+			return http.StatusTooManyRequests
 		default:
 			return http.StatusBadRequest
 		}
@@ -171,6 +194,51 @@ func ErrTokenExpired() *AppError {
 	return NewError(ErrCodeTokenExpired, "Token已过期")
 }
 
+// ErrTokenNotYetValid Token尚未生效错误
+func ErrTokenNotYetValid() *AppError {
+	return NewError(ErrCodeTokenNotYetValid, "Token尚未生效")
+}
+
+// ErrTokenSignature Token签名无效错误
+func ErrTokenSignature() *AppError {
+	return NewError(ErrCodeTokenSignature, "Token签名无效")
+}
+
+// ErrTokenMalformed Token格式错误
+func ErrTokenMalformed() *AppError {
+	return NewError(ErrCodeTokenMalformed, "Token格式错误")
+}
+
+// ErrAccountLocked 账户锁定错误
+func ErrAccountLocked(message string) *AppError {
+	return NewError(ErrCodeAccountLocked, message)
+}
+
+// ErrAccountPending 账户未激活错误
+func ErrAccountPending() *AppError {
+	return NewError(ErrCodeAccountPending, "")
+}
+
+// ErrAccountDisabled 账户已禁用错误
+func ErrAccountDisabled() *AppError {
+	return NewError(ErrCodeAccountDisabled, "")
+}
+
+// ErrAccountDeleted 账户已注销错误
+func ErrAccountDeleted() *AppError {
+	return NewError(ErrCodeAccountDeleted, "")
+}
+
+// ErrStockShortage 库存不足错误
+func ErrStockShortage() *AppError {
+	return NewError(ErrCodeStockShortage, "")
+}
+
+// ErrTooManyRequests 请求过于频繁错误
+func ErrTooManyRequests(message string) *AppError {
+	return NewError(ErrCodeTooManyRequests, message)
+}
+
 // ErrInternal 内部错误
 func ErrInternal(err error) *AppError {
 	return NewErrorWithErr(ErrCodeInternalError, "服务器内部错误", err)
@@ -186,3 +254,9 @@ func IsAppError(err error) bool {
 	_, ok := err.(*AppError)
 	return ok
 }
+
+// HasErrorCode 判断错误是否为指定错误码的应用错误
+func HasErrorCode(err error, code ErrorCode) bool {
+	appErr, ok := err.(*AppError)
+	return ok && appErr.Code == code
+}
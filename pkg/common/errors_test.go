@@ -75,6 +75,21 @@ func TestErrorCode_GetHTTPStatus(t *testing.T) {
 			code: ErrCodeTokenExpired,
 			want: http.StatusUnauthorized,
 		},
+		{
+			name: "Token尚未生效返回401",
+			code: ErrCodeTokenNotYetValid,
+			want: http.StatusUnauthorized,
+		},
+		{
+			name: "Token签名无效返回401",
+			code: ErrCodeTokenSignature,
+			want: http.StatusUnauthorized,
+		},
+		{
+			name: "Token格式错误返回401",
+			code: ErrCodeTokenMalformed,
+			want: http.StatusUnauthorized,
+		},
 		{
 			name: "禁止访问返回403",
 			code: ErrCodeForbidden,
@@ -95,6 +110,31 @@ func TestErrorCode_GetHTTPStatus(t *testing.T) {
 			code: ErrCodeInvalidParam,
 			want: http.StatusBadRequest,
 		},
+		{
+			name: "账户锁定返回423",
+			code: ErrCodeAccountLocked,
+			want: http.StatusLocked,
+		},
+		{
+			name: "账户未激活返回403",
+			code: ErrCodeAccountPending,
+			want: http.StatusForbidden,
+		},
+		{
+			name: "账户已禁用返回403",
+			code: ErrCodeAccountDisabled,
+			want: http.StatusForbidden,
+		},
+		{
+			name: "库存不足返回409",
+			code: ErrCodeStockShortage,
+			want: http.StatusConflict,
+		},
+		{
+			name: "请求过于频繁返回429",
+			code: ErrCodeTooManyRequests,
+			want: http.StatusTooManyRequests,
+		},
 		{
 			name: "内部错误返回500",
 			code: ErrCodeInternalError,
@@ -349,6 +389,38 @@ func TestErrTokenExpired(t *testing.T) {
 	assert.Equal(t, "Token已过期", err.Message)
 }
 
+func TestTokenErrors(t *testing.T) {
+	assert.Equal(t, ErrCodeTokenNotYetValid, ErrTokenNotYetValid().Code)
+	assert.Equal(t, "Token尚未生效", ErrTokenNotYetValid().Message)
+	assert.Equal(t, ErrCodeTokenSignature, ErrTokenSignature().Code)
+	assert.Equal(t, "Token签名无效", ErrTokenSignature().Message)
+	assert.Equal(t, ErrCodeTokenMalformed, ErrTokenMalformed().Code)
+	assert.Equal(t, "Token格式错误", ErrTokenMalformed().Message)
+}
+
+func TestErrAccountLocked(t *testing.T) {
+	err := ErrAccountLocked("")
+
+	assert.Equal(t, ErrCodeAccountLocked, err.Code)
+	assert.Equal(t, "账户已锁定", err.Message)
+}
+
+func TestAccountStatusErrors(t *testing.T) {
+	assert.Equal(t, ErrCodeAccountPending, ErrAccountPending().Code)
+	assert.Equal(t, "账户未激活，请先验证邮箱", ErrAccountPending().Message)
+	assert.Equal(t, ErrCodeAccountDisabled, ErrAccountDisabled().Code)
+	assert.Equal(t, "账户已被禁用", ErrAccountDisabled().Message)
+	assert.Equal(t, ErrCodeAccountDeleted, ErrAccountDeleted().Code)
+	assert.Equal(t, "账户已注销", ErrAccountDeleted().Message)
+}
+
+func TestErrTooManyRequests(t *testing.T) {
+	err := ErrTooManyRequests("")
+
+	assert.Equal(t, ErrCodeTooManyRequests, err.Code)
+	assert.Equal(t, "请求过于频繁", err.Message)
+}
+
 func TestErrInternal(t *testing.T) {
 	underlyingErr := errors.New("panic recovered")
 	err := ErrInternal(underlyingErr)
@@ -397,3 +469,17 @@ func TestIsAppError(t *testing.T) {
 		})
 	}
 }
+
+func TestHasErrorCode(t *testing.T) {
+	assert.True(t, HasErrorCode(ErrNotFound("用户"), ErrCodeNotFound))
+	assert.False(t, HasErrorCode(ErrDatabase(errors.New("db")), ErrCodeNotFound))
+	assert.False(t, HasErrorCode(errors.New("normal error"), ErrCodeNotFound))
+	assert.False(t, HasErrorCode(nil, ErrCodeNotFound))
+}
+
+func TestErrStockShortage(t *testing.T) {
+	err := ErrStockShortage()
+
+	assert.Equal(t, ErrCodeStockShortage, err.Code)
+	assert.Equal(t, "库存不足", err.Message)
+}
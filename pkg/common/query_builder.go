@@ -79,6 +79,13 @@ func WhereLT(field string, value any) QueryOption {
 	}
 }
 
+// WhereNot 不等于
+func WhereNot(field string, value any) QueryOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(fmt.Sprintf("%s <> ?", field), value)
+	}
+}
+
 // WhereNotNull NOT NULL 条件
 func WhereNotNull(field string) QueryOption {
 	return func(db *gorm.DB) *gorm.DB {
@@ -93,6 +100,13 @@ func WhereNull(field string) QueryOption {
 	}
 }
 
+// WhereRaw 原始条件表达式（如 "stock - reserved >= ?"），字段名不得来自用户输入
+func WhereRaw(query string, args ...any) QueryOption {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(query, args...)
+	}
+}
+
 // ============ 条件查询(智能过滤) ============
 
 // isNil 正确检查值是否为nil（包括接口中包含nil指针的情况）
@@ -1,6 +1,8 @@
 package common
 
 import (
+	"path/filepath"
+	"runtime"
 	"testing"
 
 	"gorm.io/driver/sqlite"
@@ -19,6 +21,30 @@ func SetupTestDB(t *testing.T) *gorm.DB {
 	return db
 }
 
+// SetupConcurrentTestDB 创建测试用的文件数据库，供并发测试使用
+// 内存数据库的每个连接相互独立，这里使用文件数据库让多个连接共享数据并真正并发执行语句
+func SetupConcurrentTestDB(t *testing.T) *gorm.DB {
+	dsn := filepath.Join(t.TempDir(), "test.db") + "?_journal_mode=WAL&_busy_timeout=5000"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("Failed to get database instance: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(8)
+	// 每条查询后主动让出调度，单核环境下也能让并发请求的语句相互交错
+	if err := db.Callback().Query().After("gorm:query").Register("test:yield", func(*gorm.DB) {
+		runtime.Gosched()
+	}); err != nil {
+		t.Fatalf("Failed to register callback: %v", err)
+	}
+	return db
+}
+
 // TeardownTestDB 清理测试数据库
 func TeardownTestDB(t *testing.T, db *gorm.DB) {
 	sqlDB, err := db.DB()
@@ -0,0 +1,44 @@
+package common
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// txContextKey context 中存储事务连接的 key
+type txContextKey struct{}
+
+// Transactor 事务管理接口（Service 层通过它组合多个仓储操作，而不直接依赖 GORM）
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// gormTransactor 基于 GORM 的事务管理实现
+type gormTransactor struct {
+	db *gorm.DB
+}
+
+// NewTransactor 创建事务管理器
+func NewTransactor(db *gorm.DB) Transactor {
+	return &gormTransactor{db: db}
+}
+
+// WithinTransaction 在事务中执行 fn，fn 返回错误时回滚
+// 已处于事务中时直接复用外层事务
+func (t *gormTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txContextKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+	return t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txContextKey{}, tx))
+	})
+}
+
+// DBFromContext 获取当前上下文中的数据库连接，存在事务时返回事务连接
+func DBFromContext(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txContextKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
@@ -2,6 +2,7 @@ package common
 
 import (
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -16,6 +17,34 @@ func ParseUintParam(c *gin.Context, paramName string) (uint, error) {
 	return uint(id), nil
 }
 
+// ParseUintList 解析逗号分隔的 ID 列表（如 "1,2,3"），忽略空项与空白
+func ParseUintList(s string) ([]uint, error) {
+	var ids []uint
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			return nil, ErrInvalidParam("无效的ID: " + part)
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids, nil
+}
+
+// SplitList 解析逗号分隔的字符串列表（如 "quiet,white"），去除空白并忽略空项
+func SplitList(s string) []string {
+	var items []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			items = append(items, part)
+		}
+	}
+	return items
+}
+
 // GetPageInfo 获取分页信息，返回 page 和 pageSize
 func GetPageInfo(page, pageSize int) (int, int) {
 	if page < 1 {
@@ -118,6 +118,40 @@ func TestParseUintParam(t *testing.T) {
 	}
 }
 
+func TestParseUintList(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []uint
+		wantErr bool
+	}{
+		{name: "逗号分隔的ID", input: "1,2,3", want: []uint{1, 2, 3}},
+		{name: "忽略空项与空白", input: " 4, ,5,", want: []uint{4, 5}},
+		{name: "空字符串", input: "", want: nil},
+		{name: "非数字", input: "1,abc", wantErr: true},
+		{name: "负数", input: "-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids, err := ParseUintList(tt.input)
+			if tt.wantErr {
+				assert.True(t, HasErrorCode(err, ErrCodeInvalidParam))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, ids)
+		})
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	assert.Equal(t, []string{"quiet", "White"}, SplitList("quiet, White"))
+	assert.Equal(t, []string{"a", "b"}, SplitList(" a, ,b,"))
+	assert.Nil(t, SplitList(""))
+	assert.Nil(t, SplitList(" , "))
+}
+
 func TestGetPageInfo(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -0,0 +1,43 @@
+package mail
+
+import (
+	"context"
+	"sync"
+)
+
+// AsyncMailer 在后台发送邮件，Send 立即返回
+// 适用于不希望响应结果或耗时反映发送情况的场景（如找回密码），发送失败交给 onError 处理
+type AsyncMailer struct {
+	next    Mailer
+	onError func(msg *Message, err error)
+	wg      sync.WaitGroup
+}
+
+var _ Mailer = &AsyncMailer{}
+
+// NewAsyncMailer 创建后台发送的邮件发送器，onError 可为 nil
+func NewAsyncMailer(next Mailer, onError func(msg *Message, err error)) *AsyncMailer {
+	return &AsyncMailer{
+		next:    next,
+		onError: onError,
+	}
+}
+
+// Send 在后台发送邮件，不随请求的 ctx 取消
+func (m *AsyncMailer) Send(ctx context.Context, msg *Message) error {
+	ctx = context.WithoutCancel(ctx)
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		if err := m.next.Send(ctx, msg); err != nil && m.onError != nil {
+			m.onError(msg, err)
+		}
+	}()
+	return nil
+}
+
+// Wait 等待所有后台发送完成
+func (m *AsyncMailer) Wait() {
+	m.wg.Wait()
+}
@@ -0,0 +1,85 @@
+package mail
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageBytes(t *testing.T) {
+	msg := &Message{
+		From:    "noreply@example.com",
+		To:      []string{"a@example.com", "b@example.com"},
+		Subject: "重置密码",
+		Body:    "line1\nline2",
+	}
+
+	raw := string(msg.Bytes())
+	assert.Contains(t, raw, "From: noreply@example.com\r\n")
+	assert.Contains(t, raw, "To: a@example.com, b@example.com\r\n")
+	assert.Contains(t, raw, "Subject: =?utf-8?q?")
+	assert.True(t, strings.HasSuffix(raw, "\r\n\r\nline1\r\nline2"))
+}
+
+func TestOutboxMailer(t *testing.T) {
+	dir := t.TempDir()
+	mailer := NewOutboxMailer(dir, "noreply@example.com")
+	ctx := context.Background()
+
+	t.Run("保存邮件并写入目录", func(t *testing.T) {
+		err := mailer.Send(ctx, &Message{To: []string{"user@example.com"}, Subject: "hello", Body: "body"})
+		require.NoError(t, err)
+
+		last := mailer.Last()
+		require.NotNil(t, last)
+		assert.Equal(t, "noreply@example.com", last.From)
+		assert.Equal(t, "body", last.Body)
+
+		files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		content, err := os.ReadFile(files[0])
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "To: user@example.com")
+	})
+
+	t.Run("没有收件人", func(t *testing.T) {
+		err := mailer.Send(ctx, &Message{Subject: "hello"})
+		assert.Error(t, err)
+		assert.Len(t, mailer.Messages(), 1)
+	})
+
+	t.Run("拒绝头部注入", func(t *testing.T) {
+		err := mailer.Send(ctx, &Message{To: []string{"user@example.com\r\nBcc: evil@example.com"}})
+		assert.Error(t, err)
+	})
+
+	t.Run("内存发件箱", func(t *testing.T) {
+		memory := NewOutboxMailer("", "")
+		assert.Nil(t, memory.Last())
+		require.NoError(t, memory.Send(ctx, &Message{To: []string{"user@example.com"}}))
+		assert.Len(t, memory.Messages(), 1)
+	})
+}
+
+func TestAsyncMailer(t *testing.T) {
+	outbox := NewOutboxMailer("", "noreply@example.com")
+	var failed []*Message
+	mailer := NewAsyncMailer(outbox, func(msg *Message, err error) {
+		failed = append(failed, msg)
+	})
+
+	// 发送失败不返回错误，交给 onError 处理
+	require.NoError(t, mailer.Send(context.Background(), &Message{To: []string{"user@example.com"}, Subject: "hello"}))
+	require.NoError(t, mailer.Send(context.Background(), &Message{Subject: "no recipients"}))
+	mailer.Wait()
+
+	require.Len(t, outbox.Messages(), 1)
+	require.Len(t, failed, 1)
+	assert.Equal(t, "no recipients", failed[0].Subject)
+}
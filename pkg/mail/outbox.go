@@ -0,0 +1,74 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// OutboxMailer 不真正发送邮件，而是保存在内存中并可选写入目录
+// 适用于开发环境与测试
+type OutboxMailer struct {
+	mu       sync.Mutex
+	dir      string
+	from     string
+	messages []*Message
+}
+
+var _ Mailer = &OutboxMailer{}
+
+// NewOutboxMailer 创建发件箱，dir 为空时只保存在内存中
+func NewOutboxMailer(dir, from string) *OutboxMailer {
+	return &OutboxMailer{
+		dir:  dir,
+		from: from,
+	}
+}
+
+// Send 保存邮件，目录非空时每封邮件写入一个 .eml 文件
+func (m *OutboxMailer) Send(ctx context.Context, msg *Message) error {
+	if msg.From == "" {
+		msg.From = m.from
+	}
+	if err := msg.validate(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.dir != "" {
+		if err := os.MkdirAll(m.dir, 0o755); err != nil {
+			return err
+		}
+		name := fmt.Sprintf("%d-%03d.eml", time.Now().UnixNano(), len(m.messages))
+		if err := os.WriteFile(filepath.Join(m.dir, name), msg.Bytes(), 0o600); err != nil {
+			return err
+		}
+	}
+
+	copied := *msg
+	copied.To = append([]string(nil), msg.To...)
+	m.messages = append(m.messages, &copied)
+	return nil
+}
+
+// Messages 返回已发送邮件的副本
+func (m *OutboxMailer) Messages() []*Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*Message(nil), m.messages...)
+}
+
+// Last 返回最后一封邮件，没有邮件时返回 nil
+func (m *OutboxMailer) Last() *Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.messages) == 0 {
+		return nil
+	}
+	return m.messages[len(m.messages)-1]
+}
@@ -0,0 +1,141 @@
+package oidc
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testIdentity = TestIdentity{
+	Subject:           "user-123",
+	Email:             "alice@example.com",
+	EmailVerified:     true,
+	Name:              "Alice",
+	PreferredUsername: "alice",
+}
+
+func TestCodeChallengeS256(t *testing.T) {
+	// RFC 7636 附录 B 示例
+	assert.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+		CodeChallengeS256("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+
+	verifier, err := NewCodeVerifier()
+	require.NoError(t, err)
+	assert.Len(t, verifier, 43)
+}
+
+func TestProvider_AuthorizationCodeFlow(t *testing.T) {
+	issuer := NewTestIssuer(t)
+	provider := NewProvider(issuer.Config("corp"), nil)
+	ctx := context.Background()
+
+	verifier, err := NewCodeVerifier()
+	require.NoError(t, err)
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", verifier)
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, "openid profile email", parsed.Query().Get("scope"))
+	assert.Equal(t, CodeChallengeS256(verifier), parsed.Query().Get("code_challenge"))
+
+	t.Run("兑换授权码并校验 ID token", func(t *testing.T) {
+		code, state := issuer.Authorize(authURL, testIdentity)
+		assert.Equal(t, "state-1", state)
+
+		tokens, err := provider.Exchange(ctx, code, verifier)
+		require.NoError(t, err)
+
+		claims, err := provider.VerifyIDToken(ctx, tokens.IDToken, "nonce-1")
+		require.NoError(t, err)
+		assert.Equal(t, "user-123", claims.Subject)
+		assert.Equal(t, "alice@example.com", claims.Email)
+		assert.True(t, claims.EmailVerified)
+		assert.Equal(t, "alice", claims.PreferredUsername)
+
+		// 授权码只能兑换一次
+		_, err = provider.Exchange(ctx, code, verifier)
+		assert.Error(t, err)
+	})
+
+	t.Run("PKCE 校验码不匹配", func(t *testing.T) {
+		code, _ := issuer.Authorize(authURL, testIdentity)
+		other, err := NewCodeVerifier()
+		require.NoError(t, err)
+		_, err = provider.Exchange(ctx, code, other)
+		assert.Error(t, err)
+	})
+}
+
+func TestProvider_VerifyIDToken(t *testing.T) {
+	issuer := NewTestIssuer(t)
+	provider := NewProvider(issuer.Config("corp"), nil)
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		mutate func(claims jwt.MapClaims)
+		nonce  string
+	}{
+		{name: "nonce 不匹配", nonce: "other-nonce"},
+		{name: "签发方不匹配", mutate: func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }},
+		{name: "受众不匹配", mutate: func(c jwt.MapClaims) { c["aud"] = "other-client" }},
+		{name: "已过期", mutate: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() }},
+		{name: "缺少过期时间", mutate: func(c jwt.MapClaims) { delete(c, "exp") }},
+		{name: "缺少 subject", mutate: func(c jwt.MapClaims) { c["sub"] = "" }},
+		{name: "azp 不是本客户端", mutate: func(c jwt.MapClaims) {
+			c["aud"] = []string{issuer.ClientID, "other-client"}
+			c["azp"] = "other-client"
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := issuer.IDTokenClaims(testIdentity, "nonce-1")
+			if tt.mutate != nil {
+				tt.mutate(claims)
+			}
+			nonce := tt.nonce
+			if nonce == "" {
+				nonce = "nonce-1"
+			}
+			_, err := provider.VerifyIDToken(ctx, issuer.SignIDToken(claims), nonce)
+			assert.Error(t, err)
+		})
+	}
+
+	t.Run("拒绝未签名与 HMAC token", func(t *testing.T) {
+		claims := issuer.IDTokenClaims(testIdentity, "nonce-1")
+		unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+		require.NoError(t, err)
+		_, err = provider.VerifyIDToken(ctx, unsigned, "nonce-1")
+		assert.Error(t, err)
+
+		hmac := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		hmac.Header["kid"] = "test-key"
+		signed, err := hmac.SignedString([]byte(issuer.ClientSecret))
+		require.NoError(t, err)
+		_, err = provider.VerifyIDToken(ctx, signed, "nonce-1")
+		assert.Error(t, err)
+	})
+
+	t.Run("有效 token", func(t *testing.T) {
+		_, err := provider.VerifyIDToken(ctx, issuer.SignIDToken(issuer.IDTokenClaims(testIdentity, "nonce-1")), "nonce-1")
+		assert.NoError(t, err)
+	})
+}
+
+func TestProvider_DiscoveryIssuerMismatch(t *testing.T) {
+	issuer := NewTestIssuer(t)
+	cfg := issuer.Config("corp")
+	cfg.Issuer = issuer.Server.URL + "/tenant"
+
+	// 发现配置不存在或 issuer 不一致时拒绝
+	_, err := NewProvider(cfg, nil).AuthCodeURL(context.Background(), "s", "n", "v")
+	assert.Error(t, err)
+}
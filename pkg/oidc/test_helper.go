@@ -0,0 +1,214 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TestIdentity 模拟身份提供方中的用户
+type TestIdentity struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+}
+
+// TestIssuer 测试用的本地 OpenID Connect 身份提供方
+// 支持发现配置、JWKS 与授权码换取令牌（校验 PKCE 与客户端凭证）
+type TestIssuer struct {
+	Server       *httptest.Server
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	// ClaimsHook 签发 ID token 前修改声明（用于构造异常 token）
+	ClaimsHook func(claims jwt.MapClaims)
+
+	t     *testing.T
+	key   *rsa.PrivateKey
+	mu    sync.Mutex
+	codes map[string]testAuthorization
+}
+
+// testAuthorization 已授权但尚未兑换的授权码
+type testAuthorization struct {
+	identity      TestIdentity
+	nonce         string
+	codeChallenge string
+	redirectURI   string
+}
+
+// NewTestIssuer 启动本地身份提供方，测试结束时自动关闭
+func NewTestIssuer(t *testing.T) *TestIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate issuer key: %v", err)
+	}
+
+	issuer := &TestIssuer{
+		ClientID:     "test-client",
+		ClientSecret: "test-client-secret",
+		RedirectURL:  "http://localhost/api/v1/auth/oidc/callback",
+		t:            t,
+		key:          key,
+		codes:        make(map[string]testAuthorization),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", issuer.handleDiscovery)
+	mux.HandleFunc("/jwks", issuer.handleJWKS)
+	mux.HandleFunc("/token", issuer.handleToken)
+	issuer.Server = httptest.NewServer(mux)
+	t.Cleanup(issuer.Server.Close)
+
+	return issuer
+}
+
+// Config 获取连接该提供方的客户端配置
+func (i *TestIssuer) Config(name string) Config {
+	return Config{
+		Name:         name,
+		Issuer:       i.Server.URL,
+		ClientID:     i.ClientID,
+		ClientSecret: i.ClientSecret,
+		RedirectURL:  i.RedirectURL,
+	}
+}
+
+// Authorize 模拟用户在提供方完成登录：校验授权地址参数并返回授权码与 state
+func (i *TestIssuer) Authorize(authURL string, identity TestIdentity) (code, state string) {
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		i.t.Fatalf("Invalid authorization url: %v", err)
+	}
+	query := parsed.Query()
+	if query.Get("response_type") != "code" || query.Get("client_id") != i.ClientID ||
+		query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		i.t.Fatalf("Unexpected authorization request: %s", authURL)
+	}
+
+	code, err = NewCodeVerifier()
+	if err != nil {
+		i.t.Fatalf("Failed to generate authorization code: %v", err)
+	}
+	i.mu.Lock()
+	i.codes[code] = testAuthorization{
+		identity:      identity,
+		nonce:         query.Get("nonce"),
+		codeChallenge: query.Get("code_challenge"),
+		redirectURI:   query.Get("redirect_uri"),
+	}
+	i.mu.Unlock()
+	return code, query.Get("state")
+}
+
+// SignIDToken 使用提供方密钥签发 ID token
+func (i *TestIssuer) SignIDToken(claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "test-key"
+	signed, err := token.SignedString(i.key)
+	if err != nil {
+		i.t.Fatalf("Failed to sign id token: %v", err)
+	}
+	return signed
+}
+
+// IDTokenClaims 生成标准的 ID token 声明
+func (i *TestIssuer) IDTokenClaims(identity TestIdentity, nonce string) jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":                i.Server.URL,
+		"sub":                identity.Subject,
+		"aud":                i.ClientID,
+		"exp":                now.Add(5 * time.Minute).Unix(),
+		"iat":                now.Unix(),
+		"nonce":              nonce,
+		"email":              identity.Email,
+		"email_verified":     identity.EmailVerified,
+		"name":               identity.Name,
+		"preferred_username": identity.PreferredUsername,
+	}
+}
+
+// handleDiscovery 返回发现配置
+func (i *TestIssuer) handleDiscovery(w http.ResponseWriter, _ *http.Request) {
+	writeTestJSON(w, http.StatusOK, Metadata{
+		Issuer:                i.Server.URL,
+		AuthorizationEndpoint: i.Server.URL + "/authorize",
+		TokenEndpoint:         i.Server.URL + "/token",
+		JWKSURI:               i.Server.URL + "/jwks",
+	})
+}
+
+// handleJWKS 返回签名公钥集合
+func (i *TestIssuer) handleJWKS(w http.ResponseWriter, _ *http.Request) {
+	pub := i.key.PublicKey
+	writeTestJSON(w, http.StatusOK, map[string]any{
+		"keys": []jsonWebKey{{
+			Kty: "RSA",
+			Kid: "test-key",
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+// handleToken 授权码换取令牌，授权码只能使用一次
+func (i *TestIssuer) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeTestJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	clientID, clientSecret, ok := r.BasicAuth()
+	if ok {
+		clientID, _ = url.QueryUnescape(clientID)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+	}
+	if !ok || clientID != i.ClientID || clientSecret != i.ClientSecret {
+		writeTestJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	code := r.PostForm.Get("code")
+	i.mu.Lock()
+	auth, found := i.codes[code]
+	delete(i.codes, code)
+	i.mu.Unlock()
+
+	if !found || r.PostForm.Get("grant_type") != "authorization_code" ||
+		r.PostForm.Get("redirect_uri") != auth.redirectURI ||
+		CodeChallengeS256(r.PostForm.Get("code_verifier")) != auth.codeChallenge {
+		writeTestJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	claims := i.IDTokenClaims(auth.identity, auth.nonce)
+	if i.ClaimsHook != nil {
+		i.ClaimsHook(claims)
+	}
+	writeTestJSON(w, http.StatusOK, TokenResponse{
+		AccessToken: "test-access-token",
+		TokenType:   "Bearer",
+		IDToken:     i.SignIDToken(claims),
+		ExpiresIn:   300,
+	})
+}
+
+// writeTestJSON 写入 JSON 响应
+func writeTestJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}